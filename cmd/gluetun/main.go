@@ -3,12 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
 	"net/netip"
 	"os"
 	"os/exec"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -30,8 +32,10 @@ import (
 	"github.com/qdm12/gluetun/internal/firewall"
 	"github.com/qdm12/gluetun/internal/healthcheck"
 	"github.com/qdm12/gluetun/internal/httpproxy"
+	"github.com/qdm12/gluetun/internal/logging"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
+	"github.com/qdm12/gluetun/internal/notify"
 	"github.com/qdm12/gluetun/internal/openvpn"
 	"github.com/qdm12/gluetun/internal/openvpn/extract"
 	"github.com/qdm12/gluetun/internal/portforward"
@@ -42,6 +46,7 @@ import (
 	"github.com/qdm12/gluetun/internal/server"
 	"github.com/qdm12/gluetun/internal/shadowsocks"
 	"github.com/qdm12/gluetun/internal/storage"
+	"github.com/qdm12/gluetun/internal/tproxy"
 	updater "github.com/qdm12/gluetun/internal/updater/loop"
 	"github.com/qdm12/gluetun/internal/updater/resolver"
 	"github.com/qdm12/gluetun/internal/updater/unzip"
@@ -159,6 +164,8 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 			return cli.FormatServers(args[2:])
 		case "genkey":
 			return cli.GenKey(args[2:])
+		case "ctl":
+			return cli.Ctl(ctx, args[2:], reader)
 		default:
 			return fmt.Errorf("command is unknown: %s", args[1])
 		}
@@ -201,10 +208,26 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	if err != nil {
 		return fmt.Errorf("log level: %w", err)
 	}
-	logger.Patch(log.SetLevel(logLevel))
-	netLinker.PatchLoggerLevel(logLevel)
+	// logBuffer keeps the last log lines to serve them from the control server.
+	const logBufferLines = 500
+	logBuffer := logging.NewBuffer(logBufferLines)
+	logOptions := []log.Option{
+		log.SetLevel(logLevel),
+		log.SetWriters(logging.NewWriter(io.MultiWriter(os.Stdout, logBuffer), *allSettings.Log.Format)),
+	}
+	if *allSettings.Log.Format == settings.LogFormatJSON {
+		// the JSON writer sets the time field itself
+		logOptions = append(logOptions, log.SetTimeFormat(""))
+	}
+	logger.Patch(logOptions...)
+	netLinker.PatchLogger(append(logOptions,
+		log.SetLevel(allSettings.Log.ComponentLevel("netlink")))...)
+	newLogger := func(component string) *log.Logger {
+		return logger.New(log.SetComponent(component),
+			log.SetLevel(allSettings.Log.ComponentLevel(component)))
+	}
 
-	routingLogger := logger.New(log.SetComponent("routing"))
+	routingLogger := newLogger("routing")
 	routingConf := routing.New(netLinker, routingLogger)
 
 	defaultRoutes, err := routingConf.DefaultRoutes()
@@ -220,7 +243,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	iptablesLogLevel, _ := log.ParseLevel(allSettings.Firewall.Iptables.LogLevel)
 	iptablesLogger := logger.New(log.SetComponent("iptables"), log.SetLevel(iptablesLogLevel))
 
-	firewallLogger := logger.New(log.SetComponent("firewall"))
+	firewallLogger := newLogger("firewall")
 	firewallConf, err := firewall.NewConfig(ctx, firewallLogger, iptablesLogger, cmder,
 		defaultRoutes, localNetworks)
 	if err != nil {
@@ -239,9 +262,10 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	}
 
 	// TODO run this in a loop or in openvpn to reload from file without restarting
-	storageLogger := logger.New(log.SetComponent("storage"))
+	storageLogger := newLogger("storage")
 	storage, err := storage.New(storageLogger, *allSettings.Storage.ServersEnabled,
-		allSettings.Storage.ServersPath, allSettings.Storage.LegacyServersFilepath)
+		allSettings.Storage.ServersPath, allSettings.Storage.LegacyServersFilepath,
+		*allSettings.Storage.RequireSignature)
 	if err != nil {
 		return err
 	}
@@ -259,7 +283,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		return err
 	}
 
-	allSettings.Pprof.HTTPServer.Logger = logger.New(log.SetComponent("pprof"))
+	allSettings.Pprof.HTTPServer.Logger = newLogger("pprof")
 	pprofServer, err := pprof.New(allSettings.Pprof)
 	if err != nil {
 		return fmt.Errorf("creating Pprof server: %w", err)
@@ -272,7 +296,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	// Create configurators
 	alpineConf := alpine.New()
 	ovpnConf := openvpn.New(
-		logger.New(log.SetComponent("openvpn configurator")),
+		newLogger("openvpn configurator"),
 		cmder, puid, pgid)
 	ovpnVersion := ovpnConf.Version26
 	if allSettings.VPN.OpenVPN.Version == copenvpn.Openvpn25 {
@@ -333,6 +357,26 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	if err := routingConf.SetOutboundRoutes(allSettings.Firewall.OutboundSubnets); err != nil {
 		return err
 	}
+	if *allSettings.Firewall.Gateway.Enabled {
+		err = firewallConf.SetGateway(ctx, allSettings.Firewall.Gateway.ClientSubnets,
+			*allSettings.Firewall.Gateway.DNSHijack)
+		if err != nil {
+			return fmt.Errorf("setting up gateway: %w", err)
+		}
+	}
+	if *allSettings.TransparentProxy.Enabled {
+		ipv6Sources := slices.ContainsFunc(allSettings.TransparentProxy.Sources,
+			func(source netip.Prefix) bool { return source.Addr().Is6() })
+		err = routingConf.RouteTransparentProxyLocally(tproxy.FirewallMark, ipv6Sources)
+		if err != nil {
+			return fmt.Errorf("routing transparent proxy traffic: %w", err)
+		}
+		err = firewallConf.SetTransparentProxy(ctx, allSettings.TransparentProxy.Sources,
+			allSettings.TransparentProxy.Port, tproxy.FirewallMark)
+		if err != nil {
+			return fmt.Errorf("setting up transparent proxy: %w", err)
+		}
+	}
 
 	err = routingConf.AddLocalRules(localNetworks)
 	if err != nil {
@@ -375,16 +419,25 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		<-pprofReady
 	}
 
-	portForwardLogger := logger.New(log.SetComponent("port forwarding"))
+	notifier, err := notify.New(allSettings.Notifications, allSettings.PublicIP, httpClient,
+		newLogger("notifications"))
+	if err != nil {
+		return fmt.Errorf("creating notifier: %w", err)
+	}
+	notifierHandler, notifierCtx, notifierDone := goshutdown.NewGoRoutineHandler(
+		"notifier", goroutine.OptionTimeout(defaultShutdownTimeout))
+	go notifier.Run(notifierCtx, notifierDone)
+
+	portForwardLogger := newLogger("port forwarding")
 	portForwardLooper := portforward.NewLoop(allSettings.VPN.Provider.PortForwarding,
-		routingConf, httpClient, firewallConf, portForwardLogger, cmder, puid, pgid)
+		routingConf, httpClient, firewallConf, portForwardLogger, cmder, notifier, puid, pgid)
 	portForwardRunError, err := portForwardLooper.Start(ctx)
 	if err != nil {
 		return fmt.Errorf("starting port forwarding loop: %w", err)
 	}
 
-	dnsLogger := logger.New(log.SetComponent("dns"))
-	dnsLooper, err := dns.NewLoop(allSettings.DNS, httpClient,
+	dnsLogger := newLogger("dns")
+	dnsLooper, err := dns.NewLoop(allSettings.DNS, httpClient, dns.NewIPLeak(httpClient),
 		dnsLogger, localNetworksToPrefixes(localNetworks))
 	if err != nil {
 		return fmt.Errorf("creating DNS loop: %w", err)
@@ -401,8 +454,8 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	go dnsLooper.RunRestartTicker(dnsTickerCtx, dnsTickerDone)
 	controlGroupHandler.Add(dnsTickerHandler)
 
-	publicIPLooper, err := publicip.NewLoop(allSettings.PublicIP, puid, pgid, httpClient,
-		logger.New(log.SetComponent("ip getter")))
+	publicIPLooper, err := publicip.NewLoop(allSettings.PublicIP, puid, pgid, httpClient, notifier,
+		newLogger("ip getter"))
 	if err != nil {
 		return fmt.Errorf("creating public ip loop: %w", err)
 	}
@@ -411,7 +464,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		return fmt.Errorf("starting public ip loop: %w", err)
 	}
 
-	healthLogger := logger.New(log.SetComponent("healthcheck"))
+	healthLogger := newLogger("healthcheck")
 	healthcheckServer := healthcheck.NewServer(allSettings.Health, healthLogger)
 	healthServerHandler, healthServerCtx, healthServerDone := goshutdown.NewGoRoutineHandler(
 		"HTTP health server", goroutine.OptionTimeout(defaultShutdownTimeout))
@@ -426,7 +479,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	if err != nil {
 		return fmt.Errorf("creating updater DoH dialer: %w", err)
 	}
-	updaterLogger := logger.New(log.SetComponent("updater"))
+	updaterLogger := newLogger("updater")
 
 	unzipper := unzip.New(httpClient)
 	parallelResolver := resolver.NewParallelResolver(dohDialer)
@@ -435,20 +488,21 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		httpClient, unzipper, parallelResolver, publicIPLooper.Fetcher(),
 		openvpnFileExtractor, allSettings.Updater)
 
-	boringPollLogger := logger.New(log.SetComponent("boring poll"))
+	boringPollLogger := newLogger("boring poll")
 	boringPoll := boringpoll.New(httpClient, boringPollLogger, allSettings.BoringPoll)
 
-	vpnLogger := logger.New(log.SetComponent("vpn"))
+	vpnLogger := newLogger("vpn")
 	vpnLooper := vpn.NewLoop(allSettings.VPN, ipv6SupportLevel, allSettings.Firewall.VPNInputPorts,
-		providers, storage, boringPoll, allSettings.Health, healthChecker, healthcheckServer,
+		allSettings.ProxyPorts(), providers, storage, boringPoll,
+		allSettings.Health, healthChecker, healthcheckServer,
 		ovpnConf, netLinker, firewallConf, routingConf, portForwardLooper, cmder, publicIPLooper,
-		dnsLooper, vpnLogger, httpClient, buildInfo, *allSettings.Version.Enabled)
+		dnsLooper, notifier, vpnLogger, allSettings.Log, httpClient, buildInfo, *allSettings.Version.Enabled)
 	vpnHandler, vpnCtx, vpnDone := goshutdown.NewGoRoutineHandler(
 		"vpn", goroutine.OptionTimeout(time.Second))
 	go vpnLooper.Run(vpnCtx, vpnDone)
 
-	updaterLooper := updater.NewLoop(allSettings.Updater,
-		providers, storage, httpClient, updaterLogger)
+	updaterLooper := updater.NewLoop(allSettings.Updater, *allSettings.Storage.RequireSignature,
+		providers, storage, vpnLooper, httpClient, notifier, updaterLogger)
 	updaterHandler, updaterCtx, updaterDone := goshutdown.NewGoRoutineHandler(
 		"updater", goroutine.OptionTimeout(defaultShutdownTimeout))
 	// wait for updaterLooper.Restart() or its ticket launched with RunRestartTicker
@@ -461,7 +515,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	controlGroupHandler.Add(updaterTickerHandler)
 
 	httpProxyLooper := httpproxy.NewLoop(
-		logger.New(log.SetComponent("http proxy")),
+		newLogger("http proxy"),
 		allSettings.HTTPProxy)
 	httpProxyHandler, httpProxyCtx, httpProxyDone := goshutdown.NewGoRoutineHandler(
 		"http proxy", goroutine.OptionTimeout(defaultShutdownTimeout))
@@ -469,18 +523,29 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 	otherGroupHandler.Add(httpProxyHandler)
 
 	shadowsocksLooper := shadowsocks.NewLoop(allSettings.Shadowsocks,
-		logger.New(log.SetComponent("shadowsocks")))
+		newLogger("shadowsocks"))
 	shadowsocksHandler, shadowsocksCtx, shadowsocksDone := goshutdown.NewGoRoutineHandler(
 		"shadowsocks proxy", goroutine.OptionTimeout(defaultShutdownTimeout))
 	go shadowsocksLooper.Run(shadowsocksCtx, shadowsocksDone)
 	otherGroupHandler.Add(shadowsocksHandler)
 
+	if *allSettings.TransparentProxy.Enabled {
+		transparentProxyServer := tproxy.New(allSettings.TransparentProxy,
+			newLogger("transparent proxy"))
+		transparentProxyReady := make(chan struct{})
+		transparentProxyHandler, transparentProxyCtx, transparentProxyDone := goshutdown.NewGoRoutineHandler(
+			"transparent proxy", goroutine.OptionTimeout(defaultShutdownTimeout))
+		go transparentProxyServer.Run(transparentProxyCtx, transparentProxyReady, transparentProxyDone)
+		otherGroupHandler.Add(transparentProxyHandler)
+		<-transparentProxyReady
+	}
+
 	httpServerHandler, httpServerCtx, httpServerDone := goshutdown.NewGoRoutineHandler(
 		"http server", goroutine.OptionTimeout(defaultShutdownTimeout))
 	httpServer, err := server.New(httpServerCtx, allSettings.ControlServer,
-		logger.New(log.SetComponent("http server")),
+		newLogger("http server"),
 		buildInfo, vpnLooper, portForwardLooper, dnsLooper, updaterLooper, publicIPLooper,
-		storage, ipv6SupportLevel.IsSupported())
+		storage, healthcheckServer, logBuffer, ipv6SupportLevel.IsSupported(), puid, pgid)
 	if err != nil {
 		return fmt.Errorf("setting up control server: %w", err)
 	}
@@ -494,7 +559,7 @@ func _main(ctx context.Context, buildInfo models.BuildInformation,
 		order.OptionOnSuccess(defaultShutdownOnSuccess),
 		order.OptionOnFailure(defaultShutdownOnFailure))
 	orderHandler.Append(controlGroupHandler, tickersGroupHandler, healthServerHandler,
-		vpnHandler, otherGroupHandler)
+		vpnHandler, otherGroupHandler, notifierHandler)
 
 	// Start VPN for the first time in a blocking call
 	// until the VPN is launched
@@ -568,7 +633,7 @@ type netLinker interface {
 		checkAddresses []netip.AddrPort, firewall netlink.Firewall,
 	) (level netlink.IPv6SupportLevel, err error)
 	FlushConntrack() error
-	PatchLoggerLevel(level log.Level)
+	PatchLogger(options ...log.Option)
 }
 
 type Addresser interface {
@@ -608,6 +673,7 @@ type clier interface {
 	HealthCheck(ctx context.Context, reader *reader.Reader, warner cli.Warner) error
 	Update(ctx context.Context, args []string, logger cli.UpdaterLogger) error
 	GenKey(args []string) error
+	Ctl(ctx context.Context, args []string, reader *reader.Reader) error
 }
 
 type RunStarter interface {
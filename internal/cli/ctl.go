@@ -0,0 +1,323 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gosettings/reader"
+)
+
+const ctlUsage = `Usage: gluetun ctl [flags] <command>
+
+Commands:
+  vpn status|start|stop|rotate
+  vpn mtu [discover]
+  vpn set [-countries=a,b] [-regions=a,b] [-cities=a,b] [-hostnames=a,b] [-names=a,b] [-isps=a,b]
+  publicip
+  portforward
+  dns status|start|stop
+  updater status|run
+
+Flags:
+`
+
+// Ctl sends a request to the control server of a running instance
+// and prints its response as a table, or as JSON if the -json flag is set.
+func (c *CLI) Ctl(ctx context.Context, args []string, reader *reader.Reader) error {
+	return ctl(ctx, args, reader, os.Stdout)
+}
+
+type ctlRequest struct {
+	method string
+	path   string
+	body   any
+}
+
+func ctl(ctx context.Context, args []string, reader *reader.Reader, stdout io.Writer) error {
+	flagSet := flag.NewFlagSet("ctl", flag.ExitOnError)
+	flagSet.Usage = func() {
+		fmt.Fprint(flagSet.Output(), ctlUsage)
+		flagSet.PrintDefaults()
+	}
+	url := flagSet.String("url", "", "base URL of the control server, defaulting to the "+
+		"control server settings of the environment")
+	socketPath := flagSet.String("socket", "", "path of the control server Unix socket")
+	apiKey := flagSet.String("api-key", "", "API key to authenticate with")
+	username := flagSet.String("username", "", "username to authenticate with basic authentication")
+	password := flagSet.String("password", "", "password to authenticate with basic authentication")
+	jsonOutput := flagSet.Bool("json", false, "print the response as JSON")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+
+	request, err := parseCtlCommand(flagSet.Args())
+	if err != nil {
+		return err
+	}
+
+	if *url == "" && *socketPath == "" {
+		*url, *socketPath, err = readControlServerTarget(reader)
+		if err != nil {
+			return fmt.Errorf("reading control server settings: %w", err)
+		}
+	}
+
+	const timeout = 10 * time.Second
+	client := &http.Client{Timeout: timeout}
+	baseURL := strings.TrimSuffix(*url, "/")
+	if *socketPath != "" {
+		dialer := &net.Dialer{}
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", *socketPath)
+			},
+		}
+		baseURL = "http://unix"
+	}
+
+	var body io.Reader
+	if request.body != nil {
+		data, err := json.Marshal(request.body)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, request.method, baseURL+request.path, body)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	if body != nil {
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+	if *apiKey != "" {
+		httpRequest.Header.Set("X-API-Key", *apiKey)
+	}
+	if *username != "" || *password != "" {
+		httpRequest.SetBasicAuth(*username, *password)
+	}
+
+	response, err := client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		_ = response.Body.Close()
+		return fmt.Errorf("reading response body: %w", err)
+	}
+	err = response.Body.Close()
+	if err != nil {
+		return fmt.Errorf("closing response body: %w", err)
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%s %s: %s: %s", request.method, request.path,
+			response.Status, strings.TrimSpace(string(data)))
+	}
+
+	return printCtlResponse(stdout, data, *jsonOutput)
+}
+
+var errCtlCommandUnknown = errors.New("command is unknown")
+
+func parseCtlCommand(args []string) (request ctlRequest, err error) {
+	if len(args) == 0 {
+		return request, fmt.Errorf("%w: no command given, see gluetun ctl -h", errCtlCommandUnknown)
+	}
+
+	switch args[0] {
+	case "vpn":
+		return parseCtlLoopCommand("/v1/vpn", args[1:])
+	case "dns":
+		return parseCtlLoopCommand("/v1/dns", args[1:])
+	case "updater":
+		return parseCtlLoopCommand("/v1/updater", args[1:])
+	case "publicip":
+		return ctlRequest{method: http.MethodGet, path: "/v1/publicip/ip"}, nil
+	case "portforward":
+		return ctlRequest{method: http.MethodGet, path: "/v1/portforward"}, nil
+	default:
+		return request, fmt.Errorf("%w: %s", errCtlCommandUnknown, args[0])
+	}
+}
+
+// parseCtlLoopCommand parses the subcommand for a loop such
+// as the VPN, DNS or updater loop, given its route prefix.
+func parseCtlLoopCommand(prefix string, args []string) (request ctlRequest, err error) {
+	if len(args) == 0 {
+		return request, fmt.Errorf("%w: %s: no subcommand given",
+			errCtlCommandUnknown, strings.TrimPrefix(prefix, "/v1/"))
+	}
+
+	type status struct {
+		Status string `json:"status"`
+	}
+
+	switch {
+	case args[0] == "status":
+		return ctlRequest{method: http.MethodGet, path: prefix + "/status"}, nil
+	case args[0] == "start", args[0] == "run" && prefix == "/v1/updater":
+		return ctlRequest{method: http.MethodPut, path: prefix + "/status", body: status{Status: "running"}}, nil
+	case args[0] == "stop" && prefix != "/v1/updater":
+		return ctlRequest{method: http.MethodPut, path: prefix + "/status", body: status{Status: "stopped"}}, nil
+	case args[0] == "rotate" && prefix == "/v1/vpn":
+		return ctlRequest{method: http.MethodPut, path: prefix + "/rotate"}, nil
+	case args[0] == "mtu" && prefix == "/v1/vpn":
+		if len(args) > 1 && args[1] == "discover" {
+			return ctlRequest{method: http.MethodPut, path: prefix + "/mtu/discover"}, nil
+		}
+		return ctlRequest{method: http.MethodGet, path: prefix + "/mtu"}, nil
+	case args[0] == "set" && prefix == "/v1/vpn":
+		return parseCtlVPNSet(args[1:])
+	default:
+		return request, fmt.Errorf("%w: %s %s", errCtlCommandUnknown,
+			strings.TrimPrefix(prefix, "/v1/"), args[0])
+	}
+}
+
+func parseCtlVPNSet(args []string) (request ctlRequest, err error) {
+	flagSet := flag.NewFlagSet("vpn set", flag.ExitOnError)
+	fields := map[string]*string{
+		"countries": flagSet.String("countries", "", "comma separated list of countries"),
+		"regions":   flagSet.String("regions", "", "comma separated list of regions"),
+		"cities":    flagSet.String("cities", "", "comma separated list of cities"),
+		"hostnames": flagSet.String("hostnames", "", "comma separated list of server hostnames"),
+		"names":     flagSet.String("names", "", "comma separated list of server names"),
+		"isps":      flagSet.String("isps", "", "comma separated list of ISPs"),
+	}
+	if err := flagSet.Parse(args); err != nil {
+		return request, err
+	}
+
+	serverSelection := make(map[string][]string)
+	flagSet.Visit(func(f *flag.Flag) {
+		values := []string{}
+		for value := range strings.SplitSeq(*fields[f.Name], ",") {
+			value = strings.TrimSpace(value)
+			if value != "" {
+				values = append(values, value)
+			}
+		}
+		serverSelection[f.Name] = values
+	})
+	if len(serverSelection) == 0 {
+		return request, errors.New("no server selection flag set, see gluetun ctl vpn set -h")
+	}
+
+	body := map[string]any{
+		"provider": map[string]any{
+			"server_selection": serverSelection,
+		},
+	}
+	return ctlRequest{method: http.MethodPut, path: "/v1/vpn/settings", body: body}, nil
+}
+
+// readControlServerTarget returns the URL or Unix socket path to reach
+// the control server, using the same settings as the running instance.
+// The Unix socket is preferred if it is set.
+func readControlServerTarget(reader *reader.Reader) (url, socketPath string, err error) {
+	var controlServer settings.ControlServer
+	err = controlServer.Read(reader)
+	if err != nil {
+		return "", "", err
+	}
+	controlServer.SetDefaults()
+
+	if *controlServer.SocketPath != "" {
+		return "", *controlServer.SocketPath, nil
+	}
+
+	host, port, err := net.SplitHostPort(*controlServer.Address)
+	if err != nil {
+		return "", "", fmt.Errorf("parsing listening address: %w", err)
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port), "", nil
+}
+
+// printCtlResponse prints the response data either as indented JSON,
+// or as a two columns table with nested JSON fields flattened.
+// Non JSON responses are printed as they are.
+func printCtlResponse(w io.Writer, data []byte, jsonOutput bool) error {
+	var value any
+	err := json.Unmarshal(data, &value)
+	if err != nil {
+		_, err = fmt.Fprintln(w, strings.TrimSpace(string(data)))
+		return err
+	}
+
+	if jsonOutput {
+		indented, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(indented))
+		return err
+	}
+
+	rows := make(map[string]string)
+	flattenCtlValue(rows, "", value)
+	keys := make([]string, 0, len(rows))
+	for key := range rows {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	const minWidth, tabWidth, padding = 0, 8, 2
+	tabWriter := tabwriter.NewWriter(w, minWidth, tabWidth, padding, ' ', 0)
+	for _, key := range keys {
+		_, err = fmt.Fprintf(tabWriter, "%s\t%s\n", key, rows[key])
+		if err != nil {
+			return err
+		}
+	}
+	return tabWriter.Flush()
+}
+
+func flattenCtlValue(rows map[string]string, key string, value any) {
+	switch typed := value.(type) {
+	case map[string]any:
+		for childKey, childValue := range typed {
+			if key != "" {
+				childKey = key + "." + childKey
+			}
+			flattenCtlValue(rows, childKey, childValue)
+		}
+	case []any:
+		values := make([]string, 0, len(typed))
+		for i, element := range typed {
+			switch element.(type) {
+			case map[string]any, []any:
+				// arrays of objects are flattened with their indexes
+				flattenCtlValue(rows, key+"."+strconv.Itoa(i), element)
+			default:
+				values = append(values, fmt.Sprint(element))
+			}
+		}
+		if len(values) > 0 || len(typed) == 0 {
+			rows[key] = strings.Join(values, ", ")
+		}
+	case nil:
+		rows[key] = ""
+	default:
+		rows[key] = fmt.Sprint(typed)
+	}
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ctl(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		args          []string
+		responseCode  int
+		responseBody  string
+		expectedPath  string
+		expectedBody  string
+		expectedKey   string
+		expectedUser  string
+		output        string
+		errMessage    string
+		skipsRequests bool
+	}{
+		"vpn_status_table": {
+			args:         []string{"vpn", "status"},
+			responseCode: http.StatusOK,
+			responseBody: `{"status":"running"}`,
+			expectedPath: "GET /v1/vpn/status",
+			output:       "status  running\n",
+		},
+		"vpn_stop_api_key": {
+			args:         []string{"-api-key", "xyz", "vpn", "stop"},
+			responseCode: http.StatusOK,
+			responseBody: `{"outcome":"stopped"}`,
+			expectedPath: "PUT /v1/vpn/status",
+			expectedBody: `{"status":"stopped"}`,
+			expectedKey:  "xyz",
+			output:       "outcome  stopped\n",
+		},
+		"vpn_set_countries": {
+			args:         []string{"-username", "user", "-password", "pass", "vpn", "set", "-countries=Canada, France"},
+			responseCode: http.StatusOK,
+			responseBody: "settings updated",
+			expectedPath: "PUT /v1/vpn/settings",
+			expectedBody: `{"provider":{"server_selection":{"countries":["Canada","France"]}}}`,
+			expectedUser: "user",
+			output:       "settings updated\n",
+		},
+		"updater_run": {
+			args:         []string{"updater", "run"},
+			responseCode: http.StatusOK,
+			responseBody: `{"outcome":"running"}`,
+			expectedPath: "PUT /v1/updater/status",
+			expectedBody: `{"status":"running"}`,
+			output:       "outcome  running\n",
+		},
+		"portforward_json": {
+			args:         []string{"-json", "portforward"},
+			responseCode: http.StatusOK,
+			responseBody: `{"port":1234,"ports":[1234,5678]}`,
+			expectedPath: "GET /v1/portforward",
+			output:       "{\n  \"port\": 1234,\n  \"ports\": [\n    1234,\n    5678\n  ]\n}\n",
+		},
+		"publicip_nested": {
+			args:         []string{"publicip"},
+			responseCode: http.StatusOK,
+			responseBody: `{"public_ip":"1.2.3.4","country":"Canada","extra":{"a":null}}`,
+			expectedPath: "GET /v1/publicip/ip",
+			output:       "country    Canada\nextra.a    \npublic_ip  1.2.3.4\n",
+		},
+		"vpn_mtu_probes": {
+			args:         []string{"vpn", "mtu"},
+			responseCode: http.StatusOK,
+			responseBody: `{"mtu":1420,"probes":[{"method":"icmp","mtu":1420}]}`,
+			expectedPath: "GET /v1/vpn/mtu",
+			output:       "mtu              1420\nprobes.0.method  icmp\nprobes.0.mtu     1420\n",
+		},
+		"error_status": {
+			args:         []string{"dns", "status"},
+			responseCode: http.StatusUnauthorized,
+			responseBody: "Unauthorized\n",
+			expectedPath: "GET /v1/dns/status",
+			errMessage:   "GET /v1/dns/status: 401 Unauthorized: Unauthorized",
+		},
+		"unknown_command": {
+			args:          []string{"vpn", "restart"},
+			errMessage:    "command is unknown: vpn restart",
+			skipsRequests: true,
+		},
+		"updater_cannot_stop": {
+			args:          []string{"updater", "stop"},
+			errMessage:    "command is unknown: updater stop",
+			skipsRequests: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.False(t, testCase.skipsRequests)
+				assert.Equal(t, testCase.expectedPath, r.Method+" "+r.URL.Path)
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expectedBody, string(body))
+				assert.Equal(t, testCase.expectedKey, r.Header.Get("X-API-Key"))
+				username, _, _ := r.BasicAuth()
+				assert.Equal(t, testCase.expectedUser, username)
+				w.WriteHeader(testCase.responseCode)
+				_, _ = w.Write([]byte(testCase.responseBody))
+			}))
+			t.Cleanup(server.Close)
+
+			args := append([]string{"-url", server.URL}, testCase.args...)
+			stdout := bytes.NewBuffer(nil)
+
+			err := ctl(context.Background(), args, nil, stdout)
+
+			if testCase.errMessage != "" {
+				require.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.output, stdout.String())
+		})
+	}
+}
@@ -72,7 +72,7 @@ func (c *CLI) FormatServers(args []string) error {
 		}
 	}
 
-	storage, err := setupStorage(newNoopLogger())
+	storage, _, err := setupStorage(newNoopLogger())
 	if err != nil {
 		return fmt.Errorf("setting up storage: %w", err)
 	}
@@ -16,7 +16,9 @@ type storageSetupLogger interface {
 	files.Warner
 }
 
-func setupStorage(logger storageSetupLogger) (s *storage.Storage, err error) {
+func setupStorage(logger storageSetupLogger) (s *storage.Storage,
+	storageSettings settings.Storage, err error,
+) {
 	settingsReader := reader.New(reader.Settings{
 		Sources: []reader.Source{
 			secrets.New(logger),
@@ -24,16 +26,15 @@ func setupStorage(logger storageSetupLogger) (s *storage.Storage, err error) {
 			env.New(env.Settings{}),
 		},
 	})
-	var settings settings.Storage
-	err = settings.Read(settingsReader)
+	err = storageSettings.Read(settingsReader)
 	if err != nil {
-		return nil, fmt.Errorf("reading storage settings: %w", err)
+		return nil, storageSettings, fmt.Errorf("reading storage settings: %w", err)
 	}
-	settings.SetDefaults()
-	storage, err := storage.New(logger, *settings.ServersEnabled, settings.ServersPath,
-		settings.LegacyServersFilepath)
+	storageSettings.SetDefaults()
+	storage, err := storage.New(logger, *storageSettings.ServersEnabled, storageSettings.ServersPath,
+		storageSettings.LegacyServersFilepath, *storageSettings.RequireSignature)
 	if err != nil {
-		return nil, fmt.Errorf("creating storage: %w", err)
+		return nil, storageSettings, fmt.Errorf("creating storage: %w", err)
 	}
-	return storage, nil
+	return storage, storageSettings, nil
 }
@@ -47,7 +47,7 @@ type IPv6Checker interface {
 func (c *CLI) OpenvpnConfig(logger OpenvpnConfigLogger, reader *reader.Reader,
 	ipv6Checker IPv6Checker,
 ) error {
-	storage, err := setupStorage(newNoopLogger())
+	storage, _, err := setupStorage(newNoopLogger())
 	if err != nil {
 		return fmt.Errorf("setting up storage: %w", err)
 	}
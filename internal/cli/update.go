@@ -2,10 +2,12 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"net/http"
+	"os"
 	"slices"
 	"strings"
 	"time"
@@ -14,6 +16,7 @@ import (
 	dnsprovider "github.com/qdm12/dns/v2/pkg/provider"
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/openvpn/extract"
 	"github.com/qdm12/gluetun/internal/provider"
 	"github.com/qdm12/gluetun/internal/publicip/api"
@@ -36,6 +39,7 @@ func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) e
 	var endUserMode, maintainerMode bool
 	var updateAll bool
 	var dnsServer, csvProviders, ipToken, protonUsername, protonEmail, protonPassword string
+	var reportPath string
 	flagSet := flag.NewFlagSet("update", flag.ExitOnError)
 	flagSet.StringVar(&dnsServer, "dns", "", "no longer used, your DNS will use DoH with Cloudflare and Google")
 	const defaultMinRatio = 0.8
@@ -48,6 +52,8 @@ func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) e
 		"(Retro-compatibility) Username to use to authenticate with Proton. Use -proton-email instead.") // v4 remove this
 	flagSet.StringVar(&protonEmail, "proton-email", "", "Email to use to authenticate with Proton")
 	flagSet.StringVar(&protonPassword, "proton-password", "", "Password to use to authenticate with Proton")
+	flagSet.StringVar(&reportPath, "report", "",
+		"File path to write the JSON report of servers changes to, or - to write it to stdout")
 	flagSet.BoolVar(&endUserMode, "enduser", false, "deprecated")
 	flagSet.BoolVar(&maintainerMode, "maintainer", false, "deprecated")
 	if err := flagSet.Parse(args); err != nil {
@@ -89,7 +95,7 @@ func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) e
 		return fmt.Errorf("options validation failed: %w", err)
 	}
 
-	storage, err := setupStorage(logger)
+	storage, storageSettings, err := setupStorage(logger)
 	if err != nil {
 		return fmt.Errorf("creating servers storage: %w", err)
 	}
@@ -125,11 +131,35 @@ func (c *CLI) Update(ctx context.Context, args []string, logger UpdaterLogger) e
 	providers := provider.NewProviders(storage, time.Now, logger, httpClient,
 		unzipper, parallelResolver, ipFetcher, openvpnFileExtractor, options)
 
-	updater := updater.New(httpClient, storage, providers, logger, *options.PreferDirectDownload)
-	err = updater.UpdateServers(ctx, options.Providers, options.MinRatio)
+	updater := updater.New(httpClient, storage, providers, logger,
+		*options.PreferDirectDownload, *storageSettings.RequireSignature)
+	report, err := updater.UpdateServers(ctx, options.Providers, options.MinRatio)
 	if err != nil {
 		return fmt.Errorf("updating server information: %w", err)
 	}
 
+	if reportPath != "" {
+		err = writeUpdateReport(reportPath, report)
+		if err != nil {
+			return fmt.Errorf("writing update report: %w", err)
+		}
+	}
+
 	return nil
 }
+
+func writeUpdateReport(path string, report models.ServersUpdateReport) (err error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding report: %w", err)
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	const perms = os.FileMode(0o644)
+	return os.WriteFile(path, data, perms)
+}
@@ -48,6 +48,9 @@ type DNS struct {
 	// Note, if the upstream type is [dnsUpstreamTypePlain] and this field is set,
 	// the Providers field is ignored.
 	UpstreamPlainAddresses []netip.AddrPort
+	// LeakCheck contains settings to configure the
+	// DNS leak check.
+	LeakCheck DNSLeakCheck
 }
 
 func (d DNS) validate() (err error) {
@@ -97,6 +100,11 @@ func (d DNS) validate() (err error) {
 		return err
 	}
 
+	err = d.LeakCheck.validate()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -127,6 +135,7 @@ func (d *DNS) Copy() (copied DNS) {
 		IPv6:                   gosettings.CopyPointer(d.IPv6),
 		Blacklist:              d.Blacklist.copy(),
 		UpstreamPlainAddresses: gosettings.CopySlice(d.UpstreamPlainAddresses),
+		LeakCheck:              d.LeakCheck.copy(),
 	}
 }
 
@@ -142,6 +151,7 @@ func (d *DNS) overrideWith(other DNS) {
 	d.IPv6 = gosettings.OverrideWithPointer(d.IPv6, other.IPv6)
 	d.Blacklist.overrideWith(other.Blacklist)
 	d.UpstreamPlainAddresses = gosettings.OverrideWithSlice(d.UpstreamPlainAddresses, other.UpstreamPlainAddresses)
+	d.LeakCheck.overrideWith(other.LeakCheck)
 }
 
 func (d *DNS) setDefaults() {
@@ -158,6 +168,7 @@ func (d *DNS) setDefaults() {
 	d.Caching = gosettings.DefaultPointer(d.Caching, true)
 	d.IPv6 = gosettings.DefaultPointer(d.IPv6, false)
 	d.Blacklist.setDefaults()
+	d.LeakCheck.setDefaults()
 }
 
 func defaultDNSProviders() []string {
@@ -211,6 +222,7 @@ func (d DNS) toLinesNode() (node *gotree.Node) {
 	node.Appendf("Update period: %s", update)
 
 	node.AppendNode(d.Blacklist.toLinesNode())
+	node.AppendNode(d.LeakCheck.toLinesNode())
 
 	return node
 }
@@ -250,6 +262,11 @@ func (d *DNS) read(r *reader.Reader) (err error) {
 		return err
 	}
 
+	err = d.LeakCheck.read(r)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -262,6 +279,18 @@ func (d *DNS) readUpstreamPlainAddresses(r *reader.Reader) (err error) {
 		return err
 	}
 
+	// The DNS addresses from the interface DNS key of a wg-quick configuration
+	// file are appended to the other upstream plain addresses, and force the
+	// upstream type to plain, since these are usually only reachable through
+	// the tunnel and are set to be used by the user.
+	wireguardDNSAddresses, err := r.CSVNetipAddrPorts("WIREGUARD_DNS_ADDRESSES")
+	if err != nil {
+		return err
+	} else if len(wireguardDNSAddresses) > 0 {
+		d.UpstreamPlainAddresses = append(d.UpstreamPlainAddresses, wireguardDNSAddresses...)
+		d.UpstreamType = DNSUpstreamTypePlain
+	}
+
 	// Retro-compatibility - remove in v4
 	// If DNS_ADDRESS is set to a non-localhost address, append it to the other
 	// upstream plain addresses, assuming port 53, and force the upstream type to plain
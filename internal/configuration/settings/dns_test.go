@@ -1,9 +1,13 @@
 package settings
 
 import (
+	"net/netip"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/qdm12/dns/v2/pkg/provider"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,3 +28,55 @@ func Test_defaultDNSProviders(t *testing.T) {
 	}
 	require.True(t, found, "no default DNS provider has a plaintext IPv4 address")
 }
+
+func Test_DNS_readUpstreamPlainAddresses(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		keyValues []sourceKeyValue
+		settings  DNS
+	}{
+		"nothing_read": {
+			keyValues: []sourceKeyValue{
+				{key: "DNS_UPSTREAM_PLAIN_ADDRESSES"},
+				{key: "WIREGUARD_DNS_ADDRESSES"},
+				{key: "DNS_PLAINTEXT_ADDRESS"},
+				{key: "DNS_ADDRESS"},
+			},
+			settings: DNS{UpstreamType: DNSUpstreamTypeDot},
+		},
+		"wireguard_dns_addresses": {
+			keyValues: []sourceKeyValue{
+				{key: "DNS_UPSTREAM_PLAIN_ADDRESSES", value: "1.1.1.1:53"},
+				{key: "WIREGUARD_DNS_ADDRESSES", value: "10.64.0.1:53"},
+				{key: "DNS_PLAINTEXT_ADDRESS"},
+				{key: "DNS_ADDRESS"},
+			},
+			settings: DNS{
+				UpstreamType: DNSUpstreamTypePlain,
+				UpstreamPlainAddresses: []netip.AddrPort{
+					netip.MustParseAddrPort("1.1.1.1:53"),
+					netip.MustParseAddrPort("10.64.0.1:53"),
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			source := newMockSource(ctrl, testCase.keyValues)
+			r := reader.New(reader.Settings{
+				Sources: []reader.Source{source},
+			})
+			settings := DNS{UpstreamType: DNSUpstreamTypeDot}
+
+			err := settings.readUpstreamPlainAddresses(r)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.settings, settings)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package settings
+
+import (
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// DNSLeakCheck contains settings to configure the DNS leak check.
+type DNSLeakCheck struct {
+	// Enabled is true if the DNS leak check should run each
+	// time the VPN tunnel is up. It defaults to true and cannot
+	// be nil in the internal state.
+	Enabled *bool `json:"enabled"`
+	// Period is the period to periodically run the DNS leak check
+	// once the VPN tunnel is up. It can be set to 0 to disable
+	// periodic checks. It defaults to 0 and cannot be nil in the
+	// internal state.
+	Period *time.Duration `json:"period"`
+	// ExpectedResolvers is the list of IP prefixes all resolvers
+	// seen by the leak check should belong to. If a resolver seen
+	// is not in any of these prefixes, a DNS leak is reported.
+	// If left empty, the upstream plain addresses are used if set
+	// with the plain upstream type. Otherwise the upstream providers
+	// addresses and known resolver egress prefixes are used, and if
+	// these are unknown for a provider, resolvers seen are only
+	// reported and no leak can be detected.
+	// It cannot be nil in the internal state.
+	ExpectedResolvers []netip.Prefix `json:"expected_resolvers"`
+	// RestartVPN is true if the VPN should be restarted
+	// when a DNS leak is detected. It defaults to false and
+	// cannot be nil in the internal state.
+	RestartVPN *bool `json:"restart_vpn"`
+}
+
+func (d DNSLeakCheck) validate() (err error) {
+	const minPeriod = time.Minute
+	if *d.Period != 0 && *d.Period < minPeriod {
+		return fmt.Errorf("leak check period is too short: %s must be bigger than %s",
+			*d.Period, minPeriod)
+	}
+
+	for _, prefix := range d.ExpectedResolvers {
+		if !prefix.IsValid() {
+			return fmt.Errorf("leak check expected resolver prefix is not valid: %s", prefix)
+		}
+	}
+
+	return nil
+}
+
+func (d *DNSLeakCheck) copy() (copied DNSLeakCheck) {
+	return DNSLeakCheck{
+		Enabled:           gosettings.CopyPointer(d.Enabled),
+		Period:            gosettings.CopyPointer(d.Period),
+		ExpectedResolvers: gosettings.CopySlice(d.ExpectedResolvers),
+		RestartVPN:        gosettings.CopyPointer(d.RestartVPN),
+	}
+}
+
+func (d *DNSLeakCheck) overrideWith(other DNSLeakCheck) {
+	d.Enabled = gosettings.OverrideWithPointer(d.Enabled, other.Enabled)
+	d.Period = gosettings.OverrideWithPointer(d.Period, other.Period)
+	d.ExpectedResolvers = gosettings.OverrideWithSlice(d.ExpectedResolvers, other.ExpectedResolvers)
+	d.RestartVPN = gosettings.OverrideWithPointer(d.RestartVPN, other.RestartVPN)
+}
+
+func (d *DNSLeakCheck) setDefaults() {
+	d.Enabled = gosettings.DefaultPointer(d.Enabled, true)
+	d.Period = gosettings.DefaultPointer(d.Period, 0)
+	d.ExpectedResolvers = gosettings.DefaultSlice(d.ExpectedResolvers, []netip.Prefix{})
+	d.RestartVPN = gosettings.DefaultPointer(d.RestartVPN, false)
+}
+
+func (d DNSLeakCheck) String() string {
+	return d.toLinesNode().String()
+}
+
+func (d DNSLeakCheck) toLinesNode() (node *gotree.Node) {
+	if !*d.Enabled {
+		return gotree.New("DNS leak check: disabled")
+	}
+
+	node = gotree.New("DNS leak check:")
+
+	period := "disabled"
+	if *d.Period > 0 {
+		period = "every " + d.Period.String()
+	}
+	node.Appendf("Periodic check: %s", period)
+
+	if len(d.ExpectedResolvers) > 0 {
+		expectedNode := node.Append("Expected resolvers:")
+		for _, prefix := range d.ExpectedResolvers {
+			expectedNode.Append(prefix.String())
+		}
+	}
+
+	node.Appendf("Restart VPN on leak: %s", gosettings.BoolToYesNo(d.RestartVPN))
+
+	return node
+}
+
+func (d *DNSLeakCheck) read(r *reader.Reader) (err error) {
+	d.Enabled, err = r.BoolPtr("DNS_LEAK_CHECK")
+	if err != nil {
+		return err
+	}
+
+	d.Period, err = r.DurationPtr("DNS_LEAK_CHECK_PERIOD")
+	if err != nil {
+		return err
+	}
+
+	d.ExpectedResolvers, err = r.CSVNetipPrefixes("DNS_LEAK_CHECK_EXPECTED_RESOLVERS")
+	if err != nil {
+		return err
+	}
+
+	d.RestartVPN, err = r.BoolPtr("DNS_LEAK_CHECK_RESTART_VPN")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package settings
+
+import (
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// ExitVerification contains settings to verify the public IP address
+// information obtained once the VPN is up matches the server selection.
+type ExitVerification struct {
+	// Enabled is true if the public IP address information should be
+	// verified each time the VPN tunnel is up. A mismatch marks the
+	// container as unhealthy. It requires the public IP fetching to be
+	// enabled. It defaults to false and cannot be nil in the internal state.
+	Enabled *bool `json:"enabled"`
+	// CheckIP is true if the public IP address should be one of the
+	// IP addresses of the server connected to, or in one of their /24 IPv4
+	// or /64 IPv6 subnets. It should be set to false for providers using
+	// exit IP addresses different from their server IP addresses.
+	// It defaults to true and cannot be nil in the internal state.
+	CheckIP *bool `json:"check_ip"`
+	// RestartVPN is true if the VPN should be restarted when the
+	// verification fails. It defaults to false and cannot be nil
+	// in the internal state.
+	RestartVPN *bool `json:"restart_vpn"`
+}
+
+func (e *ExitVerification) copy() (copied ExitVerification) {
+	return ExitVerification{
+		Enabled:    gosettings.CopyPointer(e.Enabled),
+		CheckIP:    gosettings.CopyPointer(e.CheckIP),
+		RestartVPN: gosettings.CopyPointer(e.RestartVPN),
+	}
+}
+
+func (e *ExitVerification) overrideWith(other ExitVerification) {
+	e.Enabled = gosettings.OverrideWithPointer(e.Enabled, other.Enabled)
+	e.CheckIP = gosettings.OverrideWithPointer(e.CheckIP, other.CheckIP)
+	e.RestartVPN = gosettings.OverrideWithPointer(e.RestartVPN, other.RestartVPN)
+}
+
+func (e *ExitVerification) setDefaults() {
+	e.Enabled = gosettings.DefaultPointer(e.Enabled, false)
+	e.CheckIP = gosettings.DefaultPointer(e.CheckIP, true)
+	e.RestartVPN = gosettings.DefaultPointer(e.RestartVPN, false)
+}
+
+func (e ExitVerification) String() string {
+	return e.toLinesNode().String()
+}
+
+func (e ExitVerification) toLinesNode() (node *gotree.Node) {
+	if !*e.Enabled {
+		return gotree.New("Exit verification: disabled")
+	}
+
+	node = gotree.New("Exit verification:")
+	node.Appendf("Check IP address: %s", gosettings.BoolToYesNo(e.CheckIP))
+	node.Appendf("Restart VPN on mismatch: %s", gosettings.BoolToYesNo(e.RestartVPN))
+
+	return node
+}
+
+func (e *ExitVerification) read(r *reader.Reader) (err error) {
+	e.Enabled, err = r.BoolPtr("VPN_EXIT_VERIFICATION")
+	if err != nil {
+		return err
+	}
+
+	e.CheckIP, err = r.BoolPtr("VPN_EXIT_VERIFICATION_CHECK_IP")
+	if err != nil {
+		return err
+	}
+
+	e.RestartVPN, err = r.BoolPtr("VPN_EXIT_VERIFICATION_RESTART_VPN")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -17,6 +17,7 @@ type Firewall struct {
 	OutboundSubnets []netip.Prefix
 	Enabled         *bool
 	Iptables        Iptables
+	Gateway         Gateway
 }
 
 func (f Firewall) validate() (err error) {
@@ -39,6 +40,15 @@ func (f Firewall) validate() (err error) {
 		return fmt.Errorf("iptables settings: %w", err)
 	}
 
+	err = f.Gateway.validate()
+	if err != nil {
+		return fmt.Errorf("gateway settings: %w", err)
+	}
+
+	if f.Gateway.Enabled != nil && *f.Gateway.Enabled && !*f.Enabled {
+		return errors.New("gateway cannot be enabled with the firewall disabled")
+	}
+
 	return nil
 }
 
@@ -58,6 +68,7 @@ func (f *Firewall) copy() (copied Firewall) {
 		OutboundSubnets: gosettings.CopySlice(f.OutboundSubnets),
 		Enabled:         gosettings.CopyPointer(f.Enabled),
 		Iptables:        f.Iptables.copy(),
+		Gateway:         f.Gateway.copy(),
 	}
 }
 
@@ -70,11 +81,13 @@ func (f *Firewall) overrideWith(other Firewall) {
 	f.OutboundSubnets = gosettings.OverrideWithSlice(f.OutboundSubnets, other.OutboundSubnets)
 	f.Enabled = gosettings.OverrideWithPointer(f.Enabled, other.Enabled)
 	f.Iptables.overrideWith(other.Iptables)
+	f.Gateway.overrideWith(other.Gateway)
 }
 
 func (f *Firewall) setDefaults(globalLogLevel string) {
 	f.Enabled = gosettings.DefaultPointer(f.Enabled, true)
 	f.Iptables.setDefaults(globalLogLevel)
+	f.Gateway.setDefaults()
 }
 
 func (f Firewall) String() string {
@@ -112,6 +125,8 @@ func (f Firewall) toLinesNode() (node *gotree.Node) {
 		}
 	}
 
+	node.AppendNode(f.Gateway.toLinesNode())
+
 	return node
 }
 
@@ -142,5 +157,10 @@ func (f *Firewall) read(r *reader.Reader) (err error) {
 		return fmt.Errorf("reading iptables settings: %w", err)
 	}
 
+	err = f.Gateway.read(r)
+	if err != nil {
+		return fmt.Errorf("reading gateway settings: %w", err)
+	}
+
 	return nil
 }
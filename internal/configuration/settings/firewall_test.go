@@ -46,6 +46,35 @@ func Test_Firewall_validate(t *testing.T) {
 				},
 			},
 		},
+		"gateway_without_client_subnets": {
+			firewall: Firewall{
+				Iptables: Iptables{LogLevel: log.LevelInfo.String()},
+				Gateway:  Gateway{Enabled: ptrTo(true)},
+			},
+			errMessage: "gateway settings: gateway client subnets are not set",
+		},
+		"gateway_client_subnet_not_masked": {
+			firewall: Firewall{
+				Iptables: Iptables{LogLevel: log.LevelInfo.String()},
+				Gateway: Gateway{
+					Enabled:       ptrTo(true),
+					ClientSubnets: []netip.Prefix{netip.MustParsePrefix("192.168.1.5/24")},
+				},
+			},
+			errMessage: "gateway settings: gateway client subnet is not valid: " +
+				"192.168.1.5/24 should be 192.168.1.0/24",
+		},
+		"gateway_with_firewall_disabled": {
+			firewall: Firewall{
+				Enabled:  ptrTo(false),
+				Iptables: Iptables{LogLevel: log.LevelInfo.String()},
+				Gateway: Gateway{
+					Enabled:       ptrTo(true),
+					ClientSubnets: []netip.Prefix{netip.MustParsePrefix("192.168.1.0/24")},
+				},
+			},
+			errMessage: "gateway cannot be enabled with the firewall disabled",
+		},
 		"valid_settings": {
 			firewall: Firewall{
 				Iptables:      Iptables{LogLevel: log.LevelInfo.String()},
@@ -55,6 +84,11 @@ func Test_Firewall_validate(t *testing.T) {
 					netip.MustParsePrefix("192.168.1.0/24"),
 					netip.MustParsePrefix("10.10.1.1/32"),
 				},
+				Enabled: ptrTo(true),
+				Gateway: Gateway{
+					Enabled:       ptrTo(true),
+					ClientSubnets: []netip.Prefix{netip.MustParsePrefix("192.168.1.0/24")},
+				},
 			},
 		},
 	}
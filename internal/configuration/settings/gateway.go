@@ -0,0 +1,114 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// Gateway contains settings to use Gluetun as the
+// default gateway of other hosts on the local network.
+type Gateway struct {
+	// Enabled is true if hosts in the client subnets can use
+	// Gluetun as their gateway. It cannot be nil in the
+	// internal state.
+	Enabled *bool
+	// ClientSubnets are the subnets of hosts allowed to use
+	// Gluetun as their gateway. It must be set if the gateway
+	// is enabled. Client subnets not directly connected to Gluetun
+	// should also be set as firewall outbound subnets, so traffic
+	// back to their hosts is not routed through the VPN.
+	ClientSubnets []netip.Prefix
+	// DNSHijack is true if DNS traffic from the client subnets
+	// is redirected to the built-in DNS server. It cannot be nil
+	// in the internal state.
+	DNSHijack *bool
+}
+
+var (
+	ErrGatewayClientSubnetsNotSet  = errors.New("gateway client subnets are not set")
+	ErrGatewayClientSubnetNotValid = errors.New("gateway client subnet is not valid")
+)
+
+func (g Gateway) validate() (err error) {
+	if g.Enabled == nil || !*g.Enabled {
+		return nil
+	}
+
+	if len(g.ClientSubnets) == 0 {
+		return ErrGatewayClientSubnetsNotSet
+	}
+
+	for _, subnet := range g.ClientSubnets {
+		switch {
+		case subnet.Addr().IsUnspecified():
+			return fmt.Errorf("%w: %s has an unspecified address", ErrGatewayClientSubnetNotValid, subnet)
+		case subnet.Addr().IsLoopback():
+			return fmt.Errorf("%w: %s has a loopback address", ErrGatewayClientSubnetNotValid, subnet)
+		case subnet.Masked() != subnet:
+			return fmt.Errorf("%w: %s should be %s", ErrGatewayClientSubnetNotValid, subnet, subnet.Masked())
+		}
+	}
+
+	return nil
+}
+
+func (g *Gateway) copy() (copied Gateway) {
+	return Gateway{
+		Enabled:       gosettings.CopyPointer(g.Enabled),
+		ClientSubnets: gosettings.CopySlice(g.ClientSubnets),
+		DNSHijack:     gosettings.CopyPointer(g.DNSHijack),
+	}
+}
+
+func (g *Gateway) overrideWith(other Gateway) {
+	g.Enabled = gosettings.OverrideWithPointer(g.Enabled, other.Enabled)
+	g.ClientSubnets = gosettings.OverrideWithSlice(g.ClientSubnets, other.ClientSubnets)
+	g.DNSHijack = gosettings.OverrideWithPointer(g.DNSHijack, other.DNSHijack)
+}
+
+func (g *Gateway) setDefaults() {
+	g.Enabled = gosettings.DefaultPointer(g.Enabled, false)
+	g.DNSHijack = gosettings.DefaultPointer(g.DNSHijack, true)
+}
+
+func (g Gateway) String() string {
+	return g.toLinesNode().String()
+}
+
+func (g Gateway) toLinesNode() (node *gotree.Node) {
+	if !*g.Enabled {
+		return nil
+	}
+
+	node = gotree.New("Gateway settings:")
+	clientSubnetsNode := node.Appendf("Client subnets:")
+	for _, subnet := range g.ClientSubnets {
+		clientSubnetsNode.Appendf("%s", subnet)
+	}
+	node.Appendf("DNS hijacking: %s", gosettings.BoolToYesNo(g.DNSHijack))
+	return node
+}
+
+func (g *Gateway) read(r *reader.Reader) (err error) {
+	g.Enabled, err = r.BoolPtr("FIREWALL_GATEWAY")
+	if err != nil {
+		return err
+	}
+
+	g.ClientSubnets, err = r.CSVNetipPrefixes("FIREWALL_GATEWAY_CLIENT_SUBNETS")
+	if err != nil {
+		return err
+	}
+
+	g.DNSHijack, err = r.BoolPtr("FIREWALL_GATEWAY_DNS_HIJACK")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,32 +1,75 @@
 package settings
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
 	"github.com/qdm12/gotree"
 	"github.com/qdm12/log"
 )
 
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 // Log contains settings to configure the logger.
 type Log struct {
 	// Level is the log level of the logger.
 	// It cannot be empty in the internal state.
 	Level string
+	// Format is the log output format, which can be
+	// "text" or "json". It defaults to "text" and
+	// cannot be nil in the internal state.
+	Format *string
+	// ComponentLevels maps component names, such as "dns"
+	// or "port forwarding", to a log level overriding the
+	// global log level for that component. It defaults to
+	// an empty map and cannot be nil in the internal state.
+	ComponentLevels map[string]string
 }
 
+var (
+	ErrLogComponentLevelNotValid = errors.New("log component level is not valid")
+	ErrLogComponentNotValid      = errors.New("log component is not valid")
+)
+
 func (l Log) validate() (err error) {
 	_, err = log.ParseLevel(l.Level)
 	if err != nil {
 		return fmt.Errorf("level: %w", err)
 	}
+
+	err = validate.IsOneOf(*l.Format, LogFormatText, LogFormatJSON)
+	if err != nil {
+		return fmt.Errorf("format: %w", err)
+	}
+
+	for component, level := range l.ComponentLevels {
+		if findLogComponent(component) == "" {
+			return fmt.Errorf("%w: %q must be one of %s",
+				ErrLogComponentNotValid, component, strings.Join(logComponents(), ", "))
+		}
+		_, err = log.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("level for component %s: %w", component, err)
+		}
+	}
+
 	return nil
 }
 
 func (l *Log) copy() (copied Log) {
 	return Log{
-		Level: l.Level,
+		Level:           l.Level,
+		Format:          gosettings.CopyPointer(l.Format),
+		ComponentLevels: maps.Clone(l.ComponentLevels),
 	}
 }
 
@@ -35,10 +78,75 @@ func (l *Log) copy() (copied Log) {
 // settings.
 func (l *Log) overrideWith(other Log) {
 	l.Level = gosettings.OverrideWithComparable(l.Level, other.Level)
+	l.Format = gosettings.OverrideWithPointer(l.Format, other.Format)
+	if other.ComponentLevels != nil {
+		l.ComponentLevels = maps.Clone(other.ComponentLevels)
+	}
 }
 
 func (l *Log) setDefaults() {
 	l.Level = gosettings.DefaultComparable(l.Level, log.LevelInfo.String())
+	l.Format = gosettings.DefaultPointer(l.Format, LogFormatText)
+	if l.ComponentLevels == nil {
+		l.ComponentLevels = map[string]string{}
+	}
+}
+
+// ComponentLevel returns the log level for the component given,
+// which is the global log level unless it is overridden for
+// this component. Component names are compared ignoring case,
+// spaces, dashes and underscores, such that "portforwarding"
+// matches the "port forwarding" component.
+func (l Log) ComponentLevel(component string) (level log.Level) {
+	levelString := l.Level
+	component = findLogComponent(component)
+	for name, componentLevel := range l.ComponentLevels {
+		if findLogComponent(name) == component {
+			levelString = componentLevel
+			break
+		}
+	}
+	level, _ = log.ParseLevel(levelString)
+	return level
+}
+
+// logComponents returns the names of the components
+// whose log level can be overridden.
+func logComponents() []string {
+	return []string{
+		"amneziawg", "boring poll", "dns", "firewall", "healthcheck",
+		"http proxy", "http server", "ip getter", "MTU discovery",
+		"netlink", "notifications", "openvpn", "openvpn configurator",
+		"port forwarding", "pprof", "routing", "shadowsocks", "storage",
+		"transparent proxy", "updater", "vpn", "wireguard",
+	}
+}
+
+// findLogComponent returns the known component name matching
+// the name given, or the empty string if no component matches.
+// The name "portforward" is accepted as an alias for the
+// "port forwarding" component.
+func findLogComponent(name string) (component string) {
+	normalizedName := normalizeComponent(name)
+	if normalizedName == "portforward" {
+		return "port forwarding"
+	}
+	for _, component := range logComponents() {
+		if normalizeComponent(component) == normalizedName {
+			return component
+		}
+	}
+	return ""
+}
+
+func normalizeComponent(component string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(component))
 }
 
 func (l Log) String() string {
@@ -48,10 +156,33 @@ func (l Log) String() string {
 func (l Log) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Log settings:")
 	node.Appendf("Log level: %s", l.Level)
+	node.Appendf("Log format: %s", *l.Format)
+	if len(l.ComponentLevels) > 0 {
+		componentsNode := node.Appendf("Component log levels:")
+		for _, component := range slices.Sorted(maps.Keys(l.ComponentLevels)) {
+			componentsNode.Appendf("%s: %s", component, l.ComponentLevels[component])
+		}
+	}
 	return node
 }
 
 func (l *Log) read(r *reader.Reader) (err error) {
 	l.Level = r.String("LOG_LEVEL")
+	l.Format = r.Get("LOG_FORMAT")
+
+	componentLevels := r.CSV("LOG_COMPONENT_LEVELS")
+	if componentLevels != nil {
+		l.ComponentLevels = make(map[string]string, len(componentLevels))
+		for _, componentLevel := range componentLevels {
+			component, level, ok := strings.Cut(componentLevel, "=")
+			component = strings.TrimSpace(component)
+			if !ok || component == "" {
+				return fmt.Errorf("%w: %q does not match the format component=level",
+					ErrLogComponentLevelNotValid, componentLevel)
+			}
+			l.ComponentLevels[component] = strings.TrimSpace(level)
+		}
+	}
+
 	return nil
 }
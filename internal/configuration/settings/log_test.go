@@ -0,0 +1,81 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/qdm12/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Log_validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   Log
+		errMessage string
+	}{
+		"valid_component_levels": {
+			settings: Log{
+				Level:  "info",
+				Format: ptrTo(LogFormatText),
+				ComponentLevels: map[string]string{
+					"Port_Forwarding": "debug",
+					"portforward":     "debug",
+					"wireguard":       "warn",
+					"MTU discovery":   "error",
+				},
+			},
+		},
+		"unknown_component": {
+			settings: Log{
+				Level:           "info",
+				Format:          ptrTo(LogFormatText),
+				ComponentLevels: map[string]string{"dnss": "debug"},
+			},
+			errMessage: `log component is not valid: "dnss" must be one of ` +
+				"amneziawg, boring poll, dns, firewall, healthcheck, http proxy, " +
+				"http server, ip getter, MTU discovery, netlink, notifications, " +
+				"openvpn, openvpn configurator, port forwarding, pprof, routing, " +
+				"shadowsocks, storage, transparent proxy, updater, vpn, wireguard",
+		},
+		"invalid_component_level": {
+			settings: Log{
+				Level:           "info",
+				Format:          ptrTo(LogFormatText),
+				ComponentLevels: map[string]string{"dns": "verbose"},
+			},
+			errMessage: "level for component dns: level is not recognized: verbose",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.settings.validate()
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_Log_ComponentLevel(t *testing.T) {
+	t.Parallel()
+
+	settings := Log{
+		Level: "info",
+		ComponentLevels: map[string]string{
+			"portforward":   "debug",
+			"MTU_discovery": "error",
+		},
+	}
+
+	assert.Equal(t, log.LevelDebug, settings.ComponentLevel("port forwarding"))
+	assert.Equal(t, log.LevelError, settings.ComponentLevel("MTU discovery"))
+	assert.Equal(t, log.LevelInfo, settings.ComponentLevel("dns"))
+	assert.Equal(t, log.LevelInfo, settings.ComponentLevel("iptables"))
+}
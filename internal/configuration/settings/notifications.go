@@ -0,0 +1,304 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// Notifications contains settings to send notifications
+// on events such as the VPN tunnel going up or down.
+type Notifications struct {
+	// Events is the list of events to send notifications for.
+	// It defaults to all events.
+	Events []string
+	// Webhooks is the list of URLs to send a POST request to for
+	// each notification. It defaults to an empty list.
+	Webhooks []string
+	// WebhookTemplate is a Go text template for the webhooks request
+	// body, executed with the notification. It can be the empty string
+	// to send the notification as JSON. It cannot be nil in the internal
+	// state.
+	WebhookTemplate *string
+	// NtfyURLs is the list of ntfy topic URLs to publish
+	// notifications to. It defaults to an empty list.
+	NtfyURLs []string
+	// NtfyToken is the ntfy access token. It can be the empty string
+	// and cannot be nil in the internal state.
+	NtfyToken *string
+	// GotifyURL is the base URL of a Gotify server to send
+	// notifications to. It can be the empty string to disable
+	// Gotify and cannot be nil in the internal state.
+	GotifyURL *string
+	// GotifyToken is the Gotify application token, and must be set
+	// if GotifyURL is set. It cannot be nil in the internal state.
+	GotifyToken *string
+	// SMTPAddress is the SMTP server address in the form host:port.
+	// It can be the empty string to disable email notifications and
+	// cannot be nil in the internal state.
+	SMTPAddress *string
+	// SMTPUsername is the SMTP username, which can be the empty string
+	// to not authenticate. It cannot be nil in the internal state.
+	SMTPUsername *string
+	// SMTPPassword is the SMTP password. It cannot be nil in the
+	// internal state.
+	SMTPPassword *string
+	// SMTPFrom is the email address to send notifications from, and
+	// must be set if SMTPAddress is set. It cannot be nil in the
+	// internal state.
+	SMTPFrom *string
+	// SMTPTo is the list of email addresses to send notifications to,
+	// and must be set if SMTPAddress is set.
+	SMTPTo []string
+	// Retries is the number of retries after a failed attempt to
+	// send a notification to a sink. It cannot be nil in the internal
+	// state.
+	Retries *uint
+	// RetryBackoff is the initial duration to wait before retrying,
+	// which doubles at each retry. It cannot be nil in the internal state.
+	RetryBackoff *time.Duration
+}
+
+var (
+	ErrNotificationEventNotValid = errors.New("notification event is not valid")
+	ErrGotifyTokenMissing        = errors.New("gotify token is missing")
+	ErrSMTPFromMissing           = errors.New("SMTP from address is missing")
+	ErrSMTPToMissing             = errors.New("SMTP to addresses are missing")
+)
+
+func (n Notifications) validate() (err error) {
+	validEvents := models.NotificationEvents()
+	for _, event := range n.Events {
+		if !slices.Contains(validEvents, models.NotificationEvent(event)) {
+			return fmt.Errorf("%w: %s", ErrNotificationEventNotValid, event)
+		}
+	}
+
+	for _, webhook := range n.Webhooks {
+		err = validateWebhookURL(webhook)
+		if err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+	}
+
+	if *n.WebhookTemplate != "" {
+		_, err = template.New("webhook").Parse(*n.WebhookTemplate)
+		if err != nil {
+			return fmt.Errorf("parsing webhook template: %w", err)
+		}
+	}
+
+	for _, ntfyURL := range n.NtfyURLs {
+		err = validateWebhookURL(ntfyURL)
+		if err != nil {
+			return fmt.Errorf("ntfy: %w", err)
+		}
+	}
+
+	if *n.GotifyURL != "" {
+		err = validateWebhookURL(*n.GotifyURL)
+		if err != nil {
+			return fmt.Errorf("gotify URL: %w", err)
+		} else if *n.GotifyToken == "" {
+			return ErrGotifyTokenMissing
+		}
+	}
+
+	if *n.SMTPAddress != "" {
+		err = validateSMTP(*n.SMTPAddress, *n.SMTPFrom, n.SMTPTo)
+		if err != nil {
+			return fmt.Errorf("SMTP: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateSMTP(address, from string, to []string) (err error) {
+	_, _, err = net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("address: %w", err)
+	}
+
+	if from == "" {
+		return ErrSMTPFromMissing
+	}
+	_, err = mail.ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("from address: %w", err)
+	}
+
+	if len(to) == 0 {
+		return ErrSMTPToMissing
+	}
+	for _, address := range to {
+		_, err = mail.ParseAddress(address)
+		if err != nil {
+			return fmt.Errorf("to address: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (n *Notifications) copy() (copied Notifications) {
+	return Notifications{
+		Events:          gosettings.CopySlice(n.Events),
+		Webhooks:        gosettings.CopySlice(n.Webhooks),
+		WebhookTemplate: gosettings.CopyPointer(n.WebhookTemplate),
+		NtfyURLs:        gosettings.CopySlice(n.NtfyURLs),
+		NtfyToken:       gosettings.CopyPointer(n.NtfyToken),
+		GotifyURL:       gosettings.CopyPointer(n.GotifyURL),
+		GotifyToken:     gosettings.CopyPointer(n.GotifyToken),
+		SMTPAddress:     gosettings.CopyPointer(n.SMTPAddress),
+		SMTPUsername:    gosettings.CopyPointer(n.SMTPUsername),
+		SMTPPassword:    gosettings.CopyPointer(n.SMTPPassword),
+		SMTPFrom:        gosettings.CopyPointer(n.SMTPFrom),
+		SMTPTo:          gosettings.CopySlice(n.SMTPTo),
+		Retries:         gosettings.CopyPointer(n.Retries),
+		RetryBackoff:    gosettings.CopyPointer(n.RetryBackoff),
+	}
+}
+
+func (n *Notifications) overrideWith(other Notifications) {
+	n.Events = gosettings.OverrideWithSlice(n.Events, other.Events)
+	n.Webhooks = gosettings.OverrideWithSlice(n.Webhooks, other.Webhooks)
+	n.WebhookTemplate = gosettings.OverrideWithPointer(n.WebhookTemplate, other.WebhookTemplate)
+	n.NtfyURLs = gosettings.OverrideWithSlice(n.NtfyURLs, other.NtfyURLs)
+	n.NtfyToken = gosettings.OverrideWithPointer(n.NtfyToken, other.NtfyToken)
+	n.GotifyURL = gosettings.OverrideWithPointer(n.GotifyURL, other.GotifyURL)
+	n.GotifyToken = gosettings.OverrideWithPointer(n.GotifyToken, other.GotifyToken)
+	n.SMTPAddress = gosettings.OverrideWithPointer(n.SMTPAddress, other.SMTPAddress)
+	n.SMTPUsername = gosettings.OverrideWithPointer(n.SMTPUsername, other.SMTPUsername)
+	n.SMTPPassword = gosettings.OverrideWithPointer(n.SMTPPassword, other.SMTPPassword)
+	n.SMTPFrom = gosettings.OverrideWithPointer(n.SMTPFrom, other.SMTPFrom)
+	n.SMTPTo = gosettings.OverrideWithSlice(n.SMTPTo, other.SMTPTo)
+	n.Retries = gosettings.OverrideWithPointer(n.Retries, other.Retries)
+	n.RetryBackoff = gosettings.OverrideWithPointer(n.RetryBackoff, other.RetryBackoff)
+}
+
+func (n *Notifications) setDefaults() {
+	allEvents := models.NotificationEvents()
+	defaultEvents := make([]string, len(allEvents))
+	for i, event := range allEvents {
+		defaultEvents[i] = string(event)
+	}
+	n.Events = gosettings.DefaultSlice(n.Events, defaultEvents)
+	n.Webhooks = gosettings.DefaultSlice(n.Webhooks, []string{})
+	n.WebhookTemplate = gosettings.DefaultPointer(n.WebhookTemplate, "")
+	n.NtfyURLs = gosettings.DefaultSlice(n.NtfyURLs, []string{})
+	n.NtfyToken = gosettings.DefaultPointer(n.NtfyToken, "")
+	n.GotifyURL = gosettings.DefaultPointer(n.GotifyURL, "")
+	n.GotifyToken = gosettings.DefaultPointer(n.GotifyToken, "")
+	n.SMTPAddress = gosettings.DefaultPointer(n.SMTPAddress, "")
+	n.SMTPUsername = gosettings.DefaultPointer(n.SMTPUsername, "")
+	n.SMTPPassword = gosettings.DefaultPointer(n.SMTPPassword, "")
+	n.SMTPFrom = gosettings.DefaultPointer(n.SMTPFrom, "")
+	n.SMTPTo = gosettings.DefaultSlice(n.SMTPTo, []string{})
+	const defaultRetries = 3
+	n.Retries = gosettings.DefaultPointer(n.Retries, defaultRetries)
+	n.RetryBackoff = gosettings.DefaultPointer(n.RetryBackoff, time.Second)
+}
+
+// Enabled returns true if at least one notification sink is set.
+func (n Notifications) Enabled() bool {
+	return len(n.Webhooks) > 0 || len(n.NtfyURLs) > 0 ||
+		*n.GotifyURL != "" || *n.SMTPAddress != ""
+}
+
+func (n Notifications) String() string {
+	return n.toLinesNode().String()
+}
+
+func (n Notifications) toLinesNode() (node *gotree.Node) {
+	if !n.Enabled() {
+		return nil
+	}
+
+	node = gotree.New("Notifications settings:")
+	eventsNode := node.Append("Events:")
+	for _, event := range n.Events {
+		eventsNode.Append(event)
+	}
+
+	if len(n.Webhooks) > 0 {
+		webhooksNode := node.Append("Webhooks:")
+		for _, webhook := range n.Webhooks {
+			webhooksNode.Append(webhook)
+		}
+		if *n.WebhookTemplate != "" {
+			webhooksNode.Append("Body template: custom")
+		}
+	}
+
+	if len(n.NtfyURLs) > 0 {
+		ntfyNode := node.Append("ntfy topics:")
+		for _, ntfyURL := range n.NtfyURLs {
+			ntfyNode.Append(ntfyURL)
+		}
+		if *n.NtfyToken != "" {
+			ntfyNode.Appendf("Token: %s", gosettings.ObfuscateKey(*n.NtfyToken))
+		}
+	}
+
+	if *n.GotifyURL != "" {
+		gotifyNode := node.Append("Gotify:")
+		gotifyNode.Appendf("URL: %s", *n.GotifyURL)
+		gotifyNode.Appendf("Token: %s", gosettings.ObfuscateKey(*n.GotifyToken))
+	}
+
+	if *n.SMTPAddress != "" {
+		smtpNode := node.Append("Email:")
+		smtpNode.Appendf("SMTP server address: %s", *n.SMTPAddress)
+		if *n.SMTPUsername != "" {
+			smtpNode.Appendf("SMTP username: %s", *n.SMTPUsername)
+			smtpNode.Appendf("SMTP password: %s", gosettings.ObfuscateKey(*n.SMTPPassword))
+		}
+		smtpNode.Appendf("From: %s", *n.SMTPFrom)
+		toNode := smtpNode.Append("To:")
+		for _, address := range n.SMTPTo {
+			toNode.Append(address)
+		}
+	}
+
+	node.Appendf("Retries: %d", *n.Retries)
+	node.Appendf("Retry initial backoff: %s", *n.RetryBackoff)
+
+	return node
+}
+
+func (n *Notifications) read(r *reader.Reader) (err error) {
+	n.Events = r.CSV("NOTIFY_EVENTS")
+	n.Webhooks = r.CSV("NOTIFY_WEBHOOK_URLS", reader.ForceLowercase(false))
+	n.WebhookTemplate = r.Get("NOTIFY_WEBHOOK_TEMPLATE", reader.ForceLowercase(false))
+	n.NtfyURLs = r.CSV("NOTIFY_NTFY_URLS", reader.ForceLowercase(false))
+	n.NtfyToken = r.Get("NOTIFY_NTFY_TOKEN", reader.ForceLowercase(false))
+	n.GotifyURL = r.Get("NOTIFY_GOTIFY_URL", reader.ForceLowercase(false))
+	n.GotifyToken = r.Get("NOTIFY_GOTIFY_TOKEN", reader.ForceLowercase(false))
+	n.SMTPAddress = r.Get("NOTIFY_SMTP_ADDRESS")
+	n.SMTPUsername = r.Get("NOTIFY_SMTP_USERNAME", reader.ForceLowercase(false))
+	n.SMTPPassword = r.Get("NOTIFY_SMTP_PASSWORD", reader.ForceLowercase(false))
+	n.SMTPFrom = r.Get("NOTIFY_SMTP_FROM", reader.ForceLowercase(false))
+	n.SMTPTo = r.CSV("NOTIFY_SMTP_TO", reader.ForceLowercase(false))
+
+	n.Retries, err = r.UintPtr("NOTIFY_RETRIES")
+	if err != nil {
+		return err
+	}
+
+	n.RetryBackoff, err = r.DurationPtr("NOTIFY_RETRY_BACKOFF")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -70,6 +70,8 @@ type OpenVPN struct {
 	PIAEncPreset *string `json:"pia_encryption_preset"`
 	// MSSFix is the value (1 to 10000) to set for the
 	// mssfix option for OpenVPN. It is ignored if set to 0.
+	// If set, it is also used to clamp the TCP MSS of
+	// connections going out through the VPN interface.
 	// It cannot be nil in the internal state.
 	MSSFix *uint16 `json:"mssfix"`
 	// Interface is the OpenVPN device interface name.
@@ -3,6 +3,7 @@ package settings
 import (
 	"fmt"
 	"net/netip"
+	"time"
 
 	"github.com/qdm12/gosettings"
 	"github.com/qdm12/gosettings/reader"
@@ -21,6 +22,12 @@ type PMTUD struct {
 	// TCP server on the port specified.
 	// It cannot be nil in the internal state.
 	TCPAddresses []netip.AddrPort `json:"tcp_addresses"`
+	// Period is the period to re-run the path MTU discovery
+	// while the VPN tunnel is up, to adjust the VPN interface MTU
+	// to path changes. Each discovery may briefly interrupt traffic.
+	// It defaults to 0 which disables periodic re-discovery,
+	// and cannot be nil in the internal state.
+	Period *time.Duration `json:"period"`
 }
 
 // Validate validates PMTUD settings.
@@ -35,6 +42,12 @@ func (p PMTUD) validate() (err error) {
 			return fmt.Errorf("PMTUD TCP address is not valid: at index %d", i)
 		}
 	}
+
+	const minPeriod = time.Minute
+	if *p.Period != 0 && *p.Period < minPeriod {
+		return fmt.Errorf("PMTUD period is too short: %s must be at least %s",
+			*p.Period, minPeriod)
+	}
 	return nil
 }
 
@@ -42,12 +55,14 @@ func (p *PMTUD) copy() (copied PMTUD) {
 	return PMTUD{
 		ICMPAddresses: gosettings.CopySlice(p.ICMPAddresses),
 		TCPAddresses:  gosettings.CopySlice(p.TCPAddresses),
+		Period:        gosettings.CopyPointer(p.Period),
 	}
 }
 
 func (p *PMTUD) overrideWith(other PMTUD) {
 	p.ICMPAddresses = gosettings.OverrideWithSlice(p.ICMPAddresses, other.ICMPAddresses)
 	p.TCPAddresses = gosettings.OverrideWithSlice(p.TCPAddresses, other.TCPAddresses)
+	p.Period = gosettings.OverrideWithPointer(p.Period, other.Period)
 }
 
 func (p *PMTUD) setDefaults() {
@@ -69,6 +84,7 @@ func (p *PMTUD) setDefaults() {
 		netip.AddrPortFrom(netip.MustParseAddr("2001:4860:4860::8888"), tlsPort),
 	}
 	p.TCPAddresses = gosettings.DefaultSlice(p.TCPAddresses, defaultTCPAddresses)
+	p.Period = gosettings.DefaultPointer(p.Period, 0)
 }
 
 func (p PMTUD) String() string {
@@ -87,6 +103,12 @@ func (p PMTUD) toLinesNode() (node *gotree.Node) {
 	for _, addr := range p.TCPAddresses {
 		tcpAddrNode.Append(addr.String())
 	}
+
+	if *p.Period == 0 {
+		node.Appendf("Periodic re-discovery: disabled")
+	} else {
+		node.Appendf("Periodic re-discovery: every %s", *p.Period)
+	}
 	return node
 }
 
@@ -101,5 +123,10 @@ func (p *PMTUD) read(r *reader.Reader) (err error) {
 		return err
 	}
 
+	p.Period, err = r.DurationPtr("PMTUD_PERIOD")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -31,6 +31,11 @@ type PortForwarding struct {
 	// to write to a file. It cannot be nil for the
 	// internal state
 	Filepath *string `json:"status_file_path"`
+	// BindingFilepath is the file path to persist the last port
+	// forwarding binding to, to try reclaiming the same ports on
+	// restart. It can be the empty string to indicate not to persist
+	// the binding. It cannot be nil for the internal state.
+	BindingFilepath *string `json:"binding_file_path"`
 	// UpCommand is the command to use when the port forwarding is up.
 	// It can be the empty string to indicate not to run a command.
 	// It cannot be nil in the internal state.
@@ -53,6 +58,9 @@ type PortForwarding struct {
 	Username string `json:"username"`
 	// Password is only used for Private Internet Access port forwarding.
 	Password string `json:"password"`
+	// TorrentClient contains settings to set the forwarded
+	// port as the listening port of a torrent client.
+	TorrentClient TorrentClient `json:"torrent_client"`
 }
 
 func (p PortForwarding) Validate(vpnProvider string) (err error) {
@@ -83,6 +91,13 @@ func (p PortForwarding) Validate(vpnProvider string) (err error) {
 		}
 	}
 
+	if *p.BindingFilepath != "" { // optional
+		_, err := filepath.Abs(*p.BindingFilepath)
+		if err != nil {
+			return fmt.Errorf("binding filepath is not valid: %w", err)
+		}
+	}
+
 	switch providerSelected {
 	case providers.PrivateInternetAccess:
 		const maxPortsCount = 1
@@ -116,19 +131,26 @@ func (p PortForwarding) Validate(vpnProvider string) (err error) {
 		}
 	}
 
+	err = p.TorrentClient.validate()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (p *PortForwarding) Copy() (copied PortForwarding) {
 	return PortForwarding{
-		Enabled:        gosettings.CopyPointer(p.Enabled),
-		Provider:       gosettings.CopyPointer(p.Provider),
-		Filepath:       gosettings.CopyPointer(p.Filepath),
-		UpCommand:      gosettings.CopyPointer(p.UpCommand),
-		DownCommand:    gosettings.CopyPointer(p.DownCommand),
-		ListeningPorts: gosettings.CopySlice(p.ListeningPorts),
-		Username:       p.Username,
-		Password:       p.Password,
+		Enabled:         gosettings.CopyPointer(p.Enabled),
+		Provider:        gosettings.CopyPointer(p.Provider),
+		Filepath:        gosettings.CopyPointer(p.Filepath),
+		BindingFilepath: gosettings.CopyPointer(p.BindingFilepath),
+		UpCommand:       gosettings.CopyPointer(p.UpCommand),
+		DownCommand:     gosettings.CopyPointer(p.DownCommand),
+		ListeningPorts:  gosettings.CopySlice(p.ListeningPorts),
+		Username:        p.Username,
+		Password:        p.Password,
+		TorrentClient:   p.TorrentClient.copy(),
 	}
 }
 
@@ -136,21 +158,25 @@ func (p *PortForwarding) OverrideWith(other PortForwarding) {
 	p.Enabled = gosettings.OverrideWithPointer(p.Enabled, other.Enabled)
 	p.Provider = gosettings.OverrideWithPointer(p.Provider, other.Provider)
 	p.Filepath = gosettings.OverrideWithPointer(p.Filepath, other.Filepath)
+	p.BindingFilepath = gosettings.OverrideWithPointer(p.BindingFilepath, other.BindingFilepath)
 	p.UpCommand = gosettings.OverrideWithPointer(p.UpCommand, other.UpCommand)
 	p.DownCommand = gosettings.OverrideWithPointer(p.DownCommand, other.DownCommand)
 	p.ListeningPorts = gosettings.OverrideWithSlice(p.ListeningPorts, other.ListeningPorts)
 	p.Username = gosettings.OverrideWithComparable(p.Username, other.Username)
 	p.Password = gosettings.OverrideWithComparable(p.Password, other.Password)
+	p.TorrentClient.overrideWith(other.TorrentClient)
 }
 
 func (p *PortForwarding) setDefaults() {
 	p.Enabled = gosettings.DefaultPointer(p.Enabled, false)
 	p.Provider = gosettings.DefaultPointer(p.Provider, "")
 	p.Filepath = gosettings.DefaultPointer(p.Filepath, "/tmp/gluetun/forwarded_port")
+	p.BindingFilepath = gosettings.DefaultPointer(p.BindingFilepath, "/gluetun/portforward_binding.json")
 	p.UpCommand = gosettings.DefaultPointer(p.UpCommand, "")
 	p.DownCommand = gosettings.DefaultPointer(p.DownCommand, "")
 	p.ListeningPorts = gosettings.DefaultSlice(p.ListeningPorts, []uint16{0}) // disabled
 	p.PortsCount = gosettings.DefaultComparable(p.PortsCount, 1)
+	p.TorrentClient.setDefaults()
 }
 
 func (p PortForwarding) String() string {
@@ -185,6 +211,12 @@ func (p PortForwarding) toLinesNode() (node *gotree.Node) {
 	}
 	node.Appendf("Forwarded port file path: %s", filepath)
 
+	bindingFilepath := *p.BindingFilepath
+	if bindingFilepath == "" {
+		bindingFilepath = "[not set]"
+	}
+	node.Appendf("Forwarded port binding file path: %s", bindingFilepath)
+
 	if *p.UpCommand != "" {
 		node.Appendf("Forwarded port up command: %s", *p.UpCommand)
 	}
@@ -198,6 +230,8 @@ func (p PortForwarding) toLinesNode() (node *gotree.Node) {
 		credentialsNode.Appendf("Password: %s", gosettings.ObfuscateKey(p.Password))
 	}
 
+	node.AppendNode(p.TorrentClient.toLinesNode())
+
 	return node
 }
 
@@ -220,6 +254,9 @@ func (p *PortForwarding) read(r *reader.Reader) (err error) {
 			"PRIVATE_INTERNET_ACCESS_VPN_PORT_FORWARDING_STATUS_FILE",
 		))
 
+	p.BindingFilepath = r.Get("VPN_PORT_FORWARDING_BINDING_FILE",
+		reader.ForceLowercase(false))
+
 	p.UpCommand = r.Get("VPN_PORT_FORWARDING_UP_COMMAND",
 		reader.ForceLowercase(false))
 
@@ -253,5 +290,10 @@ func (p *PortForwarding) read(r *reader.Reader) (err error) {
 		}
 	}
 
+	err = p.TorrentClient.read(r)
+	if err != nil {
+		return fmt.Errorf("torrent client: %w", err)
+	}
+
 	return nil
 }
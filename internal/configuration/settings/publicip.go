@@ -1,8 +1,11 @@
 package settings
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"path/filepath"
+	"strings"
 
 	"github.com/qdm12/gluetun/internal/publicip/api"
 	"github.com/qdm12/gosettings"
@@ -26,11 +29,30 @@ type PublicIP struct {
 	// the service rate limiting us. It defaults to use all services,
 	// with the first one being ipinfo.io for historical reasons.
 	APIs []PublicIPAPI
+	// HistoryFilepath is the file path to persist the history
+	// of public IP addresses to. It can be the empty string to
+	// indicate not to persist the history. It cannot be nil for
+	// the internal state.
+	HistoryFilepath *string
+	// HistoryMaxEntries is the maximum number of entries to keep
+	// in the public IP history, the oldest entries being removed
+	// first. It cannot be nil for the internal state.
+	HistoryMaxEntries *uint
+	// Webhooks is the list of URLs to send a JSON POST request to
+	// when the public IP address changes. It defaults to an empty list.
+	Webhooks []string
+	// WebhookSecret is the secret used to sign the webhooks request
+	// bodies with HMAC-SHA256. It can be the empty string to indicate
+	// not to sign requests. It cannot be nil for the internal state.
+	WebhookSecret *string
 }
 
 type PublicIPAPI struct {
 	// Name is the name of the public ip API service.
-	// It can be "cloudflare", "ifconfigco", "ip2location" or "ipinfo".
+	// It can be "cloudflare", "ifconfigco", "ip2location", "ipinfo",
+	// "echoip#" followed by an echoip URL, or "mmdb#" followed by
+	// colon separated absolute paths to local MaxMind DB files, for
+	// example "mmdb#/gluetun/GeoLite2-City.mmdb:/gluetun/GeoLite2-ASN.mmdb".
 	Name string
 	// Token is the token to use for the public ip API service.
 	Token string
@@ -50,6 +72,12 @@ func (p PublicIP) UpdateWith(partialUpdate PublicIP) (updatedSettings PublicIP,
 	return updatedSettings, nil
 }
 
+var (
+	ErrPublicIPHistoryMaxEntriesZero = errors.New("public IP history maximum entries cannot be zero")
+	ErrWebhookURLSchemeNotValid      = errors.New("webhook URL scheme is not http or https")
+	ErrWebhookURLHostMissing         = errors.New("webhook URL host is missing")
+)
+
 func (p PublicIP) validate() (err error) {
 	if *p.IPFilepath != "" { // optional
 		_, err := filepath.Abs(*p.IPFilepath)
@@ -65,14 +93,55 @@ func (p PublicIP) validate() (err error) {
 		}
 	}
 
+	if *p.HistoryFilepath != "" { // optional
+		_, err := filepath.Abs(*p.HistoryFilepath)
+		if err != nil {
+			return fmt.Errorf("history filepath is not valid: %w", err)
+		}
+	}
+
+	if *p.HistoryMaxEntries == 0 {
+		return ErrPublicIPHistoryMaxEntriesZero
+	}
+
+	for _, webhook := range p.Webhooks {
+		err = validateWebhookURL(webhook)
+		if err != nil {
+			return fmt.Errorf("webhook: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateWebhookURL(rawURL string) (err error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parsing URL: %w", err)
+	}
+
+	switch parsedURL.Scheme {
+	case "http", "https":
+	default:
+		return fmt.Errorf("%w: %s", ErrWebhookURLSchemeNotValid, rawURL)
+	}
+
+	if parsedURL.Host == "" {
+		return fmt.Errorf("%w: %s", ErrWebhookURLHostMissing, rawURL)
+	}
+
 	return nil
 }
 
 func (p *PublicIP) copy() (copied PublicIP) {
 	return PublicIP{
-		Enabled:    gosettings.CopyPointer(p.Enabled),
-		IPFilepath: gosettings.CopyPointer(p.IPFilepath),
-		APIs:       gosettings.CopySlice(p.APIs),
+		Enabled:           gosettings.CopyPointer(p.Enabled),
+		IPFilepath:        gosettings.CopyPointer(p.IPFilepath),
+		APIs:              gosettings.CopySlice(p.APIs),
+		HistoryFilepath:   gosettings.CopyPointer(p.HistoryFilepath),
+		HistoryMaxEntries: gosettings.CopyPointer(p.HistoryMaxEntries),
+		Webhooks:          gosettings.CopySlice(p.Webhooks),
+		WebhookSecret:     gosettings.CopyPointer(p.WebhookSecret),
 	}
 }
 
@@ -80,6 +149,10 @@ func (p *PublicIP) overrideWith(other PublicIP) {
 	p.Enabled = gosettings.OverrideWithPointer(p.Enabled, other.Enabled)
 	p.IPFilepath = gosettings.OverrideWithPointer(p.IPFilepath, other.IPFilepath)
 	p.APIs = gosettings.OverrideWithSlice(p.APIs, other.APIs)
+	p.HistoryFilepath = gosettings.OverrideWithPointer(p.HistoryFilepath, other.HistoryFilepath)
+	p.HistoryMaxEntries = gosettings.OverrideWithPointer(p.HistoryMaxEntries, other.HistoryMaxEntries)
+	p.Webhooks = gosettings.OverrideWithSlice(p.Webhooks, other.Webhooks)
+	p.WebhookSecret = gosettings.OverrideWithPointer(p.WebhookSecret, other.WebhookSecret)
 }
 
 func (p *PublicIP) setDefaults() {
@@ -91,6 +164,11 @@ func (p *PublicIP) setDefaults() {
 		{Name: string(api.IfConfigCo)},
 		{Name: string(api.IP2Location)},
 	})
+	p.HistoryFilepath = gosettings.DefaultPointer(p.HistoryFilepath, "/gluetun/publicip_history.json")
+	const defaultHistoryMaxEntries = 100
+	p.HistoryMaxEntries = gosettings.DefaultPointer(p.HistoryMaxEntries, defaultHistoryMaxEntries)
+	p.Webhooks = gosettings.DefaultSlice(p.Webhooks, []string{})
+	p.WebhookSecret = gosettings.DefaultPointer(p.WebhookSecret, "")
 }
 
 func (p PublicIP) String() string {
@@ -124,6 +202,22 @@ func (p PublicIP) toLinesNode() (node *gotree.Node) {
 		}
 	}
 
+	historyNode := node.Append("History:")
+	if *p.HistoryFilepath != "" {
+		historyNode.Appendf("File path: %s", *p.HistoryFilepath)
+	}
+	historyNode.Appendf("Maximum entries: %d", *p.HistoryMaxEntries)
+
+	if len(p.Webhooks) > 0 {
+		webhooksNode := node.Append("IP change webhooks:")
+		for _, webhook := range p.Webhooks {
+			webhooksNode.Append(webhook)
+		}
+		if *p.WebhookSecret != "" {
+			webhooksNode.Appendf("Signature secret: %s", gosettings.ObfuscateKey(*p.WebhookSecret))
+		}
+	}
+
 	return node
 }
 
@@ -136,12 +230,12 @@ func (p *PublicIP) read(r *reader.Reader, warner Warner) (err error) {
 	p.IPFilepath = r.Get("PUBLICIP_FILE",
 		reader.ForceLowercase(false), reader.RetroKeys("IP_STATUS_FILE"))
 
-	apiNames := r.CSV("PUBLICIP_API")
+	apiNames := r.CSV("PUBLICIP_API", reader.ForceLowercase(false))
 	if len(apiNames) > 0 {
 		apiTokens := r.CSV("PUBLICIP_API_TOKEN")
 		p.APIs = make([]PublicIPAPI, len(apiNames))
 		for i := range apiNames {
-			p.APIs[i].Name = apiNames[i]
+			p.APIs[i].Name = lowercaseAPIName(apiNames[i])
 			var token string
 			if i < len(apiTokens) { // only set token if it exists
 				token = apiTokens[i]
@@ -150,9 +244,30 @@ func (p *PublicIP) read(r *reader.Reader, warner Warner) (err error) {
 		}
 	}
 
+	p.HistoryFilepath = r.Get("PUBLICIP_HISTORY_FILE", reader.ForceLowercase(false))
+
+	p.HistoryMaxEntries, err = r.UintPtr("PUBLICIP_HISTORY_MAX_ENTRIES")
+	if err != nil {
+		return err
+	}
+
+	p.Webhooks = r.CSV("PUBLICIP_WEBHOOK_URLS", reader.ForceLowercase(false))
+	p.WebhookSecret = r.Get("PUBLICIP_WEBHOOK_SECRET", reader.ForceLowercase(false))
+
 	return nil
 }
 
+// lowercaseAPIName lowercases the API name, keeping the case of the
+// URL or file paths following the # of custom APIs such as
+// echoip#https://example.com or mmdb#/path/GeoLite2-City.mmdb.
+func lowercaseAPIName(apiName string) string {
+	name, custom, found := strings.Cut(apiName, "#")
+	if !found {
+		return strings.ToLower(apiName)
+	}
+	return strings.ToLower(name) + "#" + custom
+}
+
 func readPublicIPEnabled(r *reader.Reader, warner Warner) (
 	enabled *bool, err error,
 ) {
@@ -25,6 +25,10 @@ func Test_PublicIP_read(t *testing.T) {
 					{key: "IP_STATUS_FILE"},
 					{key: "PUBLICIP_FILE"},
 					{key: "PUBLICIP_API"},
+					{key: "PUBLICIP_HISTORY_FILE"},
+					{key: "PUBLICIP_HISTORY_MAX_ENTRIES"},
+					{key: "PUBLICIP_WEBHOOK_URLS"},
+					{key: "PUBLICIP_WEBHOOK_SECRET"},
 				})
 				return reader.New(reader.Settings{
 					Sources: []reader.Source{source},
@@ -40,6 +44,10 @@ func Test_PublicIP_read(t *testing.T) {
 					{key: "PUBLICIP_FILE"},
 					{key: "PUBLICIP_API", value: "ipinfo"},
 					{key: "PUBLICIP_API_TOKEN"},
+					{key: "PUBLICIP_HISTORY_FILE"},
+					{key: "PUBLICIP_HISTORY_MAX_ENTRIES"},
+					{key: "PUBLICIP_WEBHOOK_URLS"},
+					{key: "PUBLICIP_WEBHOOK_SECRET"},
 				})
 				return reader.New(reader.Settings{
 					Sources: []reader.Source{source},
@@ -60,6 +68,10 @@ func Test_PublicIP_read(t *testing.T) {
 					{key: "PUBLICIP_FILE"},
 					{key: "PUBLICIP_API", value: "ipinfo"},
 					{key: "PUBLICIP_API_TOKEN", value: "xyz"},
+					{key: "PUBLICIP_HISTORY_FILE"},
+					{key: "PUBLICIP_HISTORY_MAX_ENTRIES"},
+					{key: "PUBLICIP_WEBHOOK_URLS"},
+					{key: "PUBLICIP_WEBHOOK_SECRET"},
 				})
 				return reader.New(reader.Settings{
 					Sources: []reader.Source{source},
@@ -80,6 +92,10 @@ func Test_PublicIP_read(t *testing.T) {
 					{key: "PUBLICIP_FILE"},
 					{key: "PUBLICIP_API", value: "ipinfo,ip2location"},
 					{key: "PUBLICIP_API_TOKEN"},
+					{key: "PUBLICIP_HISTORY_FILE"},
+					{key: "PUBLICIP_HISTORY_MAX_ENTRIES"},
+					{key: "PUBLICIP_WEBHOOK_URLS"},
+					{key: "PUBLICIP_WEBHOOK_SECRET"},
 				})
 				return reader.New(reader.Settings{
 					Sources: []reader.Source{source},
@@ -101,6 +117,10 @@ func Test_PublicIP_read(t *testing.T) {
 					{key: "PUBLICIP_FILE"},
 					{key: "PUBLICIP_API", value: "ipinfo,ip2location"},
 					{key: "PUBLICIP_API_TOKEN", value: "xyz,abc"},
+					{key: "PUBLICIP_HISTORY_FILE"},
+					{key: "PUBLICIP_HISTORY_MAX_ENTRIES"},
+					{key: "PUBLICIP_WEBHOOK_URLS"},
+					{key: "PUBLICIP_WEBHOOK_SECRET"},
 				})
 				return reader.New(reader.Settings{
 					Sources: []reader.Source{source},
@@ -122,6 +142,10 @@ func Test_PublicIP_read(t *testing.T) {
 					{key: "PUBLICIP_FILE"},
 					{key: "PUBLICIP_API", value: "ipinfo,ip2location"},
 					{key: "PUBLICIP_API_TOKEN", value: "xyz"},
+					{key: "PUBLICIP_HISTORY_FILE"},
+					{key: "PUBLICIP_HISTORY_MAX_ENTRIES"},
+					{key: "PUBLICIP_WEBHOOK_URLS"},
+					{key: "PUBLICIP_WEBHOOK_SECRET"},
 				})
 				return reader.New(reader.Settings{
 					Sources: []reader.Source{source},
@@ -134,6 +158,31 @@ func Test_PublicIP_read(t *testing.T) {
 				},
 			},
 		},
+		"mmdb_path_case_kept": {
+			makeReader: func(ctrl *gomock.Controller) *reader.Reader {
+				source := newMockSource(ctrl, []sourceKeyValue{
+					{key: "PUBLICIP_PERIOD"},
+					{key: "PUBLICIP_ENABLED"},
+					{key: "IP_STATUS_FILE"},
+					{key: "PUBLICIP_FILE"},
+					{key: "PUBLICIP_API", value: "IPInfo,MMDB#/data/GeoLite2-City.mmdb"},
+					{key: "PUBLICIP_API_TOKEN"},
+					{key: "PUBLICIP_HISTORY_FILE"},
+					{key: "PUBLICIP_HISTORY_MAX_ENTRIES"},
+					{key: "PUBLICIP_WEBHOOK_URLS"},
+					{key: "PUBLICIP_WEBHOOK_SECRET"},
+				})
+				return reader.New(reader.Settings{
+					Sources: []reader.Source{source},
+				})
+			},
+			settings: PublicIP{
+				APIs: []PublicIPAPI{
+					{Name: "ipinfo"},
+					{Name: "mmdb#/data/GeoLite2-City.mmdb"},
+				},
+			},
+		},
 	}
 
 	for name, testCase := range testCases {
@@ -0,0 +1,141 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/cron"
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// Rotation contains settings to periodically reconnect
+// the VPN to another server of the filtered servers pool.
+type Rotation struct {
+	// Interval is the duration after which the VPN connection
+	// is rotated to another server, counted from the moment
+	// the tunnel is up. It can be set to 0 to disable interval
+	// based rotation. It defaults to 0 and cannot be nil in the
+	// internal state.
+	Interval *time.Duration `json:"interval"`
+	// Schedule is a 5 fields cron expression, for example
+	// "0 */6 * * *", at which the VPN connection is rotated.
+	// It can be the empty string to disable schedule based
+	// rotation. It defaults to the empty string and cannot be
+	// nil in the internal state.
+	Schedule *string `json:"schedule"`
+	// AvoidSameServer is true if the rotation should avoid
+	// picking the same server as the current one. It defaults
+	// to true and cannot be nil in the internal state.
+	AvoidSameServer *bool `json:"avoid_same_server"`
+	// AvoidSameIP is true if the rotation should avoid picking
+	// the same server IP address as the current one. It defaults
+	// to true and cannot be nil in the internal state.
+	AvoidSameIP *bool `json:"avoid_same_ip"`
+	// DrainTimeout is the maximum duration to wait for active
+	// HTTP proxy and Shadowsocks connections to close before
+	// rotating. It can be set to 0 to not wait. It defaults to 0
+	// and cannot be nil in the internal state.
+	DrainTimeout *time.Duration `json:"drain_timeout"`
+}
+
+var ErrRotationIntervalAndSchedule = errors.New("rotation interval and schedule cannot be both set")
+
+func (r Rotation) validate() (err error) {
+	const minInterval = time.Minute
+	if *r.Interval != 0 && *r.Interval < minInterval {
+		return fmt.Errorf("rotation interval is too short: %s must be bigger than %s",
+			*r.Interval, minInterval)
+	}
+
+	if *r.Schedule != "" {
+		if *r.Interval != 0 {
+			return fmt.Errorf("%w: interval %s and schedule %q",
+				ErrRotationIntervalAndSchedule, *r.Interval, *r.Schedule)
+		}
+		_, err = cron.Parse(*r.Schedule)
+		if err != nil {
+			return fmt.Errorf("rotation schedule: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Rotation) copy() (copied Rotation) {
+	return Rotation{
+		Interval:        gosettings.CopyPointer(r.Interval),
+		Schedule:        gosettings.CopyPointer(r.Schedule),
+		AvoidSameServer: gosettings.CopyPointer(r.AvoidSameServer),
+		AvoidSameIP:     gosettings.CopyPointer(r.AvoidSameIP),
+		DrainTimeout:    gosettings.CopyPointer(r.DrainTimeout),
+	}
+}
+
+func (r *Rotation) overrideWith(other Rotation) {
+	r.Interval = gosettings.OverrideWithPointer(r.Interval, other.Interval)
+	r.Schedule = gosettings.OverrideWithPointer(r.Schedule, other.Schedule)
+	r.AvoidSameServer = gosettings.OverrideWithPointer(r.AvoidSameServer, other.AvoidSameServer)
+	r.AvoidSameIP = gosettings.OverrideWithPointer(r.AvoidSameIP, other.AvoidSameIP)
+	r.DrainTimeout = gosettings.OverrideWithPointer(r.DrainTimeout, other.DrainTimeout)
+}
+
+func (r *Rotation) setDefaults() {
+	r.Interval = gosettings.DefaultPointer(r.Interval, 0)
+	r.Schedule = gosettings.DefaultPointer(r.Schedule, "")
+	r.AvoidSameServer = gosettings.DefaultPointer(r.AvoidSameServer, true)
+	r.AvoidSameIP = gosettings.DefaultPointer(r.AvoidSameIP, true)
+	r.DrainTimeout = gosettings.DefaultPointer(r.DrainTimeout, 0)
+}
+
+func (r Rotation) String() string {
+	return r.toLinesNode().String()
+}
+
+func (r Rotation) toLinesNode() (node *gotree.Node) {
+	if *r.Interval == 0 && *r.Schedule == "" {
+		return gotree.New("Server rotation: disabled")
+	}
+
+	node = gotree.New("Server rotation:")
+	if *r.Interval > 0 {
+		node.Appendf("Interval: %s", *r.Interval)
+	} else {
+		node.Appendf("Schedule: %s", *r.Schedule)
+	}
+	node.Appendf("Avoid same server: %s", gosettings.BoolToYesNo(r.AvoidSameServer))
+	node.Appendf("Avoid same IP address: %s", gosettings.BoolToYesNo(r.AvoidSameIP))
+	if *r.DrainTimeout > 0 {
+		node.Appendf("Connections drain timeout: %s", *r.DrainTimeout)
+	}
+
+	return node
+}
+
+func (r *Rotation) read(reader *reader.Reader) (err error) {
+	r.Interval, err = reader.DurationPtr("VPN_ROTATION_INTERVAL")
+	if err != nil {
+		return err
+	}
+
+	r.Schedule = reader.Get("VPN_ROTATION_SCHEDULE")
+
+	r.AvoidSameServer, err = reader.BoolPtr("VPN_ROTATION_AVOID_SAME_SERVER")
+	if err != nil {
+		return err
+	}
+
+	r.AvoidSameIP, err = reader.BoolPtr("VPN_ROTATION_AVOID_SAME_IP")
+	if err != nil {
+		return err
+	}
+
+	r.DrainTimeout, err = reader.DurationPtr("VPN_ROTATION_DRAIN_TIMEOUT")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
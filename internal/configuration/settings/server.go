@@ -3,9 +3,11 @@ package settings
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
@@ -16,9 +18,19 @@ import (
 
 // ControlServer contains settings to customize the control server operation.
 type ControlServer struct {
-	// Address is the listening address to use.
-	// It cannot be nil in the internal state.
+	// Address is the TCP listening address to use.
+	// It can be the empty string to only listen on the Unix
+	// socket, and cannot be nil in the internal state.
 	Address *string
+	// SocketPath is the path of a Unix socket to listen on, in
+	// addition to the TCP address. The socket is owned by the
+	// process user and group IDs from the system settings.
+	// It defaults to the empty string meaning no Unix socket,
+	// and cannot be nil in the internal state.
+	SocketPath *string
+	// SocketMode is the file mode of the Unix socket.
+	// It defaults to 0660 and cannot be nil in the internal state.
+	SocketMode *os.FileMode
 	// Log can be true or false to enable logging on requests.
 	// It cannot be nil in the internal state.
 	Log *bool
@@ -30,23 +42,42 @@ type ControlServer struct {
 	// AuthDefaultRole is a JSON encoded object defining the default role
 	// that applies to all routes without a previously user-defined role assigned to.
 	AuthDefaultRole string
+	// Dashboard is whether to serve the web dashboard at the route /.
+	// The dashboard page contains no data and uses the /v1 routes, so
+	// its route can be given to a role without authentication.
+	// It defaults to false and cannot be nil in the internal state.
+	Dashboard *bool
+	// AuditFilepath is the path of the audit log file recording
+	// state changing requests. It can be the empty string to
+	// disable the audit log. It defaults to /gluetun/audit.log
+	// and cannot be nil in the internal state.
+	AuditFilepath *string
 }
 
 func (c ControlServer) validate() (err error) {
-	_, portStr, err := net.SplitHostPort(*c.Address)
-	if err != nil {
-		return fmt.Errorf("listening address is not valid: %w", err)
+	switch {
+	case *c.Address == "" && *c.SocketPath == "":
+		return errors.New("listening address and unix socket path cannot be both empty")
+	case *c.Address != "":
+		err = validateListeningAddress(*c.Address)
+		if err != nil {
+			return err
+		}
 	}
 
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		return fmt.Errorf("listening port it not valid: %w", err)
+	if *c.SocketPath != "" && !filepath.IsAbs(*c.SocketPath) {
+		return fmt.Errorf("unix socket path is not absolute: %s", *c.SocketPath)
 	}
 
-	uid := os.Getuid()
-	const maxPrivilegedPort = 1023
-	if uid != 0 && port != 0 && port <= maxPrivilegedPort {
-		return fmt.Errorf("cannot use privileged port without running as root: %d when running with user ID %d", port, uid)
+	if *c.SocketMode&^os.ModePerm != 0 {
+		return fmt.Errorf("unix socket mode is not valid: %o", *c.SocketMode)
+	}
+
+	if *c.AuditFilepath != "" { // optional
+		_, err := filepath.Abs(*c.AuditFilepath)
+		if err != nil {
+			return fmt.Errorf("audit filepath is not valid: %w", err)
+		}
 	}
 
 	jsonDecoder := json.NewDecoder(bytes.NewBufferString(c.AuthDefaultRole))
@@ -67,12 +98,35 @@ func (c ControlServer) validate() (err error) {
 	return nil
 }
 
+func validateListeningAddress(address string) (err error) {
+	_, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("listening address is not valid: %w", err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("listening port it not valid: %w", err)
+	}
+
+	uid := os.Getuid()
+	const maxPrivilegedPort = 1023
+	if uid != 0 && port != 0 && port <= maxPrivilegedPort {
+		return fmt.Errorf("cannot use privileged port without running as root: %d when running with user ID %d", port, uid)
+	}
+	return nil
+}
+
 func (c *ControlServer) copy() (copied ControlServer) {
 	return ControlServer{
 		Address:         gosettings.CopyPointer(c.Address),
+		SocketPath:      gosettings.CopyPointer(c.SocketPath),
+		SocketMode:      gosettings.CopyPointer(c.SocketMode),
 		Log:             gosettings.CopyPointer(c.Log),
 		AuthFilePath:    c.AuthFilePath,
 		AuthDefaultRole: c.AuthDefaultRole,
+		Dashboard:       gosettings.CopyPointer(c.Dashboard),
+		AuditFilepath:   gosettings.CopyPointer(c.AuditFilepath),
 	}
 }
 
@@ -81,16 +135,25 @@ func (c *ControlServer) copy() (copied ControlServer) {
 // settings.
 func (c *ControlServer) overrideWith(other ControlServer) {
 	c.Address = gosettings.OverrideWithPointer(c.Address, other.Address)
+	c.SocketPath = gosettings.OverrideWithPointer(c.SocketPath, other.SocketPath)
+	c.SocketMode = gosettings.OverrideWithPointer(c.SocketMode, other.SocketMode)
 	c.Log = gosettings.OverrideWithPointer(c.Log, other.Log)
 	c.AuthFilePath = gosettings.OverrideWithComparable(c.AuthFilePath, other.AuthFilePath)
 	c.AuthDefaultRole = gosettings.OverrideWithComparable(c.AuthDefaultRole, other.AuthDefaultRole)
+	c.Dashboard = gosettings.OverrideWithPointer(c.Dashboard, other.Dashboard)
+	c.AuditFilepath = gosettings.OverrideWithPointer(c.AuditFilepath, other.AuditFilepath)
 }
 
-func (c *ControlServer) setDefaults() {
+func (c *ControlServer) SetDefaults() {
 	c.Address = gosettings.DefaultPointer(c.Address, ":8000")
+	c.SocketPath = gosettings.DefaultPointer(c.SocketPath, "")
+	const defaultSocketMode = os.FileMode(0o660)
+	c.SocketMode = gosettings.DefaultPointer(c.SocketMode, defaultSocketMode)
 	c.Log = gosettings.DefaultPointer(c.Log, true)
 	c.AuthFilePath = gosettings.DefaultComparable(c.AuthFilePath, "/gluetun/auth/config.toml")
 	c.AuthDefaultRole = gosettings.DefaultComparable(c.AuthDefaultRole, "{}")
+	c.Dashboard = gosettings.DefaultPointer(c.Dashboard, false)
+	c.AuditFilepath = gosettings.DefaultPointer(c.AuditFilepath, "/gluetun/audit.log")
 	if c.AuthDefaultRole != "{}" {
 		var role auth.Role
 		_ = json.Unmarshal([]byte(c.AuthDefaultRole), &role)
@@ -106,9 +169,22 @@ func (c ControlServer) String() string {
 
 func (c ControlServer) toLinesNode() (node *gotree.Node) {
 	node = gotree.New("Control server settings:")
-	node.Appendf("Listening address: %s", *c.Address)
+	if *c.Address == "" {
+		node.Appendf("Listening address: disabled")
+	} else {
+		node.Appendf("Listening address: %s", *c.Address)
+	}
+	if *c.SocketPath != "" {
+		node.Appendf("Unix socket: %s (mode %04o)", *c.SocketPath, *c.SocketMode)
+	}
 	node.Appendf("Logging: %s", gosettings.BoolToYesNo(c.Log))
 	node.Appendf("Authentication file path: %s", c.AuthFilePath)
+	node.Appendf("Dashboard: %s", gosettings.BoolToYesNo(c.Dashboard))
+	if *c.AuditFilepath == "" {
+		node.Appendf("Audit log: disabled")
+	} else {
+		node.Appendf("Audit log file path: %s", *c.AuditFilepath)
+	}
 	if c.AuthDefaultRole != "{}" {
 		var role auth.Role
 		_ = json.Unmarshal([]byte(c.AuthDefaultRole), &role)
@@ -117,16 +193,36 @@ func (c ControlServer) toLinesNode() (node *gotree.Node) {
 	return node
 }
 
-func (c *ControlServer) read(r *reader.Reader) (err error) {
+func (c *ControlServer) Read(r *reader.Reader) (err error) {
 	c.Log, err = r.BoolPtr("HTTP_CONTROL_SERVER_LOG")
 	if err != nil {
 		return err
 	}
 
-	c.Address = r.Get("HTTP_CONTROL_SERVER_ADDRESS")
+	c.Address = r.Get("HTTP_CONTROL_SERVER_ADDRESS", reader.AcceptEmpty(true))
+
+	c.SocketPath = r.Get("HTTP_CONTROL_SERVER_SOCKET_PATH", reader.ForceLowercase(false))
+
+	socketModeString := r.String("HTTP_CONTROL_SERVER_SOCKET_MODE")
+	if socketModeString != "" {
+		const base, bitSize = 8, 32
+		socketMode, err := strconv.ParseUint(socketModeString, base, bitSize)
+		if err != nil {
+			return fmt.Errorf("environment variable HTTP_CONTROL_SERVER_SOCKET_MODE: %w", err)
+		}
+		c.SocketMode = ptrTo(os.FileMode(socketMode))
+	}
 
 	c.AuthFilePath = r.String("HTTP_CONTROL_SERVER_AUTH_CONFIG_FILEPATH")
 	c.AuthDefaultRole = r.String("HTTP_CONTROL_SERVER_AUTH_DEFAULT_ROLE", reader.ForceLowercase(false))
 
+	c.Dashboard, err = r.BoolPtr("HTTP_CONTROL_SERVER_DASHBOARD")
+	if err != nil {
+		return err
+	}
+
+	c.AuditFilepath = r.Get("HTTP_CONTROL_SERVER_AUDIT_FILEPATH",
+		reader.ForceLowercase(false), reader.AcceptEmpty(true))
+
 	return nil
 }
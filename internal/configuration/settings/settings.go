@@ -1,7 +1,10 @@
 package settings
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings/helpers"
 	"github.com/qdm12/gluetun/internal/constants/providers"
@@ -13,22 +16,24 @@ import (
 )
 
 type Settings struct {
-	ControlServer ControlServer
-	DNS           DNS
-	Firewall      Firewall
-	Health        Health
-	HTTPProxy     HTTPProxy
-	Log           Log
-	PublicIP      PublicIP
-	Shadowsocks   Shadowsocks
-	Storage       Storage
-	System        System
-	Updater       Updater
-	Version       Version
-	VPN           VPN
-	IPv6          IPv6
-	Pprof         pprof.Settings
-	BoringPoll    BoringPoll
+	ControlServer    ControlServer
+	DNS              DNS
+	Firewall         Firewall
+	Health           Health
+	HTTPProxy        HTTPProxy
+	Log              Log
+	Notifications    Notifications
+	PublicIP         PublicIP
+	Shadowsocks      Shadowsocks
+	Storage          Storage
+	System           System
+	TransparentProxy TransparentProxy
+	Updater          Updater
+	Version          Version
+	VPN              VPN
+	IPv6             IPv6
+	Pprof            pprof.Settings
+	BoringPoll       BoringPoll
 }
 
 type FilterChoicesGetter interface {
@@ -42,19 +47,21 @@ func (s *Settings) Validate(filterChoicesGetter FilterChoicesGetter, ipv6Support
 	warner Warner,
 ) (err error) {
 	nameToValidation := map[string]func() error{
-		"control server":  s.ControlServer.validate,
-		"dns":             s.DNS.validate,
-		"firewall":        s.Firewall.validate,
-		"health":          s.Health.Validate,
-		"http proxy":      s.HTTPProxy.validate,
-		"log":             s.Log.validate,
-		"public ip check": s.PublicIP.validate,
-		"shadowsocks":     s.Shadowsocks.validate,
-		"storage":         s.Storage.validate,
-		"system":          s.System.validate,
-		"updater":         s.Updater.Validate,
-		"version":         s.Version.validate,
-		"ipv6":            s.IPv6.validate,
+		"control server":    s.ControlServer.validate,
+		"dns":               s.DNS.validate,
+		"firewall":          s.Firewall.validate,
+		"health":            s.Health.Validate,
+		"http proxy":        s.HTTPProxy.validate,
+		"log":               s.Log.validate,
+		"notifications":     s.Notifications.validate,
+		"public ip check":   s.PublicIP.validate,
+		"shadowsocks":       s.Shadowsocks.validate,
+		"storage":           s.Storage.validate,
+		"system":            s.System.validate,
+		"transparent proxy": s.TransparentProxy.validate,
+		"updater":           s.Updater.Validate,
+		"version":           s.Version.validate,
+		"ipv6":              s.IPv6.validate,
 		// Pprof validation done in pprof constructor
 		"VPN": func() error {
 			return s.VPN.Validate(filterChoicesGetter, ipv6Supported, warner)
@@ -69,27 +76,83 @@ func (s *Settings) Validate(filterChoicesGetter FilterChoicesGetter, ipv6Support
 		}
 	}
 
+	return s.validateDependencies()
+}
+
+var (
+	ErrGatewayDNSHijackWithoutDNSServer = errors.New("gateway DNS hijacking requires the DNS server to be enabled")
+	ErrTransparentProxyWithoutFirewall  = errors.New("transparent proxy cannot be enabled with the firewall disabled")
+	ErrTransparentProxyPortInUse        = errors.New("transparent proxy port is already used")
+)
+
+// validateDependencies validates settings depending on other settings,
+// once each settings group is validated on its own.
+func (s *Settings) validateDependencies() (err error) {
+	if *s.Firewall.Gateway.Enabled && *s.Firewall.Gateway.DNSHijack && !*s.DNS.ServerEnabled {
+		return ErrGatewayDNSHijackWithoutDNSServer
+	}
+
+	if *s.TransparentProxy.Enabled {
+		if !*s.Firewall.Enabled {
+			return ErrTransparentProxyWithoutFirewall
+		}
+
+		err = s.validateTransparentProxyPort()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateTransparentProxyPort returns an error if the transparent proxy
+// port is the listening port of the HTTP proxy, Shadowsocks or control
+// server, if these are enabled.
+func (s *Settings) validateTransparentProxyPort() (err error) {
+	type server struct {
+		name    string
+		enabled bool
+		address string
+	}
+	servers := []server{
+		{name: "HTTP proxy", enabled: *s.HTTPProxy.Enabled, address: s.HTTPProxy.ListeningAddress},
+		{name: "Shadowsocks", enabled: *s.Shadowsocks.Enabled, address: *s.Shadowsocks.Settings.Address},
+		{name: "control server", enabled: *s.ControlServer.Address != "", address: *s.ControlServer.Address},
+	}
+	for _, server := range servers {
+		if !server.enabled {
+			continue
+		}
+		port, ok := listeningPort(server.address)
+		if ok && port == s.TransparentProxy.Port {
+			return fmt.Errorf("%w: port %d is the %s listening port",
+				ErrTransparentProxyPortInUse, port, server.name)
+		}
+	}
 	return nil
 }
 
 func (s *Settings) copy() (copied Settings) {
 	return Settings{
-		ControlServer: s.ControlServer.copy(),
-		DNS:           s.DNS.Copy(),
-		Firewall:      s.Firewall.copy(),
-		Health:        s.Health.copy(),
-		HTTPProxy:     s.HTTPProxy.copy(),
-		Log:           s.Log.copy(),
-		PublicIP:      s.PublicIP.copy(),
-		Shadowsocks:   s.Shadowsocks.copy(),
-		Storage:       s.Storage.copy(),
-		System:        s.System.copy(),
-		Updater:       s.Updater.copy(),
-		Version:       s.Version.copy(),
-		VPN:           s.VPN.Copy(),
-		Pprof:         s.Pprof.Copy(),
-		BoringPoll:    s.BoringPoll.Copy(),
-		IPv6:          s.IPv6.copy(),
+		ControlServer:    s.ControlServer.copy(),
+		DNS:              s.DNS.Copy(),
+		Firewall:         s.Firewall.copy(),
+		Health:           s.Health.copy(),
+		HTTPProxy:        s.HTTPProxy.copy(),
+		Log:              s.Log.copy(),
+		Notifications:    s.Notifications.copy(),
+		PublicIP:         s.PublicIP.copy(),
+		Shadowsocks:      s.Shadowsocks.copy(),
+		Storage:          s.Storage.copy(),
+		System:           s.System.copy(),
+		TransparentProxy: s.TransparentProxy.copy(),
+		Updater:          s.Updater.copy(),
+		Version:          s.Version.copy(),
+		VPN:              s.VPN.Copy(),
+		Pprof:            s.Pprof.Copy(),
+		BoringPoll:       s.BoringPoll.Copy(),
+		IPv6:             s.IPv6.copy(),
 	}
 }
 
@@ -103,10 +166,12 @@ func (s *Settings) OverrideWith(other Settings,
 	patchedSettings.Health.OverrideWith(other.Health)
 	patchedSettings.HTTPProxy.overrideWith(other.HTTPProxy)
 	patchedSettings.Log.overrideWith(other.Log)
+	patchedSettings.Notifications.overrideWith(other.Notifications)
 	patchedSettings.PublicIP.overrideWith(other.PublicIP)
 	patchedSettings.Shadowsocks.overrideWith(other.Shadowsocks)
 	patchedSettings.Storage.overrideWith(other.Storage)
 	patchedSettings.System.overrideWith(other.System)
+	patchedSettings.TransparentProxy.overrideWith(other.TransparentProxy)
 	patchedSettings.Updater.overrideWith(other.Updater)
 	patchedSettings.Version.overrideWith(other.Version)
 	patchedSettings.VPN.OverrideWith(other.VPN)
@@ -122,7 +187,7 @@ func (s *Settings) OverrideWith(other Settings,
 }
 
 func (s *Settings) SetDefaults() {
-	s.ControlServer.setDefaults()
+	s.ControlServer.SetDefaults()
 	s.DNS.setDefaults()
 	s.Log.setDefaults()
 	s.Firewall.setDefaults(s.Log.Level)
@@ -130,10 +195,12 @@ func (s *Settings) SetDefaults() {
 	s.HTTPProxy.setDefaults()
 	s.Log.setDefaults()
 	s.IPv6.setDefaults()
+	s.Notifications.setDefaults()
 	s.PublicIP.setDefaults()
 	s.Shadowsocks.setDefaults()
 	s.Storage.SetDefaults()
 	s.System.setDefaults()
+	s.TransparentProxy.setDefaults()
 	s.Version.setDefaults()
 	s.VPN.setDefaults()
 	s.Updater.SetDefaults(s.VPN.Provider.Name)
@@ -156,10 +223,12 @@ func (s Settings) toLinesNode() (node *gotree.Node) {
 	node.AppendNode(s.Health.toLinesNode())
 	node.AppendNode(s.Shadowsocks.toLinesNode())
 	node.AppendNode(s.HTTPProxy.toLinesNode())
+	node.AppendNode(s.TransparentProxy.toLinesNode())
 	node.AppendNode(s.ControlServer.toLinesNode())
 	node.AppendNode(s.Storage.toLinesNode())
 	node.AppendNode(s.System.toLinesNode())
 	node.AppendNode(s.PublicIP.toLinesNode())
+	node.AppendNode(s.Notifications.toLinesNode())
 	node.AppendNode(s.Updater.toLinesNode())
 	node.AppendNode(s.Version.toLinesNode())
 	node.AppendNode(s.Pprof.ToLinesNode())
@@ -196,6 +265,34 @@ func (s Settings) Warnings() (warnings []string) {
 	return warnings
 }
 
+// ProxyPorts returns the listening ports of the HTTP proxy
+// and Shadowsocks servers, whether they are enabled or not.
+func (s Settings) ProxyPorts() (ports []uint16) {
+	addresses := []string{s.HTTPProxy.ListeningAddress, *s.Shadowsocks.Settings.Address}
+	ports = make([]uint16, 0, len(addresses))
+	for _, address := range addresses {
+		port, ok := listeningPort(address)
+		if ok {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// listeningPort returns the port of the listening address given,
+// and false if the address or its port is not valid.
+func listeningPort(address string) (port uint16, ok bool) {
+	_, portString, err := net.SplitHostPort(address)
+	if err != nil {
+		return 0, false
+	}
+	port64, err := strconv.ParseUint(portString, 10, 16)
+	if err != nil {
+		return 0, false
+	}
+	return uint16(port64), true
+}
+
 func (s *Settings) Read(r *reader.Reader, warner Warner) (err error) {
 	warnings := readObsolete(r)
 	for _, warning := range warnings {
@@ -203,24 +300,26 @@ func (s *Settings) Read(r *reader.Reader, warner Warner) (err error) {
 	}
 
 	readFunctions := map[string]func(r *reader.Reader) error{
-		"control server": s.ControlServer.read,
+		"control server": s.ControlServer.Read,
 		"DNS":            s.DNS.read,
 		"firewall":       s.Firewall.read,
 		"health":         s.Health.Read,
 		"http proxy":     s.HTTPProxy.read,
 		"log":            s.Log.read,
+		"notifications":  s.Notifications.read,
 		"public ip": func(r *reader.Reader) error {
 			return s.PublicIP.read(r, warner)
 		},
-		"shadowsocks": s.Shadowsocks.read,
-		"storage":     s.Storage.Read,
-		"system":      s.System.read,
-		"updater":     s.Updater.read,
-		"version":     s.Version.read,
-		"VPN":         s.VPN.read,
-		"IPv6":        s.IPv6.read,
-		"profiling":   s.Pprof.Read,
-		"boring poll": s.BoringPoll.read,
+		"shadowsocks":       s.Shadowsocks.read,
+		"storage":           s.Storage.Read,
+		"system":            s.System.read,
+		"transparent proxy": s.TransparentProxy.read,
+		"updater":           s.Updater.read,
+		"version":           s.Version.read,
+		"VPN":               s.VPN.read,
+		"IPv6":              s.IPv6.read,
+		"profiling":         s.Pprof.Read,
+		"boring poll":       s.BoringPoll.read,
 	}
 
 	for name, read := range readFunctions {
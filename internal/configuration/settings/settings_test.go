@@ -37,19 +37,22 @@ func Test_Settings_String(t *testing.T) {
 |   |   ├── Network interface: tun0
 |   |   ├── Run OpenVPN as: root
 |   |   └── Verbosity level: 1
-|   └── Path MTU discovery:
-|       ├── ICMP addresses:
-|       |   ├── 1.1.1.1
-|       |   └── 8.8.8.8
-|       └── TCP addresses:
-|           ├── 1.1.1.1:53
-|           ├── 8.8.8.8:53
-|           ├── 1.1.1.1:443
-|           ├── 8.8.8.8:443
-|           ├── [2606:4700:4700::1111]:53
-|           ├── [2001:4860:4860::8888]:53
-|           ├── [2606:4700:4700::1111]:443
-|           └── [2001:4860:4860::8888]:443
+|   ├── Path MTU discovery:
+|   |   ├── ICMP addresses:
+|   |   |   ├── 1.1.1.1
+|   |   |   └── 8.8.8.8
+|   |   ├── TCP addresses:
+|   |   |   ├── 1.1.1.1:53
+|   |   |   ├── 8.8.8.8:53
+|   |   |   ├── 1.1.1.1:443
+|   |   |   ├── 8.8.8.8:443
+|   |   |   ├── [2606:4700:4700::1111]:53
+|   |   |   ├── [2001:4860:4860::8888]:53
+|   |   |   ├── [2606:4700:4700::1111]:443
+|   |   |   └── [2001:4860:4860::8888]:443
+|   |   └── Periodic re-discovery: disabled
+|   ├── Server rotation: disabled
+|   └── Exit verification: disabled
 ├── DNS settings:
 |   ├── Upstream resolver type: dot
 |   ├── Upstream resolvers:
@@ -57,16 +60,20 @@ func Test_Settings_String(t *testing.T) {
 |   ├── Caching: yes
 |   ├── IPv6: no
 |   ├── Update period: every 24h0m0s
-|   └── DNS filtering settings:
-|       ├── Block malicious: yes
-|       ├── Block ads: no
-|       └── Block surveillance: yes
+|   ├── DNS filtering settings:
+|   |   ├── Block malicious: yes
+|   |   ├── Block ads: no
+|   |   └── Block surveillance: yes
+|   └── DNS leak check:
+|       ├── Periodic check: disabled
+|       └── Restart VPN on leak: no
 ├── Firewall settings:
 |   ├── Enabled: yes
 |   └── Iptables settings:
 |       └── Log level: INFO
 ├── Log settings:
-|   └── Log level: INFO
+|   ├── Log level: INFO
+|   └── Log format: text
 ├── IPv6 settings:
 |   └── Check addresses:
 |       ├── [2001:4860:4860::8888]:53
@@ -85,22 +92,30 @@ func Test_Settings_String(t *testing.T) {
 |   └── Enabled: no
 ├── HTTP proxy settings:
 |   └── Enabled: no
+├── Transparent proxy settings:
+|   └── Enabled: no
 ├── Control server settings:
 |   ├── Listening address: :8000
 |   ├── Logging: yes
-|   └── Authentication file path: /gluetun/auth/config.toml
+|   ├── Authentication file path: /gluetun/auth/config.toml
+|   ├── Dashboard: no
+|   └── Audit log file path: /gluetun/audit.log
 ├── Storage settings:
-|   └── Servers directory path: /gluetun/servers/
+|   ├── Servers directory path: /gluetun/servers/
+|   └── Require signed servers data: no
 ├── OS Alpine settings:
 |   ├── Process UID: 1000
 |   └── Process GID: 1000
 ├── Public IP settings:
 |   ├── IP file path: /tmp/gluetun/ip
 |   ├── Public IP data base API: ipinfo
-|   └── Public IP data backup APIs:
-|       ├── cloudflare
-|       ├── ifconfigco
-|       └── ip2location
+|   ├── Public IP data backup APIs:
+|   |   ├── cloudflare
+|   |   ├── ifconfigco
+|   |   └── ip2location
+|   └── History:
+|       ├── File path: /gluetun/publicip_history.json
+|       └── Maximum entries: 100
 └── Version settings:
     └── Enabled: yes`,
 		},
@@ -116,3 +131,81 @@ func Test_Settings_String(t *testing.T) {
 		})
 	}
 }
+
+func Test_Settings_validateDependencies(t *testing.T) {
+	t.Parallel()
+
+	withDefaults := func(s Settings) Settings {
+		s.SetDefaults()
+		return s
+	}
+
+	testCases := map[string]struct {
+		settings   Settings
+		errWrapped error
+	}{
+		"default settings": {
+			settings: withDefaults(Settings{}),
+		},
+		"gateway DNS hijack without DNS server": {
+			settings: withDefaults(Settings{
+				DNS: DNS{ServerEnabled: ptrTo(false)},
+				Firewall: Firewall{
+					Gateway: Gateway{
+						Enabled:   ptrTo(true),
+						DNSHijack: ptrTo(true),
+					},
+				},
+			}),
+			errWrapped: ErrGatewayDNSHijackWithoutDNSServer,
+		},
+		"transparent proxy without firewall": {
+			settings: withDefaults(Settings{
+				Firewall:         Firewall{Enabled: ptrTo(false)},
+				TransparentProxy: TransparentProxy{Enabled: ptrTo(true)},
+			}),
+			errWrapped: ErrTransparentProxyWithoutFirewall,
+		},
+		"transparent proxy port used by control server": {
+			settings: withDefaults(Settings{
+				Firewall: Firewall{Enabled: ptrTo(true)},
+				TransparentProxy: TransparentProxy{
+					Enabled: ptrTo(true),
+					Port:    8000,
+				},
+			}),
+			errWrapped: ErrTransparentProxyPortInUse,
+		},
+		"transparent proxy port used by HTTP proxy": {
+			settings: withDefaults(Settings{
+				Firewall:  Firewall{Enabled: ptrTo(true)},
+				HTTPProxy: HTTPProxy{Enabled: ptrTo(true)},
+				TransparentProxy: TransparentProxy{
+					Enabled: ptrTo(true),
+					Port:    8888,
+				},
+			}),
+			errWrapped: ErrTransparentProxyPortInUse,
+		},
+		"transparent proxy port used by disabled Shadowsocks": {
+			settings: withDefaults(Settings{
+				Firewall:    Firewall{Enabled: ptrTo(true)},
+				Shadowsocks: Shadowsocks{Enabled: ptrTo(false)},
+				TransparentProxy: TransparentProxy{
+					Enabled: ptrTo(true),
+					Port:    8388,
+				},
+			}),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.settings.validateDependencies()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
@@ -20,6 +20,13 @@ type Storage struct {
 	// LegacyServersFilepath is the legacy "fat" JSON filepath to migrate from.
 	// TODO v4: remove
 	LegacyServersFilepath string
+	// RequireSignature is whether to refuse servers data without
+	// a valid signature, both from files on disk and downloaded
+	// by the updater. Servers files written by the program are
+	// not signed, so they are refused on the next start if this
+	// is enabled. It defaults to false and cannot be nil in the
+	// internal state.
+	RequireSignature *bool
 }
 
 func (s Storage) validate() (err error) {
@@ -41,6 +48,7 @@ func (s *Storage) copy() (copied Storage) {
 		ServersEnabled:        gosettings.CopyPointer(s.ServersEnabled),
 		ServersPath:           s.ServersPath,
 		LegacyServersFilepath: s.LegacyServersFilepath,
+		RequireSignature:      gosettings.CopyPointer(s.RequireSignature),
 	}
 }
 
@@ -48,6 +56,7 @@ func (s *Storage) overrideWith(other Storage) {
 	s.ServersEnabled = gosettings.OverrideWithPointer(s.ServersEnabled, other.ServersEnabled)
 	s.ServersPath = gosettings.OverrideWithComparable(s.ServersPath, other.ServersPath)
 	s.LegacyServersFilepath = gosettings.OverrideWithComparable(s.LegacyServersFilepath, other.LegacyServersFilepath)
+	s.RequireSignature = gosettings.OverrideWithPointer(s.RequireSignature, other.RequireSignature)
 }
 
 const defaultLegacyServersFilepath = "/gluetun/servers.json"
@@ -57,6 +66,7 @@ func (s *Storage) SetDefaults() {
 	const defaultServersPath = "/gluetun/servers/"
 	s.ServersPath = gosettings.DefaultComparable(s.ServersPath, defaultServersPath)
 	s.LegacyServersFilepath = gosettings.DefaultComparable(s.LegacyServersFilepath, defaultLegacyServersFilepath)
+	s.RequireSignature = gosettings.DefaultPointer(s.RequireSignature, false)
 }
 
 func (s Storage) String() string {
@@ -72,6 +82,7 @@ func (s Storage) toLinesNode() (node *gotree.Node) {
 	if s.LegacyServersFilepath != defaultLegacyServersFilepath {
 		node.Appendf("Legacy servers filepath: %s", s.LegacyServersFilepath)
 	}
+	node.Appendf("Require signed servers data: %s", gosettings.BoolToYesNo(s.RequireSignature))
 	return node
 }
 
@@ -92,5 +103,10 @@ func (s *Storage) Read(r *reader.Reader) (err error) {
 		}
 		s.ServersPath = r.String("STORAGE_SERVERS_DIRECTORY_PATH")
 	}
+
+	s.RequireSignature, err = r.BoolPtr("STORAGE_SERVERS_REQUIRE_SIGNATURE")
+	if err != nil {
+		return err
+	}
 	return nil
 }
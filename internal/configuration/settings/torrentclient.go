@@ -0,0 +1,145 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gosettings/validate"
+	"github.com/qdm12/gotree"
+)
+
+const (
+	TorrentClientQBittorrent  = "qbittorrent"
+	TorrentClientTransmission = "transmission"
+	TorrentClientDeluge       = "deluge"
+)
+
+// TorrentClient contains settings to set the forwarded port
+// as the listening port of a torrent client.
+type TorrentClient struct {
+	// Name is the torrent client name, and can be "qbittorrent",
+	// "transmission", "deluge" or the empty string to disable
+	// the port synchronization. It cannot be nil in the internal state.
+	Name *string `json:"name"`
+	// URL is the torrent client web API URL. It defaults to the
+	// default local URL of the torrent client selected, and cannot be
+	// nil in the internal state.
+	URL *string `json:"url"`
+	// Username is the torrent client username. It can be the empty
+	// string if the client requires no authentication, and cannot be
+	// nil in the internal state.
+	Username *string `json:"username"`
+	// Password is the torrent client password. It can be the empty
+	// string if the client requires no authentication, and cannot be
+	// nil in the internal state.
+	Password *string `json:"password"`
+	// CheckPeriod is the period to check the torrent client listening
+	// port still matches the forwarded port, to set it again for example
+	// if the client restarted. It defaults to 1 minute and cannot be nil
+	// in the internal state.
+	CheckPeriod *time.Duration `json:"check_period"`
+}
+
+var ErrTorrentClientURLNotValid = errors.New("torrent client URL is not valid")
+
+func (t TorrentClient) validate() (err error) {
+	if *t.Name == "" {
+		return nil
+	}
+
+	err = validate.IsOneOf(*t.Name, TorrentClientQBittorrent,
+		TorrentClientTransmission, TorrentClientDeluge)
+	if err != nil {
+		return fmt.Errorf("torrent client name: %w", err)
+	}
+
+	parsedURL, err := url.Parse(*t.URL)
+	if err != nil {
+		return fmt.Errorf("torrent client URL: %w", err)
+	} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" || parsedURL.Host == "" {
+		return fmt.Errorf("%w: %s", ErrTorrentClientURLNotValid, *t.URL)
+	}
+
+	const minCheckPeriod = 5 * time.Second
+	if *t.CheckPeriod < minCheckPeriod {
+		return fmt.Errorf("torrent client check period is too short: %s must be at least %s",
+			*t.CheckPeriod, minCheckPeriod)
+	}
+
+	return nil
+}
+
+func (t *TorrentClient) copy() (copied TorrentClient) {
+	return TorrentClient{
+		Name:        gosettings.CopyPointer(t.Name),
+		URL:         gosettings.CopyPointer(t.URL),
+		Username:    gosettings.CopyPointer(t.Username),
+		Password:    gosettings.CopyPointer(t.Password),
+		CheckPeriod: gosettings.CopyPointer(t.CheckPeriod),
+	}
+}
+
+func (t *TorrentClient) overrideWith(other TorrentClient) {
+	t.Name = gosettings.OverrideWithPointer(t.Name, other.Name)
+	t.URL = gosettings.OverrideWithPointer(t.URL, other.URL)
+	t.Username = gosettings.OverrideWithPointer(t.Username, other.Username)
+	t.Password = gosettings.OverrideWithPointer(t.Password, other.Password)
+	t.CheckPeriod = gosettings.OverrideWithPointer(t.CheckPeriod, other.CheckPeriod)
+}
+
+func (t *TorrentClient) setDefaults() {
+	t.Name = gosettings.DefaultPointer(t.Name, "")
+	var defaultURL string
+	switch *t.Name {
+	case TorrentClientQBittorrent:
+		defaultURL = "http://127.0.0.1:8080"
+	case TorrentClientTransmission:
+		defaultURL = "http://127.0.0.1:9091/transmission/rpc"
+	case TorrentClientDeluge:
+		defaultURL = "http://127.0.0.1:8112/json"
+	}
+	t.URL = gosettings.DefaultPointer(t.URL, defaultURL)
+	t.Username = gosettings.DefaultPointer(t.Username, "")
+	t.Password = gosettings.DefaultPointer(t.Password, "")
+	t.CheckPeriod = gosettings.DefaultPointer(t.CheckPeriod, time.Minute)
+}
+
+func (t TorrentClient) String() string {
+	return t.toLinesNode().String()
+}
+
+func (t TorrentClient) toLinesNode() (node *gotree.Node) {
+	if *t.Name == "" {
+		return nil
+	}
+
+	node = gotree.New("Torrent client port synchronization:")
+	node.Appendf("Client: %s", *t.Name)
+	node.Appendf("URL: %s", *t.URL)
+	if *t.Username != "" {
+		node.Appendf("Username: %s", *t.Username)
+	}
+	if *t.Password != "" {
+		node.Appendf("Password: %s", gosettings.ObfuscateKey(*t.Password))
+	}
+	node.Appendf("Check period: %s", *t.CheckPeriod)
+	return node
+}
+
+func (t *TorrentClient) read(r *reader.Reader) (err error) {
+	t.Name = r.Get("VPN_PORT_FORWARDING_TORRENT_CLIENT")
+	t.URL = r.Get("VPN_PORT_FORWARDING_TORRENT_CLIENT_URL", reader.ForceLowercase(false))
+	t.Username = r.Get("VPN_PORT_FORWARDING_TORRENT_CLIENT_USERNAME", reader.ForceLowercase(false))
+	t.Password = r.Get("VPN_PORT_FORWARDING_TORRENT_CLIENT_PASSWORD", reader.ForceLowercase(false))
+
+	t.CheckPeriod, err = r.DurationPtr("VPN_PORT_FORWARDING_TORRENT_CLIENT_CHECK_PERIOD")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
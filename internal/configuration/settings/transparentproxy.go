@@ -0,0 +1,265 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/gosettings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/qdm12/gotree"
+)
+
+// TransparentProxy contains settings to configure the transparent proxy,
+// intercepting TCP and UDP traffic of hosts routed through Gluetun and
+// proxying it through the VPN tunnel.
+type TransparentProxy struct {
+	// Enabled is true if the transparent proxy should run,
+	// and false otherwise. It cannot be nil in the
+	// internal state.
+	Enabled *bool
+	// Port is the port the transparent proxy listens on,
+	// for both TCP and UDP. It cannot be zero in the
+	// internal state.
+	Port uint16
+	// Sources are the source subnets or IP addresses of
+	// clients whose traffic is intercepted. It must be set
+	// if the transparent proxy is enabled.
+	Sources []netip.Prefix
+	// ACL is the ordered list of rules to allow or deny
+	// connections through the transparent proxy. The first
+	// rule matching a connection is used, and connections
+	// not matching any rule are allowed.
+	ACL []TransparentProxyRule
+	// Log is true if the transparent proxy should log the
+	// original destination of each connection. It cannot
+	// be nil in the internal state.
+	Log *bool
+}
+
+// TransparentProxyRule is an access control rule
+// for connections going through the transparent proxy.
+type TransparentProxyRule struct {
+	// Allow is true to allow matching connections,
+	// and false to deny them.
+	Allow bool
+	// Source is the client subnet to match.
+	Source netip.Prefix
+	// Destination is the original destination subnet to match.
+	Destination netip.Prefix
+	// Port is the original destination port to match,
+	// and any port is matched if it is zero.
+	Port uint16
+}
+
+// Match returns true if the connection from the source address
+// to the destination address and port matches the rule.
+func (t TransparentProxyRule) Match(source netip.Addr, destination netip.AddrPort) bool {
+	return t.Source.Contains(source.Unmap()) &&
+		t.Destination.Contains(destination.Addr().Unmap()) &&
+		(t.Port == 0 || t.Port == destination.Port())
+}
+
+func (t TransparentProxyRule) String() string {
+	action := "deny"
+	if t.Allow {
+		action = "allow"
+	}
+	s := action + " " + t.Source.String() + " " + t.Destination.String()
+	if t.Port != 0 {
+		s += fmt.Sprintf(" %d", t.Port)
+	}
+	return s
+}
+
+var (
+	ErrTransparentProxyPortZero          = errors.New("transparent proxy port cannot be zero")
+	ErrTransparentProxySourcesNotSet     = errors.New("transparent proxy sources are not set")
+	ErrTransparentProxySourceUnspecified = errors.New("transparent proxy source has an unspecified address")
+	ErrTransparentProxyRuleNotValid      = errors.New("transparent proxy rule is not valid")
+	ErrIPOrPrefixNotValid                = errors.New("value is not a valid IP or CIDR range")
+)
+
+func (t TransparentProxy) validate() (err error) {
+	if !*t.Enabled {
+		return nil
+	}
+
+	if t.Port == 0 {
+		return ErrTransparentProxyPortZero
+	}
+
+	if len(t.Sources) == 0 {
+		return ErrTransparentProxySourcesNotSet
+	}
+
+	for _, source := range t.Sources {
+		if source.Addr().IsUnspecified() {
+			return fmt.Errorf("%w: %s", ErrTransparentProxySourceUnspecified, source)
+		}
+	}
+
+	for _, rule := range t.ACL {
+		if rule.Source.Addr().Is4() != rule.Destination.Addr().Is4() {
+			return fmt.Errorf("%w: %s: source and destination IP families differ",
+				ErrTransparentProxyRuleNotValid, rule)
+		}
+	}
+
+	return nil
+}
+
+func (t *TransparentProxy) copy() (copied TransparentProxy) {
+	return TransparentProxy{
+		Enabled: gosettings.CopyPointer(t.Enabled),
+		Port:    t.Port,
+		Sources: gosettings.CopySlice(t.Sources),
+		ACL:     gosettings.CopySlice(t.ACL),
+		Log:     gosettings.CopyPointer(t.Log),
+	}
+}
+
+// overrideWith overrides fields of the receiver
+// settings object with any field set in the other
+// settings.
+func (t *TransparentProxy) overrideWith(other TransparentProxy) {
+	t.Enabled = gosettings.OverrideWithPointer(t.Enabled, other.Enabled)
+	t.Port = gosettings.OverrideWithComparable(t.Port, other.Port)
+	t.Sources = gosettings.OverrideWithSlice(t.Sources, other.Sources)
+	t.ACL = gosettings.OverrideWithSlice(t.ACL, other.ACL)
+	t.Log = gosettings.OverrideWithPointer(t.Log, other.Log)
+}
+
+func (t *TransparentProxy) setDefaults() {
+	t.Enabled = gosettings.DefaultPointer(t.Enabled, false)
+	const defaultPort = 8890
+	t.Port = gosettings.DefaultComparable(t.Port, defaultPort)
+	t.Log = gosettings.DefaultPointer(t.Log, false)
+}
+
+func (t TransparentProxy) String() string {
+	return t.toLinesNode().String()
+}
+
+func (t TransparentProxy) toLinesNode() (node *gotree.Node) {
+	node = gotree.New("Transparent proxy settings:")
+	node.Appendf("Enabled: %s", gosettings.BoolToYesNo(t.Enabled))
+	if !*t.Enabled {
+		return node
+	}
+
+	node.Appendf("Port: %d", t.Port)
+
+	sourcesNode := node.Appendf("Sources:")
+	for _, source := range t.Sources {
+		sourcesNode.Appendf("%s", source)
+	}
+
+	if len(t.ACL) > 0 {
+		aclNode := node.Appendf("Access control rules:")
+		for _, rule := range t.ACL {
+			aclNode.Appendf("%s", rule)
+		}
+	}
+
+	node.Appendf("Log: %s", gosettings.BoolToYesNo(t.Log))
+
+	return node
+}
+
+func (t *TransparentProxy) read(r *reader.Reader) (err error) {
+	t.Enabled, err = r.BoolPtr("TRANSPARENT_PROXY")
+	if err != nil {
+		return err
+	}
+
+	port, err := r.Uint16Ptr("TRANSPARENT_PROXY_PORT")
+	if err != nil {
+		return err
+	} else if port != nil {
+		t.Port = *port
+	}
+
+	for _, source := range r.CSV("TRANSPARENT_PROXY_SOURCES") {
+		prefix, err := parseIPOrPrefix(source)
+		if err != nil {
+			return fmt.Errorf("environment variable TRANSPARENT_PROXY_SOURCES: %w", err)
+		}
+		t.Sources = append(t.Sources, prefix)
+	}
+
+	for _, s := range r.CSV("TRANSPARENT_PROXY_ACL") {
+		rule, err := parseTransparentProxyRule(s)
+		if err != nil {
+			return fmt.Errorf("environment variable TRANSPARENT_PROXY_ACL: %w", err)
+		}
+		t.ACL = append(t.ACL, rule)
+	}
+
+	t.Log, err = r.BoolPtr("TRANSPARENT_PROXY_LOG")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseTransparentProxyRule parses a rule in the format
+// "<allow|deny> <source> <destination> [port]" where the source
+// and destination can each be an IP address or a CIDR range.
+func parseTransparentProxyRule(s string) (rule TransparentProxyRule, err error) {
+	fields := strings.Fields(s)
+	const minFields, maxFields = 3, 4
+	if len(fields) < minFields || len(fields) > maxFields {
+		return TransparentProxyRule{}, fmt.Errorf("%w: %q: expected format "+
+			"'<allow|deny> <source> <destination> [port]'",
+			ErrTransparentProxyRuleNotValid, s)
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "allow":
+		rule.Allow = true
+	case "deny":
+	default:
+		return TransparentProxyRule{}, fmt.Errorf("%w: %q: action %q must be allow or deny",
+			ErrTransparentProxyRuleNotValid, s, fields[0])
+	}
+
+	rule.Source, err = parseIPOrPrefix(fields[1])
+	if err != nil {
+		return TransparentProxyRule{}, fmt.Errorf("%w: %q: source: %w", ErrTransparentProxyRuleNotValid, s, err)
+	}
+
+	rule.Destination, err = parseIPOrPrefix(fields[2])
+	if err != nil {
+		return TransparentProxyRule{}, fmt.Errorf("%w: %q: destination: %w", ErrTransparentProxyRuleNotValid, s, err)
+	}
+
+	if len(fields) == maxFields {
+		const base, bitSize = 10, 16
+		port, err := strconv.ParseUint(fields[3], base, bitSize)
+		if err != nil {
+			return TransparentProxyRule{}, fmt.Errorf("%w: %q: port: %w", ErrTransparentProxyRuleNotValid, s, err)
+		}
+		rule.Port = uint16(port)
+	}
+
+	return rule, nil
+}
+
+// parseIPOrPrefix parses an IP address as a single address
+// CIDR range, or a CIDR range which is then masked.
+func parseIPOrPrefix(s string) (prefix netip.Prefix, err error) {
+	ip, err := netip.ParseAddr(s)
+	if err == nil {
+		return netip.PrefixFrom(ip, ip.BitLen()), nil
+	}
+
+	prefix, err = netip.ParsePrefix(s)
+	if err != nil {
+		return prefix, fmt.Errorf("%w: %q", ErrIPOrPrefixNotValid, s)
+	}
+	return prefix.Masked(), nil
+}
@@ -0,0 +1,182 @@
+package settings
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseTransparentProxyRule(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		rule       TransparentProxyRule
+		errWrapped error
+		errMessage string
+	}{
+		"missing_fields": {
+			s:          "allow 10.0.0.0/24",
+			errWrapped: ErrTransparentProxyRuleNotValid,
+			errMessage: "transparent proxy rule is not valid: \"allow 10.0.0.0/24\": " +
+				"expected format '<allow|deny> <source> <destination> [port]'",
+		},
+		"invalid_action": {
+			s:          "drop 10.0.0.0/24 0.0.0.0/0",
+			errWrapped: ErrTransparentProxyRuleNotValid,
+			errMessage: "transparent proxy rule is not valid: \"drop 10.0.0.0/24 0.0.0.0/0\": " +
+				"action \"drop\" must be allow or deny",
+		},
+		"invalid_source": {
+			s:          "allow 10.0.0.x 0.0.0.0/0",
+			errWrapped: ErrIPOrPrefixNotValid,
+			errMessage: "transparent proxy rule is not valid: \"allow 10.0.0.x 0.0.0.0/0\": " +
+				"source: value is not a valid IP or CIDR range: \"10.0.0.x\"",
+		},
+		"invalid_port": {
+			s:          "allow 10.0.0.0/24 0.0.0.0/0 70000",
+			errWrapped: ErrTransparentProxyRuleNotValid,
+			errMessage: "transparent proxy rule is not valid: \"allow 10.0.0.0/24 0.0.0.0/0 70000\": " +
+				"port: strconv.ParseUint: parsing \"70000\": value out of range",
+		},
+		"deny_without_port": {
+			s: "deny 10.0.0.5 1.2.3.0/24",
+			rule: TransparentProxyRule{
+				Source:      netip.MustParsePrefix("10.0.0.5/32"),
+				Destination: netip.MustParsePrefix("1.2.3.0/24"),
+			},
+		},
+		"allow_with_port": {
+			s: "ALLOW 10.0.0.0/24 0.0.0.0/0 443",
+			rule: TransparentProxyRule{
+				Allow:       true,
+				Source:      netip.MustParsePrefix("10.0.0.0/24"),
+				Destination: netip.MustParsePrefix("0.0.0.0/0"),
+				Port:        443,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := parseTransparentProxyRule(testCase.s)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				require.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.rule, rule)
+		})
+	}
+}
+
+func Test_TransparentProxyRule_Match(t *testing.T) {
+	t.Parallel()
+
+	rule := TransparentProxyRule{
+		Source:      netip.MustParsePrefix("10.0.0.0/24"),
+		Destination: netip.MustParsePrefix("1.2.3.0/24"),
+		Port:        53,
+	}
+
+	testCases := map[string]struct {
+		source      netip.Addr
+		destination netip.AddrPort
+		match       bool
+	}{
+		"match": {
+			source:      netip.MustParseAddr("10.0.0.2"),
+			destination: netip.MustParseAddrPort("1.2.3.4:53"),
+			match:       true,
+		},
+		"ipv4_mapped_match": {
+			source:      netip.MustParseAddr("::ffff:10.0.0.2"),
+			destination: netip.MustParseAddrPort("[::ffff:1.2.3.4]:53"),
+			match:       true,
+		},
+		"source_mismatch": {
+			source:      netip.MustParseAddr("10.0.1.2"),
+			destination: netip.MustParseAddrPort("1.2.3.4:53"),
+		},
+		"destination_mismatch": {
+			source:      netip.MustParseAddr("10.0.0.2"),
+			destination: netip.MustParseAddrPort("1.2.4.4:53"),
+		},
+		"port_mismatch": {
+			source:      netip.MustParseAddr("10.0.0.2"),
+			destination: netip.MustParseAddrPort("1.2.3.4:80"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			match := rule.Match(testCase.source, testCase.destination)
+
+			assert.Equal(t, testCase.match, match)
+		})
+	}
+}
+
+func Test_TransparentProxy_validate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   TransparentProxy
+		errWrapped error
+	}{
+		"disabled": {
+			settings: TransparentProxy{Enabled: ptrTo(false)},
+		},
+		"port_zero": {
+			settings:   TransparentProxy{Enabled: ptrTo(true)},
+			errWrapped: ErrTransparentProxyPortZero,
+		},
+		"sources_not_set": {
+			settings:   TransparentProxy{Enabled: ptrTo(true), Port: 8890},
+			errWrapped: ErrTransparentProxySourcesNotSet,
+		},
+		"source_unspecified": {
+			settings: TransparentProxy{
+				Enabled: ptrTo(true),
+				Port:    8890,
+				Sources: []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0")},
+			},
+			errWrapped: ErrTransparentProxySourceUnspecified,
+		},
+		"rule_families_differ": {
+			settings: TransparentProxy{
+				Enabled: ptrTo(true),
+				Port:    8890,
+				Sources: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")},
+				ACL: []TransparentProxyRule{{
+					Source:      netip.MustParsePrefix("10.0.0.0/24"),
+					Destination: netip.MustParsePrefix("::/0"),
+				}},
+			},
+			errWrapped: ErrTransparentProxyRuleNotValid,
+		},
+		"valid": {
+			settings: TransparentProxy{
+				Enabled: ptrTo(true),
+				Port:    8890,
+				Sources: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.settings.validate()
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
@@ -32,6 +32,11 @@ type Updater struct {
 	// PreferDirectDownload is whether to prefer direct download of
 	// server data from Github (recommended).
 	PreferDirectDownload *bool
+	// ReconnectOnServerRemoved is whether to reconnect the VPN
+	// to another server if the current server no longer matches
+	// the server selection after an update. It cannot be nil
+	// in the internal state.
+	ReconnectOnServerRemoved *bool
 	// ProtonEmail is the email to authenticate with the Proton API.
 	ProtonEmail *string
 	// ProtonPassword is the password to authenticate with the Proton API.
@@ -75,12 +80,13 @@ func (u Updater) Validate() (err error) {
 
 func (u *Updater) copy() (copied Updater) {
 	return Updater{
-		Period:               gosettings.CopyPointer(u.Period),
-		MinRatio:             u.MinRatio,
-		Providers:            gosettings.CopySlice(u.Providers),
-		PreferDirectDownload: gosettings.CopyPointer(u.PreferDirectDownload),
-		ProtonEmail:          gosettings.CopyPointer(u.ProtonEmail),
-		ProtonPassword:       gosettings.CopyPointer(u.ProtonPassword),
+		Period:                   gosettings.CopyPointer(u.Period),
+		MinRatio:                 u.MinRatio,
+		Providers:                gosettings.CopySlice(u.Providers),
+		PreferDirectDownload:     gosettings.CopyPointer(u.PreferDirectDownload),
+		ReconnectOnServerRemoved: gosettings.CopyPointer(u.ReconnectOnServerRemoved),
+		ProtonEmail:              gosettings.CopyPointer(u.ProtonEmail),
+		ProtonPassword:           gosettings.CopyPointer(u.ProtonPassword),
 	}
 }
 
@@ -92,6 +98,8 @@ func (u *Updater) overrideWith(other Updater) {
 	u.MinRatio = gosettings.OverrideWithComparable(u.MinRatio, other.MinRatio)
 	u.Providers = gosettings.OverrideWithSlice(u.Providers, other.Providers)
 	u.PreferDirectDownload = gosettings.OverrideWithPointer(u.PreferDirectDownload, other.PreferDirectDownload)
+	u.ReconnectOnServerRemoved = gosettings.OverrideWithPointer(u.ReconnectOnServerRemoved,
+		other.ReconnectOnServerRemoved)
 	u.ProtonEmail = gosettings.OverrideWithPointer(u.ProtonEmail, other.ProtonEmail)
 	u.ProtonPassword = gosettings.OverrideWithPointer(u.ProtonPassword, other.ProtonPassword)
 }
@@ -110,6 +118,7 @@ func (u *Updater) SetDefaults(vpnProvider string) {
 
 	// Set these to empty strings to avoid nil pointer panics
 	u.PreferDirectDownload = gosettings.DefaultPointer(u.PreferDirectDownload, false)
+	u.ReconnectOnServerRemoved = gosettings.DefaultPointer(u.ReconnectOnServerRemoved, false)
 	u.ProtonEmail = gosettings.DefaultPointer(u.ProtonEmail, "")
 	u.ProtonPassword = gosettings.DefaultPointer(u.ProtonPassword, "")
 }
@@ -128,6 +137,7 @@ func (u Updater) toLinesNode() (node *gotree.Node) {
 	node.Appendf("Minimum ratio: %.1f", u.MinRatio)
 	node.Appendf("Providers to update: %s", strings.Join(u.Providers, ", "))
 	node.Appendf("Prefer direct download: %s", gosettings.BoolToYesNo(u.PreferDirectDownload))
+	node.Appendf("Reconnect on server removed: %s", gosettings.BoolToYesNo(u.ReconnectOnServerRemoved))
 	if slices.Contains(u.Providers, providers.Protonvpn) {
 		node.Appendf("Proton API email: %s", *u.ProtonEmail)
 		node.Appendf("Proton API password: %s", gosettings.ObfuscateKey(*u.ProtonPassword))
@@ -154,6 +164,11 @@ func (u *Updater) read(r *reader.Reader) (err error) {
 		return err
 	}
 
+	u.ReconnectOnServerRemoved, err = r.BoolPtr("UPDATER_RECONNECT_ON_SERVER_REMOVED")
+	if err != nil {
+		return err
+	}
+
 	u.ProtonEmail = r.Get("UPDATER_PROTONVPN_EMAIL")
 	if u.ProtonEmail == nil {
 		protonUsername := r.String("UPDATER_PROTONVPN_USERNAME", reader.IsRetro("UPDATER_PROTONVPN_EMAIL"))
@@ -20,6 +20,10 @@ type VPN struct {
 	OpenVPN   OpenVPN   `json:"openvpn"`
 	Wireguard Wireguard `json:"wireguard"`
 	PMTUD     PMTUD     `json:"pmtud"`
+	Rotation  Rotation  `json:"rotation"`
+	// ExitVerification contains settings to verify the public IP
+	// address information matches the server selection.
+	ExitVerification ExitVerification `json:"exit_verification"`
 	// UpCommand is the command to use when the VPN connection is up.
 	// It can be the empty string to indicate not to run a command.
 	// It cannot be nil in the internal state.
@@ -69,19 +73,26 @@ func (v *VPN) Validate(filterChoicesGetter FilterChoicesGetter, ipv6Supported bo
 		return fmt.Errorf("PMTUD settings: %w", err)
 	}
 
+	err = v.Rotation.validate()
+	if err != nil {
+		return fmt.Errorf("rotation settings: %w", err)
+	}
+
 	return nil
 }
 
 func (v *VPN) Copy() (copied VPN) {
 	return VPN{
-		Type:        v.Type,
-		Provider:    v.Provider.copy(),
-		AmneziaWg:   v.AmneziaWg.copy(),
-		OpenVPN:     v.OpenVPN.copy(),
-		Wireguard:   v.Wireguard.copy(),
-		PMTUD:       v.PMTUD.copy(),
-		UpCommand:   gosettings.CopyPointer(v.UpCommand),
-		DownCommand: gosettings.CopyPointer(v.DownCommand),
+		Type:             v.Type,
+		Provider:         v.Provider.copy(),
+		AmneziaWg:        v.AmneziaWg.copy(),
+		OpenVPN:          v.OpenVPN.copy(),
+		Wireguard:        v.Wireguard.copy(),
+		PMTUD:            v.PMTUD.copy(),
+		Rotation:         v.Rotation.copy(),
+		ExitVerification: v.ExitVerification.copy(),
+		UpCommand:        gosettings.CopyPointer(v.UpCommand),
+		DownCommand:      gosettings.CopyPointer(v.DownCommand),
 	}
 }
 
@@ -92,6 +103,8 @@ func (v *VPN) OverrideWith(other VPN) {
 	v.OpenVPN.overrideWith(other.OpenVPN)
 	v.Wireguard.overrideWith(other.Wireguard)
 	v.PMTUD.overrideWith(other.PMTUD)
+	v.Rotation.overrideWith(other.Rotation)
+	v.ExitVerification.overrideWith(other.ExitVerification)
 	v.UpCommand = gosettings.OverrideWithPointer(v.UpCommand, other.UpCommand)
 	v.DownCommand = gosettings.OverrideWithPointer(v.DownCommand, other.DownCommand)
 }
@@ -103,6 +116,8 @@ func (v *VPN) setDefaults() {
 	v.OpenVPN.setDefaults(v.Provider.Name)
 	v.Wireguard.setDefaults(v.Provider.Name)
 	v.PMTUD.setDefaults()
+	v.Rotation.setDefaults()
+	v.ExitVerification.setDefaults()
 	v.UpCommand = gosettings.DefaultPointer(v.UpCommand, "")
 	v.DownCommand = gosettings.DefaultPointer(v.DownCommand, "")
 }
@@ -125,6 +140,8 @@ func (v VPN) toLinesNode() (node *gotree.Node) {
 		node.AppendNode(v.Wireguard.toLinesNode())
 	}
 	node.AppendNode(v.PMTUD.toLinesNode())
+	node.AppendNode(v.Rotation.toLinesNode())
+	node.AppendNode(v.ExitVerification.toLinesNode())
 
 	if *v.UpCommand != "" {
 		node.Appendf("Up command: %s", *v.UpCommand)
@@ -165,6 +182,16 @@ func (v *VPN) read(r *reader.Reader) (err error) {
 		return fmt.Errorf("PMTUD: %w", err)
 	}
 
+	err = v.Rotation.read(r)
+	if err != nil {
+		return fmt.Errorf("rotation: %w", err)
+	}
+
+	err = v.ExitVerification.read(r)
+	if err != nil {
+		return fmt.Errorf("exit verification: %w", err)
+	}
+
 	v.UpCommand = r.Get("VPN_UP_COMMAND", reader.ForceLowercase(false))
 
 	v.DownCommand = r.Get("VPN_DOWN_COMMAND", reader.ForceLowercase(false))
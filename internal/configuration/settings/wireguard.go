@@ -41,6 +41,11 @@ type Wireguard struct {
 	// It cannot be nil in the internal state, and defaults to
 	// 0 indicating to use PMTUD.
 	MTU *uint32 `json:"mtu"`
+	// HandshakeTimeout is the maximum age of the last handshake with
+	// the server peer before the VPN connection is considered failed
+	// and is restarted. It can be set to 0 to disable this watchdog.
+	// It defaults to 0 and cannot be nil in the internal state.
+	HandshakeTimeout *time.Duration `json:"handshake_timeout"`
 	// Implementation is the Wireguard implementation to use.
 	// It can be "auto", "userspace" or "kernelspace".
 	// It defaults to "auto" and cannot be the empty string
@@ -111,6 +116,12 @@ func (w Wireguard) validate(vpnProvider string, ipv6Supported, amneziawg bool) (
 		return fmt.Errorf("persistent keep alive interval is negative: %s", *w.PersistentKeepaliveInterval)
 	}
 
+	const minHandshakeTimeout = 3 * time.Minute
+	if *w.HandshakeTimeout != 0 && *w.HandshakeTimeout < minHandshakeTimeout {
+		return fmt.Errorf("handshake timeout is too short: %s must be at least %s",
+			*w.HandshakeTimeout, minHandshakeTimeout)
+	}
+
 	// Validate interface
 	if !regexpInterfaceName.MatchString(w.Interface) {
 		return fmt.Errorf("interface name is not valid: '%s' does not match regex '%s'", w.Interface, regexpInterfaceName)
@@ -135,6 +146,7 @@ func (w *Wireguard) copy() (copied Wireguard) {
 		PersistentKeepaliveInterval: gosettings.CopyPointer(w.PersistentKeepaliveInterval),
 		Interface:                   w.Interface,
 		MTU:                         w.MTU,
+		HandshakeTimeout:            gosettings.CopyPointer(w.HandshakeTimeout),
 		Implementation:              w.Implementation,
 	}
 }
@@ -148,6 +160,7 @@ func (w *Wireguard) overrideWith(other Wireguard) {
 		other.PersistentKeepaliveInterval)
 	w.Interface = gosettings.OverrideWithComparable(w.Interface, other.Interface)
 	w.MTU = gosettings.OverrideWithComparable(w.MTU, other.MTU)
+	w.HandshakeTimeout = gosettings.OverrideWithPointer(w.HandshakeTimeout, other.HandshakeTimeout)
 	w.Implementation = gosettings.OverrideWithComparable(w.Implementation, other.Implementation)
 }
 
@@ -172,6 +185,7 @@ func (w *Wireguard) setDefaults(vpnProvider string) {
 	w.PersistentKeepaliveInterval = gosettings.DefaultPointer(w.PersistentKeepaliveInterval, 0)
 	w.Interface = gosettings.DefaultComparable(w.Interface, "wg0")
 	w.MTU = gosettings.DefaultPointer(w.MTU, 0)
+	w.HandshakeTimeout = gosettings.DefaultPointer(w.HandshakeTimeout, 0)
 	w.Implementation = gosettings.DefaultComparable(w.Implementation, "auto")
 }
 
@@ -213,6 +227,10 @@ func (w Wireguard) toLinesNode() (node *gotree.Node) {
 		interfaceNode.Appendf("MTU: %d", *w.MTU)
 	}
 
+	if *w.HandshakeTimeout > 0 {
+		node.Appendf("Handshake timeout: %s", *w.HandshakeTimeout)
+	}
+
 	if w.Implementation != "auto" {
 		node.Appendf("Implementation: %s", w.Implementation)
 	}
@@ -265,5 +283,10 @@ func (w *Wireguard) read(r *reader.Reader, amneziaWG bool) (err error) {
 	if err != nil {
 		return err
 	}
+
+	w.HandshakeTimeout, err = r.DurationPtr(prefix + "_HANDSHAKE_TIMEOUT")
+	if err != nil {
+		return err
+	}
 	return nil
 }
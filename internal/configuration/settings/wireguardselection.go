@@ -21,6 +21,11 @@ type WireguardSelection struct {
 	// not be used, it should be set to [netip.IPv4Unspecified].
 	// It can never be the zero value in the internal state.
 	EndpointIP netip.Addr `json:"endpoint_ip"`
+	// EndpointHostname is the server endpoint hostname, resolved
+	// to an IP address each time the VPN connection is started.
+	// It can be set instead of EndpointIP, and can be the empty
+	// string to indicate not to use it.
+	EndpointHostname string `json:"endpoint_hostname"`
 	// EndpointPort is a the server port to use for the VPN server.
 	// It is optional for VPN providers IVPN, Mullvad, Surfshark
 	// and Windscribe, and compulsory for the others.
@@ -44,12 +49,25 @@ func (w WireguardSelection) validate(vpnProvider string) (err error) {
 		providers.Surfshark, providers.Windscribe:
 		// endpoint IP addresses are baked in
 	case providers.Custom:
-		if !w.EndpointIP.IsValid() || w.EndpointIP.IsUnspecified() {
+		if (!w.EndpointIP.IsValid() || w.EndpointIP.IsUnspecified()) &&
+			w.EndpointHostname == "" {
 			return errors.New("endpoint IP is not set")
 		}
 	default: // Providers not supporting Wireguard
 	}
 
+	// Validate EndpointHostname
+	if w.EndpointHostname != "" {
+		if !w.EndpointIP.IsUnspecified() {
+			return fmt.Errorf("endpoint hostname and IP address are both set: %s and %s",
+				w.EndpointHostname, w.EndpointIP)
+		}
+		err = validate.MatchRegex(w.EndpointHostname, hostRegex)
+		if err != nil {
+			return fmt.Errorf("endpoint hostname is not valid: %w", err)
+		}
+	}
+
 	// Validate EndpointPort
 	switch vpnProvider {
 	// EndpointPort is required
@@ -112,14 +130,16 @@ func (w WireguardSelection) validate(vpnProvider string) (err error) {
 
 func (w *WireguardSelection) copy() (copied WireguardSelection) {
 	return WireguardSelection{
-		EndpointIP:   w.EndpointIP,
-		EndpointPort: gosettings.CopyPointer(w.EndpointPort),
-		PublicKey:    w.PublicKey,
+		EndpointIP:       w.EndpointIP,
+		EndpointHostname: w.EndpointHostname,
+		EndpointPort:     gosettings.CopyPointer(w.EndpointPort),
+		PublicKey:        w.PublicKey,
 	}
 }
 
 func (w *WireguardSelection) overrideWith(other WireguardSelection) {
 	w.EndpointIP = gosettings.OverrideWithValidator(w.EndpointIP, other.EndpointIP)
+	w.EndpointHostname = gosettings.OverrideWithComparable(w.EndpointHostname, other.EndpointHostname)
 	w.EndpointPort = gosettings.OverrideWithPointer(w.EndpointPort, other.EndpointPort)
 	w.PublicKey = gosettings.OverrideWithComparable(w.PublicKey, other.PublicKey)
 }
@@ -140,6 +160,10 @@ func (w WireguardSelection) toLinesNode() (node *gotree.Node) {
 		node.Appendf("Endpoint IP address: %s", w.EndpointIP)
 	}
 
+	if w.EndpointHostname != "" {
+		node.Appendf("Endpoint hostname: %s", w.EndpointHostname)
+	}
+
 	if *w.EndpointPort != 0 {
 		node.Appendf("Endpoint port: %d", *w.EndpointPort)
 	}
@@ -159,9 +183,11 @@ func (w *WireguardSelection) read(r *reader.Reader, amneziaWG bool) (err error)
 	w.EndpointIP, err = r.NetipAddr(prefix+"_ENDPOINT_IP", reader.RetroKeys("VPN_ENDPOINT_IP"))
 	if err != nil {
 		return fmt.Errorf("%w - note this MUST be an IP address, "+
-			"see https://github.com/qdm12/gluetun/issues/788", err)
+			"use %s_ENDPOINT_HOSTNAME for a hostname", err, prefix)
 	}
 
+	w.EndpointHostname = r.String(prefix + "_ENDPOINT_HOSTNAME")
+
 	w.EndpointPort, err = r.Uint16Ptr(prefix+"_ENDPOINT_PORT", reader.RetroKeys("VPN_ENDPOINT_PORT"))
 	if err != nil {
 		return err
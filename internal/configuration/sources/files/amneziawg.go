@@ -5,8 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-
-	"gopkg.in/ini.v1"
 )
 
 func (s *Source) lazyLoadAmneziawgConf() AmneziawgConfig {
@@ -20,6 +18,9 @@ func (s *Source) lazyLoadAmneziawgConf() AmneziawgConfig {
 	if err != nil {
 		s.warner.Warnf("skipping Amneziawg config: %s", err)
 	}
+	for _, warning := range s.cached.amneziawgConf.Wireguard.Warnings {
+		s.warner.Warnf("Amneziawg config: %s", warning)
+	}
 	return s.cached.amneziawgConf
 }
 
@@ -44,7 +45,7 @@ type AmneziawgConfig struct {
 }
 
 func ParseAmneziawgConf(path string) (config AmneziawgConfig, err error) {
-	iniFile, err := ini.InsensitiveLoad(path)
+	iniFile, err := loadWireguardINI(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return AmneziawgConfig{}, nil
@@ -52,7 +53,9 @@ func ParseAmneziawgConf(path string) (config AmneziawgConfig, err error) {
 		return AmneziawgConfig{}, fmt.Errorf("loading ini from reader: %w", err)
 	}
 
-	config.Wireguard, err = ParseWireguardConf(path)
+	amneziawgKeys := []string{"Jc", "Jmin", "Jmax", "S1", "S2", "S3", "S4",
+		"H1", "H2", "H3", "H4", "I1", "I2", "I3", "I4", "I5"}
+	config.Wireguard, err = parseWireguardConf(path, amneziawgKeys)
 	if err != nil {
 		return AmneziawgConfig{}, err
 	}
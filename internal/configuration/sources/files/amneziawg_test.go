@@ -52,6 +52,7 @@ PresharedKey = YJ680VN+dGrdsWNjSFqZ6vvwuiNhbq502ZL3G7Q3o3g=
 					PrivateKey:   ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
 					PreSharedKey: ptrTo("YJ680VN+dGrdsWNjSFqZ6vvwuiNhbq502ZL3G7Q3o3g="),
 					Addresses:    ptrTo("10.38.22.35/32"),
+					DNSAddresses: ptrTo("193.138.218.74:53"),
 				},
 				Jc: ptrTo("4"),
 				H1: ptrTo("721391205"),
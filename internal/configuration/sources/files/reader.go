@@ -69,8 +69,21 @@ func (s *Source) Get(key string) (value string, isSet bool) {
 		return strPtrToStringIsSet(s.lazyLoadWireguardConf().PublicKey)
 	case "wireguard_endpoint_ip":
 		return strPtrToStringIsSet(s.lazyLoadWireguardConf().EndpointIP)
+	case "wireguard_endpoint_hostname":
+		return strPtrToStringIsSet(s.lazyLoadWireguardConf().EndpointHostname)
 	case "wireguard_endpoint_port":
 		return strPtrToStringIsSet(s.lazyLoadWireguardConf().EndpointPort)
+	case "wireguard_allowed_ips":
+		return strPtrToStringIsSet(s.lazyLoadWireguardConf().AllowedIPs)
+	case "wireguard_persistent_keepalive_interval":
+		return strPtrToStringIsSet(s.lazyLoadWireguardConf().PersistentKeepaliveInterval)
+	case "wireguard_mtu":
+		return strPtrToStringIsSet(s.lazyLoadWireguardConf().MTU)
+	case "wireguard_dns_addresses", "vpn_up_command", "vpn_down_command":
+		value, isSet := s.getWgQuickKey(key)
+		if isSet {
+			return value, true
+		} // else continue to read from individual file
 	}
 
 	value, isSet, matched := s.getAmneziawgKey(key)
@@ -85,51 +98,83 @@ func (s *Source) Get(key string) (value string, isSet bool) {
 	return value, isSet
 }
 
+// getWgQuickKey returns the value for a key which can be set in a
+// wg-quick configuration file, looking first in the Wireguard
+// configuration and then in the Amneziawg one.
+func (s *Source) getWgQuickKey(key string) (value string, isSet bool) {
+	for _, config := range []WireguardConfig{
+		s.lazyLoadWireguardConf(),
+		s.lazyLoadAmneziawgConf().Wireguard,
+	} {
+		var ptr *string
+		switch key {
+		case "wireguard_dns_addresses":
+			ptr = config.DNSAddresses
+		case "vpn_up_command":
+			ptr = config.UpCommand
+		case "vpn_down_command":
+			ptr = config.DownCommand
+		}
+		if ptr != nil {
+			return *ptr, true
+		}
+	}
+	return "", false
+}
+
 func (s *Source) getAmneziawgKey(key string) (value string, isSet, matched bool) {
 	switch key {
-	case "amnezia_private_key":
+	case "amneziawg_private_key":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.PrivateKey)
-	case "amnezia_preshared_key":
+	case "amneziawg_preshared_key":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.PreSharedKey)
-	case "amnezia_addresses":
+	case "amneziawg_addresses":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.Addresses)
-	case "amnezia_public_key":
+	case "amneziawg_public_key":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.PublicKey)
-	case "amnezia_endpoint_ip":
+	case "amneziawg_endpoint_ip":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.EndpointIP)
-	case "amnezia_endpoint_port":
+	case "amneziawg_endpoint_hostname":
+		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.EndpointHostname)
+	case "amneziawg_endpoint_port":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.EndpointPort)
-	case "amnezia_jc":
+	case "amneziawg_allowed_ips":
+		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.AllowedIPs)
+	case "amneziawg_persistent_keepalive_interval":
+		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.PersistentKeepaliveInterval)
+	case "amneziawg_mtu":
+		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.MTU)
+	case "amneziawg_jc":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Jc)
-	case "amnezia_jmin":
+	case "amneziawg_jmin":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Jmin)
-	case "amnezia_jmax":
+	case "amneziawg_jmax":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Jmax)
-	case "amnezia_s1":
+	case "amneziawg_s1":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().S1)
-	case "amnezia_s2":
+	case "amneziawg_s2":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().S2)
-	case "amnezia_s3":
+	case "amneziawg_s3":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().S3)
-	case "amnezia_s4":
+	case "amneziawg_s4":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().S4)
-	case "amnezia_h1":
+	case "amneziawg_h1":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().H1)
-	case "amnezia_h2":
+	case "amneziawg_h2":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().H2)
-	case "amnezia_h3":
+	case "amneziawg_h3":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().H3)
-	case "amnezia_h4":
+	case "amneziawg_h4":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().H4)
-	case "amnezia_i1":
+	case "amneziawg_i1":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().I1)
-	case "amnezia_i2":
+	case "amneziawg_i2":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().I2)
-	case "amnezia_i3":
+	case "amneziawg_i3":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().I3)
-	case "amnezia_i4":
+	case "amneziawg_i4":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().I4)
-	case "amnezia_i5":
+	case "amneziawg_i5":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().I5)
 	default:
 		return "", false, false
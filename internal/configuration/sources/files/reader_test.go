@@ -0,0 +1,77 @@
+package files
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gosettings/reader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testWarner struct {
+	t *testing.T
+}
+
+func (w *testWarner) Warn(message string) {
+	w.t.Errorf("unexpected warning: %s", message)
+}
+
+func (w *testWarner) Warnf(format string, a ...interface{}) {
+	w.t.Errorf("unexpected warning: "+format, a...)
+}
+
+func Test_Source_amneziawgSettings(t *testing.T) {
+	t.Parallel()
+
+	rootDirectory := t.TempDir()
+	const perm = 0o600
+	err := os.WriteFile(filepath.Join(rootDirectory, "vpn_type"), []byte("amneziawg"), perm)
+	require.NoError(t, err)
+	err = os.Mkdir(filepath.Join(rootDirectory, "amneziawg"), 0o700)
+	require.NoError(t, err)
+	const awgConf = `
+[Interface]
+PrivateKey = QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8=
+Address = 10.38.22.35/32
+MTU = 1320
+Jc = 4
+
+[Peer]
+PublicKey = wPfOExsZMgNjaZx3pcxCrcH3VXZ5wwJf9SOddhGPXD4=
+AllowedIPs = 0.0.0.0/0
+Endpoint = vpn.example.com:51820
+PersistentKeepalive = 25
+`
+	err = os.WriteFile(filepath.Join(rootDirectory, "amneziawg", "awg0.conf"), []byte(awgConf), perm)
+	require.NoError(t, err)
+
+	warner := &testWarner{t: t}
+	source := &Source{
+		rootDirectory: rootDirectory,
+		environ:       map[string]string{},
+		warner:        warner,
+	}
+	r := reader.New(reader.Settings{Sources: []reader.Source{source}})
+
+	var s settings.Settings
+	err = s.Read(r, warner)
+	require.NoError(t, err)
+
+	amneziaWg := s.VPN.AmneziaWg
+	assert.Equal(t, ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="), amneziaWg.Wireguard.PrivateKey)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.38.22.35/32")}, amneziaWg.Wireguard.Addresses)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("0.0.0.0/0")}, amneziaWg.Wireguard.AllowedIPs)
+	assert.Equal(t, ptrTo(uint32(1320)), amneziaWg.Wireguard.MTU)
+	assert.Equal(t, ptrTo(25*time.Second), amneziaWg.Wireguard.PersistentKeepaliveInterval)
+	assert.Equal(t, ptrTo(uint16(4)), amneziaWg.JunkPacketCount)
+
+	selection := s.VPN.Provider.ServerSelection.Wireguard
+	assert.Equal(t, "vpn.example.com", selection.EndpointHostname)
+	assert.Equal(t, ptrTo(uint16(51820)), selection.EndpointPort)
+	assert.Equal(t, "wPfOExsZMgNjaZx3pcxCrcH3VXZ5wwJf9SOddhGPXD4=", selection.PublicKey)
+}
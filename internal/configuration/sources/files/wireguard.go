@@ -4,9 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
 	"gopkg.in/ini.v1"
 )
@@ -22,6 +26,9 @@ func (s *Source) lazyLoadWireguardConf() WireguardConfig {
 	if err != nil {
 		s.warner.Warnf("skipping Wireguard config: %s", err)
 	}
+	for _, warning := range s.cached.wireguardConf.Warnings {
+		s.warner.Warnf("Wireguard config: %s", warning)
+	}
 	return s.cached.wireguardConf
 }
 
@@ -31,13 +38,36 @@ type WireguardConfig struct {
 	Addresses    *string
 	PublicKey    *string
 	EndpointIP   *string
-	EndpointPort *string
+	// EndpointHostname is set instead of EndpointIP
+	// if the endpoint host is not an IP address.
+	EndpointHostname            *string
+	EndpointPort                *string
+	AllowedIPs                  *string
+	PersistentKeepaliveInterval *string
+	MTU                         *string
+	// DNSAddresses is the comma separated list of DNS
+	// address:port from the interface DNS key.
+	DNSAddresses *string
+	UpCommand    *string
+	DownCommand  *string
+	// Warnings are warnings about the configuration file,
+	// such as keys not supported and ignored.
+	Warnings []string
 }
 
 var regexINISectionNotExist = regexp.MustCompile(`^section ".+" does not exist$`)
 
+// ParseWireguardConf parses a wg-quick configuration file.
+// It returns an empty configuration and no error if the file does not exist.
 func ParseWireguardConf(path string) (config WireguardConfig, err error) {
-	iniFile, err := ini.InsensitiveLoad(path)
+	return parseWireguardConf(path, nil)
+}
+
+// parseWireguardConf parses a wg-quick configuration file, where
+// extraInterfaceKeys are additional supported keys of the interface section
+// for which no unsupported key warning should be emitted.
+func parseWireguardConf(path string, extraInterfaceKeys []string) (config WireguardConfig, err error) {
+	iniFile, err := loadWireguardINI(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return WireguardConfig{}, nil
@@ -45,18 +75,25 @@ func ParseWireguardConf(path string) (config WireguardConfig, err error) {
 		return WireguardConfig{}, fmt.Errorf("loading ini from reader: %w", err)
 	}
 
-	interfaceSection, err := iniFile.GetSection("Interface")
+	interfaceSections, err := iniFile.SectionsByName("Interface")
 	if err == nil {
-		config.PrivateKey, config.Addresses = parseWireguardInterfaceSection(interfaceSection)
+		if len(interfaceSections) > 1 {
+			config.Warnings = append(config.Warnings, fmt.Sprintf(
+				"%d [Interface] sections found, only the first one is used", len(interfaceSections)))
+		}
+		parseWireguardInterfaceSection(interfaceSections[0], &config, extraInterfaceKeys)
 	} else if !regexINISectionNotExist.MatchString(err.Error()) {
 		// can never happen
 		return WireguardConfig{}, fmt.Errorf("getting interface section: %w", err)
 	}
 
-	peerSection, err := iniFile.GetSection("Peer")
+	peerSections, err := iniFile.SectionsByName("Peer")
 	if err == nil {
-		config.PreSharedKey, config.PublicKey, config.EndpointIP,
-			config.EndpointPort = parseWireguardPeerSection(peerSection)
+		if len(peerSections) > 1 {
+			config.Warnings = append(config.Warnings, fmt.Sprintf(
+				"%d [Peer] sections found, only the first one is used", len(peerSections)))
+		}
+		parseWireguardPeerSection(peerSections[0], &config)
 	} else if !regexINISectionNotExist.MatchString(err.Error()) {
 		// can never happen
 		return WireguardConfig{}, fmt.Errorf("getting peer section: %w", err)
@@ -65,36 +102,196 @@ func ParseWireguardConf(path string) (config WireguardConfig, err error) {
 	return config, nil
 }
 
-func parseWireguardInterfaceSection(interfaceSection *ini.Section) (
-	privateKey, addresses *string,
-) {
-	privateKey = getINIKeyFromSection(interfaceSection, "PrivateKey")
-	addresses = getINIKeyFromSection(interfaceSection, "Address")
-	return privateKey, addresses
+func loadWireguardINI(path string) (iniFile *ini.File, err error) {
+	options := ini.LoadOptions{
+		Insensitive:            true,
+		AllowShadows:           true,
+		AllowNonUniqueSections: true,
+		// Commands such as PostUp can contain ';' characters,
+		// and '#' comments are stripped in getINIValuesFromSection
+		// the same way wg-quick does.
+		IgnoreInlineComment: true,
+	}
+	return ini.LoadSources(options, path)
 }
 
-func parseWireguardPeerSection(peerSection *ini.Section) (
-	preSharedKey, publicKey, endpointIP, endpointPort *string,
+func parseWireguardInterfaceSection(section *ini.Section,
+	config *WireguardConfig, extraKeys []string,
 ) {
-	preSharedKey = getINIKeyFromSection(peerSection, "PresharedKey")
-	publicKey = getINIKeyFromSection(peerSection, "PublicKey")
-	endpoint := getINIKeyFromSection(peerSection, "Endpoint")
+	config.PrivateKey = getINIKeyFromSection(section, "PrivateKey")
+	config.Addresses = getINIKeyFromSection(section, "Address")
+	config.MTU = getINIKeyFromSection(section, "MTU")
+
+	dns := getINIKeyFromSection(section, "DNS")
+	if dns != nil {
+		var warnings []string
+		config.DNSAddresses, warnings = parseWireguardDNS(*dns)
+		config.Warnings = append(config.Warnings, warnings...)
+	}
+
+	var warning string
+	config.UpCommand, warning = getINICommandFromSection(section, "PostUp")
+	if warning != "" {
+		config.Warnings = append(config.Warnings, warning)
+	}
+
+	config.DownCommand, warning = getINICommandFromSection(section, "PostDown")
+	if warning != "" {
+		config.Warnings = append(config.Warnings, warning)
+	}
+	preDownCommand, warning := getINICommandFromSection(section, "PreDown")
+	switch {
+	case warning != "":
+		config.Warnings = append(config.Warnings, warning)
+	case preDownCommand == nil:
+	case config.DownCommand != nil:
+		config.Warnings = append(config.Warnings,
+			"PreDown is ignored since PostDown is set")
+	default:
+		config.DownCommand = preDownCommand
+		config.Warnings = append(config.Warnings,
+			"PreDown command is run after the VPN connection goes down")
+	}
+
+	table := getINIKeyFromSection(section, "Table")
+	if table != nil && !strings.EqualFold(*table, "auto") {
+		config.Warnings = append(config.Warnings, fmt.Sprintf(
+			"Table = %s is not supported, Gluetun always manages its own routing table", *table))
+	}
+
+	supportedKeys := []string{"PrivateKey", "Address", "MTU", "DNS",
+		"PostUp", "PostDown", "PreDown", "Table"}
+	supportedKeys = append(supportedKeys, extraKeys...)
+	config.Warnings = append(config.Warnings,
+		getUnsupportedINIKeysWarnings(section, "Interface", supportedKeys)...)
+}
+
+func parseWireguardPeerSection(section *ini.Section, config *WireguardConfig) {
+	config.PreSharedKey = getINIKeyFromSection(section, "PresharedKey")
+	config.PublicKey = getINIKeyFromSection(section, "PublicKey")
+	config.AllowedIPs = getINIKeyFromSection(section, "AllowedIPs")
+
+	endpoint := getINIKeyFromSection(section, "Endpoint")
 	if endpoint != nil {
 		host, port, err := net.SplitHostPort(*endpoint)
 		if err == nil {
-			endpointIP = &host
-			endpointPort = &port
+			config.EndpointPort = &port
+		} else {
+			host = *endpoint
+		}
+		_, err = netip.ParseAddr(host)
+		if err == nil {
+			config.EndpointIP = &host
 		} else {
-			endpointIP = endpoint
+			config.EndpointHostname = &host
+		}
+	}
+
+	keepalive := getINIKeyFromSection(section, "PersistentKeepalive")
+	if keepalive != nil {
+		var warning string
+		config.PersistentKeepaliveInterval, warning = parseWireguardKeepalive(*keepalive)
+		if warning != "" {
+			config.Warnings = append(config.Warnings, warning)
 		}
 	}
 
-	return preSharedKey, publicKey, endpointIP, endpointPort
+	supportedKeys := []string{"PresharedKey", "PublicKey", "AllowedIPs",
+		"Endpoint", "PersistentKeepalive"}
+	config.Warnings = append(config.Warnings,
+		getUnsupportedINIKeysWarnings(section, "Peer", supportedKeys)...)
+}
+
+// parseWireguardDNS parses the comma separated DNS value of the interface
+// section into a comma separated list of address:port using port 53.
+// Search domains are not supported and produce a warning.
+func parseWireguardDNS(value string) (addresses *string, warnings []string) {
+	const dnsPort = 53
+	var addrPorts []string
+	for field := range strings.SplitSeq(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		ip, err := netip.ParseAddr(field)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf(
+				"DNS search domain %s is not supported", field))
+			continue
+		}
+		addrPorts = append(addrPorts, netip.AddrPortFrom(ip, dnsPort).String())
+	}
+
+	if len(addrPorts) == 0 {
+		return nil, warnings
+	}
+	joined := strings.Join(addrPorts, ",")
+	return &joined, warnings
+}
+
+// parseWireguardKeepalive converts the PersistentKeepalive value in seconds,
+// or "off", to a duration string.
+func parseWireguardKeepalive(value string) (interval *string, warning string) {
+	if strings.EqualFold(value, "off") {
+		value = "0"
+	}
+	seconds, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		return nil, fmt.Sprintf("PersistentKeepalive value %s is not valid and is ignored", value)
+	}
+	duration := strconv.FormatUint(seconds, 10) + "s"
+	return &duration, ""
+}
+
+// getINICommandFromSection returns the first command for the key given
+// with the wg-quick interface name placeholder %i replaced by {{VPN_INTERFACE}}.
+// A warning is returned if the key is set multiple times.
+func getINICommandFromSection(section *ini.Section, key string) (command *string, warning string) {
+	values := getINIValuesFromSection(section, key)
+	if len(values) == 0 {
+		return nil, ""
+	}
+
+	if len(values) > 1 {
+		warning = fmt.Sprintf("only the first of %d %s commands is used", len(values), key)
+	}
+	first := strings.ReplaceAll(values[0], "%i", "{{VPN_INTERFACE}}")
+	return &first, warning
+}
+
+func getUnsupportedINIKeysWarnings(section *ini.Section, sectionName string,
+	supportedKeys []string,
+) (warnings []string) {
+	for _, key := range section.Keys() {
+		supported := slices.ContainsFunc(supportedKeys, func(supportedKey string) bool {
+			return strings.EqualFold(supportedKey, key.Name())
+		})
+		if supported {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"key %s in [%s] section is not supported and is ignored", key.Name(), sectionName))
+	}
+	return warnings
 }
 
 var regexINIKeyNotExist = regexp.MustCompile(`key ".*" not exists$`)
 
+// getINIKeyFromSection returns the value for the key given, or nil if
+// the key is not set. Multiple values for the same key are joined with commas.
 func getINIKeyFromSection(section *ini.Section, key string) (value *string) {
+	values := getINIValuesFromSection(section, key)
+	if values == nil {
+		return nil
+	}
+	value = new(string)
+	*value = strings.Join(values, ",")
+	return value
+}
+
+// getINIValuesFromSection returns all the values for the key given, stripped of
+// '#' comments, or nil if the key is not set.
+func getINIValuesFromSection(section *ini.Section, key string) (values []string) {
 	iniKey, err := section.GetKey(key)
 	if err != nil {
 		if regexINIKeyNotExist.MatchString(err.Error()) {
@@ -103,7 +300,15 @@ func getINIKeyFromSection(section *ini.Section, key string) (value *string) {
 		// can never happen
 		panic(fmt.Sprintf("getting key %q: %s", key, err))
 	}
-	value = new(string)
-	*value = iniKey.String()
-	return value
+
+	rawValues := iniKey.ValueWithShadows()
+	values = make([]string, 0, len(rawValues))
+	for _, value := range rawValues {
+		value, _, _ = strings.Cut(value, "#")
+		values = append(values, strings.TrimSpace(value))
+	}
+	if len(values) == 0 { // key set to the empty string
+		values = append(values, "")
+	}
+	return values
 }
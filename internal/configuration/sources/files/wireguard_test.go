@@ -68,6 +68,51 @@ PresharedKey = YJ680VN+dGrdsWNjSFqZ6vvwuiNhbq502ZL3G7Q3o3g=
 				PrivateKey:   ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
 				PreSharedKey: ptrTo("YJ680VN+dGrdsWNjSFqZ6vvwuiNhbq502ZL3G7Q3o3g="),
 				Addresses:    ptrTo("10.38.22.35/32"),
+				DNSAddresses: ptrTo("193.138.218.74:53"),
+			},
+		},
+		"wg-quick": {
+			fileContent: `
+[Interface]
+PrivateKey = QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8= # client key
+Address = 10.38.22.35/32
+Address = fd00::2/128
+DNS = 10.64.0.1, fd00::1, example.com
+MTU = 1280
+Table = off
+ListenPort = 51820
+PostUp = iptables -A FORWARD -i %i -j ACCEPT; echo up
+PreDown = echo down
+
+[Peer]
+PublicKey = Yx5LPR9CWIcrg5VzfOG2WW6lMBHBmjAwTJH1kbMgQGs=
+AllowedIPs = 0.0.0.0/0
+AllowedIPs = ::/0
+Endpoint = vpn.example.com:51820
+PersistentKeepalive = 25
+
+[Peer]
+PublicKey = QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8=
+`,
+			wireguard: WireguardConfig{
+				PrivateKey:                  ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
+				Addresses:                   ptrTo("10.38.22.35/32,fd00::2/128"),
+				PublicKey:                   ptrTo("Yx5LPR9CWIcrg5VzfOG2WW6lMBHBmjAwTJH1kbMgQGs="),
+				EndpointHostname:            ptrTo("vpn.example.com"),
+				EndpointPort:                ptrTo("51820"),
+				AllowedIPs:                  ptrTo("0.0.0.0/0,::/0"),
+				PersistentKeepaliveInterval: ptrTo("25s"),
+				MTU:                         ptrTo("1280"),
+				DNSAddresses:                ptrTo("10.64.0.1:53,[fd00::1]:53"),
+				UpCommand:                   ptrTo("iptables -A FORWARD -i {{VPN_INTERFACE}} -j ACCEPT; echo up"),
+				DownCommand:                 ptrTo("echo down"),
+				Warnings: []string{
+					"DNS search domain example.com is not supported",
+					"PreDown command is run after the VPN connection goes down",
+					"Table = off is not supported, Gluetun always manages its own routing table",
+					"key listenport in [Interface] section is not supported and is ignored",
+					"2 [Peer] sections found, only the first one is used",
+				},
 			},
 		},
 	}
@@ -97,9 +142,9 @@ func Test_parseWireguardInterfaceSection(t *testing.T) {
 	t.Parallel()
 
 	testCases := map[string]struct {
-		iniData    string
-		privateKey *string
-		addresses  *string
+		iniData   string
+		extraKeys []string
+		config    WireguardConfig
 	}{
 		"no_fields": {
 			iniData: `[Interface]`,
@@ -108,7 +153,9 @@ func Test_parseWireguardInterfaceSection(t *testing.T) {
 			iniData: `[Interface]
 PrivateKey = x
 `,
-			privateKey: ptrTo("x"),
+			config: WireguardConfig{
+				PrivateKey: ptrTo("x"),
+			},
 		},
 		"all_fields": {
 			iniData: `
@@ -116,8 +163,41 @@ PrivateKey = x
 PrivateKey = QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8=
 Address = 10.38.22.35/32
 `,
-			privateKey: ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
-			addresses:  ptrTo("10.38.22.35/32"),
+			config: WireguardConfig{
+				PrivateKey: ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
+				Addresses:  ptrTo("10.38.22.35/32"),
+			},
+		},
+		"commands": {
+			iniData: `
+[Interface]
+PostUp = echo 1
+PostUp = echo 2
+PostDown = echo 3
+PreDown = echo 4
+`,
+			config: WireguardConfig{
+				UpCommand:   ptrTo("echo 1"),
+				DownCommand: ptrTo("echo 3"),
+				Warnings: []string{
+					"only the first of 2 PostUp commands is used",
+					"PreDown is ignored since PostDown is set",
+				},
+			},
+		},
+		"unsupported_keys": {
+			iniData: `
+[Interface]
+Table = auto
+FwMark = 51820
+Jc = 4
+`,
+			extraKeys: []string{"Jc"},
+			config: WireguardConfig{
+				Warnings: []string{
+					"key fwmark in [Interface] section is not supported and is ignored",
+				},
+			},
 		},
 	}
 
@@ -125,15 +205,18 @@ Address = 10.38.22.35/32
 		t.Run(testName, func(t *testing.T) {
 			t.Parallel()
 
-			iniFile, err := ini.Load([]byte(testCase.iniData))
+			iniFile, err := ini.LoadSources(ini.LoadOptions{
+				Insensitive:  true,
+				AllowShadows: true,
+			}, []byte(testCase.iniData))
 			require.NoError(t, err)
 			iniSection, err := iniFile.GetSection("Interface")
 			require.NoError(t, err)
 
-			privateKey, addresses := parseWireguardInterfaceSection(iniSection)
+			var config WireguardConfig
+			parseWireguardInterfaceSection(iniSection, &config, testCase.extraKeys)
 
-			assert.Equal(t, testCase.privateKey, privateKey)
-			assert.Equal(t, testCase.addresses, addresses)
+			assert.Equal(t, testCase.config, config)
 		})
 	}
 }
@@ -142,48 +225,69 @@ func Test_parseWireguardPeerSection(t *testing.T) {
 	t.Parallel()
 
 	testCases := map[string]struct {
-		iniData      string
-		preSharedKey *string
-		publicKey    *string
-		endpointIP   *string
-		endpointPort *string
-		errMessage   string
+		iniData string
+		config  WireguardConfig
 	}{
 		"public key set": {
 			iniData: `[Peer]
 PublicKey = QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8=`,
-			publicKey: ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
+			config: WireguardConfig{
+				PublicKey: ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
+			},
 		},
 		"endpoint_only_host": {
 			iniData: `[Peer]
 Endpoint = x`,
-			endpointIP: ptrTo("x"),
+			config: WireguardConfig{
+				EndpointHostname: ptrTo("x"),
+			},
 		},
 		"endpoint_no_port": {
 			iniData: `[Peer]
 Endpoint = x:`,
-			endpointIP:   ptrTo("x"),
-			endpointPort: ptrTo(""),
+			config: WireguardConfig{
+				EndpointHostname: ptrTo("x"),
+				EndpointPort:     ptrTo(""),
+			},
 		},
 		"valid_endpoint": {
 			iniData: `[Peer]
 Endpoint = 1.2.3.4:51820`,
-			endpointIP:   ptrTo("1.2.3.4"),
-			endpointPort: ptrTo("51820"),
+			config: WireguardConfig{
+				EndpointIP:   ptrTo("1.2.3.4"),
+				EndpointPort: ptrTo("51820"),
+			},
 		},
 		"all_set": {
 			iniData: `[Peer]
 PublicKey = QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8=
-Endpoint = 1.2.3.4:51820`,
-			publicKey:    ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
-			endpointIP:   ptrTo("1.2.3.4"),
-			endpointPort: ptrTo("51820"),
+Endpoint = 1.2.3.4:51820
+AllowedIPs = 0.0.0.0/0, ::/0
+PersistentKeepalive = off`,
+			config: WireguardConfig{
+				PublicKey:                   ptrTo("QOlCgyA/Sn/c/+YNTIEohrjm8IZV+OZ2AUFIoX20sk8="),
+				EndpointIP:                  ptrTo("1.2.3.4"),
+				EndpointPort:                ptrTo("51820"),
+				AllowedIPs:                  ptrTo("0.0.0.0/0, ::/0"),
+				PersistentKeepaliveInterval: ptrTo("0s"),
+			},
 		},
 		"ipv6_endpoint": {
 			iniData: `[Peer]
 Endpoint = [2a02:bbbb:aaaa:8075::10]:51820`,
-			endpointIP:   ptrTo("2a02:bbbb:aaaa:8075::10"),
-			endpointPort: ptrTo("51820"),
+			config: WireguardConfig{
+				EndpointIP:   ptrTo("2a02:bbbb:aaaa:8075::10"),
+				EndpointPort: ptrTo("51820"),
+			},
+		},
+		"invalid_keepalive": {
+			iniData: `[Peer]
+PersistentKeepalive = 25s`,
+			config: WireguardConfig{
+				Warnings: []string{
+					"PersistentKeepalive value 25s is not valid and is ignored",
+				},
+			},
 		},
 	}
 
@@ -196,18 +300,41 @@ Endpoint = [2a02:bbbb:aaaa:8075::10]:51820`,
 			iniSection, err := iniFile.GetSection("Peer")
 			require.NoError(t, err)
 
-			preSharedKey, publicKey, endpointIP,
-				endpointPort := parseWireguardPeerSection(iniSection)
+			var config WireguardConfig
+			parseWireguardPeerSection(iniSection, &config)
 
-			assert.Equal(t, testCase.preSharedKey, preSharedKey)
-			assert.Equal(t, testCase.publicKey, publicKey)
-			assert.Equal(t, testCase.endpointIP, endpointIP)
-			assert.Equal(t, testCase.endpointPort, endpointPort)
-			if testCase.errMessage != "" {
-				assert.EqualError(t, err, testCase.errMessage)
-			} else {
-				assert.NoError(t, err)
-			}
+			assert.Equal(t, testCase.config, config)
+		})
+	}
+}
+
+func Test_parseWireguardDNS(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		value     string
+		addresses *string
+		warnings  []string
+	}{
+		"empty": {},
+		"ipv4_and_ipv6": {
+			value:     "1.1.1.1,2606:4700:4700::1111",
+			addresses: ptrTo("1.1.1.1:53,[2606:4700:4700::1111]:53"),
+		},
+		"only_search_domain": {
+			value:    "home.arpa",
+			warnings: []string{"DNS search domain home.arpa is not supported"},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			addresses, warnings := parseWireguardDNS(testCase.value)
+
+			assert.Equal(t, testCase.addresses, addresses)
+			assert.Equal(t, testCase.warnings, warnings)
 		})
 	}
 }
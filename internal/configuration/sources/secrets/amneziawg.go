@@ -23,5 +23,8 @@ func (s *Source) lazyLoadAmneziawgConf() files.AmneziawgConfig {
 	if err != nil {
 		s.warner.Warnf("skipping Amneziawg config: %s", err)
 	}
+	for _, warning := range s.cached.amneziawgConf.Wireguard.Warnings {
+		s.warner.Warnf("Amneziawg config: %s", warning)
+	}
 	return s.cached.amneziawgConf
 }
@@ -81,8 +81,21 @@ func (s *Source) Get(key string) (value string, isSet bool) {
 		return strPtrToStringIsSet(s.lazyLoadWireguardConf().PublicKey)
 	case "wireguard_endpoint_ip":
 		return strPtrToStringIsSet(s.lazyLoadWireguardConf().EndpointIP)
+	case "wireguard_endpoint_hostname":
+		return strPtrToStringIsSet(s.lazyLoadWireguardConf().EndpointHostname)
 	case "wireguard_endpoint_port":
 		return strPtrToStringIsSet(s.lazyLoadWireguardConf().EndpointPort)
+	case "wireguard_allowed_ips":
+		return strPtrToStringIsSet(s.lazyLoadWireguardConf().AllowedIPs)
+	case "wireguard_persistent_keepalive_interval":
+		return strPtrToStringIsSet(s.lazyLoadWireguardConf().PersistentKeepaliveInterval)
+	case "wireguard_mtu":
+		return strPtrToStringIsSet(s.lazyLoadWireguardConf().MTU)
+	case "wireguard_dns_addresses", "vpn_up_command", "vpn_down_command":
+		value, isSet := s.getWgQuickKey(key)
+		if isSet {
+			return value, true
+		} // else continue to read from individual secret file
 	}
 
 	value, isSet, matched := s.getAmneziaWg(key)
@@ -112,6 +125,30 @@ func (s *Source) KeyTransform(key string) string {
 	}
 }
 
+// getWgQuickKey returns the value for a key which can be set in a
+// wg-quick configuration secret file, looking first in the Wireguard
+// configuration and then in the Amneziawg one.
+func (s *Source) getWgQuickKey(key string) (value string, isSet bool) {
+	for _, config := range []files.WireguardConfig{
+		s.lazyLoadWireguardConf(),
+		s.lazyLoadAmneziawgConf().Wireguard,
+	} {
+		var ptr *string
+		switch key {
+		case "wireguard_dns_addresses":
+			ptr = config.DNSAddresses
+		case "vpn_up_command":
+			ptr = config.UpCommand
+		case "vpn_down_command":
+			ptr = config.DownCommand
+		}
+		if ptr != nil {
+			return *ptr, true
+		}
+	}
+	return "", false
+}
+
 func (s *Source) getAmneziaWg(key string) (value string, isSet, matched bool) {
 	switch key {
 	case "amneziawg_private_key":
@@ -124,8 +161,16 @@ func (s *Source) getAmneziaWg(key string) (value string, isSet, matched bool) {
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.PublicKey)
 	case "amneziawg_endpoint_ip":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.EndpointIP)
+	case "amneziawg_endpoint_hostname":
+		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.EndpointHostname)
 	case "amneziawg_endpoint_port":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.EndpointPort)
+	case "amneziawg_allowed_ips":
+		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.AllowedIPs)
+	case "amneziawg_persistent_keepalive_interval":
+		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.PersistentKeepaliveInterval)
+	case "amneziawg_mtu":
+		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Wireguard.MTU)
 	case "amneziawg_jc":
 		value, isSet = strPtrToStringIsSet(s.lazyLoadAmneziawgConf().Jc)
 	case "amneziawg_jmin":
@@ -23,5 +23,8 @@ func (s *Source) lazyLoadWireguardConf() files.WireguardConfig {
 	if err != nil {
 		s.warner.Warnf("skipping Wireguard config: %s", err)
 	}
+	for _, warning := range s.cached.wireguardConf.Warnings {
+		s.warner.Warnf("Wireguard config: %s", warning)
+	}
 	return s.cached.wireguardConf
 }
@@ -0,0 +1,214 @@
+// Package cron implements parsing of standard 5 fields cron
+// expressions and computing their next activation time.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule.
+type Schedule struct {
+	minutes, hours, daysOfMonth, months, daysOfWeek uint64
+	// daysOfMonthAny and daysOfWeekAny are used to apply the
+	// standard cron logic where, if both fields are restricted,
+	// a day matches if it matches any of the two fields.
+	daysOfMonthAny, daysOfWeekAny bool
+}
+
+type bounds struct {
+	name     string
+	min, max uint
+}
+
+var (
+	minuteBounds     = bounds{name: "minute", min: 0, max: 59}       //nolint:gochecknoglobals
+	hourBounds       = bounds{name: "hour", min: 0, max: 23}         //nolint:gochecknoglobals
+	dayOfMonthBounds = bounds{name: "day of month", min: 1, max: 31} //nolint:gochecknoglobals
+	monthBounds      = bounds{name: "month", min: 1, max: 12}        //nolint:gochecknoglobals
+	// Day of week 7 is also Sunday and is converted to 0.
+	dayOfWeekBounds = bounds{name: "day of week", min: 0, max: 7} //nolint:gochecknoglobals
+)
+
+var (
+	ErrFieldsCount   = errors.New("expression does not have 5 fields")
+	ErrValueNotValid = errors.New("value is not valid")
+	ErrOutOfBounds   = errors.New("value is out of bounds")
+	ErrRangeNotValid = errors.New("range is not valid")
+	ErrStepNotValid  = errors.New("step is not valid")
+)
+
+// Parse parses a standard cron expression with 5 space separated fields:
+// minute, hour, day of month, month and day of week.
+// Each field can be '*', a value, a range 'a-b', a step '*/n' or 'a-b/n',
+// or a comma separated list of these. The shortcuts @hourly, @daily,
+// @midnight, @weekly, @monthly, @yearly and @annually are also supported.
+func Parse(expression string) (schedule Schedule, err error) {
+	switch strings.TrimSpace(expression) {
+	case "@hourly":
+		expression = "0 * * * *"
+	case "@daily", "@midnight":
+		expression = "0 0 * * *"
+	case "@weekly":
+		expression = "0 0 * * 0"
+	case "@monthly":
+		expression = "0 0 1 * *"
+	case "@yearly", "@annually":
+		expression = "0 0 1 1 *"
+	}
+
+	fields := strings.Fields(expression)
+	const expectedFields = 5
+	if len(fields) != expectedFields {
+		return schedule, fmt.Errorf("%w: %d fields in %q",
+			ErrFieldsCount, len(fields), expression)
+	}
+
+	schedule.minutes, err = parseField(fields[0], minuteBounds)
+	if err != nil {
+		return schedule, err
+	}
+	schedule.hours, err = parseField(fields[1], hourBounds)
+	if err != nil {
+		return schedule, err
+	}
+	schedule.daysOfMonth, err = parseField(fields[2], dayOfMonthBounds)
+	if err != nil {
+		return schedule, err
+	}
+	schedule.months, err = parseField(fields[3], monthBounds)
+	if err != nil {
+		return schedule, err
+	}
+	schedule.daysOfWeek, err = parseField(fields[4], dayOfWeekBounds)
+	if err != nil {
+		return schedule, err
+	}
+
+	const sunday, sundayAlias = 0, 7
+	if schedule.daysOfWeek&(1<<sundayAlias) != 0 {
+		schedule.daysOfWeek |= 1 << sunday
+		schedule.daysOfWeek &^= 1 << sundayAlias
+	}
+
+	schedule.daysOfMonthAny = fields[2] == "*"
+	schedule.daysOfWeekAny = fields[4] == "*"
+
+	return schedule, nil
+}
+
+func parseField(field string, b bounds) (bits uint64, err error) {
+	for part := range strings.SplitSeq(field, ",") {
+		partBits, err := parsePart(part, b)
+		if err != nil {
+			return 0, fmt.Errorf("%s field: %w", b.name, err)
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+func parsePart(part string, b bounds) (bits uint64, err error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := uint(1)
+	if hasStep {
+		step, err = parseValue(stepPart)
+		if err != nil || step == 0 {
+			return 0, fmt.Errorf("%w: %q", ErrStepNotValid, stepPart)
+		}
+	}
+
+	start, end := b.min, b.max
+	switch {
+	case rangePart == "*":
+	case strings.Contains(rangePart, "-"):
+		startString, endString, _ := strings.Cut(rangePart, "-")
+		start, err = parseBoundedValue(startString, b)
+		if err != nil {
+			return 0, err
+		}
+		end, err = parseBoundedValue(endString, b)
+		if err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("%w: %q", ErrRangeNotValid, rangePart)
+		}
+	default:
+		start, err = parseBoundedValue(rangePart, b)
+		if err != nil {
+			return 0, err
+		}
+		if !hasStep {
+			end = start
+		}
+	}
+
+	for value := start; value <= end; value += step {
+		bits |= 1 << value
+	}
+	return bits, nil
+}
+
+func parseBoundedValue(s string, b bounds) (value uint, err error) {
+	value, err = parseValue(s)
+	if err != nil {
+		return 0, err
+	}
+	if value < b.min || value > b.max {
+		return 0, fmt.Errorf("%w: %d must be between %d and %d",
+			ErrOutOfBounds, value, b.min, b.max)
+	}
+	return value, nil
+}
+
+func parseValue(s string) (value uint, err error) {
+	parsed, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrValueNotValid, s)
+	}
+	return uint(parsed), nil
+}
+
+// Next returns the next time strictly after the time given matching
+// the schedule, in the location of the time given. It returns the zero
+// time if no matching time is found within the next 5 years, for example
+// for the schedule "0 0 30 2 *".
+func (s Schedule) Next(t time.Time) (next time.Time) {
+	const searchYears = 5
+	limit := t.AddDate(searchYears, 0, 0)
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		switch {
+		case !has(s.months, uint(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchesDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !has(s.hours, uint(t.Hour())):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !has(s.minutes, uint(t.Minute())):
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s Schedule) matchesDay(t time.Time) bool {
+	dayOfMonth := has(s.daysOfMonth, uint(t.Day()))
+	dayOfWeek := has(s.daysOfWeek, uint(t.Weekday()))
+	if s.daysOfMonthAny || s.daysOfWeekAny {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func has(bits uint64, value uint) bool {
+	return bits&(1<<value) != 0
+}
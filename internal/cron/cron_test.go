@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		errWrapped error
+		errMessage string
+	}{
+		"valid": {
+			expression: "*/15 1-5,22 * * 1-5",
+		},
+		"shortcut": {
+			expression: "@daily",
+		},
+		"fields_count": {
+			expression: "* * * *",
+			errWrapped: ErrFieldsCount,
+			errMessage: `expression does not have 5 fields: 4 fields in "* * * *"`,
+		},
+		"value_not_valid": {
+			expression: "x * * * *",
+			errWrapped: ErrValueNotValid,
+			errMessage: `minute field: value is not valid: "x"`,
+		},
+		"out_of_bounds": {
+			expression: "0 24 * * *",
+			errWrapped: ErrOutOfBounds,
+			errMessage: "hour field: value is out of bounds: 24 must be between 0 and 23",
+		},
+		"range_not_valid": {
+			expression: "0 0 5-1 * *",
+			errWrapped: ErrRangeNotValid,
+			errMessage: `day of month field: range is not valid: "5-1"`,
+		},
+		"step_not_valid": {
+			expression: "*/0 * * * *",
+			errWrapped: ErrStepNotValid,
+			errMessage: `minute field: step is not valid: "0"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := Parse(testCase.expression)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Schedule_Next(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday 15th of January 2025
+	start := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	testCases := map[string]struct {
+		expression string
+		next       time.Time
+	}{
+		"every_minute": {
+			expression: "* * * * *",
+			next:       time.Date(2025, time.January, 15, 10, 8, 0, 0, time.UTC),
+		},
+		"every_15_minutes": {
+			expression: "*/15 * * * *",
+			next:       time.Date(2025, time.January, 15, 10, 15, 0, 0, time.UTC),
+		},
+		"hourly": {
+			expression: "@hourly",
+			next:       time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC),
+		},
+		"daily_at_3": {
+			expression: "0 3 * * *",
+			next:       time.Date(2025, time.January, 16, 3, 0, 0, 0, time.UTC),
+		},
+		"sunday_alias": {
+			expression: "30 4 * * 7",
+			next:       time.Date(2025, time.January, 19, 4, 30, 0, 0, time.UTC),
+		},
+		"day_of_month_or_day_of_week": {
+			expression: "0 0 20 * 5",
+			next:       time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC),
+		},
+		"next_year": {
+			expression: "0 0 1 1 *",
+			next:       time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"leap_day": {
+			expression: "0 0 29 2 *",
+			next:       time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		"never": {
+			expression: "0 0 30 2 *",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := Parse(testCase.expression)
+			require.NoError(t, err)
+
+			next := schedule.Next(start)
+
+			assert.Equal(t, testCase.next, next)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package dns
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// providerEgressPrefixes returns the IP prefixes the recursive resolvers
+// of the given DNS provider query authoritative servers from, which are
+// the resolver addresses a DNS leak check sees. It returns false if
+// these prefixes are not known for the provider.
+func providerEgressPrefixes(providerName string) (prefixes []netip.Prefix, ok bool) {
+	var cidrs []string
+	switch strings.ToLower(providerName) {
+	case "cloudflare", "cloudflare family", "cloudflare security":
+		// See https://www.cloudflare.com/ips/
+		cidrs = []string{
+			"173.245.48.0/20", "103.21.244.0/22", "103.22.200.0/22",
+			"103.31.4.0/22", "141.101.64.0/18", "108.162.192.0/18",
+			"190.93.240.0/20", "188.114.96.0/20", "197.234.240.0/22",
+			"198.41.128.0/17", "162.158.0.0/15", "104.16.0.0/13",
+			"104.24.0.0/14", "172.64.0.0/13", "131.0.72.0/22",
+			"2400:cb00::/32", "2606:4700::/32", "2803:f800::/32",
+			"2405:b500::/32", "2405:8100::/32", "2a06:98c0::/29",
+			"2c0f:f248::/32",
+		}
+	case "google":
+		// See https://developers.google.com/speed/public-dns/faq#locations
+		cidrs = []string{
+			"74.125.0.0/16", "172.217.0.0/16", "172.253.0.0/16",
+			"173.194.0.0/16", "2001:4860::/32", "2404:6800::/32",
+			"2607:f8b0::/32", "2800:3f0::/32", "2a00:1450::/32",
+			"2c0f:fb50::/32",
+		}
+	default:
+		return nil, false
+	}
+
+	prefixes = make([]netip.Prefix, len(cidrs))
+	for i, cidr := range cidrs {
+		prefixes[i] = netip.MustParsePrefix(cidr)
+	}
+	return prefixes, true
+}
@@ -3,15 +3,46 @@ package dns
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand/v2"
 	"net/http"
-	"sort"
+	"net/netip"
+	"slices"
 	"strings"
+
+	"github.com/qdm12/dns/v2/pkg/provider"
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
 )
 
-func leakCheck(ctx context.Context, client *http.Client) (report string, err error) {
+// LeakChecker is a DNS leak check service.
+type LeakChecker interface {
+	// Check returns the resolver IP addresses seen by the service
+	// mapped to their respective number of DNS queries seen.
+	Check(ctx context.Context) (ipToCount map[string]uint, err error)
+}
+
+// IPLeak is the [LeakChecker] implementation using ipleak.net.
+type IPLeak struct {
+	client  *http.Client
+	makeURL func(session, randomPart string) string
+}
+
+// NewIPLeak creates a [LeakChecker] using ipleak.net.
+func NewIPLeak(client *http.Client) *IPLeak {
+	return &IPLeak{
+		client: client,
+		makeURL: func(session, randomPart string) string {
+			return fmt.Sprintf("https://%s-%s.ipleak.net/dnsdetection/", session, randomPart)
+		},
+	}
+}
+
+// Check triggers several DNS queries for the same session and
+// returns the resolver IP addresses seen with their number of queries.
+func (i *IPLeak) Check(ctx context.Context) (ipToCount map[string]uint, err error) {
 	const sessionLength = 40
 	session := generateRandomString(sessionLength)
 
@@ -26,12 +57,12 @@ func leakCheck(ctx context.Context, client *http.Client) (report string, err err
 	const requestsCount = 5
 	for range requestsCount {
 		go func() {
-			dnsToCount, err := triggerDNSQuery(ctx, client, session)
+			dnsToCount, err := i.triggerDNSQuery(ctx, session)
 			resultsCh <- result{dnsToCount: dnsToCount, err: err}
 		}()
 	}
 
-	dnsToCount := make(map[string]uint)
+	ipToCount = make(map[string]uint)
 	for range requestsCount {
 		result := <-resultsCh
 		if result.err != nil {
@@ -42,15 +73,15 @@ func leakCheck(ctx context.Context, client *http.Client) (report string, err err
 			continue
 		}
 		for dns, count := range result.dnsToCount {
-			dnsToCount[dns] += count
+			ipToCount[dns] += count
 		}
 	}
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return formatPercentages(dnsToCount), nil
+	return ipToCount, nil
 }
 
 func generateRandomString(length uint) string {
@@ -62,18 +93,18 @@ func generateRandomString(length uint) string {
 	return string(b)
 }
 
-func triggerDNSQuery(ctx context.Context, client *http.Client, session string) (
+func (i *IPLeak) triggerDNSQuery(ctx context.Context, session string) (
 	dnsToCount map[string]uint, err error,
 ) {
 	const randomLength = 12
 	randomPart := generateRandomString(randomLength)
-	url := fmt.Sprintf("https://%s-%s.ipleak.net/dnsdetection/", session, randomPart)
+	url := i.makeURL(session, randomPart)
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
-	response, err := client.Do(request)
+	response, err := i.client.Do(request)
 	if err != nil {
 		return nil, fmt.Errorf("performing request: %w", err)
 	}
@@ -96,33 +127,172 @@ func triggerDNSQuery(ctx context.Context, client *http.Client, session string) (
 	return data.IP, nil
 }
 
-func formatPercentages(data map[string]uint) string {
-	if len(data) == 0 {
-		return ""
+var ErrLeakCheckDisabled = errors.New("DNS leak check is disabled")
+
+// CheckLeak runs the DNS leak check and compares the resolvers seen
+// against the expected resolvers. The resulting report is stored
+// and can be obtained with [Loop.GetLeakReport].
+// An error is returned only if the leak check could not run,
+// and a DNS leak is indicated by the report Leaking field.
+func (l *Loop) CheckLeak(ctx context.Context) (report models.DNSLeakReport, err error) {
+	settings := l.GetSettings()
+	if !*settings.LeakCheck.Enabled {
+		return models.DNSLeakReport{}, ErrLeakCheckDisabled
+	}
+
+	ipToCount, err := l.leakChecker.Check(ctx)
+	report.Time = l.timeNow()
+	if err != nil {
+		err = fmt.Errorf("running leak check: %w", err)
+		report.Error = err.Error()
+		l.setLeakReport(report)
+		l.logger.Warn(err.Error())
+		return report, err
+	}
+
+	expected := expectedResolvers(settings)
+	report.Checked = len(expected) > 0
+	report.Resolvers, report.Leaking = makeLeakResolvers(ipToCount, expected, l.logger)
+	l.setLeakReport(report)
+
+	switch {
+	case report.Leaking:
+		l.logger.Warnf("DNS leak detected: %s", formatLeakResolvers(report.Resolvers, report.Checked))
+	default:
+		l.logger.Infof("leak check report: %s", formatLeakResolvers(report.Resolvers, report.Checked))
+	}
+	return report, nil
+}
+
+// GetLeakReport returns the report from the last DNS leak check.
+// It is notably used by the HTTP control server.
+func (l *Loop) GetLeakReport() (report models.DNSLeakReport) {
+	l.leakReportMu.RLock()
+	defer l.leakReportMu.RUnlock()
+	report = l.leakReport
+	report.Resolvers = slices.Clone(l.leakReport.Resolvers)
+	return report
+}
+
+func (l *Loop) setLeakReport(report models.DNSLeakReport) {
+	l.leakReportMu.Lock()
+	defer l.leakReportMu.Unlock()
+	l.leakReport = report
+}
+
+// expectedResolvers returns the prefixes resolvers seen should belong to,
+// which are the user specified expected resolvers, the upstream plain
+// addresses if the upstream type is plain and they are set, or the
+// addresses and known resolver egress prefixes of the upstream providers.
+// It returns nil if no expected resolvers can be determined.
+func expectedResolvers(userSettings settings.DNS) (prefixes []netip.Prefix) {
+	if len(userSettings.LeakCheck.ExpectedResolvers) > 0 {
+		return userSettings.LeakCheck.ExpectedResolvers
+	}
+
+	if userSettings.UpstreamType == settings.DNSUpstreamTypePlain &&
+		len(userSettings.UpstreamPlainAddresses) > 0 {
+		prefixes = make([]netip.Prefix, len(userSettings.UpstreamPlainAddresses))
+		for i, addrPort := range userSettings.UpstreamPlainAddresses {
+			prefixes[i] = addrToPrefix(addrPort.Addr())
+		}
+		return prefixes
 	}
 
+	providersData := provider.NewProviders()
+	for _, providerName := range userSettings.Providers {
+		upstreamProvider, err := providersData.Get(providerName)
+		if err != nil {
+			return nil
+		}
+		egressPrefixes, ok := providerEgressPrefixes(upstreamProvider.Name)
+		if !ok {
+			// Resolvers seen are the provider egress resolvers and
+			// not the addresses we connect to, so a leak cannot
+			// be detected without knowing the egress prefixes.
+			return nil
+		}
+		prefixes = append(prefixes, egressPrefixes...)
+		for _, addr := range providerAddresses(upstreamProvider, userSettings.UpstreamType) {
+			prefixes = append(prefixes, addrToPrefix(addr))
+		}
+	}
+	return prefixes
+}
+
+func providerAddresses(upstreamProvider provider.Provider, upstreamType string) (addresses []netip.Addr) {
+	switch upstreamType {
+	case settings.DNSUpstreamTypeDot:
+		for _, addrPort := range slices.Concat(upstreamProvider.DoT.IPv4, upstreamProvider.DoT.IPv6) {
+			addresses = append(addresses, addrPort.Addr())
+		}
+	case settings.DNSUpstreamTypeDoh:
+		addresses = slices.Concat(upstreamProvider.DoH.IPv4, upstreamProvider.DoH.IPv6)
+	case settings.DNSUpstreamTypePlain:
+		for _, addrPort := range slices.Concat(upstreamProvider.Plain.IPv4, upstreamProvider.Plain.IPv6) {
+			addresses = append(addresses, addrPort.Addr())
+		}
+	}
+	return addresses
+}
+
+func addrToPrefix(addr netip.Addr) netip.Prefix {
+	return netip.PrefixFrom(addr, addr.BitLen())
+}
+
+func makeLeakResolvers(ipToCount map[string]uint, expected []netip.Prefix,
+	logger Logger,
+) (resolvers []models.DNSLeakResolver, leaking bool) {
 	var total uint
-	keys := make([]string, 0, len(data))
-	for k, v := range data {
-		total += v
-		keys = append(keys, k)
+	for _, count := range ipToCount {
+		total += count
 	}
 
-	sort.Slice(keys, func(i, j int) bool {
-		if data[keys[i]] == data[keys[j]] {
-			return keys[i] < keys[j] // Tie-breaker: alphabetical
+	resolvers = make([]models.DNSLeakResolver, 0, len(ipToCount))
+	ipToResolverCount := make(map[netip.Addr]uint, len(ipToCount))
+	for ipString, count := range ipToCount {
+		ip, err := netip.ParseAddr(ipString)
+		if err != nil {
+			logger.Debug("ignoring leak check resolver: " + err.Error())
+			continue
 		}
-		return data[keys[i]] > data[keys[j]]
-	})
+		ipToResolverCount[ip] = count
 
-	results := make([]string, len(keys))
-	for i, key := range keys {
-		var pct float64
+		resolver := models.DNSLeakResolver{IP: ip}
 		if total > 0 {
-			pct = math.Ceil((float64(data[key]) / float64(total)) * 100) //nolint:mnd
+			resolver.Percent = uint(math.Ceil((float64(count) / float64(total)) * 100)) //nolint:mnd
 		}
-		results[i] = fmt.Sprintf("%s (%.0f%%)", key, pct)
+		if len(expected) > 0 {
+			resolver.Expected = slices.ContainsFunc(expected, func(prefix netip.Prefix) bool {
+				return prefix.Contains(ip)
+			})
+			if !resolver.Expected {
+				leaking = true
+			}
+		}
+		resolvers = append(resolvers, resolver)
 	}
 
+	slices.SortFunc(resolvers, func(a, b models.DNSLeakResolver) int {
+		countA, countB := ipToResolverCount[a.IP], ipToResolverCount[b.IP]
+		if countA == countB {
+			return a.IP.Compare(b.IP) // Tie-breaker: IP address order
+		} else if countA > countB {
+			return -1
+		}
+		return 1
+	})
+
+	return resolvers, leaking
+}
+
+func formatLeakResolvers(resolvers []models.DNSLeakResolver, checked bool) string {
+	results := make([]string, len(resolvers))
+	for i, resolver := range resolvers {
+		results[i] = fmt.Sprintf("%s (%d%%)", resolver.IP, resolver.Percent)
+		if checked && !resolver.Expected {
+			results[i] += " unexpected"
+		}
+	}
 	return strings.Join(results, ", ")
 }
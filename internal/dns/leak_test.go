@@ -2,21 +2,210 @@ package dns
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func Test_leakCheck(t *testing.T) {
+func Test_IPLeak_Check(t *testing.T) {
 	t.Parallel()
 
 	const timeout = 10 * time.Second
 	ctx, cancel := context.WithTimeout(t.Context(), timeout)
 	t.Cleanup(cancel)
-	client := http.DefaultClient
-	report, err := leakCheck(ctx, client)
+	checker := NewIPLeak(http.DefaultClient)
+	ipToCount, err := checker.Check(ctx)
 	require.NoError(t, err)
-	require.NotEmpty(t, report)
+	require.NotEmpty(t, ipToCount)
+}
+
+func Test_IPLeak_Check_standIn(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := strings.TrimPrefix(r.URL.Path, "/")
+		data := struct {
+			Session string          `json:"session"`
+			IP      map[string]uint `json:"ip"`
+		}{
+			Session: session,
+			IP:      map[string]uint{"1.1.1.1": 1, "9.9.9.9": 2},
+		}
+		_ = json.NewEncoder(w).Encode(data)
+	}))
+	t.Cleanup(server.Close)
+
+	checker := &IPLeak{
+		client: server.Client(),
+		makeURL: func(session, _ string) string {
+			return server.URL + "/" + session
+		},
+	}
+
+	ipToCount, err := checker.Check(t.Context())
+
+	require.NoError(t, err)
+	expected := map[string]uint{"1.1.1.1": 5, "9.9.9.9": 10}
+	assert.Equal(t, expected, ipToCount)
+}
+
+func Test_makeLeakResolvers(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ipToCount map[string]uint
+		expected  []netip.Prefix
+		resolvers []models.DNSLeakResolver
+		leaking   bool
+	}{
+		"empty": {
+			resolvers: []models.DNSLeakResolver{},
+		},
+		"no_expected": {
+			ipToCount: map[string]uint{"1.1.1.1": 1, "9.9.9.9": 3},
+			resolvers: []models.DNSLeakResolver{
+				{IP: netip.AddrFrom4([4]byte{9, 9, 9, 9}), Percent: 75},
+				{IP: netip.AddrFrom4([4]byte{1, 1, 1, 1}), Percent: 25},
+			},
+		},
+		"all_expected": {
+			ipToCount: map[string]uint{"1.1.1.1": 1, "1.0.0.1": 1},
+			expected:  []netip.Prefix{netip.MustParsePrefix("1.0.0.0/8")},
+			resolvers: []models.DNSLeakResolver{
+				{IP: netip.AddrFrom4([4]byte{1, 0, 0, 1}), Percent: 50, Expected: true},
+				{IP: netip.AddrFrom4([4]byte{1, 1, 1, 1}), Percent: 50, Expected: true},
+			},
+		},
+		"leaking": {
+			ipToCount: map[string]uint{"1.1.1.1": 3, "192.0.2.1": 1, "malformed": 1},
+			expected:  []netip.Prefix{netip.MustParsePrefix("1.1.1.1/32")},
+			resolvers: []models.DNSLeakResolver{
+				{IP: netip.AddrFrom4([4]byte{1, 1, 1, 1}), Percent: 60, Expected: true},
+				{IP: netip.AddrFrom4([4]byte{192, 0, 2, 1}), Percent: 20},
+			},
+			leaking: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			resolvers, leaking := makeLeakResolvers(testCase.ipToCount,
+				testCase.expected, &noopLogger{})
+
+			assert.Equal(t, testCase.resolvers, resolvers)
+			assert.Equal(t, testCase.leaking, leaking)
+		})
+	}
+}
+
+type noopLogger struct{}
+
+func (l *noopLogger) Debug(string)         {}
+func (l *noopLogger) Info(string)          {}
+func (l *noopLogger) Infof(string, ...any) {}
+func (l *noopLogger) Warn(string)          {}
+func (l *noopLogger) Warnf(string, ...any) {}
+func (l *noopLogger) Error(string)         {}
+
+func Test_expectedResolvers(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings         settings.DNS
+		expectedContains []netip.Prefix
+		expectedNil      bool
+	}{
+		"user_expected_resolvers": {
+			settings: settings.DNS{
+				UpstreamType: settings.DNSUpstreamTypeDot,
+				Providers:    []string{"cloudflare"},
+				LeakCheck: settings.DNSLeakCheck{
+					ExpectedResolvers: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+				},
+			},
+			expectedContains: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		},
+		"plain_addresses": {
+			settings: settings.DNS{
+				UpstreamType:           settings.DNSUpstreamTypePlain,
+				UpstreamPlainAddresses: []netip.AddrPort{netip.MustParseAddrPort("9.9.9.9:53")},
+			},
+			expectedContains: []netip.Prefix{netip.MustParsePrefix("9.9.9.9/32")},
+		},
+		"default_dot_cloudflare": {
+			settings: settings.DNS{
+				UpstreamType: settings.DNSUpstreamTypeDot,
+				Providers:    []string{"Cloudflare"},
+			},
+			expectedContains: []netip.Prefix{
+				netip.MustParsePrefix("1.1.1.1/32"),
+				netip.MustParsePrefix("162.158.0.0/15"),
+			},
+		},
+		"doh_google": {
+			settings: settings.DNS{
+				UpstreamType: settings.DNSUpstreamTypeDoh,
+				Providers:    []string{"google"},
+			},
+			expectedContains: []netip.Prefix{
+				netip.MustParsePrefix("8.8.8.8/32"),
+				netip.MustParsePrefix("172.253.0.0/16"),
+			},
+		},
+		"unknown_egress_prefixes": {
+			settings: settings.DNS{
+				UpstreamType: settings.DNSUpstreamTypeDot,
+				Providers:    []string{"cloudflare", "quad9"},
+			},
+			expectedNil: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			prefixes := expectedResolvers(testCase.settings)
+
+			if testCase.expectedNil {
+				assert.Nil(t, prefixes)
+				return
+			}
+			for _, prefix := range testCase.expectedContains {
+				assert.Contains(t, prefixes, prefix)
+			}
+		})
+	}
+}
+
+func Test_makeLeakResolvers_defaultSettings(t *testing.T) {
+	t.Parallel()
+
+	dnsSettings := settings.DNS{
+		UpstreamType: settings.DNSUpstreamTypeDot,
+		Providers:    []string{"Cloudflare"},
+	}
+	expected := expectedResolvers(dnsSettings)
+	require.NotEmpty(t, expected)
+
+	ipToCount := map[string]uint{"162.158.1.1": 3, "203.0.113.1": 1}
+	resolvers, leaking := makeLeakResolvers(ipToCount, expected, nil)
+
+	assert.True(t, leaking)
+	expectedResolvers := []models.DNSLeakResolver{
+		{IP: netip.MustParseAddr("162.158.1.1"), Percent: 75, Expected: true},
+		{IP: netip.MustParseAddr("203.0.113.1"), Percent: 25},
+	}
+	assert.Equal(t, expectedResolvers, resolvers)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/netip"
+	"sync"
 	"time"
 
 	"github.com/qdm12/dns/v2/pkg/middlewares/filter/mapfilter"
@@ -25,6 +26,9 @@ type Loop struct {
 	localSubnets   []netip.Prefix
 	resolvConf     string
 	client         *http.Client
+	leakChecker    LeakChecker
+	leakReport     models.DNSLeakReport
+	leakReportMu   sync.RWMutex
 	logger         Logger
 	userTrigger    bool
 	start          <-chan struct{}
@@ -40,7 +44,8 @@ type Loop struct {
 const defaultBackoffTime = 10 * time.Second
 
 func NewLoop(settings settings.DNS,
-	client *http.Client, logger Logger, localSubnets []netip.Prefix,
+	client *http.Client, leakChecker LeakChecker,
+	logger Logger, localSubnets []netip.Prefix,
 ) (loop *Loop, err error) {
 	start := make(chan struct{})
 	running := make(chan models.LoopStatus)
@@ -66,6 +71,7 @@ func NewLoop(settings settings.DNS,
 		localSubnets:  localSubnets,
 		resolvConf:    "/etc/resolv.conf",
 		client:        client,
+		leakChecker:   leakChecker,
 		logger:        logger,
 		userTrigger:   true,
 		start:         start,
@@ -63,13 +63,6 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 
 		l.userTrigger = false
 
-		report, err := leakCheck(ctx, l.client)
-		if err != nil {
-			l.logger.Warnf("running leak check: %s", err)
-		} else {
-			l.logger.Infof("leak check report: %s", report)
-		}
-
 		exitLoop := l.runWait(ctx, runError)
 		if exitLoop {
 			return
@@ -117,6 +117,24 @@ func (c *Config) enable(ctx context.Context) (err error) {
 		return fmt.Errorf("redirecting ports: %w", err)
 	}
 
+	if c.tcpMSSClamp.interfaceName != "" {
+		const remove = false
+		err = c.impl.ClampTCPMSS(ctx, c.tcpMSSClamp.interfaceName, c.tcpMSSClamp.mtu, remove)
+		if err != nil {
+			return fmt.Errorf("clamping TCP MSS: %w", err)
+		}
+	}
+
+	err = c.setGatewayRules(ctx, c.vpnIntf, c.gateway, remove)
+	if err != nil {
+		return fmt.Errorf("setting up gateway: %w", err)
+	}
+
+	err = c.interceptForTransparentProxy(ctx, c.transparentProxy, remove)
+	if err != nil {
+		return fmt.Errorf("intercepting traffic for transparent proxy: %w", err)
+	}
+
 	if err := c.impl.RunUserPostRules(ctx, c.customRulesPath); err != nil {
 		return fmt.Errorf("running user defined post firewall rules: %w", err)
 	}
@@ -29,6 +29,9 @@ type Config struct {
 	outboundSubnets   []netip.Prefix
 	allowedInputPorts map[uint16]map[string]struct{} // port to interfaces set mapping
 	portRedirections  portRedirections
+	tcpMSSClamp       tcpMSSClamp
+	gateway           gateway
+	transparentProxy  transparentProxy
 	stateMutex        sync.Mutex
 }
 
@@ -0,0 +1,164 @@
+package firewall
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"slices"
+)
+
+type gateway struct {
+	clientSubnets []netip.Prefix
+	dnsHijack     bool
+}
+
+func (g gateway) equal(other gateway) bool {
+	return g.dnsHijack == other.dnsHijack &&
+		slices.Equal(g.clientSubnets, other.clientSubnets)
+}
+
+// SetGateway sets up Gluetun as a gateway for hosts in the client subnets
+// given, which can then use Gluetun's IP address as their default gateway.
+// It enables IP forwarding, and traffic from the client subnets is forwarded
+// and masqueraded out through the VPN interface only. If dnsHijack is true,
+// DNS traffic from the client subnets is redirected to the local DNS server.
+// Any previous gateway set up is replaced, and the gateway is disabled if
+// clientSubnets is empty.
+func (c *Config) SetGateway(ctx context.Context, clientSubnets []netip.Prefix,
+	dnsHijack bool,
+) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	newGateway := gateway{
+		clientSubnets: slices.Clone(clientSubnets),
+		dnsHijack:     dnsHijack,
+	}
+	if len(clientSubnets) == 0 {
+		newGateway.dnsHijack = false
+	}
+
+	if len(clientSubnets) > 0 {
+		err = enableIPForwarding(clientSubnets)
+		if err != nil {
+			return fmt.Errorf("enabling IP forwarding: %w", err)
+		}
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating gateway internal state")
+		c.gateway = newGateway
+		return nil
+	}
+
+	if c.gateway.equal(newGateway) {
+		return nil
+	}
+
+	const remove = true
+	err = c.setGatewayRules(ctx, c.vpnIntf, c.gateway, remove)
+	if err != nil {
+		return fmt.Errorf("removing outdated gateway rules: %w", err)
+	}
+	c.gateway = gateway{}
+
+	if len(clientSubnets) == 0 {
+		return nil
+	}
+
+	c.logger.Info(fmt.Sprintf("setting up gateway for %d client subnet(s)...", len(clientSubnets)))
+	err = c.setGatewayRules(ctx, c.vpnIntf, newGateway, !remove)
+	if err != nil {
+		return fmt.Errorf("setting up gateway: %w", err)
+	}
+	c.gateway = newGateway
+
+	return nil
+}
+
+// setGatewayRules adds or removes the firewall rules for the gateway
+// given. The forwarding rules are only set if the VPN interface
+// vpnIntf is not empty.
+func (c *Config) setGatewayRules(ctx context.Context, vpnIntf string,
+	gateway gateway, remove bool,
+) (err error) {
+	for _, subnet := range gateway.clientSubnets {
+		if vpnIntf != "" {
+			err = c.impl.ForwardClientSubnet(ctx, vpnIntf, subnet, remove)
+			if err != nil {
+				return fmt.Errorf("forwarding client subnet %s through %s: %w",
+					subnet, vpnIntf, err)
+			}
+		}
+
+		if gateway.dnsHijack {
+			err = c.impl.RedirectDNS(ctx, subnet, remove)
+			if err != nil {
+				return fmt.Errorf("redirecting DNS of client subnet %s: %w", subnet, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setGatewayForwarding adds or removes the forwarding rules of the
+// current gateway for the VPN interface vpnIntf, if the gateway is set.
+func (c *Config) setGatewayForwarding(ctx context.Context, vpnIntf string, remove bool) (err error) {
+	for _, subnet := range c.gateway.clientSubnets {
+		err = c.impl.ForwardClientSubnet(ctx, vpnIntf, subnet, remove)
+		if err != nil {
+			return fmt.Errorf("forwarding client subnet %s through %s: %w",
+				subnet, vpnIntf, err)
+		}
+	}
+	return nil
+}
+
+var errIPForwardingDisabled = errors.New("IP forwarding is disabled")
+
+// enableIPForwarding enables IP forwarding for the IP families of the
+// subnets given. Since /proc/sys is usually mounted read-only in containers,
+// it only fails if forwarding is not already enabled, for example with the
+// Docker run flag --sysctl net.ipv4.ip_forward=1.
+func enableIPForwarding(subnets []netip.Prefix) (err error) {
+	var ipv4, ipv6 bool
+	for _, subnet := range subnets {
+		ipv4 = ipv4 || subnet.Addr().Is4()
+		ipv6 = ipv6 || subnet.Addr().Is6()
+	}
+
+	type sysctl struct {
+		name string
+		path string
+	}
+	var sysctls []sysctl
+	if ipv4 {
+		sysctls = append(sysctls, sysctl{name: "net.ipv4.ip_forward", path: "/proc/sys/net/ipv4/ip_forward"})
+	}
+	if ipv6 {
+		sysctls = append(sysctls, sysctl{
+			name: "net.ipv6.conf.all.forwarding",
+			path: "/proc/sys/net/ipv6/conf/all/forwarding",
+		})
+	}
+
+	for _, sysctl := range sysctls {
+		data, err := os.ReadFile(sysctl.path)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", sysctl.name, err)
+		} else if string(bytes.TrimSpace(data)) == "1" {
+			continue
+		}
+
+		const perms = 0o644
+		err = os.WriteFile(sysctl.path, []byte("1"), perms)
+		if err != nil {
+			return fmt.Errorf("%w: %w (you can set it with the Docker run flag --sysctl %s=1)",
+				errIPForwardingDisabled, err, sysctl.name)
+		}
+	}
+	return nil
+}
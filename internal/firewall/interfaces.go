@@ -33,6 +33,11 @@ type firewallImpl interface { //nolint:interfacebloat
 	AcceptOutputThroughInterface(ctx context.Context, intf string, remove bool) error
 	AcceptOutputTrafficToVPN(ctx context.Context, intf string,
 		connection models.Connection, remove bool) error
+	ClampTCPMSS(ctx context.Context, intf string, mtu uint32, remove bool) error
+	ForwardClientSubnet(ctx context.Context, intf string, subnet netip.Prefix, remove bool) error
+	InterceptForTransparentProxy(ctx context.Context, source netip.Prefix,
+		excluded []netip.Prefix, port uint16, mark uint32, remove bool) error
+	RedirectDNS(ctx context.Context, subnet netip.Prefix, remove bool) error
 	RedirectPort(ctx context.Context, intf string, sourcePort,
 		destinationPort uint16, remove bool) error
 	RunUserPostRules(ctx context.Context, customRulesPath string) error
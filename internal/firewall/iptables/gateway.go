@@ -0,0 +1,53 @@
+package iptables
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+)
+
+// ForwardClientSubnet accepts forwarding traffic from the client subnet
+// out through the VPN interface intf, masquerading it with the VPN interface
+// address, as well as the established and related traffic coming back.
+// Since the FORWARD chain policy is DROP, forwarded traffic cannot leave
+// through any other interface. If remove is true, the rules are removed
+// instead of added.
+func (c *Config) ForwardClientSubnet(ctx context.Context, intf string,
+	subnet netip.Prefix, remove bool,
+) error {
+	instructions := []string{
+		fmt.Sprintf("-t nat %s POSTROUTING -s %s -o %s -j MASQUERADE",
+			appendOrDelete(remove), subnet, intf),
+		fmt.Sprintf("%s FORWARD -s %s -o %s -j ACCEPT",
+			appendOrDelete(remove), subnet, intf),
+		fmt.Sprintf("%s FORWARD -i %s -d %s -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT",
+			appendOrDelete(remove), intf, subnet),
+	}
+
+	if subnet.Addr().Is4() {
+		return c.runIptablesInstructions(ctx, instructions)
+	} else if c.ip6Tables == "" {
+		return fmt.Errorf("forward client subnet %s: %s", subnet, needIP6Tables)
+	}
+	return c.runIP6tablesInstructions(ctx, instructions)
+}
+
+// RedirectDNS redirects DNS traffic from the client subnet, for both TCP
+// and UDP protocols, to the local DNS server listening on port 53.
+// If remove is true, the redirection is removed instead of added.
+func (c *Config) RedirectDNS(ctx context.Context, subnet netip.Prefix, remove bool) error {
+	const dnsPort = 53
+	instructions := []string{
+		fmt.Sprintf("-t nat %s PREROUTING -s %s -p udp --dport %d -j REDIRECT --to-ports %d",
+			appendOrDelete(remove), subnet, dnsPort, dnsPort),
+		fmt.Sprintf("-t nat %s PREROUTING -s %s -p tcp --dport %d -j REDIRECT --to-ports %d",
+			appendOrDelete(remove), subnet, dnsPort, dnsPort),
+	}
+
+	if subnet.Addr().Is4() {
+		return c.runIptablesInstructions(ctx, instructions)
+	} else if c.ip6Tables == "" {
+		return fmt.Errorf("redirect DNS of client subnet %s: %s", subnet, needIP6Tables)
+	}
+	return c.runIP6tablesInstructions(ctx, instructions)
+}
@@ -21,7 +21,7 @@ type chainRule struct {
 	lineNumber      uint16 // starts from 1 and cannot be zero.
 	packets         uint64
 	bytes           uint64
-	target          string       // "ACCEPT", "DROP", "REJECT" or "REDIRECT"
+	target          string       // "ACCEPT", "DROP", "REJECT", "REDIRECT" or "TCPMSS"
 	protocol        string       // "icmp", "tcp", "udp" or "" for all protocols.
 	inputInterface  string       // input interface, for example "tun0" or "*""
 	outputInterface string       // output interface, for example "eth0" or "*""
@@ -30,9 +30,11 @@ type chainRule struct {
 	destination     netip.Prefix // destination IP CIDR, for example 0.0.0.0/0. Must be valid.
 	destinationPort uint16       // Not specified if set to zero.
 	redirPorts      []uint16     // Not specified if empty.
-	ctstate         []string     // for example ["RELATED","ESTABLISHED"]. Can be empty.
+	ctstate         []string     // sorted, for example ["ESTABLISHED","RELATED"]. Can be empty.
 	tcpFlags        tcpFlags
 	mark            mark
+	tcpMSS          uint16 // MSS for the TCPMSS target, 0 meaning clamp to the path MTU.
+	tproxyMark      uint32 // mark set by the TPROXY target, whose port is set in redirPorts.
 }
 
 type mark struct {
@@ -280,7 +282,24 @@ func parseChainRuleOptionalFields(optionalFields []string, rule *chainRule) (err
 		case "ctstate":
 			i++
 			rule.ctstate = strings.Split(optionalFields[i], ",")
+			// Sort states since for example ESTABLISHED,RELATED is listed
+			// as RELATED,ESTABLISHED by iptables.
+			slices.Sort(rule.ctstate)
 			i++
+		case "TCPMSS":
+			i++
+			consumed, err := parseTCPMSSOptional(optionalFields[i:], rule)
+			if err != nil {
+				return fmt.Errorf("parsing TCPMSS optional fields: %w", err)
+			}
+			i += consumed
+		case "TPROXY":
+			i++
+			consumed, err := parseTPROXYOptional(optionalFields[i:], rule)
+			if err != nil {
+				return fmt.Errorf("parsing TPROXY optional fields: %w", err)
+			}
+			i += consumed
 		case "mark":
 			i++
 			mark, consumed, err := parseMark(optionalFields[i:])
@@ -355,6 +374,62 @@ func parseTCPOptional(optionalFields []string, rule *chainRule) (consumed int, e
 	return consumed, nil
 }
 
+// parseTCPMSSOptional parses the TCPMSS target optional fields
+// following the TCPMSS field, which are either "set <mss>"
+// or "clamp to PMTU".
+func parseTCPMSSOptional(optionalFields []string, rule *chainRule) (consumed int, err error) {
+	const setFields, clampFields = 2, 3
+	switch {
+	case len(optionalFields) >= setFields && optionalFields[0] == "set":
+		rule.tcpMSS, err = parsePort(optionalFields[1])
+		if err != nil {
+			return 0, fmt.Errorf("parsing MSS: %w", err)
+		}
+		return setFields, nil
+	case len(optionalFields) >= clampFields &&
+		strings.Join(optionalFields[:clampFields], " ") == "clamp to PMTU":
+		rule.tcpMSS = 0
+		return clampFields, nil
+	default:
+		return 0, fmt.Errorf("chain rule is malformed: unexpected TCPMSS fields: %s",
+			strings.Join(optionalFields, " "))
+	}
+}
+
+// parseTPROXYOptional parses the TPROXY target optional fields
+// following the TPROXY field, which are in the format
+// "redirect <address>:<port> mark <value>/<mask>".
+func parseTPROXYOptional(optionalFields []string, rule *chainRule) (consumed int, err error) {
+	const expectedFields = 4
+	if len(optionalFields) < expectedFields ||
+		optionalFields[0] != "redirect" || optionalFields[2] != "mark" {
+		return 0, fmt.Errorf("chain rule is malformed: unexpected TPROXY fields: %s",
+			strings.Join(optionalFields, " "))
+	}
+
+	address := optionalFields[1]
+	colonIndex := strings.LastIndex(address, ":")
+	if colonIndex == -1 {
+		return 0, fmt.Errorf("TPROXY redirect address is malformed: %s", address)
+	}
+	port, err := parsePort(address[colonIndex+1:])
+	if err != nil {
+		return 0, fmt.Errorf("parsing TPROXY redirect port: %w", err)
+	}
+	rule.redirPorts = []uint16{port}
+
+	markValue, _, _ := strings.Cut(optionalFields[3], "/")
+	const base = 0 // auto-detect
+	const bits = 32
+	value, err := strconv.ParseUint(markValue, base, bits)
+	if err != nil {
+		return 0, fmt.Errorf("TPROXY mark value is malformed: %s", optionalFields[3])
+	}
+	rule.tproxyMark = uint32(value)
+
+	return expectedFields, nil
+}
+
 func parseDestinationPort(value string) (port uint16, err error) {
 	value = strings.TrimPrefix(value, "dpt:")
 	return parsePort(value)
@@ -373,22 +448,13 @@ func parseTCPFlags(value string) (tcpFlags, error) {
 		return tcpFlags{}, fmt.Errorf("TCP flags are malformed: expected format 'flags:<mask>/<comparison>' in %q",
 			value)
 	}
-	maskFlags := strings.Split(fields[0], ",")
-	mask := make([]tcpFlag, len(maskFlags))
-	var err error
-	for i, maskFlag := range maskFlags {
-		mask[i], err = parseTCPFlag(maskFlag)
-		if err != nil {
-			return tcpFlags{}, fmt.Errorf("parsing TCP mask flags: %w", err)
-		}
+	mask, err := parseTCPFlagsList(fields[0])
+	if err != nil {
+		return tcpFlags{}, fmt.Errorf("parsing TCP mask flags: %w", err)
 	}
-	comparisonFlags := strings.Split(fields[1], ",")
-	comparison := make([]tcpFlag, len(comparisonFlags))
-	for i, comparisonFlag := range comparisonFlags {
-		comparison[i], err = parseTCPFlag(comparisonFlag)
-		if err != nil {
-			return tcpFlags{}, fmt.Errorf("parsing TCP comparison flags: %w", err)
-		}
+	comparison, err := parseTCPFlagsList(fields[1])
+	if err != nil {
+		return tcpFlags{}, fmt.Errorf("parsing TCP comparison flags: %w", err)
 	}
 	return tcpFlags{
 		mask:       mask,
@@ -396,6 +462,36 @@ func parseTCPFlags(value string) (tcpFlags, error) {
 	}, nil
 }
 
+// parseTCPFlagsList parses a comma separated list of TCP flags,
+// where each element can be a flag name such as SYN, or a hexadecimal
+// value such as 0x06 which is expanded to its flags in bit order,
+// as shown by iptables when listing rules numerically.
+func parseTCPFlagsList(s string) (flags []tcpFlag, err error) {
+	for element := range strings.SplitSeq(s, ",") {
+		hexValue, isHex := strings.CutPrefix(element, "0x")
+		if !isHex {
+			flag, err := parseTCPFlag(element)
+			if err != nil {
+				return nil, err
+			}
+			flags = append(flags, flag)
+			continue
+		}
+
+		const base, bitSize = 16, 8
+		value, err := strconv.ParseUint(hexValue, base, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("unknown TCP flag: %s", element)
+		}
+		for flag := tcpFlagFIN; flag != 0; flag <<= 1 {
+			if uint64(flag)&value != 0 {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	return flags, nil
+}
+
 func parsePortsCSV(s string) (ports []uint16, err error) {
 	if s == "" {
 		return nil, nil
@@ -449,7 +545,7 @@ func parseLineNumber(s string) (n uint16, err error) {
 
 func checkTarget(target string) (err error) {
 	switch target {
-	case "ACCEPT", "DROP", "REJECT", "REDIRECT":
+	case "ACCEPT", "DROP", "REJECT", "RETURN", "REDIRECT", "MASQUERADE", "TCPMSS", "TPROXY":
 		return nil
 	}
 	return fmt.Errorf("unknown target: %s", target)
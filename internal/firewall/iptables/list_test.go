@@ -121,6 +121,150 @@ num pkts bytes target     prot opt in     out     source               destinati
 				},
 			},
 		},
+		"tcpmss_rules": {
+			iptablesOutput: `Chain POSTROUTING (policy ACCEPT 0 packets, 0 bytes)
+num pkts bytes target     prot opt in     out     source               destination
+1   0     0 TCPMSS     6    --  *      tun0    0.0.0.0/0   0.0.0.0/0   tcp flags:0x06/0x02 TCPMSS set 1380
+2   0     0 TCPMSS     6    --  *      tun0    0.0.0.0/0   0.0.0.0/0   tcp flags:0x06/0x02 TCPMSS clamp to PMTU
+`,
+			table: chain{
+				name:   "POSTROUTING",
+				policy: "ACCEPT",
+				rules: []chainRule{
+					{
+						lineNumber:      1,
+						target:          "TCPMSS",
+						protocol:        "tcp",
+						inputInterface:  "*",
+						outputInterface: "tun0",
+						source:          netip.MustParsePrefix("0.0.0.0/0"),
+						destination:     netip.MustParsePrefix("0.0.0.0/0"),
+						tcpFlags: tcpFlags{
+							mask:       []tcpFlag{tcpFlagSYN, tcpFlagRST},
+							comparison: []tcpFlag{tcpFlagSYN},
+						},
+						tcpMSS: 1380,
+					},
+					{
+						lineNumber:      2,
+						target:          "TCPMSS",
+						protocol:        "tcp",
+						inputInterface:  "*",
+						outputInterface: "tun0",
+						source:          netip.MustParsePrefix("0.0.0.0/0"),
+						destination:     netip.MustParsePrefix("0.0.0.0/0"),
+						tcpFlags: tcpFlags{
+							mask:       []tcpFlag{tcpFlagSYN, tcpFlagRST},
+							comparison: []tcpFlag{tcpFlagSYN},
+						},
+					},
+				},
+			},
+		},
+		"forward_rules": {
+			iptablesOutput: `Chain FORWARD (policy DROP 0 packets, 0 bytes)
+num pkts bytes target     prot opt in     out     source               destination
+1   0     0 ACCEPT     0    --  *      tun0    192.168.1.0/24   0.0.0.0/0
+2   0     0 ACCEPT     0    --  tun0   *       0.0.0.0/0        192.168.1.0/24   ctstate RELATED,ESTABLISHED
+`,
+			table: chain{
+				name:   "FORWARD",
+				policy: "DROP",
+				rules: []chainRule{
+					{
+						lineNumber:      1,
+						target:          "ACCEPT",
+						inputInterface:  "*",
+						outputInterface: "tun0",
+						source:          netip.MustParsePrefix("192.168.1.0/24"),
+						destination:     netip.MustParsePrefix("0.0.0.0/0"),
+					},
+					{
+						lineNumber:      2,
+						target:          "ACCEPT",
+						inputInterface:  "tun0",
+						outputInterface: "*",
+						source:          netip.MustParsePrefix("0.0.0.0/0"),
+						destination:     netip.MustParsePrefix("192.168.1.0/24"),
+						ctstate:         []string{"ESTABLISHED", "RELATED"},
+					},
+				},
+			},
+		},
+		"masquerade_rule": {
+			iptablesOutput: `Chain POSTROUTING (policy ACCEPT 0 packets, 0 bytes)
+num pkts bytes target     prot opt in     out     source               destination
+1   0     0 MASQUERADE 0    --  *      tun0    192.168.1.0/24   0.0.0.0/0
+`,
+			table: chain{
+				name:   "POSTROUTING",
+				policy: "ACCEPT",
+				rules: []chainRule{
+					{
+						lineNumber:      1,
+						target:          "MASQUERADE",
+						inputInterface:  "*",
+						outputInterface: "tun0",
+						source:          netip.MustParsePrefix("192.168.1.0/24"),
+						destination:     netip.MustParsePrefix("0.0.0.0/0"),
+					},
+				},
+			},
+		},
+		"tproxy_rules": {
+			iptablesOutput: `Chain PREROUTING (policy ACCEPT 0 packets, 0 bytes)
+num pkts bytes target     prot opt in     out     source               destination
+1   0     0 RETURN     0    --  *      *       172.18.0.0/16    172.18.0.0/16
+2   0     0 TPROXY     17 -- * * 172.18.0.0/16    0.0.0.0/0 TPROXY redirect 0.0.0.0:8890 mark 0x7470/0xffffffff
+`,
+			table: chain{
+				name:   "PREROUTING",
+				policy: "ACCEPT",
+				rules: []chainRule{
+					{
+						lineNumber:      1,
+						target:          "RETURN",
+						inputInterface:  "*",
+						outputInterface: "*",
+						source:          netip.MustParsePrefix("172.18.0.0/16"),
+						destination:     netip.MustParsePrefix("172.18.0.0/16"),
+					},
+					{
+						lineNumber:      2,
+						target:          "TPROXY",
+						protocol:        "udp",
+						inputInterface:  "*",
+						outputInterface: "*",
+						source:          netip.MustParsePrefix("172.18.0.0/16"),
+						destination:     netip.MustParsePrefix("0.0.0.0/0"),
+						redirPorts:      []uint16{8890},
+						tproxyMark:      0x7470,
+					},
+				},
+			},
+		},
+		"mark_match_rule": {
+			iptablesOutput: `Chain INPUT (policy DROP 0 packets, 0 bytes)
+num pkts bytes target     prot opt in     out     source               destination
+1   0     0 ACCEPT     17   --  *      *       172.18.0.0/16    0.0.0.0/0   mark match 0x7470
+`,
+			table: chain{
+				name:   "INPUT",
+				policy: "DROP",
+				rules: []chainRule{
+					{
+						lineNumber:      1,
+						target:          "ACCEPT",
+						protocol:        "udp",
+						inputInterface:  "*",
+						outputInterface: "*",
+						source:          netip.MustParsePrefix("172.18.0.0/16"),
+						destination:     netip.MustParsePrefix("0.0.0.0/0"),
+						mark:            mark{value: 0x7470},
+					},
+				},
+			},
+		},
 	}
 
 	for name, testCase := range testCases {
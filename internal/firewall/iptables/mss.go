@@ -0,0 +1,32 @@
+package iptables
+
+import (
+	"context"
+	"fmt"
+)
+
+// ClampTCPMSS clamps the MSS of TCP SYN packets going out through the
+// interface intf, for both locally generated and forwarded traffic, so
+// that TCP segments fit in the given MTU without fragmentation. The MSS
+// is derived from the MTU for each IP family, and is clamped to the path
+// MTU instead if mtu is zero. If remove is true, the rules are removed
+// instead of added.
+func (c *Config) ClampTCPMSS(ctx context.Context, intf string, mtu uint32, remove bool) error {
+	const ipv4Overhead, ipv6Overhead = 20 + 20, 40 + 20 // IP header + TCP header
+	ipv4Target, ipv6Target := "--clamp-mss-to-pmtu", "--clamp-mss-to-pmtu"
+	if mtu > 0 {
+		ipv4Target = fmt.Sprintf("--set-mss %d", mtu-ipv4Overhead)
+		ipv6Target = fmt.Sprintf("--set-mss %d", mtu-ipv6Overhead)
+	}
+
+	const format = "-t mangle %s POSTROUTING -o %s -p tcp -m tcp --tcp-flags SYN,RST SYN -j TCPMSS %s"
+	err := c.runIptablesInstruction(ctx, fmt.Sprintf(format, appendOrDelete(remove), intf, ipv4Target))
+	if err != nil {
+		return fmt.Errorf("clamping IPv4 TCP MSS: %w", err)
+	}
+	err = c.runIP6tablesInstruction(ctx, fmt.Sprintf(format, appendOrDelete(remove), intf, ipv6Target))
+	if err != nil {
+		return fmt.Errorf("clamping IPv6 TCP MSS: %w", err)
+	}
+	return nil
+}
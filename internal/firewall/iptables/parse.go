@@ -22,9 +22,11 @@ type iptablesInstruction struct {
 	destination     netip.Prefix // if not valid, then it is unspecified.
 	destinationPort uint16       // if zero, there is no destination port
 	toPorts         []uint16     // if empty, there is no redirection
-	ctstate         []string     // if empty, there is no ctstate
+	ctstate         []string     // sorted, and if empty, there is no ctstate
 	tcpFlags        tcpFlags
 	mark            mark
+	tcpMSS          uint16 // MSS for the TCPMSS target, 0 meaning clamp to the path MTU.
+	tproxyMark      uint32 // mark set by the TPROXY target, whose port is set in toPorts.
 }
 
 func (i *iptablesInstruction) setDefaults() {
@@ -65,6 +67,10 @@ func (i *iptablesInstruction) equalToRule(table, chain string, rule chainRule) (
 		return false
 	case i.mark != rule.mark:
 		return false
+	case i.tcpMSS != rule.tcpMSS:
+		return false
+	case i.tproxyMark != rule.tproxyMark:
+		return false
 	default:
 		return true
 	}
@@ -105,7 +111,10 @@ func parseInstructionFlag(fields []string, instruction *iptablesInstruction) (co
 		return 0, err
 	}
 	flag := fields[0]
-	value := fields[1]
+	value := ""
+	if consumed > 1 {
+		value = fields[1]
+	}
 
 	switch flag {
 	case "-t", "--table":
@@ -159,11 +168,28 @@ func parseInstructionFlag(fields []string, instruction *iptablesInstruction) (co
 		}
 	case "--ctstate":
 		instruction.ctstate = strings.Split(value, ",")
-	case "--to-ports":
+		// sorted to match listed chain rules regardless of the states order
+		slices.Sort(instruction.ctstate)
+	case "--to-ports", "--on-port":
 		instruction.toPorts, err = parseToPorts(value)
 		if err != nil {
 			return 0, fmt.Errorf("parsing port redirection: %w", err)
 		}
+	case "--set-mss":
+		instruction.tcpMSS, err = parsePort(value)
+		if err != nil {
+			return 0, fmt.Errorf("parsing TCP MSS: %w", err)
+		}
+	case "--clamp-mss-to-pmtu":
+		instruction.tcpMSS = 0
+	case "--tproxy-mark":
+		const base = 0 // auto-detect
+		const bits = 32
+		value, err := strconv.ParseUint(value, base, bits)
+		if err != nil {
+			return 0, fmt.Errorf("parsing TPROXY mark value %q: %w", value, err)
+		}
+		instruction.tproxyMark = uint32(value)
 	case "--tcp-flags":
 		mask, comparison := value, fields[2]
 		instruction.tcpFlags, err = parseTCPFlags(mask + "/" + comparison)
@@ -180,6 +206,8 @@ func preCheckInstructionFields(fields []string) (consumed int, err error) {
 	flag := fields[0]
 	// All flags use one value after the flag, except the following:
 	switch flag {
+	case "--clamp-mss-to-pmtu": // flag without value
+		return 1, nil
 	case "--tcp-flags": // -m can have 1 or 2 values
 		const expected = 3
 		if len(fields) < expected {
@@ -230,12 +258,18 @@ func parseMatchModule(fields []string, instruction *iptablesInstruction) (
 		// for now ignore the protocol match since it's auto-loaded
 		// when parsing the -p/--protocol flag, and we don't need to
 		// parse it twice.
+	case "conntrack":
+		consumed++
+		// the connection tracking states are parsed
+		// with the --ctstate flag.
 	case "mark":
 		consumed++
-		switch fields[consumed] {
-		case "!":
+		switch {
+		case consumed < len(fields) && fields[consumed] == "!":
 			consumed++
 			instruction.mark.invert = true
+		case consumed < len(fields) && fields[consumed] == "--mark":
+			// the mark value is parsed with the --mark flag.
 		default:
 			return consumed, fmt.Errorf("iptables command is malformed: unsupported match mark with value: %s",
 				fields[2])
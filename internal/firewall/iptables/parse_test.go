@@ -61,6 +61,82 @@ func Test_parseIptablesInstruction(t *testing.T) {
 				toPorts:         []uint16{5678},
 			},
 		},
+		"mangle_set_mss": {
+			s: "-t mangle --append POSTROUTING -o tun0 -p tcp -m tcp --tcp-flags SYN,RST SYN -j TCPMSS --set-mss 1380",
+			instruction: iptablesInstruction{
+				table:           "mangle",
+				chain:           "POSTROUTING",
+				append:          true,
+				outputInterface: "tun0",
+				protocol:        "tcp",
+				tcpFlags: tcpFlags{
+					mask:       []tcpFlag{tcpFlagSYN, tcpFlagRST},
+					comparison: []tcpFlag{tcpFlagSYN},
+				},
+				target: "TCPMSS",
+				tcpMSS: 1380,
+			},
+		},
+		"mangle_clamp_mss_to_pmtu": {
+			s: "-t mangle --delete POSTROUTING -o tun0 -p tcp --tcp-flags SYN,RST SYN -j TCPMSS --clamp-mss-to-pmtu",
+			instruction: iptablesInstruction{
+				table:           "mangle",
+				chain:           "POSTROUTING",
+				outputInterface: "tun0",
+				protocol:        "tcp",
+				tcpFlags: tcpFlags{
+					mask:       []tcpFlag{tcpFlagSYN, tcpFlagRST},
+					comparison: []tcpFlag{tcpFlagSYN},
+				},
+				target: "TCPMSS",
+			},
+		},
+		"forward_established_related": {
+			s: "--delete FORWARD -i tun0 -d 192.168.1.0/24 -m conntrack --ctstate RELATED,ESTABLISHED -j ACCEPT",
+			instruction: iptablesInstruction{
+				table:          "filter",
+				chain:          "FORWARD",
+				inputInterface: "tun0",
+				destination:    netip.MustParsePrefix("192.168.1.0/24"),
+				ctstate:        []string{"ESTABLISHED", "RELATED"},
+				target:         "ACCEPT",
+			},
+		},
+		"nat_masquerade": {
+			s: "-t nat --append POSTROUTING -s 192.168.1.0/24 -o tun0 -j MASQUERADE",
+			instruction: iptablesInstruction{
+				table:           "nat",
+				append:          true,
+				chain:           "POSTROUTING",
+				source:          netip.MustParsePrefix("192.168.1.0/24"),
+				outputInterface: "tun0",
+				target:          "MASQUERADE",
+			},
+		},
+		"mangle_tproxy": {
+			s: "-t mangle --delete PREROUTING -s 172.18.0.0/16 -p udp -j TPROXY --on-port 8890 --tproxy-mark 0x7470",
+			instruction: iptablesInstruction{
+				table:      "mangle",
+				chain:      "PREROUTING",
+				source:     netip.MustParsePrefix("172.18.0.0/16"),
+				protocol:   "udp",
+				target:     "TPROXY",
+				toPorts:    []uint16{8890},
+				tproxyMark: 0x7470,
+			},
+		},
+		"input_mark_match": {
+			s: "--append INPUT -s 172.18.0.0/16 -p udp -m mark --mark 0x7470 -j ACCEPT",
+			instruction: iptablesInstruction{
+				table:    "filter",
+				append:   true,
+				chain:    "INPUT",
+				source:   netip.MustParsePrefix("172.18.0.0/16"),
+				protocol: "udp",
+				mark:     mark{value: 0x7470},
+				target:   "ACCEPT",
+			},
+		},
 	}
 
 	for name, testCase := range testCases {
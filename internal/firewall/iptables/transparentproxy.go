@@ -0,0 +1,48 @@
+package iptables
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+)
+
+// InterceptForTransparentProxy intercepts TCP and UDP traffic coming from
+// the source subnet given and redirects it to the local port given, except
+// for traffic to the excluded subnets. TCP traffic is redirected using the
+// nat table REDIRECT target, and UDP traffic is redirected using the mangle
+// table TPROXY target which marks packets with the mark given, so they can
+// be routed locally while keeping their original destination.
+// If remove is true, the rules are removed instead of added.
+func (c *Config) InterceptForTransparentProxy(ctx context.Context, source netip.Prefix,
+	excluded []netip.Prefix, port uint16, mark uint32, remove bool,
+) error {
+	instructions := make([]string, 0, 2*len(excluded)+4) //nolint:mnd
+	for _, subnet := range excluded {
+		if subnet.Addr().Is4() != source.Addr().Is4() {
+			continue
+		}
+		instructions = append(instructions,
+			fmt.Sprintf("-t nat %s PREROUTING -s %s -d %s -j RETURN",
+				appendOrDelete(remove), source, subnet),
+			fmt.Sprintf("-t mangle %s PREROUTING -s %s -d %s -j RETURN",
+				appendOrDelete(remove), source, subnet),
+		)
+	}
+	instructions = append(instructions,
+		fmt.Sprintf("-t nat %s PREROUTING -s %s -p tcp -j REDIRECT --to-ports %d",
+			appendOrDelete(remove), source, port),
+		fmt.Sprintf("-t mangle %s PREROUTING -s %s -p udp -j TPROXY --on-port %d --tproxy-mark 0x%x",
+			appendOrDelete(remove), source, port, mark),
+		fmt.Sprintf("%s INPUT -s %s -p tcp -m tcp --dport %d -j ACCEPT",
+			appendOrDelete(remove), source, port),
+		fmt.Sprintf("%s INPUT -s %s -p udp -m mark --mark 0x%x -j ACCEPT",
+			appendOrDelete(remove), source, mark),
+	)
+
+	if source.Addr().Is4() {
+		return c.runIptablesInstructions(ctx, instructions)
+	} else if c.ip6Tables == "" {
+		return fmt.Errorf("intercept traffic from %s: %s", source, needIP6Tables)
+	}
+	return c.runIP6tablesInstructions(ctx, instructions)
+}
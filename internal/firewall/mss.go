@@ -0,0 +1,66 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+)
+
+type tcpMSSClamp struct {
+	interfaceName string
+	// mtu is the MTU to derive the MSS from,
+	// and is 0 to clamp the MSS to the path MTU.
+	mtu uint32
+}
+
+// SetTCPMSSClamp clamps the MSS of TCP connections going out through
+// the VPN interface vpnIntf, so their segments fit in the mtu given.
+// If the mtu is zero, the MSS is clamped to the path MTU instead.
+// Any previous clamping is replaced, and clamping is removed if
+// vpnIntf is the empty string.
+func (c *Config) SetTCPMSSClamp(ctx context.Context, vpnIntf string, mtu uint32) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	newClamp := tcpMSSClamp{interfaceName: vpnIntf, mtu: mtu}
+	if vpnIntf == "" {
+		newClamp.mtu = 0
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating TCP MSS clamping internal state")
+		c.tcpMSSClamp = newClamp
+		return nil
+	}
+
+	if c.tcpMSSClamp == newClamp {
+		return nil
+	}
+
+	if c.tcpMSSClamp.interfaceName != "" {
+		const remove = true
+		err = c.impl.ClampTCPMSS(ctx, c.tcpMSSClamp.interfaceName, c.tcpMSSClamp.mtu, remove)
+		if err != nil {
+			return fmt.Errorf("removing outdated TCP MSS clamping: %w", err)
+		}
+		c.tcpMSSClamp = tcpMSSClamp{}
+	}
+
+	if vpnIntf == "" {
+		return nil
+	}
+
+	if mtu == 0 {
+		c.logger.Info("clamping TCP MSS to path MTU through interface " + vpnIntf + "...")
+	} else {
+		c.logger.Info(fmt.Sprintf("clamping TCP MSS for MTU %d through interface %s...", mtu, vpnIntf))
+	}
+
+	const remove = false
+	err = c.impl.ClampTCPMSS(ctx, vpnIntf, mtu, remove)
+	if err != nil {
+		return fmt.Errorf("clamping TCP MSS: %w", err)
+	}
+	c.tcpMSSClamp = newClamp
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package firewall
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+)
+
+type transparentProxy struct {
+	sources []netip.Prefix
+	port    uint16
+	mark    uint32
+}
+
+func (t transparentProxy) equal(other transparentProxy) bool {
+	return t.port == other.port && t.mark == other.mark &&
+		slices.Equal(t.sources, other.sources)
+}
+
+// SetTransparentProxy intercepts TCP and UDP traffic from the sources given,
+// except traffic to the local networks, and redirects it to the transparent
+// proxy listening on the port given. Intercepted UDP packets are marked with
+// the mark given, which must be routed locally. Any previous interception is
+// replaced, and interception is disabled if sources is empty.
+func (c *Config) SetTransparentProxy(ctx context.Context, sources []netip.Prefix,
+	port uint16, mark uint32,
+) (err error) {
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	newProxy := transparentProxy{
+		sources: slices.Clone(sources),
+		port:    port,
+		mark:    mark,
+	}
+	if len(sources) == 0 {
+		newProxy = transparentProxy{}
+	}
+
+	if !c.enabled {
+		c.logger.Info("firewall disabled, only updating transparent proxy internal state")
+		c.transparentProxy = newProxy
+		return nil
+	}
+
+	if c.transparentProxy.equal(newProxy) {
+		return nil
+	}
+
+	const remove = true
+	err = c.interceptForTransparentProxy(ctx, c.transparentProxy, remove)
+	if err != nil {
+		return fmt.Errorf("removing outdated transparent proxy rules: %w", err)
+	}
+	c.transparentProxy = transparentProxy{}
+
+	if len(sources) == 0 {
+		return nil
+	}
+
+	c.logger.Info(fmt.Sprintf("intercepting traffic from %d source(s) to port %d...", len(sources), port))
+	err = c.interceptForTransparentProxy(ctx, newProxy, !remove)
+	if err != nil {
+		return fmt.Errorf("intercepting traffic: %w", err)
+	}
+	c.transparentProxy = newProxy
+
+	return nil
+}
+
+func (c *Config) interceptForTransparentProxy(ctx context.Context,
+	proxy transparentProxy, remove bool,
+) (err error) {
+	excluded := make([]netip.Prefix, len(c.localNetworks))
+	for i, localNetwork := range c.localNetworks {
+		excluded[i] = localNetwork.IPNet
+	}
+
+	for _, source := range proxy.sources {
+		err = c.impl.InterceptForTransparentProxy(ctx, source, excluded,
+			proxy.port, proxy.mark, remove)
+		if err != nil {
+			return fmt.Errorf("intercepting traffic from %s: %w", source, err)
+		}
+	}
+	return nil
+}
@@ -39,6 +39,9 @@ func (c *Config) SetVPNConnection(ctx context.Context,
 		if err = c.impl.AcceptOutputThroughInterface(ctx, c.vpnIntf, remove); err != nil {
 			c.logger.Error("cannot remove outdated VPN interface rule: " + err.Error())
 		}
+		if err = c.setGatewayForwarding(ctx, c.vpnIntf, remove); err != nil {
+			c.logger.Error("cannot remove outdated gateway forwarding rules: " + err.Error())
+		}
 	}
 	c.vpnIntf = ""
 
@@ -56,5 +59,9 @@ func (c *Config) SetVPNConnection(ctx context.Context,
 	}
 	c.vpnIntf = vpnIntf
 
+	if err = c.setGatewayForwarding(ctx, vpnIntf, remove); err != nil {
+		return fmt.Errorf("setting up gateway forwarding: %w", err)
+	}
+
 	return nil
 }
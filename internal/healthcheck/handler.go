@@ -2,12 +2,18 @@ package healthcheck
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"sync"
 )
 
 type handler struct {
-	healthErr   error
+	healthErr error
+	// checkErrs maps names of checks run outside the health
+	// checker to their last error.
+	checkErrs   map[string]error
 	healthErrMu sync.RWMutex
 	logger      Logger
 }
@@ -15,6 +21,7 @@ type handler struct {
 func newHandler(logger Logger) *handler {
 	return &handler{
 		healthErr: errors.New("healthcheck did not run yet"),
+		checkErrs: make(map[string]error),
 		logger:    logger,
 	}
 }
@@ -37,8 +44,25 @@ func (h *handler) setErr(err error) {
 	h.healthErr = err
 }
 
+func (h *handler) setCheckErr(name string, err error) {
+	h.healthErrMu.Lock()
+	defer h.healthErrMu.Unlock()
+	if err == nil {
+		delete(h.checkErrs, name)
+		return
+	}
+	h.checkErrs[name] = err
+}
+
 func (h *handler) getErr() (err error) {
 	h.healthErrMu.RLock()
 	defer h.healthErrMu.RUnlock()
-	return h.healthErr
+	if h.healthErr != nil {
+		return h.healthErr
+	}
+	names := slices.Sorted(maps.Keys(h.checkErrs))
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %w", names[0], h.checkErrs[names[0]])
 }
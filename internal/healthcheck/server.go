@@ -25,6 +25,20 @@ func (s *Server) SetError(err error) {
 	s.handler.setErr(err)
 }
 
+// GetError returns the current health error,
+// which is nil if the program is healthy.
+func (s *Server) GetError() (err error) {
+	return s.handler.getErr()
+}
+
+// SetCheckError sets the error for a check run outside
+// the health checker, such as the DNS leak check.
+// The health server reports unhealthy as long as one
+// of these errors is set. Set a nil error to clear it.
+func (s *Server) SetCheckError(name string, err error) {
+	s.handler.setCheckErr(name, err)
+}
+
 type StatusApplier interface {
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
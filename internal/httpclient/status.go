@@ -0,0 +1,22 @@
+// Package httpclient contains helpers shared by the HTTP clients
+// of the program talking to third party HTTP servers.
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var ErrHTTPStatusCodeNotOK = errors.New("HTTP status code is not OK")
+
+// StatusError returns an error wrapping [ErrHTTPStatusCodeNotOK]
+// with the response status and the start of its body.
+func StatusError(response *http.Response) error {
+	const maxBodySize = 1024
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	return fmt.Errorf("%w: %s: %s", ErrHTTPStatusCodeNotOK,
+		response.Status, strings.TrimSpace(string(body)))
+}
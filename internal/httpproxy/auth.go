@@ -5,12 +5,19 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/qdm12/gluetun/internal/ratelimit"
 )
 
 func (h *handler) isAuthorized(responseWriter http.ResponseWriter, request *http.Request) (authorized bool) {
 	if h.username == "" || (request.Method != http.MethodConnect && !request.URL.IsAbs()) {
 		return true
 	}
+	client := ratelimit.ClientFromRequest(request)
+	if remaining := h.limiter.LockedOut(client); remaining > 0 {
+		ratelimit.Reject(responseWriter, remaining)
+		return false
+	}
 	basicAuth := request.Header.Get("Proxy-Authorization")
 	if basicAuth == "" {
 		responseWriter.Header().Set("Proxy-Authenticate", `Basic realm="Access to Gluetun over HTTP"`)
@@ -22,12 +29,14 @@ func (h *handler) isAuthorized(responseWriter http.ResponseWriter, request *http
 	if err != nil {
 		h.logger.Info("Cannot decode Proxy-Authorization header value from " +
 			request.RemoteAddr + ": " + err.Error())
+		h.limiter.Fail(client)
 		responseWriter.WriteHeader(http.StatusUnauthorized)
 		return false
 	}
 	usernamePassword := strings.Split(string(b), ":")
 	const expectedFields = 2
 	if len(usernamePassword) != expectedFields {
+		h.limiter.Fail(client)
 		responseWriter.WriteHeader(http.StatusBadRequest)
 		return false
 	}
@@ -36,8 +45,10 @@ func (h *handler) isAuthorized(responseWriter http.ResponseWriter, request *http
 			usernamePassword[0], usernamePassword[1], request.RemoteAddr))
 		h.logger.Debug("username provided \"" + usernamePassword[0] +
 			"\" and password provided \"" + usernamePassword[1] + "\"")
+		h.limiter.Fail(client)
 		responseWriter.WriteHeader(http.StatusUnauthorized)
 		return false
 	}
+	h.limiter.Succeed(client)
 	return true
 }
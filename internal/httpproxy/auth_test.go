@@ -0,0 +1,52 @@
+package httpproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string) {}
+func (noopLogger) Info(string)  {}
+func (noopLogger) Warn(string)  {}
+func (noopLogger) Error(string) {}
+
+func Test_handler_isAuthorized_lockout(t *testing.T) {
+	t.Parallel()
+
+	h := &handler{
+		logger:   noopLogger{},
+		username: "user",
+		password: "password",
+		limiter:  ratelimit.New(ratelimit.DefaultSettings(), noopLogger{}),
+	}
+
+	isAuthorized := func(password string) (authorized bool, statusCode int) {
+		request := httptest.NewRequest(http.MethodConnect, "example.com:443", nil)
+		request.RemoteAddr = "1.2.3.4:5678"
+		request.SetBasicAuth("user", password)
+		request.Header.Set("Proxy-Authorization", request.Header.Get("Authorization"))
+		recorder := httptest.NewRecorder()
+		authorized = h.isAuthorized(recorder, request)
+		return authorized, recorder.Code
+	}
+
+	authorized, _ := isAuthorized("password")
+	assert.True(t, authorized)
+
+	maxFailures := ratelimit.DefaultSettings().MaxFailures
+	for range maxFailures {
+		authorized, statusCode := isAuthorized("wrong")
+		assert.False(t, authorized)
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+	}
+
+	authorized, statusCode := isAuthorized("password")
+	assert.False(t, authorized)
+	assert.Equal(t, http.StatusTooManyRequests, statusCode)
+}
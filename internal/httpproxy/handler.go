@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/qdm12/gluetun/internal/ratelimit"
 )
 
 func newHandler(ctx context.Context, wg *sync.WaitGroup, logger Logger,
@@ -23,6 +25,9 @@ func newHandler(ctx context.Context, wg *sync.WaitGroup, logger Logger,
 		stealth:  stealth,
 		username: username,
 		password: password,
+		// Only the lockout of clients failing to authenticate is used,
+		// since rate limiting proxied requests would break clients.
+		limiter: ratelimit.New(ratelimit.DefaultSettings(), logger),
 	}
 }
 
@@ -33,6 +38,7 @@ type handler struct {
 	logger             Logger
 	verbose, stealth   bool
 	username, password string
+	limiter            *ratelimit.Limiter
 }
 
 func (h *handler) ServeHTTP(responseWriter http.ResponseWriter, request *http.Request) {
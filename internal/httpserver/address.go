@@ -1,6 +1,7 @@
 package httpserver
 
-// GetAddress obtains the address the HTTP server is listening on.
+// GetAddress obtains the TCP address the HTTP server is listening on,
+// or its Unix socket path if it is not listening on TCP.
 func (s *Server) GetAddress() (address string) {
 	<-s.addressSet
 	return s.address
@@ -0,0 +1,42 @@
+package httpserver
+
+import (
+	"context"
+	"net"
+)
+
+// PeerCredentials are the credentials of the process
+// connected to the server through its Unix socket.
+type PeerCredentials struct {
+	PID int32
+	UID uint32
+	GID uint32
+}
+
+type peerCredentialsKey struct{}
+
+// GetPeerCredentials returns the peer credentials of the connection
+// of the request context given, and false if the request was not
+// made through the Unix socket or its credentials could not be read.
+func GetPeerCredentials(ctx context.Context) (credentials PeerCredentials, ok bool) {
+	credentials, ok = ctx.Value(peerCredentialsKey{}).(PeerCredentials)
+	return credentials, ok
+}
+
+// WithPeerCredentials returns a copy of the context given
+// holding the peer credentials given.
+func WithPeerCredentials(ctx context.Context, credentials PeerCredentials) context.Context {
+	return context.WithValue(ctx, peerCredentialsKey{}, credentials)
+}
+
+func connContext(ctx context.Context, conn net.Conn) context.Context {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+	credentials, err := getPeerCredentials(unixConn)
+	if err != nil {
+		return ctx
+	}
+	return WithPeerCredentials(ctx, credentials)
+}
@@ -0,0 +1,32 @@
+package httpserver
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+func getPeerCredentials(conn *net.UnixConn) (credentials PeerCredentials, err error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return credentials, fmt.Errorf("getting raw connection: %w", err)
+	}
+
+	var ucred *unix.Ucred
+	var getErr error
+	err = rawConn.Control(func(fd uintptr) {
+		ucred, getErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	})
+	if err != nil {
+		return credentials, fmt.Errorf("controlling raw connection: %w", err)
+	} else if getErr != nil {
+		return credentials, fmt.Errorf("getting peer credentials: %w", getErr)
+	}
+
+	return PeerCredentials{
+		PID: ucred.Pid,
+		UID: ucred.Uid,
+		GID: ucred.Gid,
+	}, nil
+}
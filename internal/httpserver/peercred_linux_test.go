@@ -0,0 +1,82 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Server_Run_unixSocket(t *testing.T) {
+	t.Parallel()
+
+	socketPath := filepath.Join(t.TempDir(), "gluetun.sock")
+	// Create a stale socket file to check it gets replaced.
+	staleListener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	staleListener.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, staleListener.Close())
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credentials, ok := GetPeerCredentials(r.Context())
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(strconv.FormatUint(uint64(credentials.UID), 10)))
+	})
+
+	const socketMode = os.FileMode(0o600)
+	server := &Server{
+		addressSet:      make(chan struct{}),
+		socketPath:      socketPath,
+		socketUID:       os.Getuid(),
+		socketGID:       os.Getgid(),
+		socketMode:      socketMode,
+		handler:         handler,
+		logger:          &testLogger{},
+		shutdownTimeout: time.Second,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ready := make(chan struct{})
+	done := make(chan struct{})
+	go server.Run(ctx, ready, done)
+	<-ready
+
+	assert.Equal(t, socketPath, server.GetAddress())
+	stat, err := os.Stat(socketPath)
+	require.NoError(t, err)
+	assert.Equal(t, socketMode, stat.Mode().Perm())
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				dialer := &net.Dialer{}
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://unix/", nil)
+	require.NoError(t, err)
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, strconv.Itoa(os.Getuid()), string(body))
+
+	cancel()
+	<-done
+	_, err = os.Stat(socketPath)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
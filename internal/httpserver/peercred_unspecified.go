@@ -0,0 +1,12 @@
+//go:build !linux
+
+package httpserver
+
+import (
+	"errors"
+	"net"
+)
+
+func getPeerCredentials(*net.UnixConn) (credentials PeerCredentials, err error) {
+	return credentials, errors.New("peer credentials are not supported on this platform")
+}
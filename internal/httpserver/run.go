@@ -3,8 +3,11 @@ package httpserver
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 )
 
 // Run runs the HTTP server until ctx is canceled.
@@ -16,6 +19,7 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 		Handler:           s.handler,
 		ReadHeaderTimeout: s.readHeaderTimeout,
 		ReadTimeout:       s.readTimeout,
+		ConnContext:       connContext,
 	}
 
 	crashed := make(chan struct{})
@@ -39,8 +43,7 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 		}
 	}()
 
-	listenConfig := &net.ListenConfig{}
-	listener, err := listenConfig.Listen(listenCtx, "tcp", s.address)
+	listeners, err := s.listen(listenCtx)
 	if err != nil {
 		close(s.addressSet)
 		close(crashed) // stop shutdown goroutine
@@ -50,24 +53,108 @@ func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- str
 		return
 	}
 
-	s.address = listener.Addr().String()
+	s.address = listeners[0].Addr().String()
 	close(s.addressSet)
 
 	// note: no further write so no need to mutex
-	s.logger.Info("http server listening on " + s.address)
+	for _, listener := range listeners {
+		s.logger.Info("http server listening on " + listener.Addr().String())
+	}
 	close(ready)
 
-	err = server.Serve(listener)
+	serveErrors := make(chan error, len(listeners))
+	for _, listener := range listeners {
+		go func() {
+			serveErrors <- server.Serve(listener)
+		}()
+	}
 
+	err = <-serveErrors
 	if err != nil && !errors.Is(ctx.Err(), context.Canceled) {
 		// server crashed
 		close(crashed) // stop shutdown goroutine
+		_ = server.Close()
 	} else {
 		err = nil
 	}
+	for range len(listeners) - 1 {
+		<-serveErrors
+	}
 	<-shutdownDone
 	if err != nil {
 		s.logger.Error(err.Error())
 	}
 	close(done)
 }
+
+// listen listens on the TCP address and on the Unix socket path,
+// if they are set. The TCP listener is first if there is one.
+func (s *Server) listen(ctx context.Context) (listeners []net.Listener, err error) {
+	listenConfig := &net.ListenConfig{}
+	if s.address != "" {
+		listener, err := listenConfig.Listen(ctx, "tcp", s.address)
+		if err != nil {
+			return nil, err
+		}
+		listeners = append(listeners, listener)
+	}
+
+	if s.socketPath != "" {
+		listener, err := s.listenUnix(ctx, listenConfig)
+		if err != nil {
+			for _, listener := range listeners {
+				_ = listener.Close()
+			}
+			return nil, fmt.Errorf("listening on unix socket: %w", err)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	return listeners, nil
+}
+
+// listenUnix listens on the Unix socket path, removing any socket
+// left over from a previous run. The socket file is removed when
+// the listener is closed.
+func (s *Server) listenUnix(ctx context.Context, listenConfig *net.ListenConfig) (
+	listener net.Listener, err error,
+) {
+	stat, err := os.Lstat(s.socketPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	case stat.Mode().Type() != os.ModeSocket:
+		return nil, fmt.Errorf("file exists and is not a socket: %s", s.socketPath)
+	default:
+		err = os.Remove(s.socketPath)
+		if err != nil {
+			return nil, fmt.Errorf("removing previous socket: %w", err)
+		}
+	}
+
+	const dirPerms = os.FileMode(0o755)
+	err = os.MkdirAll(filepath.Dir(s.socketPath), dirPerms)
+	if err != nil {
+		return nil, fmt.Errorf("creating directory: %w", err)
+	}
+
+	listener, err = listenConfig.Listen(ctx, "unix", s.socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Chmod(s.socketPath, s.socketMode)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("setting socket mode: %w", err)
+	}
+
+	err = os.Chown(s.socketPath, s.socketUID, s.socketGID)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("setting socket owner: %w", err)
+	}
+
+	return listener, nil
+}
@@ -3,6 +3,7 @@ package httpserver
 import (
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 )
 
@@ -11,6 +12,10 @@ import (
 type Server struct {
 	address           string
 	addressSet        chan struct{}
+	socketPath        string
+	socketUID         int
+	socketGID         int
+	socketMode        os.FileMode
 	handler           http.Handler
 	logger            Logger
 	readHeaderTimeout time.Duration
@@ -30,6 +35,10 @@ func New(settings Settings) (s *Server, err error) {
 	return &Server{
 		address:           settings.Address,
 		addressSet:        make(chan struct{}),
+		socketPath:        settings.SocketPath,
+		socketUID:         settings.SocketUID,
+		socketGID:         settings.SocketGID,
+		socketMode:        settings.SocketMode,
 		handler:           settings.Handler,
 		logger:            settings.Logger,
 		readHeaderTimeout: settings.ReadHeaderTimeout,
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/qdm12/gosettings"
@@ -13,9 +14,20 @@ import (
 )
 
 type Settings struct {
-	// Address is the server listening address.
-	// It defaults to :8000.
+	// Address is the server TCP listening address.
+	// It defaults to :8000 if SocketPath is empty, and can
+	// be left empty to only listen on the Unix socket.
 	Address string
+	// SocketPath is the path of a Unix domain socket to listen on,
+	// in addition to the TCP address. It defaults to the empty
+	// string, meaning no Unix socket is created.
+	SocketPath string
+	// SocketUID and SocketGID are the user and group IDs
+	// owning the Unix socket. They default to 0.
+	SocketUID, SocketGID int
+	// SocketMode is the file mode of the Unix socket.
+	// It defaults to 0660 if left unset and SocketPath is set.
+	SocketMode os.FileMode
 	// Handler is the HTTP Handler to use.
 	// It must be set and cannot be left to nil.
 	Handler http.Handler
@@ -34,7 +46,12 @@ type Settings struct {
 }
 
 func (s *Settings) SetDefaults() {
-	s.Address = gosettings.DefaultComparable(s.Address, ":8000")
+	if s.SocketPath == "" {
+		s.Address = gosettings.DefaultComparable(s.Address, ":8000")
+	} else {
+		const defaultSocketMode = os.FileMode(0o660)
+		s.SocketMode = gosettings.DefaultComparable(s.SocketMode, defaultSocketMode)
+	}
 	const defaultReadTimeout = 3 * time.Second
 	s.ReadHeaderTimeout = gosettings.DefaultComparable(s.ReadHeaderTimeout, defaultReadTimeout)
 	s.ReadTimeout = gosettings.DefaultComparable(s.ReadTimeout, defaultReadTimeout)
@@ -45,6 +62,10 @@ func (s *Settings) SetDefaults() {
 func (s Settings) Copy() Settings {
 	return Settings{
 		Address:           s.Address,
+		SocketPath:        s.SocketPath,
+		SocketUID:         s.SocketUID,
+		SocketGID:         s.SocketGID,
+		SocketMode:        s.SocketMode,
 		Handler:           s.Handler,
 		Logger:            s.Logger,
 		ReadHeaderTimeout: s.ReadHeaderTimeout,
@@ -55,6 +76,10 @@ func (s Settings) Copy() Settings {
 
 func (s *Settings) OverrideWith(other Settings) {
 	s.Address = gosettings.OverrideWithComparable(s.Address, other.Address)
+	s.SocketPath = gosettings.OverrideWithComparable(s.SocketPath, other.SocketPath)
+	s.SocketUID = gosettings.OverrideWithComparable(s.SocketUID, other.SocketUID)
+	s.SocketGID = gosettings.OverrideWithComparable(s.SocketGID, other.SocketGID)
+	s.SocketMode = gosettings.OverrideWithComparable(s.SocketMode, other.SocketMode)
 	s.Handler = gosettings.OverrideWithComparable(s.Handler, other.Handler)
 	if other.Logger != nil {
 		s.Logger = other.Logger
@@ -65,9 +90,18 @@ func (s *Settings) OverrideWith(other Settings) {
 }
 
 func (s Settings) Validate() (err error) {
-	err = validate.ListeningAddress(s.Address, os.Getuid())
-	if err != nil {
-		return err
+	switch {
+	case s.Address == "" && s.SocketPath == "":
+		return errors.New("no TCP address nor Unix socket path to listen on")
+	case s.Address != "":
+		err = validate.ListeningAddress(s.Address, os.Getuid())
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.SocketPath != "" && !filepath.IsAbs(s.SocketPath) {
+		return fmt.Errorf("unix socket path is not absolute: %s", s.SocketPath)
 	}
 
 	if s.Handler == nil {
@@ -97,7 +131,13 @@ func (s Settings) Validate() (err error) {
 
 func (s Settings) ToLinesNode() (node *gotree.Node) {
 	node = gotree.New("HTTP server settings:")
-	node.Appendf("Listening address: %s", s.Address)
+	if s.Address != "" {
+		node.Appendf("Listening address: %s", s.Address)
+	}
+	if s.SocketPath != "" {
+		node.Appendf("Unix socket: %s (owner %d:%d, mode %04o)",
+			s.SocketPath, s.SocketUID, s.SocketGID, s.SocketMode)
+	}
 	node.Appendf("Read header timeout: %s", s.ReadHeaderTimeout)
 	node.Appendf("Read timeout: %s", s.ReadTimeout)
 	node.Appendf("Shutdown timeout: %s", s.ShutdownTimeout)
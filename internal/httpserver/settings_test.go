@@ -196,6 +196,15 @@ func Test_Settings_Validate(t *testing.T) {
 			},
 			errMessage: "port value is not an integer: notanint",
 		},
+		"no_address_nor_socket": {
+			errMessage: "no TCP address nor Unix socket path to listen on",
+		},
+		"relative_socket_path": {
+			settings: Settings{
+				SocketPath: "gluetun.sock",
+			},
+			errMessage: "unix socket path is not absolute: gluetun.sock",
+		},
 		"nil handler": {
 			settings: Settings{
 				Address: ":8000",
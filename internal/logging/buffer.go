@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+)
+
+// Buffer is a writer keeping the last log lines written to it,
+// for example to show recent logs in the web dashboard.
+type Buffer struct {
+	maxLines int
+	// lines is a ring buffer where next is the index
+	// of the next line to write, and full is set once
+	// the buffer wrapped around.
+	lines   []string
+	next    int
+	full    bool
+	partial strings.Builder
+	mutex   sync.RWMutex
+}
+
+// NewBuffer creates a buffer keeping up to maxLines lines.
+func NewBuffer(maxLines int) *Buffer {
+	return &Buffer{
+		lines:    make([]string, maxLines),
+		maxLines: maxLines,
+	}
+}
+
+// Write splits the data given in lines and keeps them, evicting the
+// oldest lines if needed. A line without a trailing newline is kept
+// until the rest of the line is written.
+func (b *Buffer) Write(p []byte) (n int, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	data := string(p)
+	for {
+		line, rest, found := strings.Cut(data, "\n")
+		if !found {
+			b.partial.WriteString(line)
+			break
+		}
+		b.partial.WriteString(line)
+		b.add(b.partial.String())
+		b.partial.Reset()
+		data = rest
+	}
+	return len(p), nil
+}
+
+func (b *Buffer) add(line string) {
+	if b.maxLines == 0 {
+		return
+	}
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % b.maxLines
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Lines returns the lines kept, from the oldest to the newest.
+func (b *Buffer) Lines() (lines []string) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	if !b.full {
+		lines = make([]string, b.next)
+		copy(lines, b.lines[:b.next])
+		return lines
+	}
+	lines = make([]string, 0, b.maxLines)
+	lines = append(lines, b.lines[b.next:]...)
+	lines = append(lines, b.lines[:b.next]...)
+	return lines
+}
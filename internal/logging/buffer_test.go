@@ -0,0 +1,24 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Buffer(t *testing.T) {
+	t.Parallel()
+
+	buffer := NewBuffer(3)
+	assert.Empty(t, buffer.Lines())
+
+	writes := []string{"line 1\nline 2\n", "line", " 3\n", "line 4\nline 5 partial"}
+	for _, write := range writes {
+		n, err := buffer.Write([]byte(write))
+		require.NoError(t, err)
+		assert.Equal(t, len(write), n)
+	}
+
+	assert.Equal(t, []string{"line 2", "line 3", "line 4"}, buffer.Lines())
+}
@@ -0,0 +1,40 @@
+package logging
+
+import "strings"
+
+// fieldSeparator separates the log message from its fields,
+// and each field from the next one. It is the ASCII unit
+// separator character, which is not expected in log messages.
+const fieldSeparator = "\x1f"
+
+// Field returns a structured field to append to a log message.
+// The writer returned by [NewWriter] renders it as key=value
+// for the text format, and as a JSON field for the JSON format.
+// For example:
+//
+//	logger.Info("port forwarded" + logging.Field("port", "5000"))
+func Field(key, value string) string {
+	return fieldSeparator + key + "=" + value
+}
+
+type field struct {
+	key   string
+	value string
+}
+
+// splitFields splits the log line given into its message
+// and its fields, appended with [Field].
+func splitFields(line string) (message string, fields []field) {
+	parts := strings.Split(line, fieldSeparator)
+	message = parts[0]
+	if len(parts) == 1 {
+		return message, nil
+	}
+
+	fields = make([]field, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		key, value, _ := strings.Cut(part, "=")
+		fields = append(fields, field{key: key, value: value})
+	}
+	return message, fields
+}
@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewWriter returns a writer converting log lines written by
+// a [github.com/qdm12/log] logger to the format given, which
+// can be "text" or "json", and writing them to w.
+// For the JSON format, the logger must be configured with an
+// empty time format, since the writer sets the time field itself.
+func NewWriter(w io.Writer, format string) io.Writer {
+	if format == "json" {
+		return &jsonWriter{
+			writer: w,
+			now:    time.Now,
+		}
+	}
+	return &textWriter{writer: w}
+}
+
+// textWriter renders the fields of each log line as key=value.
+type textWriter struct {
+	writer io.Writer
+}
+
+func (t *textWriter) Write(p []byte) (n int, err error) {
+	if !bytes.Contains(p, []byte(fieldSeparator)) {
+		return t.writer.Write(p)
+	}
+
+	line := strings.TrimSuffix(string(p), "\n")
+	message, fields := splitFields(line)
+	var builder strings.Builder
+	builder.WriteString(message)
+	for _, field := range fields {
+		value := field.value
+		if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+			value = strconv.Quote(value)
+		}
+		builder.WriteString(" " + field.key + "=" + value)
+	}
+	builder.WriteString("\n")
+
+	_, err = io.WriteString(t.writer, builder.String())
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// jsonWriter converts each log line to a JSON object.
+type jsonWriter struct {
+	writer io.Writer
+	now    func() time.Time
+}
+
+var regexANSIEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// reservedKeys are the JSON keys fields cannot override.
+var reservedKeys = map[string]struct{}{ //nolint:gochecknoglobals
+	"time": {}, "level": {}, "component": {}, "message": {},
+}
+
+func (j *jsonWriter) Write(p []byte) (n int, err error) {
+	line := strings.TrimSuffix(string(p), "\n")
+	level, component, message := parseLine(line)
+	message, fields := splitFields(message)
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	err = encoder.Encode(struct {
+		Time      string `json:"time"`
+		Level     string `json:"level"`
+		Component string `json:"component,omitempty"`
+		Message   string `json:"message"`
+	}{
+		Time:      j.now().UTC().Format(time.RFC3339Nano),
+		Level:     level,
+		Component: component,
+		Message:   message,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(fields) > 0 {
+		// Remove the closing brace and newline to append the fields.
+		buffer.Truncate(buffer.Len() - len("}\n"))
+		for _, field := range fields {
+			key := field.key
+			if _, reserved := reservedKeys[key]; reserved {
+				key = "field_" + key
+			}
+			buffer.WriteString(",")
+			_ = encoder.Encode(key)
+			buffer.Truncate(buffer.Len() - 1) // remove newline
+			buffer.WriteString(":")
+			_ = encoder.Encode(field.value)
+			buffer.Truncate(buffer.Len() - 1) // remove newline
+		}
+		buffer.WriteString("}\n")
+	}
+
+	_, err = j.writer.Write(buffer.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// parseLine parses a log line of the form "LEVEL [component] message",
+// where the component part is optional and the level can be colored.
+func parseLine(line string) (level, component, message string) {
+	level, message, _ = strings.Cut(line, " ")
+	level = strings.ToLower(regexANSIEscape.ReplaceAllString(level, ""))
+
+	if strings.HasPrefix(message, "[") {
+		end := strings.Index(message, "] ")
+		if end != -1 {
+			component = message[1:end]
+			message = message[end+len("] "):]
+		}
+	}
+	return level, component, message
+}
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_textWriter(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		line     string
+		expected string
+	}{
+		"no_field": {
+			line:     "2024-01-01T00:00:00Z INFO [vpn] message\n",
+			expected: "2024-01-01T00:00:00Z INFO [vpn] message\n",
+		},
+		"fields": {
+			line: "INFO [vpn] tunnel is up" + Field("server", "ch-1") +
+				Field("name", "my server") + Field("empty", "") + "\n",
+			expected: `INFO [vpn] tunnel is up server=ch-1 name="my server" empty=""` + "\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			buffer := bytes.NewBuffer(nil)
+			writer := NewWriter(buffer, "text")
+
+			n, err := writer.Write([]byte(testCase.line))
+
+			require.NoError(t, err)
+			assert.Equal(t, len(testCase.line), n)
+			assert.Equal(t, testCase.expected, buffer.String())
+		})
+	}
+}
+
+func Test_jsonWriter(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		line     string
+		expected string
+	}{
+		"no_component": {
+			line:     "WARN message <with> html\n",
+			expected: `{"time":"2024-01-01T00:00:00Z","level":"warn","message":"message <with> html"}` + "\n",
+		},
+		"colored_level_and_component": {
+			line: "\x1b[36mINFO\x1b[0m [port forwarding] port forwarded is 5000\n",
+			expected: `{"time":"2024-01-01T00:00:00Z","level":"info","component":"port forwarding",` +
+				`"message":"port forwarded is 5000"}` + "\n",
+		},
+		"multi_line_message": {
+			line: "ERROR [openvpn] first line\nsecond line\n",
+			expected: `{"time":"2024-01-01T00:00:00Z","level":"error","component":"openvpn",` +
+				`"message":"first line\nsecond line"}` + "\n",
+		},
+		"fields": {
+			line: "INFO [vpn] tunnel is up" + Field("server", "ch-1") +
+				Field("message", "reserved") + "\n",
+			expected: `{"time":"2024-01-01T00:00:00Z","level":"info","component":"vpn",` +
+				`"message":"tunnel is up","server":"ch-1","field_message":"reserved"}` + "\n",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			buffer := bytes.NewBuffer(nil)
+			writer := &jsonWriter{
+				writer: buffer,
+				now: func() time.Time {
+					return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+				},
+			}
+
+			n, err := writer.Write([]byte(testCase.line))
+
+			require.NoError(t, err)
+			assert.Equal(t, len(testCase.line), n)
+			assert.Equal(t, testCase.expected, buffer.String())
+		})
+	}
+}
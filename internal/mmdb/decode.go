@@ -0,0 +1,284 @@
+package mmdb
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+)
+
+type dataType uint8
+
+const (
+	typeExtended dataType = iota
+	typePointer
+	typeString
+	typeDouble
+	typeBytes
+	typeUint16
+	typeUint32
+	typeMap
+	typeInt32
+	typeUint64
+	typeUint128
+	typeArray
+	typeContainer
+	typeEndMarker
+	typeBool
+	typeFloat
+)
+
+var (
+	ErrDataTypeUnknown = errors.New("data type is unknown")
+	ErrDataSizeInvalid = errors.New("data size is not valid")
+	ErrMapKeyNotString = errors.New("map key is not a string")
+	ErrMaxDepthReached = errors.New("maximum data depth reached")
+)
+
+// decoder decodes values from a data section starting
+// at sectionStart in the file and of sectionSize bytes.
+// Offsets are relative to the section start.
+type decoder struct {
+	file         io.ReaderAt
+	sectionStart int64
+	sectionSize  uint64
+}
+
+// remaining returns the number of bytes of the section
+// remaining from the offset given.
+func (d *decoder) remaining(offset uint64) uint64 {
+	if offset >= d.sectionSize {
+		return 0
+	}
+	return d.sectionSize - offset
+}
+
+func (d *decoder) decode(offset uint64) (value any, newOffset uint64, err error) {
+	const depth = 0
+	return d.decodeAt(offset, depth)
+}
+
+func (d *decoder) decodeAt(offset uint64, depth uint) (value any, newOffset uint64, err error) {
+	const maxDepth = 512
+	if depth > maxDepth {
+		return nil, 0, ErrMaxDepthReached
+	}
+
+	kind, size, offset, err := d.decodeControl(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if kind == typePointer {
+		pointer, newOffset, err := d.decodePointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err = d.decodeAt(pointer, depth+1)
+		return value, newOffset, err
+	}
+
+	return d.decodeValue(kind, size, offset, depth)
+}
+
+// decodeControl decodes the control byte(s) at the offset given and
+// returns the data type, its payload size and the offset of the payload.
+// For pointers, the size returned is the 5 bits of the control byte.
+//
+//nolint:mnd
+func (d *decoder) decodeControl(offset uint64) (kind dataType, size, newOffset uint64, err error) {
+	control, err := d.readBytes(offset, 1)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	offset++
+
+	kind = dataType(control[0] >> 5)
+	if kind == typePointer {
+		return kind, uint64(control[0] & 0x1F), offset, nil
+	}
+
+	if kind == typeExtended {
+		extended, err := d.readBytes(offset, 1)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		offset++
+		kind = dataType(extended[0] + 7)
+		if kind <= typeMap || kind > typeFloat {
+			return 0, 0, 0, fmt.Errorf("%w: extended type %d", ErrDataTypeUnknown, kind)
+		}
+	}
+
+	size = uint64(control[0] & 0x1F)
+	if size < 29 {
+		return kind, size, offset, nil
+	}
+
+	sizeBytesCount := size - 28
+	sizeBytes, err := d.readBytes(offset, sizeBytesCount)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	offset += sizeBytesCount
+
+	switch sizeBytesCount {
+	case 1:
+		size = 29 + uintFromBytes(sizeBytes)
+	case 2:
+		size = 285 + uintFromBytes(sizeBytes)
+	default:
+		size = 65821 + uintFromBytes(sizeBytes)
+	}
+	return kind, size, offset, nil
+}
+
+// decodePointer decodes the pointer with the control size bits and
+// offset given, and returns the pointed offset and the offset after
+// the pointer.
+//
+//nolint:mnd
+func (d *decoder) decodePointer(sizeBits, offset uint64) (pointer, newOffset uint64, err error) {
+	pointerSize := (sizeBits >> 3) + 1
+	buffer, err := d.readBytes(offset, pointerSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	newOffset = offset + pointerSize
+
+	value := sizeBits & 0x7
+	switch pointerSize {
+	case 1:
+		pointer = value<<8 | uintFromBytes(buffer)
+	case 2:
+		pointer = (value<<16 | uintFromBytes(buffer)) + 2048
+	case 3:
+		pointer = (value<<24 | uintFromBytes(buffer)) + 526336
+	default:
+		pointer = uintFromBytes(buffer)
+	}
+	return pointer, newOffset, nil
+}
+
+func (d *decoder) decodeValue(kind dataType, size, offset uint64, depth uint) (
+	value any, newOffset uint64, err error,
+) {
+	switch kind {
+	case typeMap:
+		return d.decodeMap(size, offset, depth)
+	case typeArray:
+		return d.decodeArray(size, offset, depth)
+	case typeBool:
+		return size != 0, offset, nil
+	case typeContainer, typeEndMarker:
+		return nil, offset, nil
+	}
+
+	buffer, err := d.readBytes(offset, size)
+	if err != nil {
+		return nil, 0, err
+	}
+	newOffset = offset + size
+
+	switch kind {
+	case typeString:
+		return string(buffer), newOffset, nil
+	case typeBytes:
+		return buffer, newOffset, nil
+	case typeDouble:
+		const doubleSize = 8
+		if size != doubleSize {
+			return nil, 0, fmt.Errorf("%w: %d bytes for double", ErrDataSizeInvalid, size)
+		}
+		return math.Float64frombits(uintFromBytes(buffer)), newOffset, nil
+	case typeFloat:
+		const floatSize = 4
+		if size != floatSize {
+			return nil, 0, fmt.Errorf("%w: %d bytes for float", ErrDataSizeInvalid, size)
+		}
+		return float64(math.Float32frombits(uint32(uintFromBytes(buffer)))), newOffset, nil
+	case typeUint16, typeUint32, typeUint64:
+		const maxUintSize = 8
+		if size > maxUintSize {
+			return nil, 0, fmt.Errorf("%w: %d bytes for unsigned integer", ErrDataSizeInvalid, size)
+		}
+		return uintFromBytes(buffer), newOffset, nil
+	case typeInt32:
+		const int32Size = 4
+		if size > int32Size {
+			return nil, 0, fmt.Errorf("%w: %d bytes for int32", ErrDataSizeInvalid, size)
+		}
+		// Sign extend if all 4 bytes are present.
+		return int64(int32(uint32(uintFromBytes(buffer)))), newOffset, nil
+	case typeUint128:
+		return new(big.Int).SetBytes(buffer), newOffset, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: %d", ErrDataTypeUnknown, kind)
+	}
+}
+
+func (d *decoder) decodeMap(size, offset uint64, depth uint) (
+	value any, newOffset uint64, err error,
+) {
+	// Each map entry takes at least one byte for its key and one
+	// byte for its value, so the size cannot exceed half of the
+	// remaining bytes for a valid file.
+	const minEntrySize = 2
+	if size > d.remaining(offset)/minEntrySize {
+		return nil, 0, fmt.Errorf("%w: %d map entries for %d bytes remaining",
+			ErrDataSizeInvalid, size, d.remaining(offset))
+	}
+	fields := make(map[string]any, size)
+	for range size {
+		var key any
+		key, offset, err = d.decodeAt(offset, depth+1)
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding map key: %w", err)
+		}
+		keyString, ok := key.(string)
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: %T", ErrMapKeyNotString, key)
+		}
+
+		fields[keyString], offset, err = d.decodeAt(offset, depth+1)
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding map value for key %s: %w", keyString, err)
+		}
+	}
+	return fields, offset, nil
+}
+
+func (d *decoder) decodeArray(size, offset uint64, depth uint) (
+	value any, newOffset uint64, err error,
+) {
+	// Each array element takes at least one byte.
+	if size > d.remaining(offset) {
+		return nil, 0, fmt.Errorf("%w: %d array elements for %d bytes remaining",
+			ErrDataSizeInvalid, size, d.remaining(offset))
+	}
+	values := make([]any, size)
+	for i := range values {
+		values[i], offset, err = d.decodeAt(offset, depth+1)
+		if err != nil {
+			return nil, 0, fmt.Errorf("decoding array element %d: %w", i, err)
+		}
+	}
+	return values, offset, nil
+}
+
+func (d *decoder) readBytes(offset, size uint64) (buffer []byte, err error) {
+	if size > d.remaining(offset) {
+		return nil, fmt.Errorf("%w: reading %d bytes at offset %d with %d bytes remaining",
+			ErrDataSizeInvalid, size, offset, d.remaining(offset))
+	}
+	buffer = make([]byte, size)
+	if size == 0 {
+		return buffer, nil
+	}
+	_, err = d.file.ReadAt(buffer, d.sectionStart+int64(offset))
+	if err != nil {
+		return nil, fmt.Errorf("reading %d bytes at offset %d: %w", size, offset, err)
+	}
+	return buffer, nil
+}
@@ -0,0 +1,60 @@
+package mmdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_decoder_sizeExceedsSection(t *testing.T) {
+	t.Parallel()
+
+	// Control bytes with a size field using 3 extra size bytes,
+	// for a size of 65821 + 0xFFFFFF, much larger than the data.
+	const hugeSize = 0x1F
+	hugeSizeBytes := []byte{0xFF, 0xFF, 0xFF}
+
+	testCases := map[string]struct {
+		data       []byte
+		errMessage string
+	}{
+		"map": {
+			data: append([]byte{byte(typeMap)<<5 | hugeSize}, hugeSizeBytes...),
+			errMessage: "data size is not valid: " +
+				"16843036 map entries for 0 bytes remaining",
+		},
+		"array": {
+			data: append([]byte{hugeSize, byte(typeArray - 7)}, hugeSizeBytes...), //nolint:mnd
+			errMessage: "data size is not valid: " +
+				"16843036 array elements for 0 bytes remaining",
+		},
+		"string": {
+			data: append([]byte{byte(typeString)<<5 | hugeSize}, hugeSizeBytes...),
+			errMessage: "data size is not valid: " +
+				"reading 16843036 bytes at offset 4 with 0 bytes remaining",
+		},
+		"map_larger_than_remaining": {
+			data: append([]byte{byte(typeMap)<<5 | 3}, encodeString("key")...),
+			errMessage: "data size is not valid: " +
+				"3 map entries for 4 bytes remaining",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			decoder := &decoder{
+				file:        bytes.NewReader(testCase.data),
+				sectionSize: uint64(len(testCase.data)),
+			}
+
+			value, _, err := decoder.decode(0)
+
+			assert.ErrorIs(t, err, ErrDataSizeInvalid)
+			assert.EqualError(t, err, testCase.errMessage)
+			assert.Nil(t, value)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+// Package mmdb implements a minimal reader for MaxMind DB files,
+// as used by MaxMind GeoIP2/GeoLite2 and DB-IP databases.
+// See https://maxmind.github.io/MaxMind-DB/
+package mmdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+)
+
+// Reader reads records from a MaxMind DB file without
+// loading the file in memory.
+type Reader struct {
+	file     io.ReaderAt
+	closer   io.Closer
+	metadata Metadata
+	// dataSectionStart is the offset of the data section in the file.
+	dataSectionStart int64
+	// dataSectionSize is the number of bytes from the data section
+	// start to the end of the file.
+	dataSectionSize uint64
+	// ipv4Start is the node where IPv4 addresses lookups start
+	// in an IPv6 search tree.
+	ipv4Start uint64
+}
+
+// Metadata contains the metadata fields of the database used for lookups.
+type Metadata struct {
+	NodeCount    uint64
+	RecordSize   uint64
+	IPVersion    uint64
+	DatabaseType string
+}
+
+const (
+	ipVersion4  = 4
+	ipVersion6  = 6
+	bitsPerByte = 8
+	// dataSectionSeparatorSize is the size of the zeroed bytes
+	// between the search tree and the data section.
+	dataSectionSeparatorSize = 16
+)
+
+var (
+	ErrMetadataNotFound      = errors.New("metadata section not found")
+	ErrMetadataNotValid      = errors.New("metadata is not valid")
+	ErrRecordSizeUnsupported = errors.New("record size is not supported")
+	ErrIPv6InIPv4Database    = errors.New("cannot lookup IPv6 address in IPv4 database")
+	ErrSearchTreeCorrupt     = errors.New("search tree is corrupt")
+)
+
+// Open opens the MaxMind DB file at the path given.
+// The caller must call Close on the returned reader.
+func Open(path string) (reader *Reader, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("getting file information: %w", err)
+	}
+
+	reader, err = newReader(file, stat.Size())
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	reader.closer = file
+	return reader, nil
+}
+
+func newReader(file io.ReaderAt, size int64) (reader *Reader, err error) {
+	metadataStart, err := findMetadataStart(file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataDecoder := &decoder{
+		file:         file,
+		sectionStart: metadataStart,
+		sectionSize:  uint64(size - metadataStart),
+	}
+	value, _, err := metadataDecoder.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("decoding metadata: %w", err)
+	}
+	metadata, err := parseMetadata(value)
+	if err != nil {
+		return nil, err
+	}
+
+	searchTreeSize := metadata.NodeCount * nodeSize(metadata.RecordSize)
+	dataSectionStart := searchTreeSize + dataSectionSeparatorSize
+	if searchTreeSize/nodeSize(metadata.RecordSize) != metadata.NodeCount ||
+		dataSectionStart > uint64(metadataStart) {
+		return nil, fmt.Errorf("%w: search tree of %d nodes exceeds the file size",
+			ErrMetadataNotValid, metadata.NodeCount)
+	}
+	reader = &Reader{
+		file:             file,
+		metadata:         metadata,
+		dataSectionStart: int64(dataSectionStart),
+		dataSectionSize:  uint64(size) - dataSectionStart,
+	}
+
+	if metadata.IPVersion == ipVersion6 {
+		// IPv4 addresses are stored in the ::/96 subnet.
+		const ipv4SubnetBits = 96
+		for i := 0; i < ipv4SubnetBits && reader.ipv4Start < metadata.NodeCount; i++ {
+			reader.ipv4Start, err = reader.readRecord(reader.ipv4Start, 0)
+			if err != nil {
+				return nil, fmt.Errorf("finding IPv4 start node: %w", err)
+			}
+		}
+	}
+
+	return reader, nil
+}
+
+// findMetadataStart returns the offset of the metadata section,
+// found after the last occurrence of the metadata start marker.
+func findMetadataStart(file io.ReaderAt, size int64) (offset int64, err error) {
+	const maxMetadataSize = 128 * 1024
+	start := max(size-maxMetadataSize, 0)
+	buffer := make([]byte, size-start)
+	_, err = file.ReadAt(buffer, start)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("reading end of file: %w", err)
+	}
+
+	marker := []byte("\xAB\xCD\xEFMaxMind.com")
+	index := bytes.LastIndex(buffer, marker)
+	if index == -1 {
+		return 0, ErrMetadataNotFound
+	}
+	return start + int64(index) + int64(len(marker)), nil
+}
+
+func parseMetadata(value any) (metadata Metadata, err error) {
+	fields, ok := value.(map[string]any)
+	if !ok {
+		return metadata, fmt.Errorf("%w: not a map", ErrMetadataNotValid)
+	}
+
+	metadata.NodeCount, _ = fields["node_count"].(uint64)
+	metadata.RecordSize, _ = fields["record_size"].(uint64)
+	metadata.IPVersion, _ = fields["ip_version"].(uint64)
+	metadata.DatabaseType, _ = fields["database_type"].(string)
+
+	switch {
+	case metadata.NodeCount == 0:
+		return metadata, fmt.Errorf("%w: node count is zero", ErrMetadataNotValid)
+	case metadata.IPVersion != ipVersion4 && metadata.IPVersion != ipVersion6:
+		return metadata, fmt.Errorf("%w: IP version %d", ErrMetadataNotValid, metadata.IPVersion)
+	}
+
+	switch metadata.RecordSize {
+	case 24, 28, 32: //nolint:mnd
+	default:
+		return metadata, fmt.Errorf("%w: %d", ErrRecordSizeUnsupported, metadata.RecordSize)
+	}
+
+	return metadata, nil
+}
+
+// Metadata returns the metadata of the database.
+func (r *Reader) Metadata() Metadata {
+	return r.metadata
+}
+
+// Close closes the database file.
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// Lookup returns the record for the IP address given, or a nil
+// record if the IP address is not found in the database.
+// Maps are decoded as map[string]any, arrays as []any, integers
+// as uint64 or int64, and floating point numbers as float64.
+func (r *Reader) Lookup(ip netip.Addr) (record any, err error) {
+	ip = ip.Unmap()
+	node := uint64(0)
+	if ip.Is4() {
+		if r.metadata.IPVersion == ipVersion6 {
+			node = r.ipv4Start
+		}
+	} else if r.metadata.IPVersion == ipVersion4 {
+		return nil, fmt.Errorf("%w: %s", ErrIPv6InIPv4Database, ip)
+	}
+
+	ipBytes := ip.AsSlice()
+	bitCount := len(ipBytes) * bitsPerByte
+	for i := 0; i < bitCount && node < r.metadata.NodeCount; i++ {
+		bit := (ipBytes[i/bitsPerByte] >> (bitsPerByte - 1 - i%bitsPerByte)) & 1
+		node, err = r.readRecord(node, bit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case node == r.metadata.NodeCount:
+		return nil, nil //nolint:nilnil
+	case node < r.metadata.NodeCount:
+		return nil, fmt.Errorf("%w: lookup ended on node %d", ErrSearchTreeCorrupt, node)
+	}
+
+	offset := node - r.metadata.NodeCount - dataSectionSeparatorSize
+	dataDecoder := &decoder{
+		file:         r.file,
+		sectionStart: r.dataSectionStart,
+		sectionSize:  r.dataSectionSize,
+	}
+	record, _, err = dataDecoder.decode(offset)
+	if err != nil {
+		return nil, fmt.Errorf("decoding record: %w", err)
+	}
+	return record, nil
+}
+
+// nodeSize returns the size in bytes of a search tree node,
+// which is made of two records.
+func nodeSize(recordSize uint64) uint64 {
+	const recordsPerNode = 2
+	return recordSize * recordsPerNode / bitsPerByte
+}
+
+// readRecord reads the left (bit 0) or right (bit 1) record of the node given.
+//
+//nolint:mnd
+func (r *Reader) readRecord(node uint64, bit byte) (record uint64, err error) {
+	size := nodeSize(r.metadata.RecordSize)
+	buffer := make([]byte, size)
+	_, err = r.file.ReadAt(buffer, int64(node*size))
+	if err != nil {
+		return 0, fmt.Errorf("reading node %d: %w", node, err)
+	}
+
+	switch r.metadata.RecordSize {
+	case 24:
+		if bit == 0 {
+			return uintFromBytes(buffer[0:3]), nil
+		}
+		return uintFromBytes(buffer[3:6]), nil
+	case 28:
+		if bit == 0 {
+			return uint64(buffer[3]&0xF0)<<20 | uintFromBytes(buffer[0:3]), nil
+		}
+		return uint64(buffer[3]&0x0F)<<24 | uintFromBytes(buffer[4:7]), nil
+	default: // 32
+		if bit == 0 {
+			return uintFromBytes(buffer[0:4]), nil
+		}
+		return uintFromBytes(buffer[4:8]), nil
+	}
+}
+
+func uintFromBytes(b []byte) (value uint64) {
+	for _, x := range b {
+		value = value<<bitsPerByte | uint64(x)
+	}
+	return value
+}
@@ -0,0 +1,279 @@
+package mmdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The helpers below encode values and search trees to build
+// small MaxMind DB databases for testing purposes.
+
+func encodeControl(kind dataType, size int) []byte {
+	if kind >= typeInt32 {
+		return []byte{byte(size), byte(kind - 7)} //nolint:mnd
+	}
+	return []byte{byte(kind)<<5 | byte(size)} //nolint:mnd
+}
+
+func encodeString(s string) []byte {
+	return append(encodeControl(typeString, len(s)), s...)
+}
+
+func encodeUint(kind dataType, value uint64) []byte {
+	b := binary.BigEndian.AppendUint64(nil, value)
+	b = bytes.TrimLeft(b, "\x00")
+	return append(encodeControl(kind, len(b)), b...)
+}
+
+func encodeDouble(value float64) []byte {
+	return binary.BigEndian.AppendUint64(encodeControl(typeDouble, 8), //nolint:mnd
+		math.Float64bits(value))
+}
+
+func encodeMap(keyValues ...[]byte) []byte {
+	b := encodeControl(typeMap, len(keyValues)/2) //nolint:mnd
+	for _, keyValue := range keyValues {
+		b = append(b, keyValue...)
+	}
+	return b
+}
+
+func encodeArray(values ...[]byte) []byte {
+	b := encodeControl(typeArray, len(values))
+	for _, value := range values {
+		b = append(b, value...)
+	}
+	return b
+}
+
+// encodePointer encodes a pointer smaller than 2048 on 2 bytes.
+func encodePointer(pointer uint16) []byte {
+	return []byte{byte(typePointer)<<5 | byte(pointer>>8), byte(pointer)} //nolint:mnd
+}
+
+type trieNode struct {
+	children [2]*trieNode
+	// dataOffset is the data offset if the node is a leaf with data.
+	dataOffset *uint64
+}
+
+func buildDatabase(t *testing.T, ipVersion, recordSize uint64,
+	prefixToOffset map[netip.Prefix]uint64, dataSection []byte,
+) []byte {
+	t.Helper()
+
+	root := &trieNode{}
+	for prefix, offset := range prefixToOffset {
+		bits := prefix.Bits()
+		ipBytes := prefix.Addr().AsSlice()
+		if ipVersion == ipVersion6 && prefix.Addr().Is4() {
+			ipBytes = append(make([]byte, 12), ipBytes...) //nolint:mnd
+			bits += 96
+		}
+		node := root
+		for i := range bits {
+			bit := (ipBytes[i/8] >> (7 - i%8)) & 1 //nolint:mnd
+			if node.children[bit] == nil {
+				node.children[bit] = &trieNode{}
+			}
+			node = node.children[bit]
+		}
+		node.dataOffset = &offset
+	}
+
+	// Number inner nodes in breadth first order
+	var nodes []*trieNode
+	nodeToIndex := map[*trieNode]uint64{}
+	queue := []*trieNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node.dataOffset != nil {
+			continue
+		}
+		nodeToIndex[node] = uint64(len(nodes))
+		nodes = append(nodes, node)
+		for _, child := range node.children {
+			if child != nil {
+				queue = append(queue, child)
+			}
+		}
+	}
+	nodeCount := uint64(len(nodes))
+
+	recordValue := func(child *trieNode) uint64 {
+		switch {
+		case child == nil:
+			return nodeCount
+		case child.dataOffset != nil:
+			return nodeCount + dataSectionSeparatorSize + *child.dataOffset
+		default:
+			return nodeToIndex[child]
+		}
+	}
+
+	var file []byte
+	for _, node := range nodes {
+		left, right := recordValue(node.children[0]), recordValue(node.children[1])
+		switch recordSize {
+		case 24: //nolint:mnd
+			file = append(file, byte(left>>16), byte(left>>8), byte(left), //nolint:mnd
+				byte(right>>16), byte(right>>8), byte(right)) //nolint:mnd
+		case 28: //nolint:mnd
+			file = append(file, byte(left>>16), byte(left>>8), byte(left), //nolint:mnd
+				byte(left>>24)<<4|byte(right>>24),            //nolint:mnd
+				byte(right>>16), byte(right>>8), byte(right)) //nolint:mnd
+		case 32: //nolint:mnd
+			file = binary.BigEndian.AppendUint32(file, uint32(left))
+			file = binary.BigEndian.AppendUint32(file, uint32(right))
+		}
+	}
+
+	file = append(file, make([]byte, dataSectionSeparatorSize)...)
+	file = append(file, dataSection...)
+	file = append(file, "\xAB\xCD\xEFMaxMind.com"...)
+	file = append(file, encodeMap(
+		encodeString("node_count"), encodeUint(typeUint32, nodeCount),
+		encodeString("record_size"), encodeUint(typeUint16, recordSize),
+		encodeString("ip_version"), encodeUint(typeUint16, ipVersion),
+		encodeString("database_type"), encodeString("Test-City"),
+	)...)
+	return file
+}
+
+func Test_Reader_Lookup(t *testing.T) {
+	t.Parallel()
+
+	// Data section with a shared string referenced by a pointer.
+	var dataSection []byte
+	sharedOffset := uint64(len(dataSection))
+	dataSection = append(dataSection, encodeString("Europe")...)
+	franceOffset := uint64(len(dataSection))
+	dataSection = append(dataSection, encodeMap(
+		encodeString("continent"), encodePointer(uint16(sharedOffset)),
+		encodeString("country"), encodeMap(
+			encodeString("iso_code"), encodeString("FR"),
+		),
+		encodeString("location"), encodeMap(
+			encodeString("latitude"), encodeDouble(48.5),
+		),
+		encodeString("subdivisions"), encodeArray(encodeString("IDF")),
+		encodeString("is_anycast"), encodeControl(typeBool, 1),
+		encodeString("asn"), encodeUint(typeUint32, 3215),
+	)...)
+	germanyOffset := uint64(len(dataSection))
+	dataSection = append(dataSection, encodeMap(
+		encodeString("continent"), encodePointer(uint16(sharedOffset)),
+	)...)
+
+	franceRecord := map[string]any{
+		"continent":    "Europe",
+		"country":      map[string]any{"iso_code": "FR"},
+		"location":     map[string]any{"latitude": 48.5},
+		"subdivisions": []any{"IDF"},
+		"is_anycast":   true,
+		"asn":          uint64(3215),
+	}
+	germanyRecord := map[string]any{"continent": "Europe"}
+
+	testCases := map[string]struct {
+		ipVersion  uint64
+		recordSize uint64
+		networks   map[netip.Prefix]uint64
+		ip         netip.Addr
+		record     any
+		errMessage string
+	}{
+		"ipv4_database_found": {
+			ipVersion:  ipVersion4,
+			recordSize: 24,
+			networks: map[netip.Prefix]uint64{
+				netip.MustParsePrefix("1.2.0.0/16"): franceOffset,
+				netip.MustParsePrefix("5.0.0.0/8"):  germanyOffset,
+			},
+			ip:     netip.MustParseAddr("1.2.3.4"),
+			record: franceRecord,
+		},
+		"ipv4_database_not_found": {
+			ipVersion:  ipVersion4,
+			recordSize: 24,
+			networks: map[netip.Prefix]uint64{
+				netip.MustParsePrefix("1.2.0.0/16"): franceOffset,
+			},
+			ip: netip.MustParseAddr("1.3.0.0"),
+		},
+		"ipv4_database_ipv6_address": {
+			ipVersion:  ipVersion4,
+			recordSize: 24,
+			networks: map[netip.Prefix]uint64{
+				netip.MustParsePrefix("1.2.0.0/16"): franceOffset,
+			},
+			ip:         netip.MustParseAddr("2001:db8::1"),
+			errMessage: "cannot lookup IPv6 address in IPv4 database: 2001:db8::1",
+		},
+		"ipv6_database_ipv4_address_record_size_28": {
+			ipVersion:  ipVersion6,
+			recordSize: 28,
+			networks: map[netip.Prefix]uint64{
+				netip.MustParsePrefix("1.2.0.0/16"):    franceOffset,
+				netip.MustParsePrefix("2001:db8::/32"): germanyOffset,
+			},
+			ip:     netip.MustParseAddr("5.6.7.8"),
+			record: nil,
+		},
+		"ipv6_database_ipv6_address_record_size_32": {
+			ipVersion:  ipVersion6,
+			recordSize: 32,
+			networks: map[netip.Prefix]uint64{
+				netip.MustParsePrefix("1.2.0.0/16"):    franceOffset,
+				netip.MustParsePrefix("2001:db8::/32"): germanyOffset,
+			},
+			ip:     netip.MustParseAddr("2001:db8::1"),
+			record: germanyRecord,
+		},
+		"ipv6_database_ipv4_mapped_address": {
+			ipVersion:  ipVersion6,
+			recordSize: 24,
+			networks: map[netip.Prefix]uint64{
+				netip.MustParsePrefix("1.2.0.0/16"): franceOffset,
+			},
+			ip:     netip.MustParseAddr("::ffff:1.2.3.4"),
+			record: franceRecord,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file := buildDatabase(t, testCase.ipVersion, testCase.recordSize,
+				testCase.networks, dataSection)
+			reader, err := newReader(bytes.NewReader(file), int64(len(file)))
+			require.NoError(t, err)
+			assert.Equal(t, "Test-City", reader.Metadata().DatabaseType)
+
+			record, err := reader.Lookup(testCase.ip)
+
+			assert.Equal(t, testCase.record, record)
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_newReader_noMetadata(t *testing.T) {
+	t.Parallel()
+
+	file := []byte("not a database")
+	_, err := newReader(bytes.NewReader(file), int64(len(file)))
+	assert.ErrorIs(t, err, ErrMetadataNotFound)
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"net/netip"
+	"time"
+)
+
+// AuditEntry is a state changing request made to the control server.
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+	// Role is the name of the authentication role authorized
+	// for the request.
+	Role string `json:"role"`
+	// ClientIP is the IP address of the client, and is not set
+	// for a client connected through the Unix socket.
+	ClientIP netip.Addr `json:"client_ip,omitzero"`
+	// ClientUID and ClientPID are the user ID and process ID
+	// of the client process, and are only set for a client
+	// connected through the Unix socket.
+	ClientUID *uint32 `json:"client_uid,omitempty"`
+	ClientPID *int32  `json:"client_pid,omitempty"`
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	// Request is the JSON request body with secret values redacted,
+	// and is empty if the request body is empty or is not JSON.
+	Request    json.RawMessage `json:"request,omitempty"`
+	StatusCode int             `json:"status_code"`
+	// Changes are the settings changes made by the request,
+	// with secret values redacted.
+	Changes []SettingChange `json:"changes,omitempty"`
+}
+
+// SettingChange is a change of a setting value, where the path
+// is the dot separated JSON field names of the setting, for
+// example "provider.server_selection.countries".
+type SettingChange struct {
+	Path     string `json:"path"`
+	Previous any    `json:"previous"`
+	Current  any    `json:"current"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// AuthLockout is a client locked out after failing
+// to authenticate too many times.
+type AuthLockout struct {
+	// IP is the IP address of the client locked out, and is
+	// not set for a client connected through the Unix socket.
+	IP netip.Addr `json:"ip,omitzero"`
+	// UID is the user ID of the process of the client locked out,
+	// and is only set for a client connected through the Unix socket.
+	UID *uint32 `json:"uid,omitempty"`
+	// Until is the time at which the lockout ends.
+	Until time.Time `json:"until"`
+	// Lockouts is the number of consecutive lockouts
+	// of the client, including the current one.
+	Lockouts uint `json:"lockouts"`
+}
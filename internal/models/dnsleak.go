@@ -0,0 +1,32 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// DNSLeakReport is the result of a DNS leak check.
+type DNSLeakReport struct {
+	// Time is the time the leak check completed.
+	Time time.Time `json:"time"`
+	// Resolvers are the resolvers seen by the leak check service,
+	// sorted by decreasing number of queries seen.
+	Resolvers []DNSLeakResolver `json:"resolvers"`
+	// Checked is true if the resolvers seen were compared against
+	// a set of expected resolvers.
+	Checked bool `json:"checked"`
+	// Leaking is true if at least one resolver seen is not expected.
+	Leaking bool `json:"leaking"`
+	// Error is the error message if the leak check failed.
+	Error string `json:"error,omitempty"`
+}
+
+// DNSLeakResolver is a resolver seen by the DNS leak check service.
+type DNSLeakResolver struct {
+	IP netip.Addr `json:"ip"`
+	// Percent is the percentage of queries seen from this resolver.
+	Percent uint `json:"percent"`
+	// Expected is true if the resolver IP is in the expected resolvers set.
+	// It is always false if the report Checked field is false.
+	Expected bool `json:"expected"`
+}
@@ -1,11 +1,11 @@
 package models
 
 type FilterChoices struct {
-	Countries  []string
-	Regions    []string
-	Cities     []string
-	Categories []string
-	ISPs       []string
-	Names      []string
-	Hostnames  []string
+	Countries  []string `json:"countries"`
+	Regions    []string `json:"regions"`
+	Cities     []string `json:"cities"`
+	Categories []string `json:"categories"`
+	ISPs       []string `json:"isps"`
+	Names      []string `json:"names"`
+	Hostnames  []string `json:"hostnames"`
 }
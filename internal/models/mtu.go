@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// MTUDiscovery is the report of a path MTU discovery run
+// on the VPN interface.
+type MTUDiscovery struct {
+	// Interface is the VPN interface name.
+	Interface string `json:"interface"`
+	// Trigger is what started the discovery, and can be
+	// "tunnel up", "periodic" or "manual".
+	Trigger string `json:"trigger"`
+	// Start is the time the discovery started at.
+	Start time.Time `json:"start"`
+	// DurationMilliseconds is the discovery duration in milliseconds.
+	DurationMilliseconds int64 `json:"duration_ms"`
+	// PreviousMTU is the VPN interface MTU before the discovery.
+	PreviousMTU uint32 `json:"previous_mtu"`
+	// MTU is the VPN interface MTU after the discovery, which
+	// is the previous MTU if the discovery failed.
+	MTU uint32 `json:"mtu"`
+	// Method is the discovery method which found the MTU,
+	// and can be "icmp" or "tcp". It is empty if the discovery failed.
+	Method string `json:"method,omitempty"`
+	// Probes are the ICMP and TCP discovery attempts, in order.
+	Probes []MTUProbe `json:"probes"`
+	// Error is the discovery error message, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// MTUProbe is a single ICMP or TCP path MTU discovery attempt.
+type MTUProbe struct {
+	// Method is "icmp" or "tcp".
+	Method string `json:"method"`
+	// Target is the address probed, or the comma separated
+	// addresses probed for TCP.
+	Target string `json:"target"`
+	// MTU is the maximum valid MTU found, and is 0 if the probe failed.
+	MTU uint32 `json:"mtu,omitempty"`
+	// DurationMilliseconds is the probe duration in milliseconds.
+	DurationMilliseconds int64 `json:"duration_ms"`
+	// Error is the probe error message, if any.
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+type NotificationEvent string
+
+const (
+	NotificationTunnelUp          NotificationEvent = "tunnel_up"
+	NotificationTunnelDown        NotificationEvent = "tunnel_down"
+	NotificationVPNCrashed        NotificationEvent = "vpn_crashed"
+	NotificationHealthFailure     NotificationEvent = "health_failure"
+	NotificationPortChanged       NotificationEvent = "port_changed"
+	NotificationPublicIPChanged   NotificationEvent = "public_ip_changed"
+	NotificationServersUpdated    NotificationEvent = "servers_updated"
+	NotificationServerUnavailable NotificationEvent = "server_unavailable"
+)
+
+// NotificationEvents returns all the notification events.
+func NotificationEvents() []NotificationEvent {
+	return []NotificationEvent{
+		NotificationTunnelUp,
+		NotificationTunnelDown,
+		NotificationVPNCrashed,
+		NotificationHealthFailure,
+		NotificationPortChanged,
+		NotificationPublicIPChanged,
+		NotificationServersUpdated,
+		NotificationServerUnavailable,
+	}
+}
+
+// Title returns a short human readable title for the event.
+func (n NotificationEvent) Title() string {
+	switch n {
+	case NotificationTunnelUp:
+		return "VPN tunnel up"
+	case NotificationTunnelDown:
+		return "VPN tunnel down"
+	case NotificationVPNCrashed:
+		return "VPN crashed"
+	case NotificationHealthFailure:
+		return "Health check failed"
+	case NotificationPortChanged:
+		return "Forwarded port changed"
+	case NotificationPublicIPChanged:
+		return "Public IP address changed"
+	case NotificationServersUpdated:
+		return "VPN servers updated"
+	case NotificationServerUnavailable:
+		return "VPN server unavailable"
+	default:
+		return string(n)
+	}
+}
+
+// Notification is a typed event sent to the notification sinks.
+type Notification struct {
+	Event   NotificationEvent `json:"event"`
+	Title   string            `json:"title"`
+	Message string            `json:"message"`
+	Time    time.Time         `json:"time"`
+	// Fields contains extra event specific information,
+	// such as the VPN server name or the forwarded ports.
+	Fields map[string]string `json:"fields,omitempty"`
+}
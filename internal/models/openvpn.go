@@ -0,0 +1,30 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// OpenVPNStatus is the status of the OpenVPN process,
+// as reported by its management interface.
+type OpenVPNStatus struct {
+	// State is the OpenVPN state, for example CONNECTING,
+	// CONNECTED, RECONNECTING or EXITING.
+	State string `json:"state"`
+	// StateDescription is the optional description of the state,
+	// for example SUCCESS or auth-failure.
+	StateDescription string `json:"state_description,omitempty"`
+	// StateTime is the time at which OpenVPN entered the state.
+	StateTime time.Time `json:"state_time"`
+	// LocalIP is the VPN interface IP address, and is only
+	// valid once connected.
+	LocalIP netip.Addr `json:"local_ip"`
+	// RemoteIP is the VPN server IP address.
+	RemoteIP netip.Addr `json:"remote_ip"`
+	// BytesIn is the number of bytes received from the VPN server.
+	BytesIn uint64 `json:"bytes_in"`
+	// BytesOut is the number of bytes sent to the VPN server.
+	BytesOut uint64 `json:"bytes_out"`
+	// AuthFailed is true if the VPN server rejected the credentials.
+	AuthFailed bool `json:"auth_failed"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// TorrentClientStatus is the synchronization status of the
+// forwarded port with the listening port of a torrent client.
+type TorrentClientStatus struct {
+	Client   string    `json:"client"`
+	Port     uint16    `json:"port,omitempty"`
+	Synced   bool      `json:"synced"`
+	LastSync time.Time `json:"last_sync,omitzero"`
+	Error    string    `json:"error,omitempty"`
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"net/netip"
+	"time"
 )
 
 type PublicIP struct {
@@ -30,3 +31,18 @@ func (p *PublicIP) Copy() (publicIPCopy PublicIP) {
 	}
 	return publicIPCopy
 }
+
+// PublicIPHistoryEntry is an entry of the public IP addresses history.
+type PublicIPHistoryEntry struct {
+	IP           netip.Addr `json:"public_ip"`
+	Country      string     `json:"country,omitempty"`
+	City         string     `json:"city,omitempty"`
+	Organization string     `json:"organization,omitempty"`
+	ServerName   string     `json:"server_name,omitempty"`
+	Start        time.Time  `json:"start"`
+	// End is the zero time if the public IP address is still in use.
+	End time.Time `json:"end,omitzero"`
+	// DurationSeconds is the number of seconds the public IP address
+	// was used for, or has been used for if it is still in use.
+	DurationSeconds uint64 `json:"duration_seconds"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// ServersUpdateReport contains the changes made to the
+// servers data by a servers update.
+type ServersUpdateReport struct {
+	Time      time.Time             `json:"time"`
+	Providers []ProviderServersDiff `json:"providers"`
+}
+
+// ProviderServersDiff contains the changes made to
+// the servers data of a provider.
+type ProviderServersDiff struct {
+	Provider      string          `json:"provider"`
+	PreviousCount int             `json:"previous_count"`
+	CurrentCount  int             `json:"current_count"`
+	Added         []string        `json:"added,omitempty"`
+	Removed       []string        `json:"removed,omitempty"`
+	Changed       []ServerChanges `json:"changed,omitempty"`
+}
+
+// IsEmpty returns true if the diff contains no change.
+func (p ProviderServersDiff) IsEmpty() bool {
+	return len(p.Added) == 0 && len(p.Removed) == 0 && len(p.Changed) == 0
+}
+
+// ServerChanges contains the changes made to a server
+// present both before and after the update.
+type ServerChanges struct {
+	Server     string        `json:"server"`
+	AddedIPs   []netip.Addr  `json:"added_ips,omitempty"`
+	RemovedIPs []netip.Addr  `json:"removed_ips,omitempty"`
+	Fields     []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange is a change of a server field value,
+// where the name is the server JSON field name,
+// for example "port_forward" or "stream".
+type FieldChange struct {
+	Name     string `json:"name"`
+	Previous any    `json:"previous"`
+	Current  any    `json:"current"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"net/netip"
+	"time"
+)
+
+// WireguardStatus is the status of the Wireguard server peer,
+// as seen by the Wireguard or AmneziaWG device.
+type WireguardStatus struct {
+	// Interface is the name of the Wireguard network interface.
+	Interface string `json:"interface"`
+	// PublicKey is the server peer public key.
+	PublicKey string `json:"public_key"`
+	// Endpoint is the server peer endpoint address.
+	Endpoint netip.AddrPort `json:"endpoint"`
+	// LastHandshake is the time of the last handshake with the
+	// server peer, and is the zero time if no handshake occurred.
+	LastHandshake time.Time `json:"last_handshake"`
+	// ReceivedBytes is the number of bytes received from the server peer.
+	ReceivedBytes int64 `json:"rx_bytes"`
+	// TransmittedBytes is the number of bytes sent to the server peer.
+	TransmittedBytes int64 `json:"tx_bytes"`
+	// PersistentKeepaliveSeconds is the persistent keepalive
+	// interval in seconds, and is 0 if disabled.
+	PersistentKeepaliveSeconds uint `json:"persistent_keepalive_seconds"`
+}
@@ -12,6 +12,6 @@ func New(debugLogger DebugLogger) *NetLink {
 	}
 }
 
-func (n *NetLink) PatchLoggerLevel(level log.Level) {
-	n.debugLogger.Patch(log.SetLevel(level))
+func (n *NetLink) PatchLogger(options ...log.Option) {
+	n.debugLogger.Patch(options...)
 }
@@ -25,8 +25,12 @@ const (
 
 	// RouteTypeUnicast is a placeholder only and should not be used.
 	RouteTypeUnicast = 0
+	// RouteTypeLocal is a placeholder only and should not be used.
+	RouteTypeLocal = 0
 	// ScopeUniverse is a placeholder only and should not be used.
 	ScopeUniverse = 0
+	// ScopeHost is a placeholder only and should not be used.
+	ScopeHost = 0
 	// ProtoStatic is a placeholder only and should not be used.
 	ProtoStatic = 0
 
@@ -4,7 +4,9 @@ import "golang.org/x/sys/unix"
 
 const (
 	RouteTypeUnicast = unix.RTN_UNICAST
+	RouteTypeLocal   = unix.RTN_LOCAL
 	ScopeUniverse    = unix.RT_SCOPE_UNIVERSE
+	ScopeHost        = unix.RT_SCOPE_HOST
 	ProtoStatic      = unix.RTPROT_STATIC
 
 	rtTableCompat = unix.RT_TABLE_COMPAT
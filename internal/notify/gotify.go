@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// gotify sends notifications to a Gotify server.
+// See https://gotify.net/docs/pushmsg
+type gotify struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func newGotify(client *http.Client, baseURL, token string) *gotify {
+	return &gotify{
+		client: client,
+		url:    strings.TrimSuffix(baseURL, "/") + "/message",
+		token:  token,
+	}
+}
+
+func (g *gotify) String() string {
+	return "Gotify " + g.url
+}
+
+func (g *gotify) Send(ctx context.Context, notification models.Notification) (err error) {
+	const priority = 5
+	body, err := json.Marshal(struct {
+		Title    string `json:"title"`
+		Message  string `json:"message"`
+		Priority int    `json:"priority"`
+	}{
+		Title:    notification.Title,
+		Message:  notification.Message,
+		Priority: priority,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding message: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Gotify-Key", g.token)
+
+	return doRequest(g.client, request)
+}
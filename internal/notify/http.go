@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/qdm12/gluetun/internal/httpclient"
+)
+
+// doRequest sends the request and returns an error if the
+// response status code is not in the 2xx range.
+func doRequest(client *http.Client, request *http.Request) (err error) {
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices {
+		_, _ = io.Copy(io.Discard, response.Body)
+		return nil
+	}
+
+	return httpclient.StatusError(response)
+}
@@ -0,0 +1,185 @@
+// Package notify sends notifications on events such as the VPN tunnel
+// going up or down, to webhooks, ntfy, Gotify and email sinks.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type Notifier struct {
+	sinks        []eventSink
+	retries      uint
+	retryBackoff time.Duration
+	queue        chan models.Notification
+	logger       Logger
+	timeNow      func() time.Time
+}
+
+type sink interface {
+	String() string
+	Send(ctx context.Context, notification models.Notification) (err error)
+}
+
+// eventSink is a sink only sent notifications for the events it is
+// subscribed to.
+type eventSink struct {
+	sink
+	events map[models.NotificationEvent]struct{}
+}
+
+func (e eventSink) subscribed(event models.NotificationEvent) bool {
+	_, ok := e.events[event]
+	return ok
+}
+
+// New creates a notifier sending notifications for the events enabled
+// in the notifications settings to the sinks configured, as well as
+// public IP change notifications to the public IP webhooks, signed with
+// the public IP webhook secret if it is set.
+func New(settings settings.Notifications, publicIP settings.PublicIP,
+	client *http.Client, logger Logger,
+) (notifier *Notifier, err error) {
+	var sinks []sink
+
+	var webhookTemplate *template.Template
+	if *settings.WebhookTemplate != "" {
+		webhookTemplate, err = template.New("webhook").Parse(*settings.WebhookTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("parsing webhook template: %w", err)
+		}
+	}
+	for _, url := range settings.Webhooks {
+		const secret = ""
+		sinks = append(sinks, newWebhook(client, url, webhookTemplate, secret))
+	}
+
+	for _, url := range settings.NtfyURLs {
+		sinks = append(sinks, newNtfy(client, url, *settings.NtfyToken))
+	}
+
+	if *settings.GotifyURL != "" {
+		sinks = append(sinks, newGotify(client, *settings.GotifyURL, *settings.GotifyToken))
+	}
+
+	if *settings.SMTPAddress != "" {
+		sinks = append(sinks, newEmail(*settings.SMTPAddress, *settings.SMTPUsername,
+			*settings.SMTPPassword, *settings.SMTPFrom, settings.SMTPTo))
+	}
+
+	events := make(map[models.NotificationEvent]struct{}, len(settings.Events))
+	for _, event := range settings.Events {
+		events[models.NotificationEvent(event)] = struct{}{}
+	}
+
+	eventSinks := make([]eventSink, 0, len(sinks)+len(publicIP.Webhooks))
+	for _, sink := range sinks {
+		eventSinks = append(eventSinks, eventSink{sink: sink, events: events})
+	}
+
+	publicIPEvents := map[models.NotificationEvent]struct{}{
+		models.NotificationPublicIPChanged: {},
+	}
+	for _, url := range publicIP.Webhooks {
+		sink := newWebhook(client, url, nil, *publicIP.WebhookSecret)
+		eventSinks = append(eventSinks, eventSink{sink: sink, events: publicIPEvents})
+	}
+
+	const queueSize = 100
+	return &Notifier{
+		sinks:        eventSinks,
+		retries:      *settings.Retries,
+		retryBackoff: *settings.RetryBackoff,
+		queue:        make(chan models.Notification, queueSize),
+		logger:       logger,
+		timeNow:      time.Now,
+	}, nil
+}
+
+// Notify queues a notification for the event given, with a message and
+// optional extra fields. It does nothing if no sink is subscribed to the
+// event, and never blocks.
+func (n *Notifier) Notify(event models.NotificationEvent, message string,
+	fields map[string]string,
+) {
+	subscribed := slices.ContainsFunc(n.sinks, func(sink eventSink) bool {
+		return sink.subscribed(event)
+	})
+	if !subscribed {
+		return
+	}
+
+	notification := models.Notification{
+		Event:   event,
+		Title:   event.Title(),
+		Message: message,
+		Time:    n.timeNow(),
+		Fields:  fields,
+	}
+	select {
+	case n.queue <- notification:
+	default:
+		n.logger.Warn("notifications queue is full, dropping " +
+			string(event) + " notification: " + message)
+	}
+}
+
+// Run sends the queued notifications until the context is canceled,
+// at which point it tries to flush the remaining queued notifications
+// once, without retrying.
+func (n *Notifier) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	for {
+		select {
+		case <-ctx.Done():
+			n.flush()
+			return
+		case notification := <-n.queue:
+			n.send(ctx, notification, n.retries)
+		}
+	}
+}
+
+func (n *Notifier) flush() {
+	const flushTimeout = 300 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	for {
+		select {
+		case notification := <-n.queue:
+			n.send(ctx, notification, 0)
+		default:
+			return
+		}
+	}
+}
+
+// send sends the notification to all the sinks subscribed to its event
+// in parallel, retrying each failed sink up to the number of retries given.
+func (n *Notifier) send(ctx context.Context, notification models.Notification,
+	retries uint,
+) {
+	var waitGroup sync.WaitGroup
+	for _, sink := range n.sinks {
+		if !sink.subscribed(notification.Event) {
+			continue
+		}
+		waitGroup.Go(func() {
+			err := sendWithRetries(ctx, sink, notification, retries, n.retryBackoff)
+			if err != nil && ctx.Err() == nil {
+				n.logger.Warn(fmt.Sprintf("sending %s notification to %s: %s",
+					notification.Event, sink, err))
+			}
+		})
+	}
+	waitGroup.Wait()
+}
@@ -0,0 +1,263 @@
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sendWithRetries(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		failures   int32
+		retries    uint
+		requests   int32
+		errMessage string
+	}{
+		"success_first_attempt": {
+			retries:  2,
+			requests: 1,
+		},
+		"success_after_retries": {
+			failures: 2,
+			retries:  2,
+			requests: 3,
+		},
+		"all_attempts_fail": {
+			failures: 5,
+			retries:  1,
+			requests: 2,
+			errMessage: "after 2 attempts: HTTP status code is not OK: " +
+				"503 Service Unavailable: unavailable",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if requests.Add(1) <= testCase.failures {
+					http.Error(w, "unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(server.Close)
+
+			sink := newWebhook(server.Client(), server.URL, nil, "")
+			const backoff = time.Millisecond
+			err := sendWithRetries(context.Background(), sink,
+				models.Notification{}, testCase.retries, backoff)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.requests, requests.Load())
+		})
+	}
+}
+
+func Test_webhook_Send(t *testing.T) {
+	t.Parallel()
+
+	notification := models.Notification{
+		Event:   models.NotificationTunnelUp,
+		Title:   "VPN tunnel up",
+		Message: "connected to server",
+		Time:    time.Unix(0, 0).UTC(),
+		Fields:  map[string]string{"server_name": "server"},
+	}
+
+	const jsonBody = `{"event":"tunnel_up","title":"VPN tunnel up","message":"connected to server",` +
+		`"time":"1970-01-01T00:00:00Z","fields":{"server_name":"server"}}`
+
+	testCases := map[string]struct {
+		template    string
+		secret      string
+		body        string
+		contentType string
+		signature   string
+	}{
+		"json": {
+			body:        jsonBody,
+			contentType: "application/json",
+		},
+		"json_signed": {
+			secret:      "secret",
+			body:        jsonBody,
+			contentType: "application/json",
+			signature:   "sha256=" + sign("secret", []byte(jsonBody)),
+		},
+		"text_template": {
+			template:    `{{.Title}} on {{index .Fields "server_name"}}`,
+			body:        "VPN tunnel up on server",
+			contentType: "text/plain; charset=utf-8",
+		},
+		"json_template": {
+			template:    `{"text":"{{.Message}}"}`,
+			body:        `{"text":"connected to server"}`,
+			contentType: "application/json",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, testCase.contentType, r.Header.Get("Content-Type"))
+				assert.Equal(t, testCase.signature, r.Header.Get(signatureHeader))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.body, string(body))
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			t.Cleanup(server.Close)
+
+			var bodyTemplate *template.Template
+			if testCase.template != "" {
+				bodyTemplate = template.Must(template.New("").Parse(testCase.template))
+			}
+			sink := newWebhook(server.Client(), server.URL, bodyTemplate, testCase.secret)
+
+			err := sink.Send(context.Background(), notification)
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_ntfy_Send(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/gluetun", r.URL.Path)
+		assert.Equal(t, "VPN crashed", r.Header.Get("Title"))
+		assert.Equal(t, "vpn_crashed", r.Header.Get("Tags"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "reason", string(body))
+	}))
+	t.Cleanup(server.Close)
+
+	sink := newNtfy(server.Client(), server.URL+"/gluetun", "token")
+	err := sink.Send(context.Background(), models.Notification{
+		Event:   models.NotificationVPNCrashed,
+		Title:   "VPN crashed",
+		Message: "reason",
+	})
+
+	require.NoError(t, err)
+}
+
+func Test_gotify_Send(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/message", r.URL.Path)
+		assert.Equal(t, "token", r.Header.Get("X-Gotify-Key"))
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"title":"VPN tunnel down","message":"stopped","priority":5}`, string(body))
+	}))
+	t.Cleanup(server.Close)
+
+	sink := newGotify(server.Client(), server.URL+"/", "token")
+	err := sink.Send(context.Background(), models.Notification{
+		Title:   "VPN tunnel down",
+		Message: "stopped",
+	})
+
+	require.NoError(t, err)
+}
+
+func Test_makeEmail(t *testing.T) {
+	t.Parallel()
+
+	message := makeEmail("gluetun@example.com",
+		[]string{"a@example.com", "b@example.com"},
+		models.Notification{
+			Title:   "Forwarded port changed",
+			Message: "forwarded ports are now 1000",
+			Time:    time.Unix(0, 0).UTC(),
+			Fields:  map[string]string{"ports": "1000", "interface": "tun0"},
+		})
+
+	const expected = "From: gluetun@example.com\r\n" +
+		"To: a@example.com, b@example.com\r\n" +
+		"Subject: [gluetun] Forwarded port changed\r\n" +
+		"Date: Thu, 01 Jan 1970 00:00:00 +0000\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"forwarded ports are now 1000\r\n" +
+		"\r\n" +
+		"interface: tun0\r\n" +
+		"ports: 1000\r\n"
+	assert.Equal(t, expected, string(message))
+}
+
+func Test_Notifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	notifier := &Notifier{
+		sinks: []eventSink{{
+			sink: newWebhook(http.DefaultClient, "http://localhost", nil, ""),
+			events: map[models.NotificationEvent]struct{}{
+				models.NotificationTunnelUp: {},
+			},
+		}, {
+			sink: newWebhook(http.DefaultClient, "http://localhost", nil, "secret"),
+			events: map[models.NotificationEvent]struct{}{
+				models.NotificationPublicIPChanged: {},
+			},
+		}},
+		queue:   make(chan models.Notification, 2),
+		timeNow: func() time.Time { return time.Unix(1, 0) },
+	}
+
+	notifier.Notify(models.NotificationTunnelDown, "not enabled", nil)
+	notifier.Notify(models.NotificationTunnelUp, "enabled", nil)
+	notifier.Notify(models.NotificationPublicIPChanged, "public IP webhook", nil)
+
+	require.Len(t, notifier.queue, 2)
+	expected := models.Notification{
+		Event:   models.NotificationTunnelUp,
+		Title:   "VPN tunnel up",
+		Message: "enabled",
+		Time:    time.Unix(1, 0),
+	}
+	assert.Equal(t, expected, <-notifier.queue)
+	expected = models.Notification{
+		Event:   models.NotificationPublicIPChanged,
+		Title:   "Public IP address changed",
+		Message: "public IP webhook",
+		Time:    time.Unix(1, 0),
+	}
+	assert.Equal(t, expected, <-notifier.queue)
+}
+
+func Test_sign(t *testing.T) {
+	t.Parallel()
+
+	signature := sign("key", []byte("The quick brown fox jumps over the lazy dog"))
+
+	const expected = "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	assert.Equal(t, expected, signature)
+}
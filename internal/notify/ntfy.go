@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// ntfy publishes notifications to an ntfy topic URL.
+// See https://docs.ntfy.sh/publish/
+type ntfy struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+func newNtfy(client *http.Client, url, token string) *ntfy {
+	return &ntfy{
+		client: client,
+		url:    url,
+		token:  token,
+	}
+}
+
+func (n *ntfy) String() string {
+	return "ntfy " + n.url
+}
+
+func (n *ntfy) Send(ctx context.Context, notification models.Notification) (err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url,
+		strings.NewReader(notification.Message))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Title", notification.Title)
+	request.Header.Set("Tags", string(notification.Event))
+	if n.token != "" {
+		request.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	return doRequest(n.client, request)
+}
@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// sendWithRetries sends the notification to the sink, retrying up to
+// retries times on failure, with an exponential backoff starting at
+// the initial backoff given.
+func sendWithRetries(ctx context.Context, sink sink,
+	notification models.Notification, retries uint, backoff time.Duration,
+) (err error) {
+	for attempt := uint(0); ; attempt++ {
+		err = sink.Send(ctx, notification)
+		if err == nil {
+			return nil
+		} else if attempt == retries {
+			return fmt.Errorf("after %d attempts: %w", attempt+1, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("after %d attempts: %w", attempt+1, err)
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
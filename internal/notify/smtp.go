@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net"
+	"net/smtp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// email sends notifications as emails through an SMTP server.
+type email struct {
+	address  string
+	auth     smtp.Auth
+	from     string
+	to       []string
+	sendMail func(address string, auth smtp.Auth, from string, to []string, message []byte) error
+}
+
+func newEmail(address, username, password, from string, to []string) *email {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(address)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &email{
+		address:  address,
+		auth:     auth,
+		from:     from,
+		to:       to,
+		sendMail: smtp.SendMail,
+	}
+}
+
+func (e *email) String() string {
+	return "SMTP " + e.address
+}
+
+func (e *email) Send(ctx context.Context, notification models.Notification) (err error) {
+	message := makeEmail(e.from, e.to, notification)
+
+	// smtp.SendMail does not accept a context, so run it in a goroutine
+	// to return early if the context is canceled.
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- e.sendMail(e.address, e.auth, e.from, e.to, message)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-errCh:
+		if err != nil {
+			return fmt.Errorf("sending email: %w", err)
+		}
+		return nil
+	}
+}
+
+func makeEmail(from string, to []string, notification models.Notification) []byte {
+	var builder strings.Builder
+	builder.WriteString("From: " + from + "\r\n")
+	builder.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	builder.WriteString("Subject: [gluetun] " + notification.Title + "\r\n")
+	builder.WriteString("Date: " + notification.Time.Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(notification.Message + "\r\n")
+
+	keys := slices.Sorted(maps.Keys(notification.Fields))
+	if len(keys) > 0 {
+		builder.WriteString("\r\n")
+	}
+	for _, key := range keys {
+		builder.WriteString(key + ": " + notification.Fields[key] + "\r\n")
+	}
+
+	return []byte(builder.String())
+}
@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const signatureHeader = "X-Gluetun-Signature"
+
+// webhook sends notifications as HTTP POST requests, with the body being
+// either the notification as JSON or the result of the template given.
+// If the secret is not empty, the body is signed with HMAC-SHA256 and the
+// signature is set in the X-Gluetun-Signature header.
+type webhook struct {
+	client   *http.Client
+	url      string
+	template *template.Template
+	secret   string
+}
+
+func newWebhook(client *http.Client, url string, template *template.Template,
+	secret string,
+) *webhook {
+	return &webhook{
+		client:   client,
+		url:      url,
+		template: template,
+		secret:   secret,
+	}
+}
+
+func (w *webhook) String() string {
+	return "webhook " + w.url
+}
+
+func (w *webhook) Send(ctx context.Context, notification models.Notification) (err error) {
+	body, contentType, err := w.makeBody(notification)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", contentType)
+	if w.secret != "" {
+		request.Header.Set(signatureHeader, "sha256="+sign(w.secret, body))
+	}
+
+	return doRequest(w.client, request)
+}
+
+func (w *webhook) makeBody(notification models.Notification) (
+	body []byte, contentType string, err error,
+) {
+	if w.template == nil {
+		body, err = json.Marshal(notification)
+		if err != nil {
+			return nil, "", fmt.Errorf("encoding notification: %w", err)
+		}
+		return body, "application/json", nil
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	err = w.template.Execute(buffer, notification)
+	if err != nil {
+		return nil, "", fmt.Errorf("executing template: %w", err)
+	}
+	body = buffer.Bytes()
+
+	contentType = "text/plain; charset=utf-8"
+	if json.Valid(body) {
+		contentType = "application/json"
+	}
+	return body, contentType, nil
+}
+
+// sign returns the hex encoded HMAC-SHA256 of the body using the secret.
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,263 @@
+package openvpn
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var errManagementNotConnected = errors.New("management interface is not connected")
+
+// management is a client for the OpenVPN management interface
+// listening on a local unix socket.
+type management struct {
+	logger Logger
+	conn   net.Conn
+	// connMu protects conn writes.
+	connMu sync.Mutex
+
+	status   models.OpenVPNStatus
+	statusMu sync.RWMutex
+}
+
+func newManagement(logger Logger) *management {
+	return &management{
+		logger: logger,
+	}
+}
+
+// connect connects to the OpenVPN management interface unix socket,
+// retrying until the socket is available or the timeout expires.
+// It then subscribes to real time state and byte count notifications.
+func (m *management) connect(ctx context.Context, path string) (err error) {
+	const timeout = 10 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	const retryPeriod = 100 * time.Millisecond
+	var dialer net.Dialer
+	for {
+		var conn net.Conn
+		conn, err = dialer.DialContext(ctx, "unix", path)
+		if err == nil {
+			m.connMu.Lock()
+			m.conn = conn
+			m.connMu.Unlock()
+			break
+		}
+
+		timer := time.NewTimer(retryPeriod)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("dialing unix socket: %w", err)
+		case <-timer.C:
+		}
+	}
+
+	const byteCountPeriodSeconds = 5
+	for _, command := range []string{
+		"state on",
+		"bytecount " + strconv.Itoa(byteCountPeriodSeconds),
+		"state",
+	} {
+		err = m.send(command)
+		if err != nil {
+			_ = m.conn.Close()
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *management) send(command string) (err error) {
+	m.connMu.Lock()
+	defer m.connMu.Unlock()
+	if m.conn == nil {
+		return fmt.Errorf("sending command %q: %w", command, errManagementNotConnected)
+	}
+	_, err = m.conn.Write([]byte(command + "\n"))
+	if err != nil {
+		return fmt.Errorf("sending command %q: %w", command, err)
+	}
+	return nil
+}
+
+// signalTerm asks OpenVPN to exit cleanly.
+func (m *management) signalTerm() (err error) {
+	return m.send("signal SIGTERM")
+}
+
+// read reads and processes the management interface lines until the
+// connection is closed or the context is canceled. It signals on
+// tunnelReady each time OpenVPN becomes connected.
+func (m *management) read(ctx context.Context, done chan<- struct{},
+	tunnelReady chan<- struct{},
+) {
+	defer close(done)
+
+	go func() {
+		<-ctx.Done()
+		_ = m.conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(m.conn)
+	for scanner.Scan() {
+		m.statusMu.Lock()
+		event, message := parseManagementLine(scanner.Text(), &m.status)
+		m.statusMu.Unlock()
+
+		switch event {
+		case eventNone:
+			if message != "" {
+				m.logger.Debug(message)
+			}
+		case eventConnected:
+			select {
+			case tunnelReady <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		case eventAuthFailed, eventFatal:
+			m.logger.Error(message)
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil && ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+		m.logger.Debug("reading management interface: " + err.Error())
+	}
+}
+
+func (m *management) getStatus() (status models.OpenVPNStatus) {
+	m.statusMu.RLock()
+	defer m.statusMu.RUnlock()
+	return m.status
+}
+
+type managementEvent uint8
+
+const (
+	eventNone managementEvent = iota
+	eventConnected
+	eventAuthFailed
+	eventFatal
+)
+
+// parseManagementLine updates the status given using the management
+// interface line given. It returns the event the line corresponds to,
+// and a message for the line, which can be empty.
+// See https://openvpn.net/community-resources/management-interface/
+func parseManagementLine(line string, status *models.OpenVPNStatus) (
+	event managementEvent, message string,
+) {
+	// Real time notifications are prefixed with '>'
+	notification, isNotification := strings.CutPrefix(line, ">")
+	if !isNotification {
+		// Reply to a command, the state command reply being
+		// the state line without the ">STATE:" prefix.
+		if stateLine, ok := isStateLine(line); ok {
+			return parseStateLine(stateLine, status)
+		}
+		return eventNone, "management interface: " + line
+	}
+
+	kind, value, _ := strings.Cut(notification, ":")
+	switch kind {
+	case "STATE":
+		return parseStateLine(value, status)
+	case "BYTECOUNT":
+		bytesIn, bytesOut, ok := strings.Cut(value, ",")
+		if !ok {
+			return eventNone, "malformed byte count: " + value
+		}
+		status.BytesIn, _ = strconv.ParseUint(bytesIn, 10, 64)
+		status.BytesOut, _ = strconv.ParseUint(bytesOut, 10, 64)
+		return eventNone, ""
+	case "PASSWORD":
+		if strings.HasPrefix(value, "Verification Failed") {
+			status.AuthFailed = true
+			return eventAuthFailed, "authentication failed: " + value
+		}
+		return eventNone, ""
+	case "FATAL":
+		return eventFatal, value
+	default: // INFO, HOLD, LOG, etc.
+		return eventNone, ""
+	}
+}
+
+// isStateLine returns the line given and true if it
+// is a state line starting with a unix timestamp.
+func isStateLine(line string) (stateLine string, ok bool) {
+	timestamp, _, found := strings.Cut(line, ",")
+	if !found {
+		return "", false
+	}
+	_, err := strconv.ParseInt(timestamp, 10, 64)
+	return line, err == nil
+}
+
+// parseStateLine parses a state line of the form
+// "time,state,description,localIP,remoteIP,..." into the status given.
+func parseStateLine(line string, status *models.OpenVPNStatus) (
+	event managementEvent, message string,
+) {
+	const minFields = 2
+	fields := strings.Split(line, ",")
+	if len(fields) < minFields {
+		return eventNone, "malformed state: " + line
+	}
+
+	previousState := status.State
+	status.State = fields[1]
+	status.StateDescription = ""
+	status.StateTime = time.Time{}
+	status.LocalIP = netip.Addr{}
+	status.RemoteIP = netip.Addr{}
+
+	timestamp, err := strconv.ParseInt(fields[0], 10, 64)
+	if err == nil {
+		status.StateTime = time.Unix(timestamp, 0)
+	}
+
+	const (
+		descriptionIndex = 2
+		localIPIndex     = 3
+		remoteIPIndex    = 4
+	)
+	if len(fields) > descriptionIndex {
+		status.StateDescription = fields[descriptionIndex]
+	}
+	if len(fields) > localIPIndex {
+		status.LocalIP, _ = netip.ParseAddr(fields[localIPIndex])
+	}
+	if len(fields) > remoteIPIndex {
+		status.RemoteIP, _ = netip.ParseAddr(fields[remoteIPIndex])
+	}
+
+	message = "state: " + status.State
+	if status.StateDescription != "" {
+		message += " (" + status.StateDescription + ")"
+	}
+
+	switch {
+	case status.StateDescription == "auth-failure":
+		status.AuthFailed = true
+		return eventAuthFailed, "authentication failed: " + message
+	case status.State == "CONNECTED" && previousState != "CONNECTED":
+		status.AuthFailed = false
+		return eventConnected, message
+	default:
+		return eventNone, message
+	}
+}
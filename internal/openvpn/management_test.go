@@ -0,0 +1,119 @@
+package openvpn
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseManagementLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		line           string
+		initialStatus  models.OpenVPNStatus
+		expectedStatus models.OpenVPNStatus
+		event          managementEvent
+		message        string
+	}{
+		"info": {
+			line:    ">INFO:OpenVPN Management Interface Version 5",
+			event:   eventNone,
+			message: "",
+		},
+		"command_reply": {
+			line:    "SUCCESS: real-time state notification set to ON",
+			event:   eventNone,
+			message: "management interface: SUCCESS: real-time state notification set to ON",
+		},
+		"state_connecting": {
+			line: ">STATE:1700000000,CONNECTING,,,,,,",
+			expectedStatus: models.OpenVPNStatus{
+				State:     "CONNECTING",
+				StateTime: time.Unix(1700000000, 0),
+			},
+			event:   eventNone,
+			message: "state: CONNECTING",
+		},
+		"state_connected": {
+			line:          ">STATE:1700000000,CONNECTED,SUCCESS,10.8.0.2,1.2.3.4,1194,,",
+			initialStatus: models.OpenVPNStatus{State: "GET_CONFIG", BytesIn: 1},
+			expectedStatus: models.OpenVPNStatus{
+				State:            "CONNECTED",
+				StateDescription: "SUCCESS",
+				StateTime:        time.Unix(1700000000, 0),
+				LocalIP:          netip.MustParseAddr("10.8.0.2"),
+				RemoteIP:         netip.MustParseAddr("1.2.3.4"),
+				BytesIn:          1,
+			},
+			event:   eventConnected,
+			message: "state: CONNECTED (SUCCESS)",
+		},
+		"state_reply_already_connected": {
+			line:          "1700000000,CONNECTED,SUCCESS,10.8.0.2,1.2.3.4,1194,,",
+			initialStatus: models.OpenVPNStatus{State: "CONNECTED"},
+			expectedStatus: models.OpenVPNStatus{
+				State:            "CONNECTED",
+				StateDescription: "SUCCESS",
+				StateTime:        time.Unix(1700000000, 0),
+				LocalIP:          netip.MustParseAddr("10.8.0.2"),
+				RemoteIP:         netip.MustParseAddr("1.2.3.4"),
+			},
+			event:   eventNone,
+			message: "state: CONNECTED (SUCCESS)",
+		},
+		"state_auth_failure": {
+			line: ">STATE:1700000000,EXITING,auth-failure,,,,,",
+			expectedStatus: models.OpenVPNStatus{
+				State:            "EXITING",
+				StateDescription: "auth-failure",
+				StateTime:        time.Unix(1700000000, 0),
+				AuthFailed:       true,
+			},
+			event:   eventAuthFailed,
+			message: "authentication failed: state: EXITING (auth-failure)",
+		},
+		"malformed_state": {
+			line:    ">STATE:1700000000",
+			event:   eventNone,
+			message: "malformed state: 1700000000",
+		},
+		"bytecount": {
+			line:          ">BYTECOUNT:1234,5678",
+			initialStatus: models.OpenVPNStatus{State: "CONNECTED"},
+			expectedStatus: models.OpenVPNStatus{
+				State:    "CONNECTED",
+				BytesIn:  1234,
+				BytesOut: 5678,
+			},
+			event: eventNone,
+		},
+		"password_verification_failed": {
+			line:           ">PASSWORD:Verification Failed: 'Auth'",
+			expectedStatus: models.OpenVPNStatus{AuthFailed: true},
+			event:          eventAuthFailed,
+			message:        "authentication failed: Verification Failed: 'Auth'",
+		},
+		"fatal": {
+			line:    ">FATAL:Cannot open TUN/TAP dev",
+			event:   eventFatal,
+			message: "Cannot open TUN/TAP dev",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status := testCase.initialStatus
+			event, message := parseManagementLine(testCase.line, &status)
+
+			assert.Equal(t, testCase.expectedStatus, status)
+			assert.Equal(t, testCase.event, event)
+			assert.Equal(t, testCase.message, message)
+		})
+	}
+}
@@ -1,3 +1,6 @@
 package openvpn
 
-const configPath = "/etc/openvpn/target.ovpn"
+const (
+	configPath           = "/etc/openvpn/target.ovpn"
+	managementSocketPath = "/etc/openvpn/management.sock"
+)
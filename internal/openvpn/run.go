@@ -2,28 +2,50 @@ package openvpn
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
 )
 
 type Runner struct {
-	settings settings.OpenVPN
-	starter  CmdStarter
-	logger   Logger
+	settings   settings.OpenVPN
+	starter    CmdStarter
+	logger     Logger
+	management *management
 }
 
 func NewRunner(settings settings.OpenVPN, starter CmdStarter,
 	logger Logger,
 ) *Runner {
 	return &Runner{
-		starter:  starter,
-		logger:   logger,
-		settings: settings,
+		starter:    starter,
+		logger:     logger,
+		settings:   settings,
+		management: newManagement(logger),
 	}
 }
 
+// Status returns the OpenVPN status obtained from its management interface.
+func (r *Runner) Status() (status models.OpenVPNStatus) {
+	return r.management.getStatus()
+}
+
 func (r *Runner) Run(ctx context.Context, errCh chan<- error, ready chan<- struct{}) {
-	stdoutLines, stderrLines, waitError, err := start(ctx, r.starter, r.settings.Version, r.settings.Flags)
+	err := os.Remove(managementSocketPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		errCh <- fmt.Errorf("removing previous management socket: %w", err)
+		return
+	}
+
+	// The process context is only canceled if OpenVPN does not exit
+	// cleanly after being sent a SIGTERM through its management interface.
+	processCtx, processCancel := context.WithCancel(context.Background())
+	defer processCancel()
+	stdoutLines, stderrLines, waitError, err := start(processCtx, r.starter, r.settings.Version, r.settings.Flags)
 	if err != nil {
 		errCh <- err
 		return
@@ -32,17 +54,64 @@ func (r *Runner) Run(ctx context.Context, errCh chan<- error, ready chan<- struc
 	streamCtx, streamCancel := context.WithCancel(context.Background())
 	streamDone := make(chan struct{})
 	go streamLines(streamCtx, streamDone, r.logger,
-		stdoutLines, stderrLines, ready)
+		stdoutLines, stderrLines)
+
+	managementCtx, managementCancel := context.WithCancel(context.Background())
+	managementDone := make(chan struct{})
+	managementErr := make(chan error, 1)
+	go func() {
+		err := r.management.connect(managementCtx, managementSocketPath)
+		if err != nil {
+			close(managementDone)
+			managementErr <- err
+			return
+		}
+		r.management.read(managementCtx, managementDone, ready)
+	}()
 
 	select {
 	case <-ctx.Done():
-		<-waitError
+		r.stop(waitError, processCancel)
+		managementCancel()
+		<-managementDone
 		streamCancel()
 		<-streamDone
 		errCh <- ctx.Err()
 	case err := <-waitError:
+		managementCancel()
+		<-managementDone
 		streamCancel()
 		<-streamDone
 		errCh <- err
+	case err := <-managementErr:
+		processCancel()
+		<-waitError
+		managementCancel()
+		streamCancel()
+		<-streamDone
+		errCh <- fmt.Errorf("connecting to management interface: %w", err)
+	}
+}
+
+// stop sends a SIGTERM to OpenVPN through its management interface
+// and waits for it to exit, killing it if it does not exit in time.
+func (r *Runner) stop(waitError <-chan error, processCancel context.CancelFunc) {
+	err := r.management.signalTerm()
+	if err != nil {
+		r.logger.Warn("stopping OpenVPN cleanly: " + err.Error())
+		processCancel()
+		<-waitError
+		return
+	}
+
+	const timeout = 5 * time.Second
+	timer := time.NewTimer(timeout)
+	select {
+	case <-waitError:
+		timer.Stop()
+	case <-timer.C:
+		r.logger.Warn("OpenVPN did not exit within " + timeout.String() + ", killing it")
+		processCancel()
+		<-waitError
 	}
 }
@@ -26,7 +26,8 @@ func start(ctx context.Context, starter CmdStarter, version string, flags []stri
 		return nil, nil, nil, fmt.Errorf("OpenVPN version is unknown: %s", version)
 	}
 
-	args := []string{"--config", configPath}
+	args := []string{"--config", configPath,
+		"--management", managementSocketPath, "unix"}
 	args = append(args, flags...)
 	cmd := exec.CommandContext(ctx, bin, args...)
 	setCmdSysProcAttr(cmd)
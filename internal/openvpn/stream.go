@@ -2,12 +2,10 @@ package openvpn
 
 import (
 	"context"
-	"strings"
 )
 
 func streamLines(ctx context.Context, done chan<- struct{},
 	logger Logger, stdout, stderr <-chan string,
-	tunnelReady chan<- struct{},
 ) {
 	defer close(done)
 
@@ -37,10 +35,5 @@ func streamLines(ctx context.Context, done chan<- struct{},
 		case levelError:
 			logger.Error(line)
 		}
-		if strings.Contains(line, "Initialization Sequence Completed") {
-			// do not close tunnelReady in case the initialization
-			// happens multiple times without Openvpn restarting
-			tunnelReady <- struct{}{}
-		}
 	}
 }
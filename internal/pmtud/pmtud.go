@@ -5,14 +5,32 @@ import (
 	"errors"
 	"fmt"
 	"net/netip"
+	"strings"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/firewall/iptables"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/pmtud/constants"
 	"github.com/qdm12/gluetun/internal/pmtud/icmp"
 	"github.com/qdm12/gluetun/internal/pmtud/tcp"
 )
 
+const (
+	MethodICMP = "icmp"
+	MethodTCP  = "tcp"
+)
+
+// Result is the result of a path MTU discovery.
+type Result struct {
+	// MTU is the maximum valid MTU found, and is 0 if it was not found.
+	MTU uint32
+	// Method is the method which determined the MTU,
+	// and can be [MethodICMP] or [MethodTCP].
+	Method string
+	// Probes are the ICMP and TCP discovery attempts, in order.
+	Probes []models.MTUProbe
+}
+
 // PathMTUDiscover discovers the maximum MTU using both ICMP and TCP.
 // Multiple ICMP addresses and TCP addresses can be specified for redundancy.
 // ICMP PMTUD is run first. If successful, the range of possible MTU values to
@@ -22,10 +40,10 @@ import (
 // If the physicalLinkMTU is zero, it defaults to 1500 which is the ethernet standard MTU.
 // If the pingTimeout is zero, it defaults to 1 second.
 // If the logger is nil, a no-op logger is used.
-// It returns [ErrMTUNotFound] if the MTU could not be determined.
+// The result probes are set even if an error is returned.
 func PathMTUDiscover(ctx context.Context, icmpAddrs []netip.Addr, tcpAddrs []netip.AddrPort,
 	physicalLinkMTU uint32, tryTimeout time.Duration, fw tcp.Firewall, logger Logger) (
-	mtu uint32, err error,
+	result Result, err error,
 ) {
 	if physicalLinkMTU == 0 {
 		const ethernetStandardMTU = 1500
@@ -42,8 +60,10 @@ func PathMTUDiscover(ctx context.Context, icmpAddrs []netip.Addr, tcpAddrs []net
 	maxPossibleMTU := physicalLinkMTU
 	icmpSuccess := false
 	for _, icmpIP := range icmpAddrs {
+		start := time.Now()
 		mtu, err := icmp.PathMTUDiscover(ctx, icmpIP, physicalLinkMTU,
 			tryTimeout, logger)
+		result.Probes = append(result.Probes, makeProbe(MethodICMP, icmpIP.String(), start, mtu, err))
 		switch {
 		case err == nil:
 			logger.Debugf("ICMP path MTU discovery against %s found maximum valid MTU %d", icmpIP, mtu)
@@ -52,7 +72,7 @@ func PathMTUDiscover(ctx context.Context, icmpAddrs []netip.Addr, tcpAddrs []net
 		case errors.Is(err, icmp.ErrNotPermitted), errors.Is(err, icmp.ErrMTUNotFound):
 			logger.Debugf("ICMP path MTU discovery failed: %s", err)
 		default:
-			return 0, fmt.Errorf("ICMP path MTU discovery: %w", err)
+			return result, fmt.Errorf("ICMP path MTU discovery: %w", err)
 		}
 		if icmpSuccess {
 			break
@@ -67,20 +87,45 @@ func PathMTUDiscover(ctx context.Context, icmpAddrs []netip.Addr, tcpAddrs []net
 		const mtuMargin = 150
 		minMTU = max(maxPossibleMTU-mtuMargin, minMTU)
 	}
-	mtu, err = tcp.PathMTUDiscover(ctx, tcpAddrs, minMTU, maxPossibleMTU, tryTimeout, fw, logger)
+	tcpTargets := make([]string, len(tcpAddrs))
+	for i, addr := range tcpAddrs {
+		tcpTargets[i] = addr.String()
+	}
+	start := time.Now()
+	mtu, err := tcp.PathMTUDiscover(ctx, tcpAddrs, minMTU, maxPossibleMTU, tryTimeout, fw, logger)
+	result.Probes = append(result.Probes, makeProbe(MethodTCP, strings.Join(tcpTargets, ", "), start, mtu, err))
 	if err != nil {
 		if errors.Is(err, iptables.ErrMarkMatchModuleMissing) {
 			logger.Debugf("aborting TCP path MTU discovery: %s", err)
 			if icmpSuccess {
-				return maxPossibleMTU, nil // only rely on ICMP PMTUD results
+				// only rely on ICMP PMTUD results
+				result.MTU = maxPossibleMTU
+				result.Method = MethodICMP
+				return result, nil
 			}
 		}
 		if icmpSuccess {
-			return 0, fmt.Errorf("PMTUD succeeded with ICMP but failed with TCP "+
+			return result, fmt.Errorf("PMTUD succeeded with ICMP but failed with TCP "+
 				"- discarding ICMP obtained MTU %d", maxPossibleMTU)
 		}
-		return 0, errors.New("PMTUD failed with both ICMP and TCP")
+		return result, errors.New("PMTUD failed with both ICMP and TCP")
 	}
 	logger.Debugf("TCP path MTU discovery found maximum valid MTU %d", mtu)
-	return mtu, nil
+	result.MTU = mtu
+	result.Method = MethodTCP
+	return result, nil
+}
+
+func makeProbe(method, target string, start time.Time, mtu uint32, err error) (probe models.MTUProbe) {
+	probe = models.MTUProbe{
+		Method:               method,
+		Target:               target,
+		DurationMilliseconds: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		probe.Error = err.Error()
+	} else {
+		probe.MTU = mtu
+	}
+	return probe
 }
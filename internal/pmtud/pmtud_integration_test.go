@@ -35,8 +35,8 @@ func Test_PathMTUDiscover(t *testing.T) {
 	tcpAddrs := []netip.AddrPort{
 		netip.MustParseAddrPort("1.1.1.1:80"),
 	}
-	mtu, err := PathMTUDiscover(t.Context(), icmpAddrs, tcpAddrs,
+	result, err := PathMTUDiscover(t.Context(), icmpAddrs, tcpAddrs,
 		physicalLinkMTU, timeout, fw, logger)
 	require.NoError(t, err)
-	t.Log("MTU found:", mtu)
+	t.Log("MTU found:", result.MTU, "with method", result.Method)
 }
@@ -6,6 +6,7 @@ import (
 	"os/exec"
 
 	"github.com/qdm12/gluetun/internal/command"
+	"github.com/qdm12/gluetun/internal/models"
 )
 
 type Service interface {
@@ -40,3 +41,13 @@ type Cmder interface {
 		waitError <-chan error, startErr error)
 	RunAndLog(ctx context.Context, commandString string, logger command.Logger) (err error)
 }
+
+type Notifier interface {
+	Notify(event models.NotificationEvent, message string, fields map[string]string)
+}
+
+type TorrentSyncer interface {
+	Start(port uint16)
+	Stop()
+	Status() (status models.TorrentClientStatus)
+}
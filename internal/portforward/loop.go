@@ -9,7 +9,10 @@ import (
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/logging"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/portforward/service"
+	"github.com/qdm12/gluetun/internal/portforward/torrent"
 )
 
 type Loop struct {
@@ -23,6 +26,10 @@ type Loop struct {
 	portAllower PortAllower
 	logger      Logger
 	cmder       Cmder
+	notifier    Notifier
+	// torrentSyncer is nil if the torrent client
+	// port synchronization is disabled.
+	torrentSyncer TorrentSyncer
 	// Fixed parameters
 	uid, gid int
 	// Internal channels and locks
@@ -37,18 +44,19 @@ type Loop struct {
 
 func NewLoop(settings settings.PortForwarding, routing Routing,
 	client *http.Client, portAllower PortAllower,
-	logger Logger, cmder Cmder, uid, gid int,
+	logger Logger, cmder Cmder, notifier Notifier, uid, gid int,
 ) *Loop {
-	return &Loop{
+	loop := &Loop{
 		settings: Settings{
 			VPNIsUp: ptrTo(false),
 			Service: service.Settings{
-				Enabled:        settings.Enabled,
-				Filepath:       *settings.Filepath,
-				UpCommand:      *settings.UpCommand,
-				DownCommand:    *settings.DownCommand,
-				ListeningPorts: settings.ListeningPorts,
-				PortsCount:     settings.PortsCount,
+				Enabled:         settings.Enabled,
+				Filepath:        *settings.Filepath,
+				BindingFilepath: *settings.BindingFilepath,
+				UpCommand:       *settings.UpCommand,
+				DownCommand:     *settings.DownCommand,
+				ListeningPorts:  settings.ListeningPorts,
+				PortsCount:      settings.PortsCount,
 			},
 		},
 		routing:     routing,
@@ -56,9 +64,14 @@ func NewLoop(settings settings.PortForwarding, routing Routing,
 		portAllower: portAllower,
 		logger:      logger,
 		cmder:       cmder,
+		notifier:    notifier,
 		uid:         uid,
 		gid:         gid,
 	}
+	if *settings.TorrentClient.Name != "" {
+		loop.torrentSyncer = torrent.New(settings.TorrentClient, client, logger)
+	}
+	return loop
 }
 
 func (l *Loop) String() string {
@@ -107,7 +120,7 @@ func (l *Loop) run(runCtx context.Context, runDone chan<- struct{},
 			l.settings = updatedSettings
 			l.settingsMutex.Unlock()
 		case err := <-serviceRunError:
-			l.logger.Error(err.Error())
+			l.logger.Error("port forwarding service" + logging.Field("error", err.Error()))
 		case <-retryAfter:
 			// Retry starting the service after a delay
 			retryAfter = nil
@@ -126,7 +139,7 @@ func (l *Loop) run(runCtx context.Context, runDone chan<- struct{},
 		*serviceSettings.Enabled = *serviceSettings.Enabled && *l.settings.VPNIsUp
 
 		l.service = service.New(serviceSettings, l.routing, l.client,
-			l.portAllower, l.logger, l.cmder, l.uid, l.gid)
+			l.portAllower, l.logger, l.cmder, l.notifier, l.torrentSyncer, l.uid, l.gid)
 
 		var err error
 		serviceRunError, err = l.service.Start(runCtx)
@@ -139,7 +152,9 @@ func (l *Loop) run(runCtx context.Context, runDone chan<- struct{},
 			updateResult <- err
 		} else if err != nil {
 			// Log the error and schedule a retry
-			l.logger.Errorf("starting port forwarding service: %s - retrying in %s", err, retryDelay)
+			l.logger.Error("starting port forwarding service" +
+				logging.Field("retry_in", retryDelay.String()) +
+				logging.Field("error", err.Error()))
 			retryAfter = time.After(retryDelay)
 		}
 	}
@@ -185,6 +200,16 @@ func (l *Loop) SetPortsForwarded(ports []uint16) (err error) {
 	return l.service.SetPortsForwarded(l.runCtx, ports)
 }
 
+// GetTorrentClientStatus returns the torrent client port
+// synchronization status, or nil if it is disabled.
+func (l *Loop) GetTorrentClientStatus() (status *models.TorrentClientStatus) {
+	if l.torrentSyncer == nil {
+		return nil
+	}
+	torrentStatus := l.torrentSyncer.Status()
+	return &torrentStatus
+}
+
 func ptrTo[T any](value T) *T {
 	return &value
 }
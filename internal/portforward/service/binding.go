@@ -0,0 +1,130 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants/providers"
+)
+
+// binding is the last successful port forwarding binding, persisted
+// to try reclaiming the same ports on the next start.
+type binding struct {
+	Provider                string            `json:"provider"`
+	ServerName              string            `json:"server_name"`
+	InternalToExternalPorts map[uint16]uint16 `json:"internal_to_external_ports"`
+	Expiry                  time.Time         `json:"expiry"`
+}
+
+// bindingLifetime is the duration after which a persisted binding is
+// no longer reclaimed, since the provider most likely assigned its
+// ports to someone else by then.
+const bindingLifetime = 24 * time.Hour
+
+func (b binding) matches(provider, serverName string, now time.Time) bool {
+	return len(b.InternalToExternalPorts) > 0 &&
+		b.Provider == provider &&
+		b.ServerName == serverName &&
+		now.Before(b.Expiry)
+}
+
+// previousPorts returns the internal to external ports of the persisted
+// binding if it matches the current provider and server and is not
+// expired, and nil otherwise.
+func (s *Service) previousPorts() (internalToExternalPorts map[uint16]uint16) {
+	if s.settings.BindingFilepath == "" {
+		return nil
+	}
+
+	previous, err := readBinding(s.settings.BindingFilepath)
+	if err != nil {
+		s.logger.Warn("reading previous port forwarding binding: " + err.Error())
+		return nil
+	}
+
+	providerName := s.settings.PortForwarder.Name()
+	if !previous.matches(providerName, s.settings.ServerName, time.Now()) {
+		return nil
+	} else if !canReclaimPorts(providerName) {
+		s.logger.Debug(providerName + " cannot request the previously forwarded ports")
+		return nil
+	}
+	return previous.InternalToExternalPorts
+}
+
+// canReclaimPorts returns true if the port forwarding code of the provider
+// can request the previously forwarded ports. Other providers cannot:
+//   - Perfect Privacy ports are calculated from the VPN internal IP address
+//   - PrivateVPN assigns a port per server IP address and its API does not
+//     accept a port to request
+//   - Private Internet Access persists and reuses its own port forwarding data
+func canReclaimPorts(providerName string) bool {
+	return providerName == providers.Protonvpn
+}
+
+// persistBinding writes the binding for the internal to external ports
+// given to the binding file, with its expiry set from the current time.
+// Errors are only logged, since persisting the binding is not critical.
+func (s *Service) persistBinding(internalToExternalPorts map[uint16]uint16) {
+	if s.settings.BindingFilepath == "" {
+		return
+	}
+
+	current := binding{
+		Provider:                s.settings.PortForwarder.Name(),
+		ServerName:              s.settings.ServerName,
+		InternalToExternalPorts: maps.Clone(internalToExternalPorts),
+		Expiry:                  time.Now().Add(bindingLifetime),
+	}
+	err := writeBinding(s.settings.BindingFilepath, current, s.puid, s.pgid)
+	if err != nil {
+		s.logger.Warn("persisting port forwarding binding: " + err.Error())
+	}
+}
+
+func readBinding(path string) (b binding, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return binding{}, nil
+		}
+		return binding{}, fmt.Errorf("reading file: %w", err)
+	}
+
+	err = json.Unmarshal(data, &b)
+	if err != nil {
+		return binding{}, fmt.Errorf("decoding file: %w", err)
+	}
+	return b, nil
+}
+
+func writeBinding(path string, b binding, puid, pgid int) (err error) {
+	const dirPerms = os.FileMode(0o755)
+	err = os.MkdirAll(filepath.Dir(path), dirPerms)
+	if err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding binding: %w", err)
+	}
+
+	const perms = os.FileMode(0o600)
+	err = os.WriteFile(path, data, perms)
+	if err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	err = os.Chown(path, puid, pgid)
+	if err != nil {
+		return fmt.Errorf("chowning file: %w", err)
+	}
+
+	return nil
+}
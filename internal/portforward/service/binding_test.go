@@ -0,0 +1,96 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_binding_matches(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	validBinding := binding{
+		Provider:                "protonvpn",
+		ServerName:              "CH#1",
+		InternalToExternalPorts: map[uint16]uint16{56789: 40000},
+		Expiry:                  now.Add(time.Hour),
+	}
+
+	testCases := map[string]struct {
+		binding    binding
+		provider   string
+		serverName string
+		matches    bool
+	}{
+		"empty_binding": {
+			provider: "protonvpn",
+		},
+		"matching": {
+			binding:    validBinding,
+			provider:   "protonvpn",
+			serverName: "CH#1",
+			matches:    true,
+		},
+		"different_provider": {
+			binding:    validBinding,
+			provider:   "privatevpn",
+			serverName: "CH#1",
+		},
+		"different_server": {
+			binding:    validBinding,
+			provider:   "protonvpn",
+			serverName: "CH#2",
+		},
+		"expired": {
+			binding: binding{
+				Provider:                "protonvpn",
+				ServerName:              "CH#1",
+				InternalToExternalPorts: map[uint16]uint16{56789: 40000},
+				Expiry:                  now,
+			},
+			provider:   "protonvpn",
+			serverName: "CH#1",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			matches := testCase.binding.matches(testCase.provider, testCase.serverName, now)
+			assert.Equal(t, testCase.matches, matches)
+		})
+	}
+}
+
+func Test_readWriteBinding(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "subdir", "binding.json")
+
+	b, err := readBinding(path)
+	require.NoError(t, err)
+	assert.Equal(t, binding{}, b)
+
+	written := binding{
+		Provider:                "protonvpn",
+		ServerName:              "CH#1",
+		InternalToExternalPorts: map[uint16]uint16{56789: 40000, 56790: 40001},
+		Expiry:                  time.Unix(1000, 0).UTC(),
+	}
+	err = writeBinding(path, written, os.Getuid(), os.Getgid())
+	require.NoError(t, err)
+
+	b, err = readBinding(path)
+	require.NoError(t, err)
+	assert.Equal(t, written, b)
+
+	err = os.WriteFile(path, []byte("invalid"), 0o600)
+	require.NoError(t, err)
+	_, err = readBinding(path)
+	assert.ErrorContains(t, err, "decoding file")
+}
@@ -5,6 +5,7 @@ import (
 	"net/netip"
 
 	"github.com/qdm12/gluetun/internal/command"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider/utils"
 )
 
@@ -37,3 +38,12 @@ type PortForwarder interface {
 type Cmder interface {
 	RunAndLog(ctx context.Context, command string, logger command.Logger) (err error)
 }
+
+type Notifier interface {
+	Notify(event models.NotificationEvent, message string, fields map[string]string)
+}
+
+type TorrentSyncer interface {
+	Start(port uint16)
+	Stop()
+}
@@ -12,16 +12,21 @@ type Service struct {
 	// State
 	portMutex sync.RWMutex
 	ports     []uint16
+	// internalToExternalPorts is the binding obtained from
+	// the port forwarder, persisted again when stopping.
+	internalToExternalPorts map[uint16]uint16
 	// Fixed parameters
 	settings Settings
 	puid     int
 	pgid     int
 	// Fixed injected objects
-	routing     Routing
-	client      *http.Client
-	portAllower PortAllower
-	logger      Logger
-	cmder       Cmder
+	routing       Routing
+	client        *http.Client
+	portAllower   PortAllower
+	logger        Logger
+	cmder         Cmder
+	notifier      Notifier
+	torrentSyncer TorrentSyncer
 	// Internal channels and locks
 	startStopMutex sync.Mutex
 	keepPortCancel context.CancelFunc
@@ -29,7 +34,8 @@ type Service struct {
 }
 
 func New(settings Settings, routing Routing, client *http.Client,
-	portAllower PortAllower, logger Logger, cmder Cmder, puid, pgid int,
+	portAllower PortAllower, logger Logger, cmder Cmder, notifier Notifier,
+	torrentSyncer TorrentSyncer, puid, pgid int,
 ) *Service {
 	return &Service{
 		// Fixed parameters
@@ -37,11 +43,13 @@ func New(settings Settings, routing Routing, client *http.Client,
 		puid:     puid,
 		pgid:     pgid,
 		// Fixed injected objects
-		routing:     routing,
-		client:      client,
-		portAllower: portAllower,
-		logger:      logger,
-		cmder:       cmder,
+		routing:       routing,
+		client:        client,
+		portAllower:   portAllower,
+		logger:        logger,
+		cmder:         cmder,
+		notifier:      notifier,
+		torrentSyncer: torrentSyncer,
 	}
 }
 
@@ -68,6 +76,8 @@ func (s *Service) SetPortsForwarded(ctx context.Context, ports []uint16) (err er
 	if err != nil {
 		return fmt.Errorf("cleaning up: %w", err)
 	}
+	// Ports set manually are not a port forwarder binding to persist.
+	s.internalToExternalPorts = nil
 
 	internalToExternalPorts := make(map[uint16]uint16, len(ports))
 	for _, port := range ports {
@@ -10,24 +10,29 @@ import (
 )
 
 type Settings struct {
-	Enabled        *bool
-	PortForwarder  PortForwarder
-	Filepath       string
-	UpCommand      string
-	DownCommand    string
-	Interface      string // needed for PIA, PrivateVPN and ProtonVPN, tun0 for example
-	ServerName     string // needed for PIA
-	CanPortForward bool   // needed for PIA
-	ListeningPorts []uint16
-	PortsCount     uint16
-	Username       string // needed for PIA
-	Password       string // needed for PIA
+	Enabled       *bool
+	PortForwarder PortForwarder
+	Filepath      string
+	// BindingFilepath is the file path to persist the last port
+	// forwarding binding to, in order to try reclaiming the same ports
+	// on the next start. It can be the empty string to disable it.
+	BindingFilepath string
+	UpCommand       string
+	DownCommand     string
+	Interface       string // needed for PIA, PrivateVPN and ProtonVPN, tun0 for example
+	ServerName      string // needed for PIA
+	CanPortForward  bool   // needed for PIA
+	ListeningPorts  []uint16
+	PortsCount      uint16
+	Username        string // needed for PIA
+	Password        string // needed for PIA
 }
 
 func (s Settings) Copy() (copied Settings) {
 	copied.Enabled = gosettings.CopyPointer(s.Enabled)
 	copied.PortForwarder = s.PortForwarder
 	copied.Filepath = s.Filepath
+	copied.BindingFilepath = s.BindingFilepath
 	copied.UpCommand = s.UpCommand
 	copied.DownCommand = s.DownCommand
 	copied.Interface = s.Interface
@@ -44,6 +49,7 @@ func (s *Settings) OverrideWith(update Settings) {
 	s.Enabled = gosettings.OverrideWithPointer(s.Enabled, update.Enabled)
 	s.PortForwarder = gosettings.OverrideWithComparable(s.PortForwarder, update.PortForwarder)
 	s.Filepath = gosettings.OverrideWithComparable(s.Filepath, update.Filepath)
+	s.BindingFilepath = gosettings.OverrideWithComparable(s.BindingFilepath, update.BindingFilepath)
 	s.UpCommand = gosettings.OverrideWithComparable(s.UpCommand, update.UpCommand)
 	s.DownCommand = gosettings.OverrideWithComparable(s.DownCommand, update.DownCommand)
 	s.Interface = gosettings.OverrideWithComparable(s.Interface, update.Interface)
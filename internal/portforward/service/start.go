@@ -6,6 +6,8 @@ import (
 	"maps"
 	"slices"
 
+	"github.com/qdm12/gluetun/internal/logging"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
 	"github.com/qdm12/gluetun/internal/provider/utils"
 )
@@ -44,12 +46,21 @@ func (s *Service) Start(ctx context.Context) (runError <-chan error, err error)
 		Username:       s.settings.Username,
 		Password:       s.settings.Password,
 		PortsCount:     s.settings.PortsCount,
+		PreviousPorts:  s.previousPorts(),
 	}
 	internalToExternalPorts, err := s.settings.PortForwarder.PortForward(ctx, obj)
 	if err != nil {
 		return nil, fmt.Errorf("port forwarding for the first time: %w", err)
 	}
 
+	if obj.PreviousPorts != nil {
+		if maps.Equal(obj.PreviousPorts, internalToExternalPorts) {
+			s.logger.Info("reclaimed previously forwarded ports")
+		} else {
+			s.logger.Info("previously forwarded ports could not be reclaimed")
+		}
+	}
+
 	s.portMutex.Lock()
 	defer s.portMutex.Unlock()
 
@@ -58,6 +69,9 @@ func (s *Service) Start(ctx context.Context) (runError <-chan error, err error)
 		return nil, err
 	}
 
+	s.internalToExternalPorts = internalToExternalPorts
+	s.persistBinding(internalToExternalPorts)
+
 	keepPortCtx, keepPortCancel := context.WithCancel(context.Background())
 	s.keepPortCancel = keepPortCancel
 	runErrorCh := make(chan error)
@@ -101,7 +115,10 @@ func (s *Service) onNewPorts(ctx context.Context, internalToExternalPorts map[ui
 	externalPorts := slices.Collect(maps.Keys(externalToInternalPorts))
 	slices.Sort(externalPorts)
 
-	s.logger.Info(portsToString(externalPorts))
+	s.logger.Info(portsToString(externalPorts) +
+		logging.Field("ports", portsToCSV(externalPorts)) +
+		logging.Field("server", s.settings.ServerName) +
+		logging.Field("interface", s.settings.Interface))
 
 	userRedirectionEnabled := !slices.Equal(s.settings.ListeningPorts, []uint16{0})
 	for i, port := range externalPorts {
@@ -140,11 +157,24 @@ func (s *Service) onNewPorts(ctx context.Context, internalToExternalPorts map[ui
 	s.ports = make([]uint16, len(internalToExternalPorts))
 	copy(s.ports, externalPorts)
 
+	s.notifier.Notify(models.NotificationPortChanged, portsToString(externalPorts),
+		map[string]string{
+			"ports":     portsToCSV(externalPorts),
+			"interface": s.settings.Interface,
+		})
+
+	if s.torrentSyncer != nil && len(externalPorts) > 0 {
+		torrentPort := externalPorts[0]
+		if userRedirectionEnabled {
+			torrentPort = s.settings.ListeningPorts[0]
+		}
+		s.torrentSyncer.Start(torrentPort)
+	}
+
 	if s.settings.UpCommand != "" {
 		err = runCommand(ctx, s.cmder, s.logger, s.settings.UpCommand, externalPorts, s.settings.Interface)
 		if err != nil {
-			err = fmt.Errorf("running up command: %w", err)
-			s.logger.Error(err.Error())
+			s.logger.Error("running up command" + logging.Field("error", err.Error()))
 		}
 	}
 
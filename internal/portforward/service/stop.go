@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"slices"
 	"time"
+
+	"github.com/qdm12/gluetun/internal/logging"
 )
 
 func (s *Service) Stop() (err error) {
@@ -24,18 +26,27 @@ func (s *Service) Stop() (err error) {
 	s.keepPortCancel()
 	<-s.keepPortDoneCh
 
+	if s.internalToExternalPorts != nil {
+		// Refresh the binding expiry from the time it was last in use.
+		s.persistBinding(s.internalToExternalPorts)
+		s.internalToExternalPorts = nil
+	}
+
 	return s.cleanup()
 }
 
 func (s *Service) cleanup() (err error) {
+	if s.torrentSyncer != nil {
+		s.torrentSyncer.Stop()
+	}
+
 	if s.settings.DownCommand != "" {
 		const downTimeout = 60 * time.Second
 		ctx, cancel := context.WithTimeout(context.Background(), downTimeout)
 		defer cancel()
 		err = runCommand(ctx, s.cmder, s.logger, s.settings.DownCommand, s.ports, s.settings.Interface)
 		if err != nil {
-			err = fmt.Errorf("running down command: %w", err)
-			s.logger.Error(err.Error())
+			s.logger.Error("running down command" + logging.Field("error", err.Error()))
 		}
 	}
 
@@ -0,0 +1,172 @@
+package torrent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrDelugeRPC              = errors.New("deluge RPC error")
+	ErrDelugeLoginFailed      = errors.New("deluge login failed")
+	ErrDelugeNoDaemonHost     = errors.New("deluge web UI has no daemon host configured")
+	ErrDelugeDaemonConnection = errors.New("deluge web UI failed connecting to the daemon")
+	ErrDelugeListenPortsEmpty = errors.New("deluge listen ports is empty")
+)
+
+// deluge uses the Deluge Web UI JSON-RPC API, see
+// https://deluge.readthedocs.io/en/latest/reference/webapi.html
+type deluge struct {
+	client   *http.Client
+	url      string
+	password string
+	id       uint
+}
+
+func newDeluge(client *http.Client, url, password string) *deluge {
+	return &deluge{
+		client:   client,
+		url:      url,
+		password: password,
+	}
+}
+
+func (d *deluge) getPort(ctx context.Context) (port uint16, err error) {
+	err = d.connect(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var listenPorts []uint16
+	err = d.call(ctx, "core.get_config_value", []any{"listen_ports"}, &listenPorts)
+	if err != nil {
+		return 0, err
+	} else if len(listenPorts) == 0 {
+		return 0, ErrDelugeListenPortsEmpty
+	}
+	return listenPorts[0], nil
+}
+
+func (d *deluge) setPort(ctx context.Context, port uint16) (err error) {
+	err = d.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	config := map[string]any{
+		"listen_ports": []uint16{port, port},
+		"random_port":  false,
+	}
+	return d.call(ctx, "core.set_config", []any{config}, nil)
+}
+
+// connect logs in the web UI if the session is not valid,
+// and connects the web UI to its first daemon host if it
+// is not connected to a daemon.
+func (d *deluge) connect(ctx context.Context) (err error) {
+	var sessionValid bool
+	err = d.call(ctx, "auth.check_session", []any{}, &sessionValid)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+
+	if !sessionValid {
+		var loggedIn bool
+		err = d.call(ctx, "auth.login", []any{d.password}, &loggedIn)
+		if err != nil {
+			return fmt.Errorf("logging in: %w", err)
+		} else if !loggedIn {
+			return ErrDelugeLoginFailed
+		}
+	}
+
+	var connected bool
+	err = d.call(ctx, "web.connected", []any{}, &connected)
+	if err != nil {
+		return fmt.Errorf("checking daemon connection: %w", err)
+	} else if connected {
+		return nil
+	}
+
+	// Each host is an array of the form [id, address, port, status]
+	var hosts [][]any
+	err = d.call(ctx, "web.get_hosts", []any{}, &hosts)
+	if err != nil {
+		return fmt.Errorf("getting daemon hosts: %w", err)
+	} else if len(hosts) == 0 || len(hosts[0]) == 0 {
+		return ErrDelugeNoDaemonHost
+	}
+
+	err = d.call(ctx, "web.connect", []any{hosts[0][0]}, nil)
+	if err != nil {
+		return fmt.Errorf("connecting to daemon: %w", err)
+	}
+
+	err = d.call(ctx, "web.connected", []any{}, &connected)
+	if err != nil {
+		return fmt.Errorf("checking daemon connection: %w", err)
+	} else if !connected {
+		return ErrDelugeDaemonConnection
+	}
+	return nil
+}
+
+// call calls the JSON-RPC method with the parameters given, and decodes
+// the response result into result if it is not nil.
+func (d *deluge) call(ctx context.Context, method string,
+	params []any, result any,
+) (err error) {
+	d.id++
+	body, err := json.Marshal(struct {
+		Method string `json:"method"`
+		Params []any  `json:"params"`
+		ID     uint   `json:"id"`
+	}{
+		Method: method,
+		Params: params,
+		ID:     d.id,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding request body: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, d.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	var data struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"error"`
+	}
+	err = decodeJSON(response, &data)
+	if err != nil {
+		return err
+	} else if data.Error != nil {
+		return fmt.Errorf("%w: %s: %s (code %d)", ErrDelugeRPC, method,
+			data.Error.Message, data.Error.Code)
+	}
+
+	if result == nil {
+		return nil
+	}
+	err = json.Unmarshal(data.Result, result)
+	if err != nil {
+		return fmt.Errorf("decoding %s result: %w", method, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package torrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_deluge(t *testing.T) {
+	t.Parallel()
+
+	const sessionCookie = "_session_id"
+	connected := false
+	listenPorts := []uint16{6881, 6891}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+			ID     uint              `json:"id"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		cookie, err := r.Cookie(sessionCookie)
+		authenticated := err == nil && cookie.Value == "abc"
+
+		var result string
+		switch {
+		case request.Method == "auth.check_session":
+			result = fmt.Sprint(authenticated)
+		case request.Method == "auth.login":
+			loggedIn := len(request.Params) == 1 && string(request.Params[0]) == `"secret"`
+			if loggedIn {
+				http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "abc", Path: "/"})
+			}
+			result = fmt.Sprint(loggedIn)
+		case !authenticated:
+			_, _ = fmt.Fprintf(w, `{"result":null,"error":{"message":"Not authenticated","code":1},"id":%d}`,
+				request.ID)
+			return
+		case request.Method == "web.connected":
+			result = fmt.Sprint(connected)
+		case request.Method == "web.get_hosts":
+			result = `[["host-id","127.0.0.1",58846,"Online"]]`
+		case request.Method == "web.connect":
+			assert.Equal(t, []json.RawMessage{json.RawMessage(`"host-id"`)}, request.Params)
+			connected = true
+			result = "null"
+		case request.Method == "core.get_config_value":
+			encoded, err := json.Marshal(listenPorts)
+			assert.NoError(t, err)
+			result = string(encoded)
+		case request.Method == "core.set_config":
+			assert.Equal(t, []json.RawMessage{
+				json.RawMessage(`{"listen_ports":[1234,1234],"random_port":false}`),
+			}, request.Params)
+			listenPorts = []uint16{1234, 1234}
+			result = "null"
+		default:
+			t.Errorf("unexpected method %q", request.Method)
+		}
+		_, _ = fmt.Fprintf(w, `{"result":%s,"error":null,"id":%d}`, result, request.ID)
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	client := &http.Client{Jar: jar}
+
+	badPassword := newDeluge(client, server.URL, "wrong")
+	_, err = badPassword.getPort(ctx)
+	require.ErrorIs(t, err, ErrDelugeLoginFailed)
+
+	deluge := newDeluge(client, server.URL, "secret")
+	port, err := deluge.getPort(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(6881), port)
+	assert.True(t, connected)
+
+	err = deluge.setPort(ctx, 1234)
+	require.NoError(t, err)
+
+	port, err = deluge.getPort(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1234), port)
+}
@@ -0,0 +1,23 @@
+package torrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/qdm12/gluetun/internal/httpclient"
+)
+
+// decodeJSON checks the response status code is OK and
+// decodes its JSON body into the value given.
+func decodeJSON(response *http.Response, v any) (err error) {
+	if response.StatusCode != http.StatusOK {
+		return httpclient.StatusError(response)
+	}
+
+	err = json.NewDecoder(response.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("decoding response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+package torrent
+
+type Logger interface {
+	Info(s string)
+	Warn(s string)
+}
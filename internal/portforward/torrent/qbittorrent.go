@@ -0,0 +1,153 @@
+package torrent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/httpclient"
+)
+
+var ErrQBittorrentLoginFailed = errors.New("qBittorrent login failed")
+
+// qBittorrent uses the qBittorrent WebUI API, see
+// https://github.com/qbittorrent/qBittorrent/wiki/WebUI-API-(qBittorrent-4.1)
+type qBittorrent struct {
+	client   *http.Client
+	baseURL  string
+	username string
+	password string
+}
+
+func newQBittorrent(client *http.Client, baseURL, username, password string) *qBittorrent {
+	return &qBittorrent{
+		client:   client,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+	}
+}
+
+func (q *qBittorrent) getPort(ctx context.Context) (port uint16, err error) {
+	response, err := q.do(ctx, http.MethodGet, "/api/v2/app/preferences", nil)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	var preferences struct {
+		ListenPort uint16 `json:"listen_port"`
+	}
+	err = decodeJSON(response, &preferences)
+	if err != nil {
+		return 0, err
+	}
+	return preferences.ListenPort, nil
+}
+
+func (q *qBittorrent) setPort(ctx context.Context, port uint16) (err error) {
+	preferences, err := json.Marshal(struct {
+		ListenPort uint16 `json:"listen_port"`
+		RandomPort bool   `json:"random_port"`
+	}{
+		ListenPort: port,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding preferences: %w", err)
+	}
+
+	form := url.Values{"json": {string(preferences)}}
+	response, err := q.do(ctx, http.MethodPost, "/api/v2/app/setPreferences", form)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return httpclient.StatusError(response)
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	return nil
+}
+
+// do sends a request to the qBittorrent API, logging in and
+// retrying once if the API responds with 403 Forbidden, which
+// happens when there is no session cookie or it expired.
+func (q *qBittorrent) do(ctx context.Context, method, path string,
+	form url.Values,
+) (response *http.Response, err error) {
+	for attempt := 0; ; attempt++ {
+		response, err = q.request(ctx, method, path, form)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode != http.StatusForbidden || attempt > 0 {
+			return response, nil
+		}
+		_ = response.Body.Close()
+
+		err = q.login(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("logging in: %w", err)
+		}
+	}
+}
+
+func (q *qBittorrent) login(ctx context.Context) (err error) {
+	form := url.Values{
+		"username": {q.username},
+		"password": {q.password},
+	}
+	response, err := q.request(ctx, http.MethodPost, "/api/v2/auth/login", form)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return httpclient.StatusError(response)
+	}
+
+	const maxBodySize = 64
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize))
+	if err != nil {
+		return fmt.Errorf("reading response body: %w", err)
+	}
+	// qBittorrent responds with "Ok." on success and "Fails." otherwise.
+	if strings.TrimSpace(string(body)) != "Ok." {
+		return fmt.Errorf("%w: %s", ErrQBittorrentLoginFailed, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func (q *qBittorrent) request(ctx context.Context, method, path string,
+	form url.Values,
+) (response *http.Response, err error) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, q.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if form != nil {
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	// qBittorrent rejects requests with a Referer or Origin header
+	// not matching its host, when its CSRF protection is enabled.
+	request.Header.Set("Referer", q.baseURL)
+
+	response, err = q.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
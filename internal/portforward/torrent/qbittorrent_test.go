@@ -0,0 +1,75 @@
+package torrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_qBittorrent(t *testing.T) {
+	t.Parallel()
+
+	const sessionCookie = "SID"
+	listenPort := uint16(6881)
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			logins++
+			assert.NoError(t, r.ParseForm())
+			if r.PostForm.Get("username") != "admin" || r.PostForm.Get("password") != "secret" {
+				_, _ = w.Write([]byte("Fails."))
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: "abc", Path: "/"})
+			_, _ = w.Write([]byte("Ok."))
+			return
+		}
+
+		cookie, err := r.Cookie(sessionCookie)
+		if err != nil || cookie.Value != "abc" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			_, _ = fmt.Fprintf(w, `{"listen_port":%d,"random_port":false}`, listenPort)
+		case "/api/v2/app/setPreferences":
+			assert.NoError(t, r.ParseForm())
+			assert.JSONEq(t, `{"listen_port":51413,"random_port":false}`, r.PostForm.Get("json"))
+			listenPort = 51413
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	client := &http.Client{Jar: jar}
+
+	ctx := context.Background()
+
+	badCredentials := newQBittorrent(client, server.URL, "admin", "wrong")
+	_, err = badCredentials.getPort(ctx)
+	require.ErrorIs(t, err, ErrQBittorrentLoginFailed)
+
+	q := newQBittorrent(client, server.URL+"/", "admin", "secret")
+	port, err := q.getPort(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(6881), port)
+
+	err = q.setPort(ctx, 51413)
+	require.NoError(t, err)
+
+	port, err = q.getPort(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(51413), port)
+	assert.Equal(t, 2, logins)
+}
@@ -0,0 +1,190 @@
+package torrent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type client interface {
+	getPort(ctx context.Context) (port uint16, err error)
+	setPort(ctx context.Context, port uint16) (err error)
+}
+
+// Syncer keeps the listening port of a torrent client
+// set to the forwarded port.
+type Syncer struct {
+	// Fixed parameters
+	name        string
+	checkPeriod time.Duration
+	// Fixed injected objects
+	client client
+	logger Logger
+	// State
+	statusMutex sync.RWMutex
+	status      models.TorrentClientStatus
+	// Internal channels and locks
+	startStopMutex sync.Mutex
+	cancel         context.CancelFunc
+	done           <-chan struct{}
+}
+
+// New creates a torrent client port syncer using the settings given.
+// The settings torrent client name must not be empty.
+// The HTTP client transport and timeout are reused, with a dedicated
+// cookie jar to hold the torrent client session cookies.
+func New(clientSettings settings.TorrentClient, httpClient *http.Client,
+	logger Logger,
+) *Syncer {
+	jar, _ := cookiejar.New(nil) // error is always nil without options
+	sessionClient := &http.Client{
+		Transport: httpClient.Transport,
+		Timeout:   httpClient.Timeout,
+		Jar:       jar,
+	}
+
+	var torrentClient client
+	switch *clientSettings.Name {
+	case settings.TorrentClientQBittorrent:
+		torrentClient = newQBittorrent(sessionClient, *clientSettings.URL,
+			*clientSettings.Username, *clientSettings.Password)
+	case settings.TorrentClientTransmission:
+		torrentClient = newTransmission(sessionClient, *clientSettings.URL,
+			*clientSettings.Username, *clientSettings.Password)
+	case settings.TorrentClientDeluge:
+		torrentClient = newDeluge(sessionClient, *clientSettings.URL, *clientSettings.Password)
+	default:
+		panic(fmt.Sprintf("torrent client %q not implemented", *clientSettings.Name))
+	}
+
+	return newSyncer(*clientSettings.Name, *clientSettings.CheckPeriod, torrentClient, logger)
+}
+
+func newSyncer(name string, checkPeriod time.Duration,
+	client client, logger Logger,
+) *Syncer {
+	return &Syncer{
+		name:        name,
+		checkPeriod: checkPeriod,
+		client:      client,
+		logger:      logger,
+		status:      models.TorrentClientStatus{Client: name},
+	}
+}
+
+// Start starts setting the listening port of the torrent client to
+// the port given, and keeps on checking it periodically in case
+// the torrent client restarts or its port is changed.
+// Any previous synchronization is stopped first.
+func (s *Syncer) Start(port uint16) {
+	s.startStopMutex.Lock()
+	defer s.startStopMutex.Unlock()
+	s.stop()
+
+	s.statusMutex.Lock()
+	s.status = models.TorrentClientStatus{Client: s.name, Port: port}
+	s.statusMutex.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	done := make(chan struct{})
+	s.done = done
+	go s.run(ctx, port, done)
+}
+
+// Stop stops the synchronization, if it is running.
+func (s *Syncer) Stop() {
+	s.startStopMutex.Lock()
+	defer s.startStopMutex.Unlock()
+	s.stop()
+
+	s.statusMutex.Lock()
+	s.status = models.TorrentClientStatus{Client: s.name}
+	s.statusMutex.Unlock()
+}
+
+func (s *Syncer) stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+	s.cancel = nil
+	s.done = nil
+}
+
+// Status returns the current synchronization status.
+func (s *Syncer) Status() (status models.TorrentClientStatus) {
+	s.statusMutex.RLock()
+	defer s.statusMutex.RUnlock()
+	return s.status
+}
+
+const minRetryBackoff = 5 * time.Second
+
+func (s *Syncer) run(ctx context.Context, port uint16, done chan<- struct{}) {
+	defer close(done)
+
+	backoff := minRetryBackoff
+	for {
+		wait := s.checkPeriod
+		err := s.sync(ctx, port)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			s.logger.Warn(fmt.Sprintf("%s port synchronization: %s - retrying in %s",
+				s.name, err, backoff))
+			wait = backoff
+			backoff = min(2*backoff, s.checkPeriod) //nolint:mnd
+		default:
+			backoff = minRetryBackoff
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// sync sets the torrent client listening port to the port
+// given if it is not already set to it, and updates the
+// synchronization status accordingly.
+func (s *Syncer) sync(ctx context.Context, port uint16) (err error) {
+	defer func() {
+		s.statusMutex.Lock()
+		defer s.statusMutex.Unlock()
+		s.status.Synced = err == nil
+		if err != nil {
+			s.status.Error = err.Error()
+			return
+		}
+		s.status.Error = ""
+		s.status.LastSync = time.Now()
+	}()
+
+	currentPort, err := s.client.getPort(ctx)
+	if err != nil {
+		return fmt.Errorf("getting listening port: %w", err)
+	} else if currentPort == port {
+		return nil
+	}
+
+	err = s.client.setPort(ctx, port)
+	if err != nil {
+		return fmt.Errorf("setting listening port: %w", err)
+	}
+	s.logger.Info(fmt.Sprintf("%s listening port changed from %d to %d",
+		s.name, currentPort, port))
+	return nil
+}
@@ -0,0 +1,75 @@
+package torrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClient struct {
+	mutex   sync.Mutex
+	port    uint16
+	getErrs []error
+	sets    int
+}
+
+func (f *fakeClient) getPort(context.Context) (port uint16, err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(f.getErrs) > 0 {
+		err, f.getErrs = f.getErrs[0], f.getErrs[1:]
+		return 0, err
+	}
+	return f.port, nil
+}
+
+func (f *fakeClient) setPort(_ context.Context, port uint16) (err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.port = port
+	f.sets++
+	return nil
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Info(string) {}
+func (noopLogger) Warn(string) {}
+
+func Test_Syncer(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	client := &fakeClient{port: 6881, getErrs: []error{errTest}}
+	syncer := newSyncer("qbittorrent", time.Hour, client, noopLogger{})
+
+	status := syncer.sync(context.Background(), 1234)
+	assert.ErrorIs(t, status, errTest)
+	assert.Equal(t, "getting listening port: test error", syncer.Status().Error)
+	assert.False(t, syncer.Status().Synced)
+
+	syncer.Start(1234)
+	assert.Eventually(t, func() bool {
+		return syncer.Status().Synced
+	}, time.Second, time.Millisecond)
+
+	status2 := syncer.Status()
+	assert.Equal(t, "qbittorrent", status2.Client)
+	assert.Equal(t, uint16(1234), status2.Port)
+	assert.Empty(t, status2.Error)
+	assert.False(t, status2.LastSync.IsZero())
+
+	client.mutex.Lock()
+	assert.Equal(t, uint16(1234), client.port)
+	assert.Equal(t, 1, client.sets)
+	client.mutex.Unlock()
+
+	syncer.Stop()
+	assert.Equal(t, "qbittorrent", syncer.Status().Client)
+	assert.False(t, syncer.Status().Synced)
+	assert.Zero(t, syncer.Status().Port)
+}
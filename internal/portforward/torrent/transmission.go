@@ -0,0 +1,116 @@
+package torrent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var ErrTransmissionResult = errors.New("transmission RPC result is not success")
+
+// transmission uses the Transmission RPC API, see
+// https://github.com/transmission/transmission/blob/main/docs/rpc-spec.md
+type transmission struct {
+	client    *http.Client
+	url       string
+	username  string
+	password  string
+	sessionID string
+}
+
+func newTransmission(client *http.Client, url, username, password string) *transmission {
+	return &transmission{
+		client:   client,
+		url:      url,
+		username: username,
+		password: password,
+	}
+}
+
+func (t *transmission) getPort(ctx context.Context) (port uint16, err error) {
+	var arguments struct {
+		PeerPort uint16 `json:"peer-port"`
+	}
+	err = t.call(ctx, "session-get", map[string]any{
+		"fields": []string{"peer-port"},
+	}, &arguments)
+	if err != nil {
+		return 0, err
+	}
+	return arguments.PeerPort, nil
+}
+
+func (t *transmission) setPort(ctx context.Context, port uint16) (err error) {
+	return t.call(ctx, "session-set", map[string]any{
+		"peer-port":                 port,
+		"peer-port-random-on-start": false,
+	}, nil)
+}
+
+// call calls the RPC method with the arguments given, and decodes
+// the response arguments into result if it is not nil.
+// The request is sent again once if the server responds with
+// 409 Conflict, which happens when the session ID is missing or
+// outdated, using the session ID given in the response header.
+func (t *transmission) call(ctx context.Context, method string,
+	arguments any, result any,
+) (err error) {
+	body, err := json.Marshal(struct {
+		Method    string `json:"method"`
+		Arguments any    `json:"arguments"`
+	}{
+		Method:    method,
+		Arguments: arguments,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding request body: %w", err)
+	}
+
+	const sessionIDHeader = "X-Transmission-Session-Id"
+	for attempt := 0; ; attempt++ {
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("creating request: %w", err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(sessionIDHeader, t.sessionID)
+		if t.username != "" {
+			request.SetBasicAuth(t.username, t.password)
+		}
+
+		response, err := t.client.Do(request)
+		if err != nil {
+			return err
+		}
+
+		if response.StatusCode == http.StatusConflict && attempt == 0 {
+			t.sessionID = response.Header.Get(sessionIDHeader)
+			_ = response.Body.Close()
+			continue
+		}
+
+		var data struct {
+			Result    string          `json:"result"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		err = decodeJSON(response, &data)
+		_ = response.Body.Close()
+		if err != nil {
+			return err
+		} else if data.Result != "success" {
+			return fmt.Errorf("%w: %s", ErrTransmissionResult, data.Result)
+		}
+
+		if result == nil {
+			return nil
+		}
+		err = json.Unmarshal(data.Arguments, result)
+		if err != nil {
+			return fmt.Errorf("decoding response arguments: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package torrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/httpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_transmission(t *testing.T) {
+	t.Parallel()
+
+	const sessionID = "session-id"
+	peerPort := uint16(51413)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get("X-Transmission-Session-Id") != sessionID {
+			w.Header().Set("X-Transmission-Session-Id", sessionID)
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+
+		var request struct {
+			Method    string         `json:"method"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+
+		switch request.Method {
+		case "session-get":
+			_, _ = fmt.Fprintf(w, `{"result":"success","arguments":{"peer-port":%d}}`, peerPort)
+		case "session-set":
+			assert.Equal(t, map[string]any{
+				"peer-port":                 float64(1234),
+				"peer-port-random-on-start": false,
+			}, request.Arguments)
+			peerPort = 1234
+			_, _ = w.Write([]byte(`{"result":"success","arguments":{}}`))
+		default:
+			_, _ = w.Write([]byte(`{"result":"method name not recognized"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	ctx := context.Background()
+
+	unauthorized := newTransmission(server.Client(), server.URL, "", "")
+	_, err := unauthorized.getPort(ctx)
+	require.ErrorIs(t, err, httpclient.ErrHTTPStatusCodeNotOK)
+
+	transmission := newTransmission(server.Client(), server.URL, "user", "pass")
+	port, err := transmission.getPort(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(51413), port)
+
+	err = transmission.setPort(ctx, 1234)
+	require.NoError(t, err)
+
+	port, err = transmission.getPort(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1234), port)
+
+	err = transmission.call(ctx, "unknown", nil, nil)
+	require.ErrorIs(t, err, ErrTransmissionResult)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 	"time"
 
@@ -39,12 +40,18 @@ func (p *Provider) PortForward(ctx context.Context, objects utils.PortForwardObj
 	logger := objects.Logger
 
 	logger.Debug("gateway external IPv4 address is " + externalIPv4Address.String())
-	const externalPort = 0
 	const lifetime = 60 * time.Second
+	const anyExternalPort = 0
+
+	previousExternalPorts := sortedExternalPorts(objects.PreviousPorts)
 
 	p.internalToExternalPorts = make(map[uint16]uint16, objects.PortsCount)
 	for i := range objects.PortsCount {
 		internalPort := nonSymmetricPortStart + i
+		requestedExternalPort := uint16(anyExternalPort)
+		if int(i) < len(previousExternalPorts) {
+			requestedExternalPort = previousExternalPorts[i]
+		}
 		protoToInternalPort := map[string]uint16{
 			"udp": 0,
 			"tcp": 0,
@@ -52,7 +59,13 @@ func (p *Provider) PortForward(ctx context.Context, objects utils.PortForwardObj
 		protoToExternalPort := maps.Clone(protoToInternalPort)
 		for protocol := range protoToExternalPort {
 			_, assignedInternalPort, assignedExternalPort, assignedLifetime, err := client.AddPortMapping(
-				ctx, objects.Gateway, protocol, internalPort, externalPort, lifetime)
+				ctx, objects.Gateway, protocol, internalPort, requestedExternalPort, lifetime)
+			if err != nil && requestedExternalPort != anyExternalPort {
+				logger.Debug(fmt.Sprintf("reclaiming previous %s external port %d failed: %s",
+					strings.ToUpper(protocol), requestedExternalPort, err))
+				_, assignedInternalPort, assignedExternalPort, assignedLifetime, err = client.AddPortMapping(
+					ctx, objects.Gateway, protocol, internalPort, anyExternalPort, lifetime)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("adding %d/%d %s port mapping: %w",
 					i+1, objects.PortsCount, strings.ToUpper(protocol), err)
@@ -71,6 +84,17 @@ func (p *Provider) PortForward(ctx context.Context, objects utils.PortForwardObj
 	return maps.Clone(p.internalToExternalPorts), nil
 }
 
+// sortedExternalPorts returns the external ports of the internal to
+// external ports map given, sorted by their internal port.
+func sortedExternalPorts(internalToExternalPorts map[uint16]uint16) (externalPorts []uint16) {
+	internalPorts := slices.Sorted(maps.Keys(internalToExternalPorts))
+	externalPorts = make([]uint16, len(internalPorts))
+	for i, internalPort := range internalPorts {
+		externalPorts[i] = internalToExternalPorts[internalPort]
+	}
+	return externalPorts
+}
+
 func checkLifetime(logger utils.Logger, protocol string,
 	requested, actual time.Duration,
 ) {
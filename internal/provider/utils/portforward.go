@@ -27,6 +27,12 @@ type PortForwardObjects struct {
 	Password string
 	// PortsCount is used by ProtonVPN for port forwarding.
 	PortsCount uint16
+	// PreviousPorts are the internal to external ports previously
+	// forwarded for the same provider and server, which the port
+	// forwarding code can try to obtain again. It is nil if there is no
+	// previous binding, and is only used by ProtonVPN since other providers
+	// cannot request specific ports.
+	PreviousPorts map[uint16]uint16
 }
 
 type Routing interface {
@@ -48,6 +48,9 @@ func New(nameTokenPairs []NameToken, client *http.Client) (
 		case strings.HasPrefix(string(provider), echoipPrefix):
 			url := strings.TrimPrefix(string(provider), echoipPrefix)
 			fetchers[i] = newEchoip(client, url)
+		case strings.HasPrefix(string(provider), mmdbPrefix):
+			paths := strings.Split(strings.TrimPrefix(string(provider), mmdbPrefix), ":")
+			fetchers[i] = newMMDB(client, paths)
 		default:
 			panic("provider not valid: " + provider)
 		}
@@ -86,13 +89,22 @@ func ParseProvider(s string) (provider Provider, err error) {
 		return Provider(s), nil
 	}
 
-	providerStrings := make([]string, 0, len(stringToProvider)+len(customPrefixToURLRegex))
+	match, err := checkMMDBPaths(s)
+	if match {
+		if err != nil {
+			return "", err
+		}
+		return Provider(s), nil
+	}
+
+	providerStrings := make([]string, 0, len(stringToProvider)+len(customPrefixToURLRegex)+1)
 	for _, providerString := range slices.Sorted(maps.Keys(stringToProvider)) {
 		providerStrings = append(providerStrings, `"`+providerString+`"`)
 	}
 	for _, prefix := range slices.Sorted(maps.Keys(customPrefixToURLRegex)) {
 		providerStrings = append(providerStrings, "a custom "+prefix+" url")
 	}
+	providerStrings = append(providerStrings, "an "+mmdbPrefix+" file path")
 
 	return "", fmt.Errorf("API name is not valid: %q can only be %s",
 		s, orStrings(providerStrings))
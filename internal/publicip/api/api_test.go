@@ -16,12 +16,12 @@ func Test_ParseProvider(t *testing.T) {
 	}{
 		"empty": {
 			errMessage: `API name is not valid: "" can only be ` +
-				`"cloudflare", "ifconfigco", "ip2location", "ipinfo" or a custom echoip# url`,
+				`"cloudflare", "ifconfigco", "ip2location", "ipinfo", a custom echoip# url or an mmdb# file path`,
 		},
 		"invalid": {
 			s: "xyz",
 			errMessage: `API name is not valid: "xyz" can only be ` +
-				`"cloudflare", "ifconfigco", "ip2location", "ipinfo" or a custom echoip# url`,
+				`"cloudflare", "ifconfigco", "ip2location", "ipinfo", a custom echoip# url or an mmdb# file path`,
 		},
 		"ipinfo": {
 			s:        "ipinfo",
@@ -45,6 +45,14 @@ func Test_ParseProvider(t *testing.T) {
 			s:        "echoip#http://localhost:3451",
 			provider: Provider("echoip#http://localhost:3451"),
 		},
+		"mmdb_path_relative": {
+			s:          "mmdb#/gluetun/city.mmdb:asn.mmdb",
+			errMessage: `MaxMind DB file path is not absolute: "asn.mmdb"`,
+		},
+		"mmdb_paths_valid": {
+			s:        "mmdb#/gluetun/city.mmdb:/gluetun/asn.mmdb",
+			provider: Provider("mmdb#/gluetun/city.mmdb:/gluetun/asn.mmdb"),
+		},
 	}
 
 	for name, testCase := range testCases {
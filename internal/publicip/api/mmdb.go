@@ -0,0 +1,203 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/mmdb"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const mmdbPrefix = "mmdb#"
+
+// mmdbFetcher fetches IP address information from local MaxMind DB
+// files, such as MaxMind GeoLite2 or DB-IP Lite city and ASN databases.
+// It only uses a remote service to find the public IP address
+// of the machine, and not to find information on it.
+type mmdbFetcher struct {
+	paths     []string
+	ipFetcher InfoFetcher
+	// readers are the database readers opened on the first lookup,
+	// in the same order as paths, and closed by [mmdbFetcher.Close].
+	readers      []*mmdb.Reader
+	readersMutex sync.Mutex
+}
+
+func newMMDB(client *http.Client, paths []string) *mmdbFetcher {
+	return &mmdbFetcher{
+		paths:     paths,
+		ipFetcher: newCloudflare(client),
+	}
+}
+
+func (m *mmdbFetcher) String() string {
+	return mmdbPrefix + strings.Join(m.paths, ":")
+}
+
+func (m *mmdbFetcher) CanFetchAnyIP() bool {
+	return true
+}
+
+func (m *mmdbFetcher) Token() string {
+	return ""
+}
+
+// FetchInfo obtains information on the ip address provided using
+// the local MaxMind DB files. If the ip is the zero value, the public
+// IP address of the machine is first fetched from Cloudflare.
+// Information from each database file is merged in the result, with
+// the first database having precedence for each field.
+func (m *mmdbFetcher) FetchInfo(ctx context.Context, ip netip.Addr) (
+	result models.PublicIP, err error,
+) {
+	if !ip.IsValid() {
+		ipResult, err := m.ipFetcher.FetchInfo(ctx, ip)
+		if err != nil {
+			return result, fmt.Errorf("fetching public IP address: %w", err)
+		}
+		ip = ipResult.IP
+	}
+
+	m.readersMutex.Lock()
+	defer m.readersMutex.Unlock()
+
+	err = m.openReaders()
+	if err != nil {
+		return result, err
+	}
+
+	result.IP = ip
+	for i, reader := range m.readers {
+		record, err := reader.Lookup(ip)
+		if err != nil {
+			return result, fmt.Errorf("looking up %s: %w", filepath.Base(m.paths[i]), err)
+		}
+		mergeMMDBRecord(&result, record)
+	}
+	return result, nil
+}
+
+// openReaders opens the database files if they are not already opened.
+// It must be called with the readers mutex locked.
+func (m *mmdbFetcher) openReaders() (err error) {
+	if m.readers != nil {
+		return nil
+	}
+
+	readers := make([]*mmdb.Reader, 0, len(m.paths))
+	for _, path := range m.paths {
+		reader, err := mmdb.Open(path)
+		if err != nil {
+			for _, reader := range readers {
+				_ = reader.Close()
+			}
+			return fmt.Errorf("opening %s: %w", filepath.Base(path), err)
+		}
+		readers = append(readers, reader)
+	}
+	m.readers = readers
+	return nil
+}
+
+// Close closes the database files opened. The files are opened
+// again if [mmdbFetcher.FetchInfo] is called after Close.
+func (m *mmdbFetcher) Close() (err error) {
+	m.readersMutex.Lock()
+	defer m.readersMutex.Unlock()
+
+	errs := make([]error, 0, len(m.readers))
+	for i, reader := range m.readers {
+		closeErr := reader.Close()
+		if closeErr != nil {
+			errs = append(errs, fmt.Errorf("closing %s: %w", filepath.Base(m.paths[i]), closeErr))
+		}
+	}
+	m.readers = nil
+	return errors.Join(errs...)
+}
+
+// mergeMMDBRecord sets empty fields of the result using the record given,
+// which can be a record from a city, country or ASN database.
+func mergeMMDBRecord(result *models.PublicIP, record any) {
+	setIfEmpty(&result.Country, mmdbString(record, "country", "names", "en"))
+	setIfEmpty(&result.Region, mmdbString(record, "subdivisions", 0, "names", "en"))
+	setIfEmpty(&result.City, mmdbString(record, "city", "names", "en"))
+	setIfEmpty(&result.PostalCode, mmdbString(record, "postal", "code"))
+	setIfEmpty(&result.Timezone, mmdbString(record, "location", "time_zone"))
+
+	latitude, latitudeOK := mmdbValue(record, "location", "latitude").(float64)
+	longitude, longitudeOK := mmdbValue(record, "location", "longitude").(float64)
+	if latitudeOK && longitudeOK {
+		setIfEmpty(&result.Location, fmt.Sprintf("%f,%f", latitude, longitude))
+	}
+
+	organization := mmdbString(record, "autonomous_system_organization")
+	asn, asnOK := mmdbValue(record, "autonomous_system_number").(uint64)
+	if organization == "" { // GeoIP2 ISP and Enterprise databases
+		organization = mmdbString(record, "traits", "autonomous_system_organization")
+		asn, asnOK = mmdbValue(record, "traits", "autonomous_system_number").(uint64)
+	}
+	if asnOK {
+		organization = strings.TrimSpace(fmt.Sprintf("AS%d %s", asn, organization))
+	}
+	setIfEmpty(&result.Organization, organization)
+}
+
+func setIfEmpty(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+func mmdbString(record any, path ...any) string {
+	s, _ := mmdbValue(record, path...).(string)
+	return s
+}
+
+// mmdbValue returns the value found following the path given, where each
+// path element is either a map key string or an array index int.
+// It returns nil if the path does not exist.
+func mmdbValue(record any, path ...any) (value any) {
+	value = record
+	for _, element := range path {
+		switch key := element.(type) {
+		case string:
+			fields, ok := value.(map[string]any)
+			if !ok {
+				return nil
+			}
+			value = fields[key]
+		case int:
+			values, ok := value.([]any)
+			if !ok || key >= len(values) {
+				return nil
+			}
+			value = values[key]
+		default:
+			panic(fmt.Sprintf("path element type not supported: %T", element))
+		}
+	}
+	return value
+}
+
+var ErrMMDBPathNotAbsolute = errors.New("MaxMind DB file path is not absolute")
+
+// checkMMDBPaths checks the colon separated MaxMind DB file paths
+// given with the mmdb# prefix are all absolute paths.
+func checkMMDBPaths(s string) (match bool, err error) {
+	if !strings.HasPrefix(s, mmdbPrefix) {
+		return false, nil
+	}
+	for path := range strings.SplitSeq(strings.TrimPrefix(s, mmdbPrefix), ":") {
+		if !filepath.IsAbs(path) {
+			return true, fmt.Errorf("%w: %q", ErrMMDBPathNotAbsolute, path)
+		}
+	}
+	return true, nil
+}
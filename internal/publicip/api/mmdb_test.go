@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_mergeMMDBRecord(t *testing.T) {
+	t.Parallel()
+
+	cityRecord := map[string]any{
+		"country": map[string]any{
+			"iso_code": "FR",
+			"names":    map[string]any{"en": "France", "fr": "France"},
+		},
+		"subdivisions": []any{
+			map[string]any{"names": map[string]any{"en": "Île-de-France"}},
+		},
+		"city":     map[string]any{"names": map[string]any{"en": "Paris"}},
+		"postal":   map[string]any{"code": "75001"},
+		"location": map[string]any{"latitude": 48.5, "longitude": 2.25, "time_zone": "Europe/Paris"},
+	}
+	asnRecord := map[string]any{
+		"autonomous_system_number":       uint64(3215),
+		"autonomous_system_organization": "Orange",
+	}
+
+	var result models.PublicIP
+	mergeMMDBRecord(&result, cityRecord)
+	mergeMMDBRecord(&result, asnRecord)
+	mergeMMDBRecord(&result, nil) // not found in database
+
+	expected := models.PublicIP{
+		Country:      "France",
+		Region:       "Île-de-France",
+		City:         "Paris",
+		PostalCode:   "75001",
+		Timezone:     "Europe/Paris",
+		Location:     "48.500000,2.250000",
+		Organization: "AS3215 Orange",
+	}
+	assert.Equal(t, expected, result)
+}
+
+func Test_mmdbFetcher_FetchInfo_missingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "GeoLite2-City.mmdb")
+	fetcher := newMMDB(nil, []string{path})
+
+	_, err := fetcher.FetchInfo(t.Context(), netip.MustParseAddr("1.2.3.4"))
+
+	require.ErrorIs(t, err, os.ErrNotExist)
+	assert.Nil(t, fetcher.readers)
+	assert.NoError(t, fetcher.Close())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/netip"
 	"strings"
 	"sync"
@@ -222,7 +223,7 @@ func getMostPopularString(values []string) (winnerIdx int, memberIdxs []int) {
 	var groups []cluster
 
 	for i, value := range values {
-		normP := normalize(value)
+		normP := Normalize(value)
 		found := false
 
 		for j := range groups {
@@ -277,12 +278,41 @@ func (r *ResilientFetcher) UpdateFetchers(fetchers []Fetcher) {
 		}
 	}
 
+	closeErr := closeFetchers(r.fetchers)
+	if closeErr != nil {
+		r.logger.Warn("closing previous fetchers: " + closeErr.Error())
+	}
+
 	r.fetchers = fetchers
 	r.fetcherToBanTime = newFetcherToBanTime
 }
 
-// normalize removes accents, trims space, and lowercases the string.
-func normalize(s string) string {
+// Close closes the fetchers holding resources, such as
+// the local MaxMind DB files opened.
+func (r *ResilientFetcher) Close() (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return closeFetchers(r.fetchers)
+}
+
+func closeFetchers(fetchers []Fetcher) (err error) {
+	errs := make([]error, 0, len(fetchers))
+	for _, fetcher := range fetchers {
+		closer, ok := fetcher.(io.Closer)
+		if !ok {
+			continue
+		}
+		err = closer.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fetcher, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Normalize removes accents, any parenthesized suffix, trims space,
+// and lowercases the string.
+func Normalize(s string) string {
 	firstParentheseIndex := strings.Index(s, " (")
 	if firstParentheseIndex != -1 {
 		s = s[:firstParentheseIndex]
@@ -1,6 +1,10 @@
 package publicip
 
-import "github.com/qdm12/gluetun/internal/models"
+import (
+	"fmt"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
 
 // GetData returns the public IP data obtained from the last
 // fetch. It is notably used by the HTTP control server.
@@ -19,6 +23,13 @@ func (l *Loop) ClearData() (err error) {
 
 	l.settingsMutex.RLock()
 	filepath := *l.settings.IPFilepath
+	historyFilepath := *l.settings.HistoryFilepath
 	l.settingsMutex.RUnlock()
+
+	err = l.closeHistory(historyFilepath)
+	if err != nil {
+		return fmt.Errorf("persisting public IP history: %w", err)
+	}
+
 	return persistPublicIP(filepath, "", l.puid, l.pgid)
 }
@@ -0,0 +1,179 @@
+package publicip
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// SetServerName sets the VPN server name to record in the public IP
+// history for the next public IP address fetched.
+func (l *Loop) SetServerName(serverName string) {
+	l.historyMutex.Lock()
+	defer l.historyMutex.Unlock()
+	l.serverName = serverName
+}
+
+// GetHistory returns the public IP addresses history, from the
+// oldest to the most recent entry. It is notably used by the HTTP
+// control server.
+func (l *Loop) GetHistory() (history []models.PublicIPHistoryEntry) {
+	l.historyMutex.RLock()
+	defer l.historyMutex.RUnlock()
+	return withDurations(l.history, l.timeNow())
+}
+
+// recordHistory records the public IP data given in the history
+// and persists the history. It returns the previous public IP address,
+// which is the zero address if there is no previous history entry.
+func (l *Loop) recordHistory(data models.PublicIP) (previousIP netip.Addr, err error) {
+	l.historyMutex.Lock()
+	defer l.historyMutex.Unlock()
+	l.history, previousIP = addHistoryEntry(l.history, data, l.serverName,
+		l.timeNow(), *l.settings.HistoryMaxEntries)
+	return previousIP, persistHistory(*l.settings.HistoryFilepath, l.history, l.puid, l.pgid)
+}
+
+// closeHistory marks the last history entry as ended, if it is still
+// in use, and persists the history.
+func (l *Loop) closeHistory(historyFilepath string) (err error) {
+	l.historyMutex.Lock()
+	defer l.historyMutex.Unlock()
+	if !closeLastEntry(l.history, l.timeNow()) {
+		return nil
+	}
+	return persistHistory(historyFilepath, l.history, l.puid, l.pgid)
+}
+
+// addHistoryEntry adds a history entry for the public IP data and server
+// name given, unless the last entry is still in use and matches them.
+// The last entry is marked as ended if it is still in use, and the oldest
+// entries are removed so there are at most maxEntries entries.
+func addHistoryEntry(history []models.PublicIPHistoryEntry, data models.PublicIP,
+	serverName string, now time.Time, maxEntries uint,
+) (updated []models.PublicIPHistoryEntry, previousIP netip.Addr) {
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		previousIP = last.IP
+		if last.End.IsZero() && last.IP == data.IP && last.ServerName == serverName {
+			return history, previousIP
+		}
+		closeLastEntry(history, now)
+	}
+
+	history = append(history, models.PublicIPHistoryEntry{
+		IP:           data.IP,
+		Country:      data.Country,
+		City:         data.City,
+		Organization: data.Organization,
+		ServerName:   serverName,
+		Start:        now,
+	})
+
+	return trimHistory(history, maxEntries), previousIP
+}
+
+// trimHistory removes the oldest entries of the history
+// so it contains at most maxEntries entries.
+func trimHistory(history []models.PublicIPHistoryEntry,
+	maxEntries uint,
+) (trimmed []models.PublicIPHistoryEntry) {
+	if uint(len(history)) <= maxEntries {
+		return history
+	}
+	return slices.Delete(history, 0, len(history)-int(maxEntries)) //nolint:gosec
+}
+
+// closeLastEntry sets the end time and duration of the last history entry
+// if it is still in use, and returns true if it did so.
+func closeLastEntry(history []models.PublicIPHistoryEntry, now time.Time) (closed bool) {
+	if len(history) == 0 || !history[len(history)-1].End.IsZero() {
+		return false
+	}
+	last := &history[len(history)-1]
+	last.End = now
+	last.DurationSeconds = durationSeconds(last.Start, now)
+	return true
+}
+
+// withDurations returns a copy of the history with the duration of the
+// last entry set relative to the current time if it is still in use.
+func withDurations(history []models.PublicIPHistoryEntry,
+	now time.Time,
+) (copied []models.PublicIPHistoryEntry) {
+	copied = slices.Clone(history)
+	if len(copied) > 0 && copied[len(copied)-1].End.IsZero() {
+		last := &copied[len(copied)-1]
+		last.DurationSeconds = durationSeconds(last.Start, now)
+	}
+	return copied
+}
+
+func durationSeconds(start, end time.Time) uint64 {
+	duration := end.Sub(start)
+	if duration < 0 {
+		return 0
+	}
+	return uint64(duration / time.Second)
+}
+
+func loadHistory(path string) (history []models.PublicIPHistoryEntry, err error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+
+	err = json.Unmarshal(data, &history)
+	if err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+
+	// Entries still in use from a previous run can no longer be in use.
+	for i := range history {
+		if history[i].End.IsZero() {
+			history[i].End = history[i].Start.Add(
+				time.Duration(history[i].DurationSeconds) * time.Second) //nolint:gosec
+		}
+	}
+
+	return history, nil
+}
+
+func persistHistory(path string, history []models.PublicIPHistoryEntry,
+	puid, pgid int,
+) (err error) {
+	if path == "" {
+		return nil
+	}
+
+	const dirPermission = 0o755
+	err = os.MkdirAll(filepath.Dir(path), dirPermission)
+	if err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+
+	err = persistPublicIP(path, string(data), puid, pgid)
+	if err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
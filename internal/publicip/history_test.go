@@ -0,0 +1,118 @@
+package publicip
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_addHistoryEntry(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1000, 0)
+	now := time.Unix(1100, 0)
+	ipA := netip.MustParseAddr("1.1.1.1")
+	ipB := netip.MustParseAddr("2.2.2.2")
+
+	testCases := map[string]struct {
+		history    []models.PublicIPHistoryEntry
+		data       models.PublicIP
+		serverName string
+		maxEntries uint
+		updated    []models.PublicIPHistoryEntry
+		previousIP netip.Addr
+	}{
+		"empty_history": {
+			data:       models.PublicIP{IP: ipA, Country: "Canada"},
+			serverName: "server",
+			maxEntries: 10,
+			updated: []models.PublicIPHistoryEntry{
+				{IP: ipA, Country: "Canada", ServerName: "server", Start: now},
+			},
+		},
+		"same_ip_and_server_in_use": {
+			history: []models.PublicIPHistoryEntry{
+				{IP: ipA, ServerName: "server", Start: start},
+			},
+			data:       models.PublicIP{IP: ipA},
+			serverName: "server",
+			maxEntries: 10,
+			updated: []models.PublicIPHistoryEntry{
+				{IP: ipA, ServerName: "server", Start: start},
+			},
+			previousIP: ipA,
+		},
+		"ip_changed": {
+			history: []models.PublicIPHistoryEntry{
+				{IP: ipA, Start: start},
+			},
+			data:       models.PublicIP{IP: ipB},
+			maxEntries: 10,
+			updated: []models.PublicIPHistoryEntry{
+				{IP: ipA, Start: start, End: now, DurationSeconds: 100},
+				{IP: ipB, Start: now},
+			},
+			previousIP: ipA,
+		},
+		"same_ip_after_reconnect": {
+			history: []models.PublicIPHistoryEntry{
+				{IP: ipA, Start: start, End: start.Add(time.Second), DurationSeconds: 1},
+			},
+			data:       models.PublicIP{IP: ipA},
+			maxEntries: 10,
+			updated: []models.PublicIPHistoryEntry{
+				{IP: ipA, Start: start, End: start.Add(time.Second), DurationSeconds: 1},
+				{IP: ipA, Start: now},
+			},
+			previousIP: ipA,
+		},
+		"oldest_entry_removed": {
+			history: []models.PublicIPHistoryEntry{
+				{IP: ipB, Start: start, End: start},
+				{IP: ipA, Start: start},
+			},
+			data:       models.PublicIP{IP: ipB},
+			maxEntries: 2,
+			updated: []models.PublicIPHistoryEntry{
+				{IP: ipA, Start: start, End: now, DurationSeconds: 100},
+				{IP: ipB, Start: now},
+			},
+			previousIP: ipA,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			updated, previousIP := addHistoryEntry(testCase.history, testCase.data,
+				testCase.serverName, now, testCase.maxEntries)
+
+			assert.Equal(t, testCase.updated, updated)
+			assert.Equal(t, testCase.previousIP, previousIP)
+		})
+	}
+}
+
+func Test_withDurations(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1000, 0)
+	now := time.Unix(1060, 0)
+	history := []models.PublicIPHistoryEntry{
+		{Start: start, End: start.Add(time.Second), DurationSeconds: 1},
+		{Start: start},
+	}
+
+	copied := withDurations(history, now)
+
+	expected := []models.PublicIPHistoryEntry{
+		{Start: start, End: start.Add(time.Second), DurationSeconds: 1},
+		{Start: start, DurationSeconds: 60},
+	}
+	assert.Equal(t, expected, copied)
+	assert.Zero(t, history[1].DurationSeconds)
+}
@@ -1,7 +1,13 @@
 package publicip
 
+import "github.com/qdm12/gluetun/internal/models"
+
 type Logger interface {
 	Info(s string)
 	Warn(s string)
 	Error(s string)
 }
+
+type Notifier interface {
+	Notify(event models.NotificationEvent, message string, fields map[string]string)
+}
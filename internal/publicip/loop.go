@@ -20,8 +20,12 @@ type Loop struct {
 	ipData        models.PublicIP
 	ipDataMutex   sync.RWMutex
 	fetcher       *api.ResilientFetcher
+	history       []models.PublicIPHistoryEntry
+	serverName    string
+	historyMutex  sync.RWMutex
 	// Fixed injected objects
 	httpClient *http.Client
+	notifier   Notifier
 	logger     Logger
 	// Fixed parameters
 	puid int
@@ -41,17 +45,24 @@ type Loop struct {
 }
 
 func NewLoop(settings settings.PublicIP, puid, pgid int,
-	httpClient *http.Client, logger Logger,
+	httpClient *http.Client, notifier Notifier, logger Logger,
 ) (loop *Loop, err error) {
 	fetchers, err := api.New(makeNameTokenPairs(settings.APIs), httpClient)
 	if err != nil {
 		return nil, fmt.Errorf("creating fetchers: %w", err)
 	}
 
+	history, err := loadHistory(*settings.HistoryFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("loading public IP history: %w", err)
+	}
+
 	return &Loop{
 		settings:   settings,
 		httpClient: httpClient,
 		fetcher:    api.NewResilient(fetchers, logger),
+		history:    history,
+		notifier:   notifier,
 		logger:     logger,
 		puid:       puid,
 		pgid:       pgid,
@@ -135,6 +146,15 @@ func (l *Loop) run(runCtx context.Context, runDone chan<- struct{},
 		l.ipData = result
 		l.ipDataMutex.Unlock()
 
+		previousIP, err := l.recordHistory(result)
+		if err != nil {
+			l.logger.Warn("persisting public IP history: " + err.Error())
+		}
+		if previousIP != result.IP {
+			l.notifier.Notify(models.NotificationPublicIPChanged, message,
+				l.makeIPChangeFields(previousIP, result))
+		}
+
 		filepath := *l.settings.IPFilepath
 		err = persistPublicIP(filepath, result.IP.String(), l.puid, l.pgid)
 		if err != nil {
@@ -183,6 +203,10 @@ func (l *Loop) UpdateWith(partialUpdate settings.PublicIP) (err error) {
 func (l *Loop) Stop() (err error) {
 	l.runCancel()
 	<-l.runDone
+	err = l.fetcher.Close()
+	if err != nil {
+		l.logger.Warn("closing fetchers: " + err.Error())
+	}
 	return l.ClearData()
 }
 
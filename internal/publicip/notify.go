@@ -0,0 +1,35 @@
+package publicip
+
+import (
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// makeIPChangeFields returns the notification fields for a public IP
+// change, omitting the fields with an empty value.
+func (l *Loop) makeIPChangeFields(previousIP netip.Addr,
+	data models.PublicIP,
+) (fields map[string]string) {
+	l.historyMutex.RLock()
+	serverName := l.serverName
+	l.historyMutex.RUnlock()
+
+	fields = map[string]string{"public_ip": data.IP.String()}
+	if previousIP.IsValid() {
+		fields["previous_public_ip"] = previousIP.String()
+	}
+	optionalFields := map[string]string{
+		"server_name":  serverName,
+		"country":      data.Country,
+		"region":       data.Region,
+		"city":         data.City,
+		"organization": data.Organization,
+	}
+	for key, value := range optionalFields {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	return fields
+}
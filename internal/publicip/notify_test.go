@@ -0,0 +1,31 @@
+package publicip
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Loop_makeIPChangeFields(t *testing.T) {
+	t.Parallel()
+
+	loop := &Loop{serverName: "se-sto-001"}
+	data := models.PublicIP{
+		IP:      netip.AddrFrom4([4]byte{1, 2, 3, 4}),
+		Country: "Sweden",
+	}
+
+	fields := loop.makeIPChangeFields(netip.Addr{}, data)
+	expected := map[string]string{
+		"public_ip":   "1.2.3.4",
+		"server_name": "se-sto-001",
+		"country":     "Sweden",
+	}
+	assert.Equal(t, expected, fields)
+
+	fields = loop.makeIPChangeFields(netip.AddrFrom4([4]byte{5, 6, 7, 8}), data)
+	expected["previous_public_ip"] = "5.6.7.8"
+	assert.Equal(t, expected, fields)
+}
@@ -1,6 +1,7 @@
 package publicip
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -39,6 +40,12 @@ func (l *Loop) update(partialUpdate settings.PublicIP) (err error) {
 		}
 	}
 
+	err = l.updateHistory(*l.settings.HistoryFilepath, *updatedSettings.HistoryFilepath,
+		*updatedSettings.HistoryMaxEntries)
+	if err != nil {
+		return fmt.Errorf("updating history: %w", err)
+	}
+
 	if !reflect.DeepEqual(l.settings.APIs, updatedSettings.APIs) {
 		newFetchers, err := api.New(makeNameTokenPairs(updatedSettings.APIs), l.httpClient)
 		if err != nil {
@@ -52,3 +59,19 @@ func (l *Loop) update(partialUpdate settings.PublicIP) (err error) {
 
 	return nil
 }
+
+func (l *Loop) updateHistory(oldFilepath, newFilepath string, maxEntries uint) (err error) {
+	l.historyMutex.Lock()
+	defer l.historyMutex.Unlock()
+
+	l.history = trimHistory(l.history, maxEntries)
+
+	if oldFilepath != "" && oldFilepath != newFilepath {
+		err = os.Remove(oldFilepath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing history file: %w", err)
+		}
+	}
+
+	return persistHistory(newFilepath, l.history, l.puid, l.pgid)
+}
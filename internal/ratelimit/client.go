@@ -0,0 +1,58 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/netip"
+	"strconv"
+
+	"github.com/qdm12/gluetun/internal/httpserver"
+)
+
+// Client identifies a client to rate limit and lock out.
+// Clients connected over TCP are identified by their IP address.
+// Clients connected through the Unix socket have no IP address,
+// and are identified by the user ID of their process instead, since
+// the process ID changes with each process started by the same user.
+type Client struct {
+	// IP is the IP address of a client connected over TCP.
+	IP netip.Addr
+	// UID is the user ID of the process of a client
+	// connected through the Unix socket.
+	UID uint32
+	// Unix is true if the client is connected
+	// through the Unix socket.
+	Unix bool
+}
+
+// ClientFromRequest returns the client of the request given.
+func ClientFromRequest(request *http.Request) (client Client) {
+	credentials, ok := httpserver.GetPeerCredentials(request.Context())
+	if ok {
+		return Client{UID: credentials.UID, Unix: true}
+	}
+	return Client{IP: ClientIP(request)}
+}
+
+func (c Client) String() string {
+	if c.Unix {
+		return "Unix socket client with user ID " + strconv.FormatUint(uint64(c.UID), 10)
+	}
+	return c.IP.String()
+}
+
+func (c Client) normalize() Client {
+	c.IP = c.IP.Unmap()
+	return c
+}
+
+// ClientIP returns the IP address of the client of the request given,
+// or the zero address if it cannot be parsed, which is the case for
+// requests made through a Unix socket. Proxy headers such as
+// X-Forwarded-For are deliberately ignored since clients can set them.
+func ClientIP(request *http.Request) (ip netip.Addr) {
+	addrPort, err := netip.ParseAddrPort(request.RemoteAddr)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return addrPort.Addr().Unmap()
+}
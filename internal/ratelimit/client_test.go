@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/httpserver"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ClientFromRequest(t *testing.T) {
+	t.Parallel()
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.RemoteAddr = "[::ffff:1.2.3.4]:5678"
+
+	client := ClientFromRequest(request)
+
+	assert.Equal(t, Client{IP: netip.MustParseAddr("1.2.3.4")}, client)
+	assert.Equal(t, "1.2.3.4", client.String())
+
+	request = request.WithContext(httpserver.WithPeerCredentials(request.Context(),
+		httpserver.PeerCredentials{PID: 100, UID: 1000, GID: 1000}))
+	request.RemoteAddr = "@"
+
+	client = ClientFromRequest(request)
+
+	assert.Equal(t, Client{UID: 1000, Unix: true}, client)
+	assert.Equal(t, "Unix socket client with user ID 1000", client.String())
+}
+
+func Test_ClientIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		remoteAddr string
+		ip         netip.Addr
+	}{
+		"ipv4": {
+			remoteAddr: "1.2.3.4:5678",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+		},
+		"ipv4_mapped_ipv6": {
+			remoteAddr: "[::ffff:1.2.3.4]:5678",
+			ip:         netip.MustParseAddr("1.2.3.4"),
+		},
+		"ipv6": {
+			remoteAddr: "[::1]:5678",
+			ip:         netip.MustParseAddr("::1"),
+		},
+		"malformed": {
+			remoteAddr: "malformed",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = testCase.remoteAddr
+
+			ip := ClientIP(request)
+
+			assert.Equal(t, testCase.ip, ip)
+		})
+	}
+}
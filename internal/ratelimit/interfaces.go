@@ -0,0 +1,5 @@
+package ratelimit
+
+type Warner interface {
+	Warn(message string)
+}
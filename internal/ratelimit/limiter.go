@@ -0,0 +1,234 @@
+// Package ratelimit implements per client rate limiting and
+// lockout of clients failing to authenticate repeatedly.
+package ratelimit
+
+import (
+	"cmp"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// Limiter limits the request rate of each client, and locks out
+// clients failing to authenticate too many times in a row, for a
+// duration growing exponentially with each lockout.
+// It is safe for concurrent use.
+type Limiter struct {
+	settings Settings
+	logger   Warner
+	timeNow  func() time.Time
+
+	mutex     sync.Mutex
+	clients   map[Client]*client
+	lastPrune time.Time
+}
+
+type client struct {
+	// tokens is the number of requests the client can make
+	// without being rate limited, as of lastSeen.
+	tokens   float64
+	lastSeen time.Time
+	// failures is the number of consecutive failed authentications
+	// since the last lockout or successful authentication.
+	failures uint
+	// lockouts is the number of consecutive lockouts, used to
+	// compute the duration of the next lockout.
+	lockouts    uint
+	lockedUntil time.Time
+}
+
+// New creates a new limiter using the settings given, which must be valid.
+func New(settings Settings, logger Warner) *Limiter {
+	return &Limiter{
+		settings: settings,
+		logger:   logger,
+		timeNow:  time.Now,
+		clients:  make(map[Client]*client),
+	}
+}
+
+// Allow returns true if a request from the client given
+// is allowed. Otherwise, it returns false and the duration after which
+// the client can retry, because the client is either locked out or
+// exceeding its request rate.
+func (l *Limiter) Allow(client Client) (allowed bool, retryAfter time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.timeNow()
+	l.prune(now)
+	c := l.getClient(client, now)
+	if now.Before(c.lockedUntil) {
+		return false, c.lockedUntil.Sub(now)
+	}
+
+	if l.settings.RequestsPerSecond == 0 {
+		return true, 0
+	}
+	elapsed := now.Sub(c.lastSeen).Seconds()
+	c.tokens = math.Min(float64(l.settings.Burst), c.tokens+elapsed*l.settings.RequestsPerSecond)
+	c.lastSeen = now
+	if c.tokens < 1 {
+		missingSeconds := (1 - c.tokens) / l.settings.RequestsPerSecond
+		return false, time.Duration(missingSeconds * float64(time.Second))
+	}
+	c.tokens--
+	return true, 0
+}
+
+// LockedOut returns the remaining lockout duration of the client
+// given, or 0 if the client is not locked out.
+// Contrary to [Limiter.Allow], it does not count as a request
+// for rate limiting.
+func (l *Limiter) LockedOut(client Client) (remaining time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.timeNow()
+	l.prune(now)
+	c, ok := l.clients[client.normalize()]
+	if !ok {
+		return 0
+	}
+	if !now.Before(c.lockedUntil) {
+		return 0
+	}
+	return c.lockedUntil.Sub(now)
+}
+
+// Fail records a failed authentication for the client
+// given, locking it out if it reaches the maximum number of
+// consecutive failures.
+func (l *Limiter) Fail(client Client) {
+	if l.settings.MaxFailures == 0 {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.timeNow()
+	l.prune(now)
+	c := l.getClient(client, now)
+	c.lastSeen = now
+	c.failures++
+	if c.failures < l.settings.MaxFailures {
+		return
+	}
+
+	lockout := l.settings.Lockout
+	for range c.lockouts {
+		lockout *= 2
+		if lockout >= l.settings.MaxLockout {
+			lockout = l.settings.MaxLockout
+			break
+		}
+	}
+	c.lockedUntil = now.Add(lockout)
+	c.lockouts++
+	l.logger.Warn("locking out " + client.normalize().String() + " for " + lockout.String() +
+		" after " + strconv.FormatUint(uint64(c.failures), 10) + " failed authentications")
+	c.failures = 0
+}
+
+// Succeed records a successful authentication for the client
+// given, resetting its failures and lockouts.
+func (l *Limiter) Succeed(client Client) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	c, ok := l.clients[client.normalize()]
+	if !ok {
+		return
+	}
+	c.failures = 0
+	c.lockouts = 0
+}
+
+// Lockouts returns the clients currently locked out, sorted by
+// IP address first and by Unix socket peer user ID second.
+func (l *Limiter) Lockouts() (lockouts []models.AuthLockout) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.timeNow()
+	lockouts = make([]models.AuthLockout, 0)
+	for client, c := range l.clients {
+		if !now.Before(c.lockedUntil) {
+			continue
+		}
+		lockout := models.AuthLockout{
+			IP:       client.IP,
+			Until:    c.lockedUntil,
+			Lockouts: c.lockouts,
+		}
+		if client.Unix {
+			lockout.UID = &client.UID
+		}
+		lockouts = append(lockouts, lockout)
+	}
+	slices.SortFunc(lockouts, func(a, b models.AuthLockout) int {
+		uid := func(lockout models.AuthLockout) uint32 {
+			if lockout.UID == nil {
+				return 0
+			}
+			return *lockout.UID
+		}
+		return cmp.Or(a.IP.Compare(b.IP), cmp.Compare(uid(a), uid(b)))
+	})
+	return lockouts
+}
+
+func (l *Limiter) getClient(key Client, now time.Time) *client {
+	key = key.normalize()
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{
+			tokens:   float64(l.settings.Burst),
+			lastSeen: now,
+		}
+		l.clients[key] = c
+	}
+	return c
+}
+
+// prune removes clients which are not locked out and have not been
+// seen for long enough that keeping them makes no difference,
+// to bound the memory used. It runs at most once per minute, and is
+// called by [Limiter.Allow], [Limiter.LockedOut] and [Limiter.Fail]
+// so users of the limiter only calling the latter two are pruned too.
+func (l *Limiter) prune(now time.Time) {
+	const pruneInterval = time.Minute
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+
+	// A client not seen for the maximum lockout duration has its
+	// lockouts count forgotten, and a client not seen for the burst
+	// refill duration has all its tokens back.
+	expiry := l.settings.MaxLockout
+	if l.settings.RequestsPerSecond > 0 {
+		refill := time.Duration(float64(l.settings.Burst) / l.settings.RequestsPerSecond * float64(time.Second))
+		expiry = max(expiry, refill)
+	}
+	for key, c := range l.clients {
+		if now.Before(c.lockedUntil) || now.Sub(c.lastSeen) < expiry {
+			continue
+		}
+		delete(l.clients, key)
+	}
+}
+
+// Reject writes a 429 Too Many Requests response with a Retry-After
+// header set from the duration given.
+func Reject(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int64(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
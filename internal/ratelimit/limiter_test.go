@@ -0,0 +1,203 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type noopWarner struct{}
+
+func (noopWarner) Warn(string) {}
+
+func newTestLimiter(settings Settings) (limiter *Limiter, now *time.Time) {
+	now = new(time.Time)
+	*now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter = New(settings, noopWarner{})
+	limiter.timeNow = func() time.Time { return *now }
+	return limiter, now
+}
+
+func Test_Limiter_Allow(t *testing.T) {
+	t.Parallel()
+
+	limiter, now := newTestLimiter(Settings{
+		RequestsPerSecond: 2,
+		Burst:             3,
+	})
+	ip := Client{IP: netip.MustParseAddr("1.2.3.4")}
+	otherIP := Client{IP: netip.MustParseAddr("::ffff:5.6.7.8")}
+
+	for range 3 {
+		allowed, _ := limiter.Allow(ip)
+		assert.True(t, allowed)
+	}
+	allowed, retryAfter := limiter.Allow(ip)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	allowed, _ = limiter.Allow(otherIP)
+	assert.True(t, allowed)
+
+	*now = now.Add(500 * time.Millisecond)
+	allowed, _ = limiter.Allow(ip)
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow(ip)
+	assert.False(t, allowed)
+}
+
+func Test_Limiter_Fail(t *testing.T) {
+	t.Parallel()
+
+	limiter, now := newTestLimiter(Settings{
+		MaxFailures: 2,
+		Lockout:     time.Minute,
+		MaxLockout:  3 * time.Minute,
+	})
+	ip := Client{IP: netip.MustParseAddr("1.2.3.4")}
+	start := *now
+
+	limiter.Fail(ip)
+	assert.Zero(t, limiter.LockedOut(ip))
+	limiter.Fail(ip)
+	assert.Equal(t, time.Minute, limiter.LockedOut(ip))
+	allowed, retryAfter := limiter.Allow(ip)
+	assert.False(t, allowed)
+	assert.Equal(t, time.Minute, retryAfter)
+	assert.Equal(t, []models.AuthLockout{
+		{IP: ip.IP, Until: start.Add(time.Minute), Lockouts: 1},
+	}, limiter.Lockouts())
+
+	*now = now.Add(time.Minute)
+	allowed, _ = limiter.Allow(ip)
+	assert.True(t, allowed)
+	assert.Empty(t, limiter.Lockouts())
+
+	// Second lockout lasts twice as long
+	limiter.Fail(ip)
+	limiter.Fail(ip)
+	assert.Equal(t, 2*time.Minute, limiter.LockedOut(ip))
+
+	// Third lockout is capped to the maximum lockout duration
+	*now = now.Add(2 * time.Minute)
+	limiter.Fail(ip)
+	limiter.Fail(ip)
+	assert.Equal(t, 3*time.Minute, limiter.LockedOut(ip))
+
+	// A successful authentication resets the lockouts count
+	*now = now.Add(3 * time.Minute)
+	limiter.Succeed(ip)
+	limiter.Fail(ip)
+	limiter.Fail(ip)
+	assert.Equal(t, time.Minute, limiter.LockedOut(ip))
+}
+
+func Test_Limiter_prune(t *testing.T) {
+	t.Parallel()
+
+	limiter, now := newTestLimiter(Settings{
+		RequestsPerSecond: 1,
+		Burst:             1,
+		MaxFailures:       1,
+		Lockout:           time.Minute,
+		MaxLockout:        time.Hour,
+	})
+	lockedIP := Client{IP: netip.MustParseAddr("1.2.3.4")}
+	idleIP := Client{IP: netip.MustParseAddr("5.6.7.8")}
+
+	limiter.Allow(idleIP)
+	*now = now.Add(30 * time.Minute)
+	limiter.Fail(lockedIP)
+
+	// The idle client was not seen for more than the maximum lockout
+	// duration, whereas the locked out client still has its lockouts
+	// count kept to lengthen its next lockout.
+	*now = now.Add(31 * time.Minute)
+	limiter.Allow(Client{IP: netip.MustParseAddr("9.9.9.9")})
+
+	assert.NotContains(t, limiter.clients, idleIP)
+	assert.Contains(t, limiter.clients, lockedIP)
+}
+
+func Test_Limiter_pruneWithoutAllow(t *testing.T) {
+	t.Parallel()
+
+	// Settings used by the HTTP proxy, which only calls
+	// LockedOut, Fail and Succeed on the limiter.
+	limiter, now := newTestLimiter(Settings{
+		MaxFailures: 3,
+		Lockout:     time.Minute,
+		MaxLockout:  time.Hour,
+	})
+	staleIPs := []Client{
+		{IP: netip.MustParseAddr("1.2.3.4")},
+		{IP: netip.MustParseAddr("5.6.7.8")},
+	}
+	for _, ip := range staleIPs {
+		limiter.Fail(ip)
+	}
+	assert.Len(t, limiter.clients, len(staleIPs))
+
+	*now = now.Add(time.Hour)
+	otherIP := Client{IP: netip.MustParseAddr("9.9.9.9")}
+	limiter.LockedOut(otherIP)
+	assert.Empty(t, limiter.clients)
+
+	limiter.Fail(otherIP)
+	*now = now.Add(time.Hour)
+	limiter.Fail(staleIPs[0])
+	assert.NotContains(t, limiter.clients, otherIP)
+	assert.Contains(t, limiter.clients, staleIPs[0])
+}
+
+func Test_Limiter_unixClients(t *testing.T) {
+	t.Parallel()
+
+	limiter, now := newTestLimiter(Settings{
+		RequestsPerSecond: 1,
+		Burst:             1,
+		MaxFailures:       1,
+		Lockout:           time.Minute,
+		MaxLockout:        time.Hour,
+	})
+	rootClient := Client{Unix: true}
+	userClient := Client{UID: 1000, Unix: true}
+	ipClient := Client{IP: netip.MustParseAddr("1.2.3.4")}
+
+	// Unix socket clients of different users do not share
+	// their rate limit, nor with a TCP client.
+	allowed, _ := limiter.Allow(rootClient)
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow(userClient)
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow(ipClient)
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow(userClient)
+	assert.False(t, allowed)
+
+	limiter.Fail(userClient)
+	limiter.Fail(ipClient)
+	assert.Zero(t, limiter.LockedOut(rootClient))
+	assert.Equal(t, time.Minute, limiter.LockedOut(userClient))
+	uid := uint32(1000)
+	assert.Equal(t, []models.AuthLockout{
+		{UID: &uid, Until: now.Add(time.Minute), Lockouts: 1},
+		{IP: ipClient.IP, Until: now.Add(time.Minute), Lockouts: 1},
+	}, limiter.Lockouts())
+}
+
+func Test_Reject(t *testing.T) {
+	t.Parallel()
+
+	recorder := httptest.NewRecorder()
+
+	Reject(recorder, 1500*time.Millisecond)
+
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+}
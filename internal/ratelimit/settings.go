@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Settings contains the settings of a [Limiter].
+type Settings struct {
+	// RequestsPerSecond is the rate of requests allowed per client IP
+	// address. A value of 0 disables rate limiting.
+	RequestsPerSecond float64
+	// Burst is the number of requests a client IP address can make
+	// at once before being rate limited. It must be at least 1 if
+	// RequestsPerSecond is not zero.
+	Burst uint
+	// MaxFailures is the number of consecutive failed authentications
+	// after which a client IP address is locked out. A value of 0
+	// disables the lockout.
+	MaxFailures uint
+	// Lockout is the duration of the first lockout of a client IP
+	// address. It doubles for each following lockout of the same
+	// client IP address, up to MaxLockout.
+	Lockout time.Duration
+	// MaxLockout is the maximum duration of a lockout.
+	MaxLockout time.Duration
+}
+
+// DefaultSettings returns the default settings.
+func DefaultSettings() Settings {
+	const (
+		requestsPerSecond = 10
+		burst             = 50
+		maxFailures       = 5
+		lockout           = time.Minute
+		maxLockout        = time.Hour
+	)
+	return Settings{
+		RequestsPerSecond: requestsPerSecond,
+		Burst:             burst,
+		MaxFailures:       maxFailures,
+		Lockout:           lockout,
+		MaxLockout:        maxLockout,
+	}
+}
+
+var (
+	ErrRequestsPerSecondNegative = errors.New("requests per second cannot be negative")
+	ErrBurstZero                 = errors.New("burst cannot be zero")
+	ErrLockoutZero               = errors.New("lockout duration cannot be zero")
+	ErrMaxLockoutTooSmall        = errors.New("maximum lockout duration is smaller than the lockout duration")
+)
+
+func (s Settings) Validate() (err error) {
+	if s.RequestsPerSecond < 0 {
+		return fmt.Errorf("%w: %g", ErrRequestsPerSecondNegative, s.RequestsPerSecond)
+	}
+	if s.RequestsPerSecond > 0 && s.Burst == 0 {
+		return fmt.Errorf("%w: when requests per second is set", ErrBurstZero)
+	}
+	if s.MaxFailures > 0 {
+		if s.Lockout <= 0 {
+			return fmt.Errorf("%w: when maximum failures is set", ErrLockoutZero)
+		}
+		if s.MaxLockout < s.Lockout {
+			return fmt.Errorf("%w: %s is smaller than %s",
+				ErrMaxLockoutTooSmall, s.MaxLockout, s.Lockout)
+		}
+	}
+	return nil
+}
@@ -51,5 +51,12 @@ func (r *Routing) TearDown() error {
 		return fmt.Errorf("setting outbound subnets routes: %w", err)
 	}
 
+	r.stateMutex.Lock()
+	err = r.unrouteTransparentProxy()
+	r.stateMutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("removing transparent proxy routing: %w", err)
+	}
+
 	return nil
 }
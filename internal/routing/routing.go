@@ -44,10 +44,11 @@ type Linker interface {
 }
 
 type Routing struct {
-	netLinker       NetLinker
-	logger          Logger
-	outboundSubnets []netip.Prefix
-	stateMutex      sync.RWMutex
+	netLinker        NetLinker
+	logger           Logger
+	outboundSubnets  []netip.Prefix
+	transparentProxy transparentProxyRouting
+	stateMutex       sync.RWMutex
 }
 
 // New creates a new routing instance.
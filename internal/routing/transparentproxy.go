@@ -0,0 +1,143 @@
+package routing
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/qdm12/gluetun/internal/netlink"
+)
+
+const (
+	transparentProxyTable uint32 = 201
+	// transparentProxyPriority is lower than the local (98) and outbound (99)
+	// priorities so marked packets are delivered locally whatever their
+	// destination is.
+	transparentProxyPriority uint32 = 97
+)
+
+type transparentProxyRouting struct {
+	mark uint32
+	ipv6 bool
+}
+
+// RouteTransparentProxyLocally routes packets marked with the mark given
+// to the loopback interface, so packets intercepted with the iptables TPROXY
+// target are delivered locally to the transparent proxy, while keeping their
+// original destination. IPv6 packets are only routed if ipv6 is true.
+// Any previous transparent proxy routing is replaced, and it is removed
+// by [Routing.TearDown].
+func (r *Routing) RouteTransparentProxyLocally(mark uint32, ipv6 bool) (err error) {
+	r.stateMutex.Lock()
+	defer r.stateMutex.Unlock()
+
+	newRouting := transparentProxyRouting{mark: mark, ipv6: ipv6}
+	if r.transparentProxy == newRouting {
+		return nil
+	}
+
+	err = r.unrouteTransparentProxy()
+	if err != nil {
+		return fmt.Errorf("removing outdated transparent proxy routing: %w", err)
+	}
+
+	r.transparentProxy = newRouting
+	families := []uint8{netlink.FamilyV4}
+	if ipv6 {
+		families = append(families, netlink.FamilyV6)
+	}
+	for _, family := range families {
+		err = r.routeMarkLocally(mark, family)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Routing) routeMarkLocally(mark uint32, family uint8) (err error) {
+	loopback, err := r.netLinker.LinkByName("lo")
+	if err != nil {
+		return fmt.Errorf("finding loopback interface: %w", err)
+	}
+
+	route := netlink.Route{
+		Dst:       defaultDestination(family),
+		LinkIndex: loopback.Index,
+		Family:    family,
+		Table:     transparentProxyTable,
+		Type:      netlink.RouteTypeLocal,
+		Scope:     netlink.ScopeHost,
+		Proto:     netlink.ProtoStatic,
+	}
+	err = r.netLinker.RouteReplace(route)
+	if err != nil {
+		return fmt.Errorf("replacing local route for %s in table %d: %w",
+			route.Dst, transparentProxyTable, err)
+	}
+
+	priority := transparentProxyPriority
+	rule := netlink.Rule{
+		Priority: &priority,
+		Family:   family,
+		Table:    transparentProxyTable,
+		Mark:     &mark,
+		Action:   netlink.ActionToTable,
+	}
+	err = r.netLinker.RuleAdd(rule)
+	if err != nil {
+		return fmt.Errorf("adding %s: %w", rule, err)
+	}
+
+	return nil
+}
+
+// unrouteTransparentProxy removes the current transparent proxy
+// routing, if any. It must be called with the state mutex locked.
+func (r *Routing) unrouteTransparentProxy() (err error) {
+	if r.transparentProxy.mark == 0 {
+		return nil
+	}
+
+	families := []uint8{netlink.FamilyV4}
+	if r.transparentProxy.ipv6 {
+		families = append(families, netlink.FamilyV6)
+	}
+	for _, family := range families {
+		priority := transparentProxyPriority
+		rule := netlink.Rule{
+			Priority: &priority,
+			Family:   family,
+			Table:    transparentProxyTable,
+			Mark:     &r.transparentProxy.mark,
+			Action:   netlink.ActionToTable,
+		}
+		err = r.netLinker.RuleDel(rule)
+		if err != nil {
+			return fmt.Errorf("deleting rule %s: %w", rule, err)
+		}
+
+		route := netlink.Route{
+			Dst:    defaultDestination(family),
+			Family: family,
+			Table:  transparentProxyTable,
+			Type:   netlink.RouteTypeLocal,
+		}
+		err = r.netLinker.RouteDel(route)
+		if err != nil {
+			return fmt.Errorf("deleting local route for %s in table %d: %w",
+				route.Dst, transparentProxyTable, err)
+		}
+	}
+	r.transparentProxy = transparentProxyRouting{}
+
+	return nil
+}
+
+func defaultDestination(family uint8) netip.Prefix {
+	const bits = 0
+	if family == netlink.FamilyV6 {
+		return netip.PrefixFrom(netip.IPv6Unspecified(), bits)
+	}
+	return netip.PrefixFrom(netip.IPv4Unspecified(), bits)
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func newAuditHandler(auditLog AuditLog, warner warner) http.Handler {
+	return &auditHandler{
+		auditLog: auditLog,
+		warner:   warner,
+	}
+}
+
+type auditHandler struct {
+	auditLog AuditLog
+	warner   warner
+}
+
+func (h *auditHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getEntries(w)
+	default:
+		errMethodNotSupported(w, r.Method)
+	}
+}
+
+func (h *auditHandler) getEntries(w http.ResponseWriter) {
+	entries, err := h.auditLog.Entries()
+	if err != nil {
+		h.warner.Warn("reading audit log: " + err.Error())
+		http.Error(w, "reading audit log", http.StatusInternalServerError)
+		return
+	}
+
+	data := auditWrapper{Entries: entries}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+func newAuthHandler(lockouts AuthLockouts, warner warner) http.Handler {
+	return &authHandler{
+		lockouts: lockouts,
+		warner:   warner,
+	}
+}
+
+type authHandler struct {
+	lockouts AuthLockouts
+	warner   warner
+}
+
+func (h *authHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.RequestURI = strings.TrimPrefix(r.RequestURI, "/auth")
+	switch r.RequestURI {
+	case "/lockouts":
+		switch r.Method {
+		case http.MethodGet:
+			h.getLockouts(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	default:
+		errRouteNotSupported(w, r.RequestURI)
+	}
+}
+
+func (h *authHandler) getLockouts(w http.ResponseWriter) {
+	data := lockoutsWrapper{Lockouts: h.lockouts.Lockouts()}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
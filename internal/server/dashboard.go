@@ -0,0 +1,39 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed dashboard/index.html
+var dashboardHTML []byte
+
+func newDashboardHandler(enabled bool, warner warner) http.Handler {
+	return &dashboardHandler{
+		enabled: enabled,
+		warner:  warner,
+	}
+}
+
+type dashboardHandler struct {
+	enabled bool
+	warner  warner
+}
+
+func (h *dashboardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.enabled {
+		http.Error(w, "dashboard is disabled", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		if _, err := w.Write(dashboardHTML); err != nil {
+			h.warner.Warn(err.Error())
+		}
+	default:
+		errMethodNotSupported(w, r.Method)
+	}
+}
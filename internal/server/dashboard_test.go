@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_dashboardHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		enabled     bool
+		method      string
+		status      int
+		contentType string
+	}{
+		"disabled": {
+			method:      http.MethodGet,
+			status:      http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8",
+		},
+		"enabled": {
+			enabled:     true,
+			method:      http.MethodGet,
+			status:      http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+		},
+		"method_not_supported": {
+			enabled:     true,
+			method:      http.MethodPost,
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := newDashboardHandler(testCase.enabled, noopLogger{})
+			request := httptest.NewRequest(testCase.method, "/", nil)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.contentType, recorder.Header().Get("Content-Type"))
+		})
+	}
+}
@@ -35,6 +35,13 @@ func (h *dnsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			errMethodNotSupported(w, r.Method)
 		}
+	case "/leak":
+		switch r.Method {
+		case http.MethodGet:
+			h.getLeakReport(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
 	default:
 		errRouteNotSupported(w, r.RequestURI)
 	}
@@ -75,3 +82,13 @@ func (h *dnsHandler) setStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+func (h *dnsHandler) getLeakReport(w http.ResponseWriter) {
+	report := h.loop.GetLeakReport()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(report); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
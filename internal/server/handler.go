@@ -7,12 +7,14 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/models"
-	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
+	"github.com/qdm12/gluetun/internal/ratelimit"
+	auditmiddleware "github.com/qdm12/gluetun/internal/server/middlewares/audit"
+	authmiddleware "github.com/qdm12/gluetun/internal/server/middlewares/auth"
 	"github.com/qdm12/gluetun/internal/server/middlewares/log"
 )
 
 func newHandler(ctx context.Context, logger Logger, logging bool,
-	authSettings auth.Settings,
+	authSettings authmiddleware.Settings,
 	buildInfo models.BuildInformation,
 	vpnLooper VPNLooper,
 	pf PortForwarding,
@@ -20,26 +22,41 @@ func newHandler(ctx context.Context, logger Logger, logging bool,
 	updaterLooper UpdaterLooper,
 	publicIPLooper PublicIPLoop,
 	storage Storage,
-	ipv6Supported bool,
+	healthServer HealthServer,
+	logBuffer LogBuffer,
+	auditLog AuditLog,
+	ipv6Supported, dashboard bool,
 ) (httpHandler http.Handler, err error) {
 	handler := &handler{}
 
 	vpn := newVPNHandler(ctx, vpnLooper, storage, ipv6Supported, logger)
 	openvpn := newOpenvpnHandler(ctx, vpnLooper, logger)
+	wireguard := newWireguardHandler(vpnLooper, logger)
 	dns := newDNSHandler(ctx, dnsLooper, logger)
-	updater := newUpdaterHandler(ctx, updaterLooper, logger)
+	updater := newUpdaterHandler(ctx, updaterLooper, storage, logger)
 	publicip := newPublicIPHandler(publicIPLooper, logger)
 	portForward := newPortForwardHandler(ctx, pf, logger)
+	health := newHealthHandler(healthServer, logger)
+	logs := newLogsHandler(logBuffer, logger)
+	limiter := ratelimit.New(authSettings.RateLimit.LimiterSettings(), logger)
+	auth := newAuthHandler(limiter, logger)
+	audit := newAuditHandler(auditLog, logger)
+
+	handler.dashboard = newDashboardHandler(dashboard, logger)
 
 	handler.v0 = newHandlerV0(ctx, logger, vpnLooper, dnsLooper, updaterLooper)
-	handler.v1 = newHandlerV1(logger, buildInfo, vpn, openvpn, dns, updater, publicip, portForward)
+	handler.v1 = newHandlerV1(logger, buildInfo, vpn, openvpn, wireguard,
+		dns, updater, publicip, portForward, health, logs, auth, audit)
 
-	authMiddleware, err := auth.New(authSettings, logger)
+	authMiddleware, err := authmiddleware.New(authSettings, limiter, logger)
 	if err != nil {
 		return nil, fmt.Errorf("creating auth middleware: %w", err)
 	}
 
+	// The audit middleware must be after the authentication
+	// middleware to know the role authorized for the request.
 	middlewares := []func(http.Handler) http.Handler{
+		auditmiddleware.New(auditLog, logger),
 		authMiddleware,
 		log.New(logger, logging),
 	}
@@ -51,12 +68,17 @@ func newHandler(ctx context.Context, logger Logger, logging bool,
 }
 
 type handler struct {
-	v0 http.Handler
-	v1 http.Handler
+	dashboard http.Handler
+	v0        http.Handler
+	v1        http.Handler
 }
 
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r.RequestURI = strings.TrimSuffix(r.RequestURI, "/")
+	if r.RequestURI == "" {
+		h.dashboard.ServeHTTP(w, r)
+		return
+	}
 	if !strings.HasPrefix(r.RequestURI, "/v1/") && r.RequestURI != "/v1" {
 		h.v0.ServeHTTP(w, r)
 		return
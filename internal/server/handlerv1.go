@@ -10,39 +10,56 @@ import (
 )
 
 func newHandlerV1(w warner, buildInfo models.BuildInformation,
-	vpn, openvpn, dns, updater, publicip, portForward http.Handler,
+	vpn, openvpn, wireguard, dns, updater, publicip, portForward,
+	health, logs, auth, audit http.Handler,
 ) http.Handler {
 	return &handlerV1{
 		warner:      w,
 		buildInfo:   buildInfo,
+		openAPI:     buildOpenAPI(buildInfo.Version),
 		vpn:         vpn,
 		openvpn:     openvpn,
+		wireguard:   wireguard,
 		dns:         dns,
 		updater:     updater,
 		publicip:    publicip,
 		portForward: portForward,
+		health:      health,
+		logs:        logs,
+		auth:        auth,
+		audit:       audit,
 	}
 }
 
 type handlerV1 struct {
 	warner      warner
 	buildInfo   models.BuildInformation
+	openAPI     map[string]any
 	vpn         http.Handler
 	openvpn     http.Handler
+	wireguard   http.Handler
 	dns         http.Handler
 	updater     http.Handler
 	publicip    http.Handler
 	portForward http.Handler
+	health      http.Handler
+	logs        http.Handler
+	auth        http.Handler
+	audit       http.Handler
 }
 
 func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case r.RequestURI == "/version" && r.Method == http.MethodGet:
 		h.getVersion(w)
+	case r.RequestURI == "/openapi.json" && r.Method == http.MethodGet:
+		h.getOpenAPI(w)
 	case strings.HasPrefix(r.RequestURI, "/vpn"):
 		h.vpn.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/openvpn"):
 		h.openvpn.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/wireguard"):
+		h.wireguard.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/dns"):
 		h.dns.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/updater"):
@@ -51,6 +68,14 @@ func (h *handlerV1) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.publicip.ServeHTTP(w, r)
 	case strings.HasPrefix(r.RequestURI, "/portforward"):
 		h.portForward.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/health"):
+		h.health.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/logs"):
+		h.logs.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/auth"):
+		h.auth.ServeHTTP(w, r)
+	case strings.HasPrefix(r.RequestURI, "/audit"):
+		h.audit.ServeHTTP(w, r)
 	default:
 		errString := fmt.Sprintf("%s %s not found", r.Method, r.RequestURI)
 		http.Error(w, errString, http.StatusBadRequest)
@@ -64,3 +89,12 @@ func (h *handlerV1) getVersion(w http.ResponseWriter) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 }
+
+func (h *handlerV1) getOpenAPI(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(h.openAPI); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
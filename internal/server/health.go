@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func newHealthHandler(healthServer HealthServer, warner warner) http.Handler {
+	return &healthHandler{
+		healthServer: healthServer,
+		warner:       warner,
+	}
+}
+
+type healthHandler struct {
+	healthServer HealthServer
+	warner       warner
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getHealth(w)
+	default:
+		errMethodNotSupported(w, r.Method)
+	}
+}
+
+func (h *healthHandler) getHealth(w http.ResponseWriter) {
+	data := healthWrapper{Healthy: true}
+	if err := h.healthServer.GetError(); err != nil {
+		data.Healthy = false
+		data.Error = err.Error()
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
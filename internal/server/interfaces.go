@@ -13,23 +13,50 @@ type VPNLooper interface {
 		outcome string, err error)
 	GetSettings() (settings settings.VPN)
 	SetSettings(ctx context.Context, settings settings.VPN) (outcome string)
+	GetWireguardStatus() (status models.WireguardStatus, err error)
+	GetOpenVPNStatus() (status models.OpenVPNStatus, err error)
+	Rotate(ctx context.Context) (outcome string, err error)
+	GetConnection() (connection models.Connection)
+	GetMTUDiscovery() (discovery models.MTUDiscovery, err error)
+	DiscoverMTU(ctx context.Context) (discovery models.MTUDiscovery, err error)
 }
 
 type DNSLoop interface {
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
 	GetStatus() (status models.LoopStatus)
+	GetLeakReport() (report models.DNSLeakReport)
 }
 
 type PortForwarding interface {
 	GetPortsForwarded() (ports []uint16)
 	SetPortsForwarded(ports []uint16) (err error)
+	GetTorrentClientStatus() (status *models.TorrentClientStatus)
 }
 
 type PublicIPLoop interface {
 	GetData() (data models.PublicIP)
+	GetHistory() (history []models.PublicIPHistoryEntry)
+}
+
+type HealthServer interface {
+	GetError() (err error)
+}
+
+type AuthLockouts interface {
+	Lockouts() (lockouts []models.AuthLockout)
+}
+
+type AuditLog interface {
+	Append(entry models.AuditEntry) (err error)
+	Entries() (entries []models.AuditEntry, err error)
+}
+
+type LogBuffer interface {
+	Lines() (lines []string)
 }
 
 type Storage interface {
 	GetFilterChoices(provider string) models.FilterChoices
+	GetUpdateReport() (report models.ServersUpdateReport)
 }
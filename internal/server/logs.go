@@ -0,0 +1,36 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func newLogsHandler(logBuffer LogBuffer, warner warner) http.Handler {
+	return &logsHandler{
+		logBuffer: logBuffer,
+		warner:    warner,
+	}
+}
+
+type logsHandler struct {
+	logBuffer LogBuffer
+	warner    warner
+}
+
+func (h *logsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getLogs(w)
+	default:
+		errMethodNotSupported(w, r.Method)
+	}
+}
+
+func (h *logsHandler) getLogs(w http.ResponseWriter) {
+	data := logsWrapper{Lines: h.logBuffer.Lines()}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(data); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
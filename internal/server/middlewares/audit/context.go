@@ -0,0 +1,20 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+type recordKey struct{}
+
+// SetChanges sets the changes made by the request of the context
+// given, to be recorded in its audit entry. It does nothing if the
+// request is not audited.
+func SetChanges(ctx context.Context, changes []models.SettingChange) {
+	entry, ok := ctx.Value(recordKey{}).(*models.AuditEntry)
+	if !ok {
+		return
+	}
+	entry.Changes = changes
+}
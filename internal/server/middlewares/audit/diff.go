@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// Diff returns the changes between the JSON encodings of the previous
+// and current values given, typically settings, with secret values
+// redacted. Nested objects are compared field by field, whereas other
+// values such as arrays are compared as a whole.
+func Diff(previous, current any) (changes []models.SettingChange) {
+	previousFields := flattenJSON(previous)
+	currentFields := flattenJSON(current)
+
+	paths := slices.Collect(maps.Keys(previousFields))
+	for path := range currentFields {
+		if _, ok := previousFields[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		previousValue, currentValue := previousFields[path], currentFields[path]
+		if reflect.DeepEqual(previousValue, currentValue) {
+			continue
+		}
+		if isSecret(lastPathElement(path)) {
+			previousValue, currentValue = redactedOrNil(previousValue), redactedOrNil(currentValue)
+		}
+		changes = append(changes, models.SettingChange{
+			Path:     path,
+			Previous: previousValue,
+			Current:  currentValue,
+		})
+	}
+	return changes
+}
+
+func flattenJSON(value any) (fields map[string]any) {
+	fields = make(map[string]any)
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fields
+	}
+	var decoded any
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		return fields
+	}
+	flattenValue("", decoded, fields)
+	return fields
+}
+
+func flattenValue(path string, value any, fields map[string]any) {
+	object, ok := value.(map[string]any)
+	if !ok {
+		fields[path] = value
+		return
+	}
+	for name, fieldValue := range object {
+		fieldPath := name
+		if path != "" {
+			fieldPath = path + "." + name
+		}
+		flattenValue(fieldPath, fieldValue, fields)
+	}
+}
+
+func lastPathElement(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '.' {
+			return path[i+1:]
+		}
+	}
+	return path
+}
+
+func redactedOrNil(value any) any {
+	if value == nil {
+		return nil
+	}
+	return redacted
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diff(t *testing.T) {
+	t.Parallel()
+
+	type nested struct {
+		Countries  []string `json:"countries"`
+		PrivateKey *string  `json:"private_key"`
+	}
+	type settings struct {
+		Name     string `json:"name"`
+		Password string `json:"password"`
+		Nested   nested `json:"nested"`
+	}
+
+	previousKey, currentKey := "abc", "def"
+	previous := settings{
+		Name:     "a",
+		Password: "secret",
+		Nested: nested{
+			Countries: []string{"France"},
+		},
+	}
+	current := settings{
+		Name:     "a",
+		Password: "other secret",
+		Nested: nested{
+			Countries:  []string{"France", "Spain"},
+			PrivateKey: &currentKey,
+		},
+	}
+
+	changes := Diff(previous, current)
+
+	expected := []models.SettingChange{
+		{Path: "nested.countries", Previous: []any{"France"}, Current: []any{"France", "Spain"}},
+		{Path: "nested.private_key", Previous: nil, Current: "[redacted]"},
+		{Path: "password", Previous: "[redacted]", Current: "[redacted]"},
+	}
+	assert.Equal(t, expected, changes)
+
+	previous.Nested.PrivateKey = &previousKey
+	previous.Password = current.Password
+	previous.Nested.Countries = current.Nested.Countries
+	changes = Diff(previous, current)
+	expected = []models.SettingChange{
+		{Path: "nested.private_key", Previous: "[redacted]", Current: "[redacted]"},
+	}
+	assert.Equal(t, expected, changes)
+}
+
+func Test_redactJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data     string
+		redacted string
+	}{
+		"empty": {},
+		"not_json": {
+			data: "not json",
+		},
+		"not_object": {
+			data: `["password"]`,
+		},
+		"nested_secrets": {
+			data:     `{"openvpn":{"user":"me","password":"secret","key":null},"list":[{"api_key":"x"}]}`,
+			redacted: `{"list":[{"api_key":"[redacted]"}],"openvpn":{"key":null,"password":"[redacted]","user":"me"}}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			redacted := redactJSON([]byte(testCase.data))
+
+			assert.Equal(t, testCase.redacted, string(redacted))
+		})
+	}
+}
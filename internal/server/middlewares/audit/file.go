@@ -0,0 +1,146 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// File is an audit log file of JSON entries, one per line.
+// Once the file exceeds its maximum size, it is renamed with
+// the .1 suffix, replacing any previous such file, and a new
+// file is started. It is safe for concurrent use.
+type File struct {
+	path    string
+	maxSize int64
+	mutex   sync.Mutex
+}
+
+// NewFile creates an audit log file at the path given.
+// If the path is empty, entries are discarded.
+func NewFile(path string) *File {
+	const maxSize = 1024 * 1024
+	return &File{
+		path:    path,
+		maxSize: maxSize,
+	}
+}
+
+// Append appends the entry given to the file,
+// rotating the file first if needed.
+func (f *File) Append(entry models.AuditEntry) (err error) {
+	if f.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding entry: %w", err)
+	}
+	data = append(data, '\n')
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	err = f.rotate(int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("rotating file: %w", err)
+	}
+
+	const dirPerms, perms = os.FileMode(0o755), os.FileMode(0o600)
+	err = os.MkdirAll(filepath.Dir(f.path), dirPerms)
+	if err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, perms)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+
+	_, err = file.Write(data)
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("writing entry: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("closing file: %w", err)
+	}
+	return nil
+}
+
+func (f *File) rotate(appendSize int64) (err error) {
+	stat, err := os.Stat(f.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil
+	case err != nil:
+		return err
+	case stat.Size()+appendSize <= f.maxSize:
+		return nil
+	}
+	return os.Rename(f.path, f.rotatedPath())
+}
+
+func (f *File) rotatedPath() string {
+	return f.path + ".1"
+}
+
+// Entries returns the entries of the rotated file and of the
+// current file, from the oldest to the newest. Malformed lines,
+// for example a line partially written, are skipped.
+func (f *File) Entries() (entries []models.AuditEntry, err error) {
+	entries = make([]models.AuditEntry, 0)
+	if f.path == "" {
+		return entries, nil
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, path := range []string{f.rotatedPath(), f.path} {
+		entries, err = readEntries(path, entries)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	return entries, nil
+}
+
+func readEntries(path string, entries []models.AuditEntry) (
+	updatedEntries []models.AuditEntry, err error,
+) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entries, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	const maxLineSize = 1024 * 1024
+	scanner.Buffer(nil, maxLineSize)
+	for scanner.Scan() {
+		var entry models.AuditEntry
+		err = json.Unmarshal(scanner.Bytes(), &entry)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_File(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "subdirectory", "audit.log")
+	file := NewFile(path)
+	file.maxSize = 200
+
+	entries, err := file.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	makeEntry := func(i int) models.AuditEntry {
+		return models.AuditEntry{
+			Time:       time.Date(2024, 1, 1, 0, 0, i, 0, time.UTC),
+			Role:       "admin",
+			Method:     "PUT",
+			Path:       "/v1/vpn/status",
+			StatusCode: 200,
+		}
+	}
+
+	const count = 5
+	for i := range count {
+		err = file.Append(makeEntry(i))
+		require.NoError(t, err)
+	}
+
+	_, err = os.Stat(path + ".1")
+	require.NoError(t, err, "file should have been rotated")
+
+	entries, err = file.Entries()
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Less(t, len(entries), count, "oldest entries should be dropped")
+	for i, entry := range entries {
+		expected := makeEntry(count - len(entries) + i)
+		expected.ClientIP = entry.ClientIP
+		assert.Equal(t, expected, entry)
+	}
+}
+
+func Test_File_disabled(t *testing.T) {
+	t.Parallel()
+
+	file := NewFile("")
+
+	err := file.Append(models.AuditEntry{})
+	require.NoError(t, err)
+
+	entries, err := file.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
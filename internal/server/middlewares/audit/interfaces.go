@@ -0,0 +1,11 @@
+package audit
+
+import "github.com/qdm12/gluetun/internal/models"
+
+type Warner interface {
+	Warn(message string)
+}
+
+type Appender interface {
+	Append(entry models.AuditEntry) (err error)
+}
@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/httpserver"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/ratelimit"
+	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
+)
+
+// New returns a middleware recording state changing requests to the
+// appender given. It must be placed after the authentication middleware
+// so the role name authorized for the request is known.
+func New(appender Appender, warner Warner) (
+	middleware func(http.Handler) http.Handler,
+) {
+	return func(handler http.Handler) http.Handler {
+		return &auditMiddleware{
+			childHandler: handler,
+			appender:     appender,
+			warner:       warner,
+			timeNow:      time.Now,
+		}
+	}
+}
+
+type auditMiddleware struct {
+	childHandler http.Handler
+	appender     Appender
+	warner       Warner
+	timeNow      func() time.Time
+}
+
+// legacyActionPaths are unversioned routes changing state
+// despite using the GET method.
+var legacyActionPaths = map[string]struct{}{ //nolint:gochecknoglobals
+	"/openvpn/actions/restart": {},
+	"/unbound/actions/restart": {},
+	"/updater/restart":         {},
+}
+
+func isStateChanging(request *http.Request) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		_, ok := legacyActionPaths[request.URL.Path]
+		return ok
+	default:
+		return true
+	}
+}
+
+func (m *auditMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isStateChanging(r) {
+		m.childHandler.ServeHTTP(w, r)
+		return
+	}
+
+	entry := &models.AuditEntry{
+		Time:     m.timeNow(),
+		Role:     auth.RoleName(r.Context()),
+		ClientIP: ratelimit.ClientIP(r),
+		Method:   r.Method,
+		Path:     r.URL.Path,
+	}
+	credentials, ok := httpserver.GetPeerCredentials(r.Context())
+	if ok {
+		entry.ClientUID = &credentials.UID
+		entry.ClientPID = &credentials.PID
+	}
+
+	if r.Body != nil {
+		const maxBodySize = 64 * 1024
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+		if err != nil {
+			m.warner.Warn("reading request body for audit: " + err.Error())
+		}
+		entry.Request = redactJSON(body)
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	}
+
+	r = r.WithContext(context.WithValue(r.Context(), recordKey{}, entry))
+	statusWriter := &statusResponseWriter{httpWriter: w}
+	m.childHandler.ServeHTTP(statusWriter, r)
+	entry.StatusCode = statusWriter.statusCode
+	if entry.StatusCode == 0 {
+		entry.StatusCode = http.StatusOK
+	}
+
+	err := m.appender.Append(*entry)
+	if err != nil {
+		m.warner.Warn("writing audit entry: " + err.Error())
+	}
+}
+
+type statusResponseWriter struct {
+	httpWriter http.ResponseWriter
+	statusCode int
+}
+
+func (w *statusResponseWriter) Write(b []byte) (n int, err error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	return w.httpWriter.Write(b)
+}
+
+func (w *statusResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.httpWriter.WriteHeader(statusCode)
+}
+
+func (w *statusResponseWriter) Header() http.Header {
+	return w.httpWriter.Header()
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/httpserver"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type entriesRecorder struct {
+	entries []models.AuditEntry
+}
+
+func (r *entriesRecorder) Append(entry models.AuditEntry) error {
+	r.entries = append(r.entries, entry)
+	return nil
+}
+
+type noopWarner struct{}
+
+func (noopWarner) Warn(string) {}
+
+func Test_auditMiddleware(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder := &entriesRecorder{}
+	childHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		if string(body) == "" {
+			return
+		}
+		assert.JSONEq(t, `{"status":"stopped","password":"secret"}`, string(body))
+		SetChanges(r.Context(), []models.SettingChange{{Path: "status"}})
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := New(recorder, noopWarner{})(childHandler)
+	handler.(*auditMiddleware).timeNow = func() time.Time { return now }
+
+	serve := func(method, path, body string) {
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.RemoteAddr = "1.2.3.4:5678"
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	serve(http.MethodGet, "/v1/vpn/status", "")
+	serve(http.MethodPut, "/v1/vpn/status", `{"status":"stopped","password":"secret"}`)
+	serve(http.MethodGet, "/updater/restart", "")
+
+	// Request through the Unix socket, which has no client IP address
+	request := httptest.NewRequest(http.MethodGet, "/updater/restart", nil)
+	request.RemoteAddr = "@"
+	request = request.WithContext(httpserver.WithPeerCredentials(request.Context(),
+		httpserver.PeerCredentials{PID: 100, UID: 1000, GID: 1000}))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	uid, pid := uint32(1000), int32(100)
+
+	expected := []models.AuditEntry{{
+		Time:       now,
+		ClientIP:   netip.MustParseAddr("1.2.3.4"),
+		Method:     http.MethodPut,
+		Path:       "/v1/vpn/status",
+		Request:    []byte(`{"password":"[redacted]","status":"stopped"}`),
+		StatusCode: http.StatusAccepted,
+		Changes:    []models.SettingChange{{Path: "status"}},
+	}, {
+		Time:       now,
+		ClientIP:   netip.MustParseAddr("1.2.3.4"),
+		Method:     http.MethodGet,
+		Path:       "/updater/restart",
+		StatusCode: http.StatusOK,
+	}, {
+		Time:       now,
+		ClientUID:  &uid,
+		ClientPID:  &pid,
+		Method:     http.MethodGet,
+		Path:       "/updater/restart",
+		StatusCode: http.StatusOK,
+	}}
+	assert.Equal(t, expected, recorder.entries)
+}
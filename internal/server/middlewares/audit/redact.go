@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const redacted = "[redacted]"
+
+// isSecret returns true if the JSON field name given is
+// considered to hold a secret value, such as "password",
+// "private_key" or "key_passphrase".
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, secretWord := range [...]string{"password", "key", "secret", "token"} {
+		if strings.Contains(name, secretWord) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJSON returns the JSON data given with the values of secret
+// fields replaced, or nil if the data is not a JSON object.
+func redactJSON(data []byte) json.RawMessage {
+	var object map[string]any
+	err := json.Unmarshal(data, &object)
+	if err != nil {
+		return nil
+	}
+	redactObject(object)
+	data, err = json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func redactObject(object map[string]any) {
+	for name, value := range object {
+		if isSecret(name) && value != nil {
+			object[name] = redacted
+			continue
+		}
+		redactValue(value)
+	}
+}
+
+func redactValue(value any) {
+	switch typed := value.(type) {
+	case map[string]any:
+		redactObject(typed)
+	case []any:
+		for _, element := range typed {
+			redactValue(element)
+		}
+	}
+}
@@ -54,6 +54,20 @@ routes = ["GET /v1/vpn/status"]
 				}},
 			},
 		},
+		"rate_limit": {
+			fileContent: `[rate_limit]
+requests_per_second = 0.5
+max_failures = 3
+lockout_seconds = 30
+`,
+			settings: Settings{
+				RateLimit: RateLimit{
+					RequestsPerSecond: ptrTo(0.5),
+					MaxFailures:       ptrTo(uint(3)),
+					LockoutSeconds:    ptrTo(uint(30)),
+				},
+			},
+		},
 	}
 
 	for name, testCase := range testCases {
@@ -0,0 +1,16 @@
+package auth
+
+import "context"
+
+type roleNameKey struct{}
+
+// RoleName returns the name of the role authorized for the request
+// of the context given, or the empty string if there is none.
+func RoleName(ctx context.Context) (name string) {
+	name, _ = ctx.Value(roleNameKey{}).(string)
+	return name
+}
+
+func withRoleName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, roleNameKey{}, name)
+}
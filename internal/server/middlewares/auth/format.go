@@ -1,5 +1,7 @@
 package auth
 
+import "strconv"
+
 func andStrings(strings []string) (result string) {
 	return joinStrings(strings, "and")
 }
@@ -20,3 +22,11 @@ func joinStrings(strings []string, lastJoin string) (result string) {
 
 	return result
 }
+
+func joinUint32s(values []uint32) (result string) {
+	strings := make([]string, len(values))
+	for i, value := range values {
+		strings[i] = strconv.FormatUint(uint64(value), 10)
+	}
+	return joinStrings(strings, "and")
+}
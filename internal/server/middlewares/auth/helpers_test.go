@@ -0,0 +1,7 @@
+package auth
+
+func ptrTo[T any](v T) *T { return &v }
+
+type noopWarner struct{}
+
+func (noopWarner) Warn(string) {}
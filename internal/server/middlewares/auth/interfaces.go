@@ -1,6 +1,18 @@
 package auth
 
+import (
+	"time"
+
+	"github.com/qdm12/gluetun/internal/ratelimit"
+)
+
 type DebugLogger interface {
 	Debugf(format string, args ...any)
 	Warnf(format string, args ...any)
 }
+
+type Limiter interface {
+	Allow(client ratelimit.Client) (allowed bool, retryAfter time.Duration)
+	Fail(client ratelimit.Client)
+	Succeed(client ratelimit.Client)
+}
@@ -20,6 +20,8 @@ func settingsToLookupMap(settings Settings) (routeToRoles map[string][]internalR
 			checker = newAPIKeyMethod(role.APIKey)
 		case AuthBasic:
 			checker = newBasicAuthMethod(role.Username, role.Password)
+		case AuthPeerCred:
+			checker = newPeerCredMethod(role.UIDs, role.GIDs)
 		default:
 			return nil, fmt.Errorf("authentication method not supported: %s", role.Auth)
 		}
@@ -40,6 +40,21 @@ func Test_settingsToLookupMap(t *testing.T) {
 				},
 			},
 		},
+		"peercred": {
+			settings: Settings{
+				Roles: []Role{
+					{Name: "a", Auth: AuthPeerCred, UIDs: []uint32{1000}, Routes: []string{"GET /path"}},
+					{Name: "b", Auth: AuthPeerCred, UIDs: []uint32{1000}, Routes: []string{"GET /path"}},
+					{Name: "c", Auth: AuthPeerCred, GIDs: []uint32{1000}, Routes: []string{"GET /path"}},
+				},
+			},
+			routeToRoles: map[string][]internalRole{
+				"GET /path": {
+					{name: "a", checker: newPeerCredMethod([]uint32{1000}, nil)},
+					{name: "c", checker: newPeerCredMethod(nil, []uint32{1000})},
+				},
+			},
+		},
 	}
 
 	for name, testCase := range testCases {
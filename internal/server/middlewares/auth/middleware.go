@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"net/http"
 	"slices"
+
+	"github.com/qdm12/gluetun/internal/ratelimit"
 )
 
-func New(settings Settings, debugLogger DebugLogger) (
+func New(settings Settings, limiter Limiter, debugLogger DebugLogger) (
 	middleware func(http.Handler) http.Handler,
 	err error,
 ) {
@@ -19,6 +21,7 @@ func New(settings Settings, debugLogger DebugLogger) (
 		return &authHandler{
 			childHandler: handler,
 			routeToRoles: routeToRoles,
+			limiter:      limiter,
 			logger:       debugLogger,
 		}
 	}, nil
@@ -27,10 +30,19 @@ func New(settings Settings, debugLogger DebugLogger) (
 type authHandler struct {
 	childHandler http.Handler
 	routeToRoles map[string][]internalRole
+	limiter      Limiter
 	logger       DebugLogger
 }
 
 func (h *authHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	client := ratelimit.ClientFromRequest(request)
+	allowed, retryAfter := h.limiter.Allow(client)
+	if !allowed {
+		h.logger.Debugf("request from %s rejected for %s", client, retryAfter)
+		ratelimit.Reject(writer, retryAfter)
+		return
+	}
+
 	methods, ok := validRoutes[request.URL.Path]
 	if !ok {
 		h.logger.Debugf("url path %s is not a valid route", request.URL.Path)
@@ -57,6 +69,10 @@ func (h *authHandler) ServeHTTP(writer http.ResponseWriter, request *http.Reques
 		}
 
 		h.logger.Debugf("access to route %s authorized for role %s", route, role.name)
+		if hasCredentials(request) {
+			h.limiter.Succeed(client)
+		}
+		request = request.WithContext(withRoleName(request.Context(), role.name))
 		h.childHandler.ServeHTTP(writer, request)
 		return
 	}
@@ -74,5 +90,19 @@ func (h *authHandler) ServeHTTP(writer http.ResponseWriter, request *http.Reques
 	}
 	h.logger.Debugf("access to route %s unauthorized after checking for roles %s",
 		route, andStrings(allRoleNames))
+	// Requests without credentials are not counted as failures, since
+	// they are not guesses and are made for example by web browsers
+	// before prompting for basic authentication credentials.
+	if hasCredentials(request) {
+		h.limiter.Fail(client)
+	}
 	http.Error(writer, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 }
+
+// hasCredentials returns true if the request carries basic
+// authentication credentials or an API key.
+func hasCredentials(request *http.Request) bool {
+	return request.Header.Get("Authorization") != "" ||
+		request.Header.Get("X-API-Key") != "" ||
+		request.URL.Query().Get("api_key") != ""
+}
@@ -7,8 +7,10 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/ratelimit"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -67,7 +69,8 @@ func Test_authHandler_ServeHTTP(t *testing.T) {
 			if testCase.makeLogger != nil {
 				debugLogger = testCase.makeLogger(ctrl)
 			}
-			middleware, err := New(testCase.settings, debugLogger)
+			limiter := ratelimit.New(ratelimit.DefaultSettings(), noopWarner{})
+			middleware, err := New(testCase.settings, limiter, debugLogger)
 			require.NoError(t, err)
 
 			childHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -100,3 +103,53 @@ func Test_authHandler_ServeHTTP(t *testing.T) {
 		})
 	}
 }
+
+func Test_authHandler_lockout(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	settings := Settings{
+		Roles: []Role{
+			{Name: "role1", Auth: AuthAPIKey, APIKey: "xyz", Routes: []string{"GET /v1/vpn/status"}},
+		},
+	}
+	limiter := ratelimit.New(ratelimit.Settings{
+		MaxFailures: 2,
+		Lockout:     time.Minute,
+		MaxLockout:  time.Minute,
+	}, noopWarner{})
+	logger := NewMockDebugLogger(ctrl)
+	logger.EXPECT().Debugf(gomock.Any(), gomock.Any()).AnyTimes()
+	middleware, err := New(settings, limiter, logger)
+	require.NoError(t, err)
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(apiKey string) (recorder *httptest.ResponseRecorder) {
+		request := httptest.NewRequest(http.MethodGet, "/v1/vpn/status", nil)
+		request.RemoteAddr = "1.2.3.4:5678"
+		if apiKey != "" {
+			request.Header.Set("X-API-Key", apiKey)
+		}
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Requests without credentials do not count as failures
+	for range 3 {
+		recorder := serve("")
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+
+	recorder := serve("wrong")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	recorder = serve("wrong")
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// Locked out, even with the right API key
+	recorder = serve("xyz")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "60", recorder.Header().Get("Retry-After"))
+}
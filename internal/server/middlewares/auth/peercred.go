@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/qdm12/gluetun/internal/httpserver"
+)
+
+type peerCredMethod struct {
+	uids []uint32
+	gids []uint32
+}
+
+func newPeerCredMethod(uids, gids []uint32) *peerCredMethod {
+	return &peerCredMethod{
+		uids: slices.Clone(uids),
+		gids: slices.Clone(gids),
+	}
+}
+
+// equal returns true if another auth checker is equal.
+// This is used to deduplicate checkers for a particular route.
+func (p *peerCredMethod) equal(other authorizationChecker) bool {
+	otherPeerCredMethod, ok := other.(*peerCredMethod)
+	if !ok {
+		return false
+	}
+	return slices.Equal(p.uids, otherPeerCredMethod.uids) &&
+		slices.Equal(p.gids, otherPeerCredMethod.gids)
+}
+
+// isAuthorized returns true if the request was made through the
+// control server Unix socket by a process running with one of the
+// user IDs or group IDs of the method.
+func (p *peerCredMethod) isAuthorized(_ http.Header, request *http.Request) bool {
+	credentials, ok := httpserver.GetPeerCredentials(request.Context())
+	if !ok {
+		return false
+	}
+	return slices.Contains(p.uids, credentials.UID) ||
+		slices.Contains(p.gids, credentials.GID)
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/ratelimit"
+	"github.com/qdm12/gosettings"
+)
+
+// RateLimit contains the rate limiting and lockout settings
+// of the control server, applied per client IP address.
+type RateLimit struct {
+	// RequestsPerSecond is the rate of requests allowed per client
+	// IP address. It defaults to 10 and 0 disables rate limiting.
+	RequestsPerSecond *float64 `toml:"requests_per_second"`
+	// Burst is the number of requests a client IP address can make
+	// at once before being rate limited. It defaults to 50.
+	Burst *uint `toml:"burst"`
+	// MaxFailures is the number of consecutive failed authentications
+	// after which a client IP address is locked out. It defaults to 5
+	// and 0 disables the lockout.
+	MaxFailures *uint `toml:"max_failures"`
+	// LockoutSeconds is the duration in seconds of the first lockout,
+	// doubling with each following lockout. It defaults to 60.
+	LockoutSeconds *uint `toml:"lockout_seconds"`
+	// MaxLockoutSeconds is the maximum duration in seconds of a
+	// lockout. It defaults to 3600.
+	MaxLockoutSeconds *uint `toml:"max_lockout_seconds"`
+}
+
+func (r *RateLimit) setDefaults() {
+	defaults := ratelimit.DefaultSettings()
+	r.RequestsPerSecond = gosettings.DefaultPointer(r.RequestsPerSecond, defaults.RequestsPerSecond)
+	r.Burst = gosettings.DefaultPointer(r.Burst, defaults.Burst)
+	r.MaxFailures = gosettings.DefaultPointer(r.MaxFailures, defaults.MaxFailures)
+	r.LockoutSeconds = gosettings.DefaultPointer(r.LockoutSeconds, uint(defaults.Lockout/time.Second))
+	r.MaxLockoutSeconds = gosettings.DefaultPointer(r.MaxLockoutSeconds, uint(defaults.MaxLockout/time.Second))
+}
+
+func (r RateLimit) validate() (err error) {
+	err = r.LimiterSettings().Validate()
+	if err != nil {
+		return fmt.Errorf("rate limit: %w", err)
+	}
+	return nil
+}
+
+// LimiterSettings returns the settings for a [ratelimit.Limiter].
+// It must be called after [Settings.SetDefaults].
+func (r RateLimit) LimiterSettings() ratelimit.Settings {
+	return ratelimit.Settings{
+		RequestsPerSecond: *r.RequestsPerSecond,
+		Burst:             *r.Burst,
+		MaxFailures:       *r.MaxFailures,
+		Lockout:           time.Duration(*r.LockoutSeconds) * time.Second,
+		MaxLockout:        time.Duration(*r.MaxLockoutSeconds) * time.Second,
+	}
+}
@@ -17,6 +17,8 @@ type Settings struct {
 	// Roles is a list of roles with their associated authentication
 	// and routes.
 	Roles []Role
+	// RateLimit contains the rate limiting and lockout settings.
+	RateLimit RateLimit `toml:"rate_limit"`
 }
 
 // SetDefaultRole sets a default role to apply to all routes without a
@@ -68,6 +70,10 @@ func (s *Settings) SetDefaultRole(jsonRole string) error {
 	return nil
 }
 
+func (s *Settings) SetDefaults() {
+	s.RateLimit.setDefaults()
+}
+
 func (s Settings) Validate() (err error) {
 	for i, role := range s.Roles {
 		err = role.Validate()
@@ -77,13 +83,19 @@ func (s Settings) Validate() (err error) {
 		}
 	}
 
+	err = s.RateLimit.validate()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 const (
-	AuthNone   = "none"
-	AuthAPIKey = "apikey"
-	AuthBasic  = "basic"
+	AuthNone     = "none"
+	AuthAPIKey   = "apikey"
+	AuthBasic    = "basic"
+	AuthPeerCred = "peercred"
 )
 
 // Role contains the role name, authentication method name and
@@ -92,7 +104,8 @@ type Role struct {
 	// Name is the role name and is only used for documentation
 	// and in the authentication middleware debug logs.
 	Name string `json:"name"`
-	// Auth is the authentication method to use, which can be 'none', 'basic' or 'apikey'.
+	// Auth is the authentication method to use, which can be 'none', 'basic',
+	// 'apikey' or 'peercred'.
 	Auth string `json:"auth"`
 	// APIKey is the API key to use when using the 'apikey' authentication.
 	APIKey string `json:"apikey"`
@@ -100,13 +113,20 @@ type Role struct {
 	Username string `json:"username"`
 	// Password for HTTP Basic authentication method.
 	Password string `json:"password"`
+	// UIDs are the user IDs of processes allowed for the 'peercred'
+	// authentication method, which only accepts requests made through
+	// the control server Unix socket.
+	UIDs []uint32 `json:"uids"`
+	// GIDs are the group IDs of processes allowed for the 'peercred'
+	// authentication method.
+	GIDs []uint32 `json:"gids"`
 	// Routes is a list of routes that the role can access in the format
 	// "HTTP_METHOD PATH", for example "GET /v1/vpn/status"
 	Routes []string `json:"-"`
 }
 
 func (r Role) Validate() (err error) {
-	err = validate.IsOneOf(r.Auth, AuthNone, AuthAPIKey, AuthBasic)
+	err = validate.IsOneOf(r.Auth, AuthNone, AuthAPIKey, AuthBasic, AuthPeerCred)
 	if err != nil {
 		return fmt.Errorf("authentication method not supported: %s", r.Auth)
 	}
@@ -118,6 +138,8 @@ func (r Role) Validate() (err error) {
 		return fmt.Errorf("for role %s: username is empty", r.Name)
 	case r.Auth == AuthBasic && r.Password == "":
 		return fmt.Errorf("for role %s: password is empty", r.Name)
+	case r.Auth == AuthPeerCred && len(r.UIDs) == 0 && len(r.GIDs) == 0:
+		return fmt.Errorf("for role %s: no user ID nor group ID is set", r.Name)
 	}
 
 	for i, route := range r.Routes {
@@ -140,21 +162,36 @@ func (r Role) Validate() (err error) {
 // validRoutes maps URL paths to allowed HTTP methods.
 // WARNING: do not mutate programmatically.
 var validRoutes = map[string][]string{ //nolint:gochecknoglobals
+	"/":                         {http.MethodGet},
 	"/openvpn/actions/restart":  {http.MethodGet},
 	"/openvpn/portforwarded":    {http.MethodGet},
 	"/openvpn/settings":         {http.MethodGet},
 	"/unbound/actions/restart":  {http.MethodGet},
 	"/updater/restart":          {http.MethodGet},
 	"/v1/version":               {http.MethodGet},
+	"/v1/openapi.json":          {http.MethodGet},
 	"/v1/vpn/status":            {http.MethodGet, http.MethodPut},
 	"/v1/vpn/settings":          {http.MethodGet, http.MethodPut},
+	"/v1/vpn/rotate":            {http.MethodPut},
+	"/v1/vpn/connection":        {http.MethodGet},
+	"/v1/vpn/choices":           {http.MethodGet},
+	"/v1/vpn/mtu":               {http.MethodGet},
+	"/v1/vpn/mtu/discover":      {http.MethodPut},
 	"/v1/openvpn/status":        {http.MethodGet, http.MethodPut},
 	"/v1/openvpn/portforwarded": {http.MethodGet},
 	"/v1/openvpn/settings":      {http.MethodGet},
+	"/v1/wireguard/status":      {http.MethodGet},
 	"/v1/dns/status":            {http.MethodGet, http.MethodPut},
+	"/v1/dns/leak":              {http.MethodGet},
 	"/v1/updater/status":        {http.MethodGet, http.MethodPut},
+	"/v1/updater/report":        {http.MethodGet},
 	"/v1/publicip/ip":           {http.MethodGet},
+	"/v1/publicip/history":      {http.MethodGet},
 	"/v1/portforward":           {http.MethodGet, http.MethodPut},
+	"/v1/health":                {http.MethodGet},
+	"/v1/logs":                  {http.MethodGet},
+	"/v1/auth/lockouts":         {http.MethodGet},
+	"/v1/audit":                 {http.MethodGet},
 }
 
 func countValidRoutes() (count int) {
@@ -174,6 +211,13 @@ func (r Role) ToLinesNode() (node *gotree.Node) {
 		node.Appendf("Password: %s", gosettings.ObfuscateKey(r.Password))
 	case AuthAPIKey:
 		node.Appendf("API key: %s", gosettings.ObfuscateKey(r.APIKey))
+	case AuthPeerCred:
+		if len(r.UIDs) > 0 {
+			node.Appendf("User IDs: %s", joinUint32s(r.UIDs))
+		}
+		if len(r.GIDs) > 0 {
+			node.Appendf("Group IDs: %s", joinUint32s(r.GIDs))
+		}
 	default:
 		panic("missing code for authentication method: " + r.Auth)
 	}
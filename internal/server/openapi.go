@@ -0,0 +1,310 @@
+package server
+
+import (
+	"encoding"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// apiOperation describes a /v1 control server route and method,
+// and is used to build the OpenAPI document.
+type apiOperation struct {
+	method  string
+	path    string
+	summary string
+	// request is a value of the JSON request body type,
+	// and is nil if the operation has no request body.
+	request any
+	// response is a value of the JSON response body type,
+	// and is nil if the response body is not JSON.
+	response any
+	// textResponse is set if the response body is plain text.
+	textResponse bool
+	// redirect is set if the operation redirects to another route.
+	redirect   bool
+	deprecated bool
+}
+
+func apiOperations() []apiOperation {
+	return []apiOperation{
+		{method: http.MethodGet, path: "/v1/version", summary: "Get the program build information",
+			response: models.BuildInformation{}},
+		{method: http.MethodGet, path: "/v1/openapi.json", summary: "Get this OpenAPI document",
+			response: map[string]any{}},
+		{method: http.MethodGet, path: "/v1/vpn/status", summary: "Get the VPN status",
+			response: statusWrapper{}},
+		{method: http.MethodPut, path: "/v1/vpn/status", summary: "Start or stop the VPN",
+			request: statusWrapper{}, response: outcomeWrapper{}},
+		{method: http.MethodGet, path: "/v1/vpn/settings", summary: "Get the VPN settings",
+			response: settings.VPN{}},
+		{method: http.MethodPut, path: "/v1/vpn/settings",
+			summary: "Update the VPN settings with the fields set in the request body",
+			request: settings.VPN{}, textResponse: true},
+		{method: http.MethodPut, path: "/v1/vpn/rotate", summary: "Reconnect the VPN to another server",
+			response: outcomeWrapper{}},
+		{method: http.MethodGet, path: "/v1/vpn/connection", summary: "Get the current VPN server connection",
+			response: models.Connection{}},
+		{method: http.MethodGet, path: "/v1/vpn/choices",
+			summary:  "Get the server selection filter choices for the current VPN provider",
+			response: models.FilterChoices{}},
+		{method: http.MethodGet, path: "/v1/vpn/mtu", summary: "Get the last path MTU discovery report",
+			response: models.MTUDiscovery{}},
+		{method: http.MethodPut, path: "/v1/vpn/mtu/discover",
+			summary:  "Run a path MTU discovery and update the VPN interface MTU",
+			response: models.MTUDiscovery{}},
+		{method: http.MethodGet, path: "/v1/openvpn/status", summary: "Get the OpenVPN status",
+			response: openvpnStatusWrapper{}},
+		{method: http.MethodPut, path: "/v1/openvpn/status", summary: "Start or stop OpenVPN",
+			request: statusWrapper{}, response: outcomeWrapper{}},
+		{method: http.MethodGet, path: "/v1/openvpn/settings", summary: "Get the OpenVPN settings",
+			response: settings.OpenVPN{}},
+		{method: http.MethodGet, path: "/v1/openvpn/portforwarded",
+			summary: "Redirects to /v1/portforward", redirect: true, deprecated: true},
+		{method: http.MethodGet, path: "/v1/wireguard/status", summary: "Get the Wireguard status",
+			response: models.WireguardStatus{}},
+		{method: http.MethodGet, path: "/v1/dns/status", summary: "Get the DNS status",
+			response: statusWrapper{}},
+		{method: http.MethodPut, path: "/v1/dns/status", summary: "Start or stop the DNS server",
+			request: statusWrapper{}, response: outcomeWrapper{}},
+		{method: http.MethodGet, path: "/v1/dns/leak", summary: "Get the last DNS leak test report",
+			response: models.DNSLeakReport{}},
+		{method: http.MethodGet, path: "/v1/updater/status", summary: "Get the servers updater status",
+			response: statusWrapper{}},
+		{method: http.MethodPut, path: "/v1/updater/status", summary: "Start or stop the servers updater",
+			request: statusWrapper{}, response: outcomeWrapper{}},
+		{method: http.MethodGet, path: "/v1/updater/report", summary: "Get the last servers update report",
+			response: models.ServersUpdateReport{}},
+		{method: http.MethodGet, path: "/v1/publicip/ip", summary: "Get the public IP address information",
+			response: models.PublicIP{}},
+		{method: http.MethodGet, path: "/v1/publicip/history", summary: "Get the public IP addresses history",
+			response: []models.PublicIPHistoryEntry{}},
+		{method: http.MethodGet, path: "/v1/portforward", summary: "Get the forwarded ports",
+			response: portsWrapper{}},
+		{method: http.MethodPut, path: "/v1/portforward", summary: "Set the forwarded ports",
+			request: portsWrapper{}},
+		{method: http.MethodGet, path: "/v1/health", summary: "Get the health state",
+			response: healthWrapper{}},
+		{method: http.MethodGet, path: "/v1/logs", summary: "Get the recent log lines",
+			response: logsWrapper{}},
+		{method: http.MethodGet, path: "/v1/auth/lockouts",
+			summary:  "Get the client IP addresses locked out after failing to authenticate",
+			response: lockoutsWrapper{}},
+		{method: http.MethodGet, path: "/v1/audit",
+			summary:  "Get the audit log of state changing requests, from the oldest to the newest",
+			response: auditWrapper{}},
+	}
+}
+
+// buildOpenAPI builds the OpenAPI 3 document describing the
+// /v1 routes of the control server.
+func buildOpenAPI(version string) (document map[string]any) {
+	schemas := make(map[string]any)
+	paths := make(map[string]any)
+	for _, operation := range apiOperations() {
+		pathItem, ok := paths[operation.path].(map[string]any)
+		if !ok {
+			pathItem = make(map[string]any)
+			paths[operation.path] = pathItem
+		}
+		pathItem[strings.ToLower(operation.method)] = operation.toOpenAPI(schemas)
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "Gluetun control server",
+			"version": version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"responses": map[string]any{
+				"Error": map[string]any{
+					"description": "Error message",
+					"content": map[string]any{
+						"text/plain": map[string]any{"schema": map[string]any{"type": "string"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (o apiOperation) toOpenAPI(schemas map[string]any) (operation map[string]any) {
+	errorResponse := map[string]any{"$ref": "#/components/responses/Error"}
+	responses := map[string]any{
+		"400": errorResponse,
+		"401": errorResponse,
+		"500": errorResponse,
+	}
+	switch {
+	case o.redirect:
+		responses["301"] = map[string]any{"description": "Moved permanently"}
+	case o.textResponse:
+		responses["200"] = map[string]any{
+			"description": "Success",
+			"content": map[string]any{
+				"text/plain": map[string]any{"schema": map[string]any{"type": "string"}},
+			},
+		}
+	case o.response != nil:
+		responses["200"] = map[string]any{
+			"description": "Success",
+			"content":     jsonContent(reflect.TypeOf(o.response), schemas),
+		}
+	default:
+		responses["200"] = map[string]any{"description": "Success"}
+	}
+
+	operation = map[string]any{
+		"summary":   o.summary,
+		"responses": responses,
+	}
+	if o.request != nil {
+		operation["requestBody"] = map[string]any{
+			"required": true,
+			"content":  jsonContent(reflect.TypeOf(o.request), schemas),
+		}
+	}
+	if o.deprecated {
+		operation["deprecated"] = true
+	}
+	return operation
+}
+
+func jsonContent(t reflect.Type, schemas map[string]any) map[string]any {
+	return map[string]any{
+		"application/json": map[string]any{"schema": schemaOf(t, schemas)},
+	}
+}
+
+//nolint:gochecknoglobals
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schemaOf returns the OpenAPI schema of the JSON encoding of the type
+// given. Named exported struct types are added to the schemas map given
+// and referenced from the schema returned.
+func schemaOf(t reflect.Type, schemas map[string]any) (schema map[string]any) {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == durationType:
+		return map[string]any{"type": "integer", "format": "int64", "description": "duration in nanoseconds"}
+	case t.Kind() != reflect.Pointer && t.Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema = schemaOf(t.Elem(), schemas)
+		if _, isRef := schema["$ref"]; isRef {
+			return map[string]any{"allOf": []any{schema}, "nullable": true}
+		}
+		schema["nullable"] = true
+		return schema
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+		if t.Name() == "" || !isExported(t.Name()) {
+			return structSchema(t, schemas)
+		}
+		name := t.String() // for example settings.VPN
+		ref := map[string]any{"$ref": "#/components/schemas/" + name}
+		if _, ok := schemas[name]; ok {
+			return ref
+		}
+		schemas[name] = nil // placeholder for recursive types
+		schemas[name] = structSchema(t, schemas)
+		return ref
+	default: // interface
+		return map[string]any{}
+	}
+}
+
+func structSchema(t reflect.Type, schemas map[string]any) (schema map[string]any) {
+	properties := make(map[string]any)
+	var required []string
+	const optional = false
+	addStructFields(t, schemas, properties, &required, optional)
+	schema = map[string]any{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		slices.Sort(required)
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addStructFields adds the JSON fields of the struct type given to the
+// properties map, flattening embedded structs without a JSON tag
+// like the encoding/json package does. Fields are never added to the
+// required slice if optional is true, which is the case for fields of
+// an embedded struct pointer.
+func addStructFields(t reflect.Type, schemas map[string]any,
+	properties map[string]any, required *[]string, optional bool,
+) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			embeddedOptional := optional
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+				embeddedOptional = true
+			}
+			if fieldType.Kind() == reflect.Struct {
+				addStructFields(fieldType, schemas, properties, required, embeddedOptional)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaOf(field.Type, schemas)
+		omitted := strings.Contains(options, "omitempty") || strings.Contains(options, "omitzero")
+		if !optional && !omitted && field.Type.Kind() != reflect.Pointer {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func isExported(name string) bool {
+	return name != "" && strings.ToUpper(name[:1]) == name[:1]
+}
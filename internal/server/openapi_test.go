@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_apiOperations_validRoutes fails if the OpenAPI operations
+// drift from the /v1 routes allowed by the auth middleware.
+func Test_apiOperations_validRoutes(t *testing.T) {
+	t.Parallel()
+
+	var authSettings auth.Settings
+	err := authSettings.SetDefaultRole(`{"name":"all","auth":"none"}`)
+	require.NoError(t, err)
+	require.Len(t, authSettings.Roles, 1)
+
+	var validV1Routes []string
+	for _, route := range authSettings.Roles[0].Routes {
+		_, path, _ := strings.Cut(route, " ")
+		if strings.HasPrefix(path, "/v1/") {
+			validV1Routes = append(validV1Routes, route)
+		}
+	}
+
+	operations := apiOperations()
+	operationRoutes := make([]string, len(operations))
+	for i, operation := range operations {
+		operationRoutes[i] = operation.method + " " + operation.path
+	}
+	slices.Sort(operationRoutes)
+
+	assert.Equal(t, validV1Routes, operationRoutes)
+}
+
+// Test_handler_apiOperations fails if an OpenAPI operation
+// is not handled by the control server handler.
+func Test_handler_apiOperations(t *testing.T) {
+	t.Parallel()
+
+	var authSettings auth.Settings
+	err := authSettings.SetDefaultRole(`{"name":"all","auth":"none"}`)
+	require.NoError(t, err)
+	// Disable rate limiting since all requests come from the same address.
+	requestsPerSecond := 0.0
+	authSettings.RateLimit.RequestsPerSecond = &requestsPerSecond
+	authSettings.SetDefaults()
+
+	loopers := stubLoopers{}
+	handler, err := newHandler(context.Background(), noopLogger{}, false, authSettings,
+		models.BuildInformation{}, loopers, loopers, loopers, stubUpdaterLooper{},
+		loopers, loopers, loopers, loopers, loopers, false, false)
+	require.NoError(t, err)
+
+	for _, operation := range apiOperations() {
+		t.Run(operation.method+" "+operation.path, func(t *testing.T) {
+			t.Parallel()
+
+			var body *strings.Reader
+			if operation.request != nil {
+				// Handlers fail decoding the body before calling any looper.
+				body = strings.NewReader("not json")
+			} else {
+				body = strings.NewReader("")
+			}
+			request := httptest.NewRequest(operation.method, operation.path, body)
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			response := recorder.Result()
+			defer response.Body.Close()
+			assert.NotContains(t, []int{http.StatusNotFound, http.StatusMethodNotAllowed},
+				response.StatusCode)
+			responseBody := recorder.Body.String()
+			assert.NotContains(t, responseBody, "not supported")
+			assert.NotContains(t, responseBody, "not found")
+		})
+	}
+}
+
+func Test_buildOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	document := buildOpenAPI("v1.2.3")
+
+	data, err := json.Marshal(document)
+	require.NoError(t, err)
+
+	// Check every schema reference has its schema defined
+	var decoded struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	err = json.Unmarshal(data, &decoded)
+	require.NoError(t, err)
+	const schemaRefPrefix = `"#/components/schemas/`
+	for _, part := range strings.Split(string(data), schemaRefPrefix)[1:] {
+		name, _, _ := strings.Cut(part, `"`)
+		assert.Contains(t, decoded.Components.Schemas, name)
+	}
+	assert.Contains(t, decoded.Components.Schemas, "settings.VPN")
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...any) {}
+func (noopLogger) Info(string)           {}
+func (noopLogger) Infof(string, ...any)  {}
+func (noopLogger) Warn(string)           {}
+func (noopLogger) Warnf(string, ...any)  {}
+func (noopLogger) Error(string)          {}
+
+// stubLoopers implements the VPNLooper, DNSLoop, PortForwarding,
+// PublicIPLoop, Storage, HealthServer, LogBuffer and AuditLog interfaces.
+type stubLoopers struct{}
+
+func (stubLoopers) GetStatus() models.LoopStatus { return constants.Stopped }
+func (stubLoopers) ApplyStatus(context.Context, models.LoopStatus) (string, error) {
+	return "", nil
+}
+func (stubLoopers) GetSettings() settings.VPN                        { return settings.VPN{} }
+func (stubLoopers) SetSettings(context.Context, settings.VPN) string { return "" }
+func (stubLoopers) GetWireguardStatus() (models.WireguardStatus, error) {
+	return models.WireguardStatus{}, nil
+}
+func (stubLoopers) GetOpenVPNStatus() (models.OpenVPNStatus, error) {
+	return models.OpenVPNStatus{}, nil
+}
+func (stubLoopers) GetMTUDiscovery() (models.MTUDiscovery, error) {
+	return models.MTUDiscovery{}, nil
+}
+func (stubLoopers) DiscoverMTU(context.Context) (models.MTUDiscovery, error) {
+	return models.MTUDiscovery{}, nil
+}
+func (stubLoopers) Rotate(context.Context) (string, error)              { return "", nil }
+func (stubLoopers) GetLeakReport() models.DNSLeakReport                 { return models.DNSLeakReport{} }
+func (stubLoopers) GetPortsForwarded() []uint16                         { return nil }
+func (stubLoopers) SetPortsForwarded([]uint16) error                    { return nil }
+func (stubLoopers) GetTorrentClientStatus() *models.TorrentClientStatus { return nil }
+func (stubLoopers) GetData() models.PublicIP                            { return models.PublicIP{} }
+func (stubLoopers) GetHistory() []models.PublicIPHistoryEntry           { return nil }
+func (stubLoopers) GetFilterChoices(string) models.FilterChoices        { return models.FilterChoices{} }
+func (stubLoopers) GetUpdateReport() models.ServersUpdateReport         { return models.ServersUpdateReport{} }
+func (stubLoopers) GetConnection() models.Connection                    { return models.Connection{} }
+func (stubLoopers) GetError() error                                     { return nil }
+func (stubLoopers) Lines() []string                                     { return nil }
+func (stubLoopers) Append(models.AuditEntry) error                      { return nil }
+func (stubLoopers) Entries() ([]models.AuditEntry, error)               { return nil, nil }
+
+type stubUpdaterLooper struct{}
+
+func (stubUpdaterLooper) GetStatus() models.LoopStatus { return constants.Stopped }
+func (stubUpdaterLooper) SetStatus(context.Context, models.LoopStatus) (string, error) {
+	return "", nil
+}
+func (stubUpdaterLooper) SetSettings(settings.Updater) string { return "" }
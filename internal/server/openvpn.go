@@ -64,8 +64,16 @@ func (h *openvpnHandler) getStatus(w http.ResponseWriter) {
 			openVPNStatus = constants.Stopped
 		}
 	}
+	data := openvpnStatusWrapper{
+		Status: string(openVPNStatus),
+	}
+	if openVPNStatus == constants.Running {
+		managementStatus, err := h.looper.GetOpenVPNStatus()
+		if err == nil {
+			data.OpenVPNStatus = &managementStatus
+		}
+	}
 	encoder := json.NewEncoder(w)
-	data := statusWrapper{Status: string(openVPNStatus)}
 	if err := encoder.Encode(data); err != nil {
 		h.warner.Warn(err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
@@ -37,7 +37,10 @@ func (h *portForwardHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (h *portForwardHandler) getPortForwarded(w http.ResponseWriter) {
 	ports := h.portForward.GetPortsForwarded()
 	encoder := json.NewEncoder(w)
-	data := portsWrapper{Ports: ports}
+	data := portsWrapper{
+		Ports:         ports,
+		TorrentClient: h.portForward.GetTorrentClientStatus(),
+	}
 	if len(ports) > 0 {
 		data.Port = ports[0] // TODO v4 remove
 	}
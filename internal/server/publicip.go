@@ -28,6 +28,13 @@ func (h *publicIPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			errMethodNotSupported(w, r.Method)
 		}
+	case "/history":
+		switch r.Method {
+		case http.MethodGet:
+			h.getHistory(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
 	default:
 		errRouteNotSupported(w, r.RequestURI)
 	}
@@ -42,3 +49,13 @@ func (h *publicIPHandler) getPublicIP(w http.ResponseWriter) {
 		return
 	}
 }
+
+func (h *publicIPHandler) getHistory(w http.ResponseWriter) {
+	history := h.loop.GetHistory()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(history); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
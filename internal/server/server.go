@@ -9,6 +9,7 @@ import (
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/httpserver"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/server/middlewares/audit"
 	"github.com/qdm12/gluetun/internal/server/middlewares/auth"
 )
 
@@ -16,7 +17,8 @@ func New(ctx context.Context, settings settings.ControlServer, logger Logger,
 	buildInfo models.BuildInformation, openvpnLooper VPNLooper,
 	pf PortForwarding, dnsLooper DNSLoop,
 	updaterLooper UpdaterLooper, publicIPLooper PublicIPLoop, storage Storage,
-	ipv6Supported bool) (
+	healthServer HealthServer, logBuffer LogBuffer, ipv6Supported bool,
+	puid, pgid int) (
 	server *httpserver.Server, err error,
 ) {
 	authSettings, err := setupAuthMiddleware(settings.AuthFilePath, settings.AuthDefaultRole, logger)
@@ -26,15 +28,20 @@ func New(ctx context.Context, settings settings.ControlServer, logger Logger,
 
 	handler, err := newHandler(ctx, logger, *settings.Log, authSettings, buildInfo,
 		openvpnLooper, pf, dnsLooper, updaterLooper, publicIPLooper,
-		storage, ipv6Supported)
+		storage, healthServer, logBuffer, audit.NewFile(*settings.AuditFilepath),
+		ipv6Supported, *settings.Dashboard)
 	if err != nil {
 		return nil, fmt.Errorf("creating handler: %w", err)
 	}
 
 	httpServerSettings := httpserver.Settings{
-		Address: *settings.Address,
-		Handler: handler,
-		Logger:  logger,
+		Address:    *settings.Address,
+		SocketPath: *settings.SocketPath,
+		SocketUID:  puid,
+		SocketGID:  pgid,
+		SocketMode: *settings.SocketMode,
+		Handler:    handler,
+		Logger:     logger,
 	}
 
 	server, err = httpserver.New(httpServerSettings)
@@ -60,6 +67,7 @@ func setupAuthMiddleware(authPath, jsonDefaultRole string, logger Logger) (
 	if err != nil {
 		return auth.Settings{}, fmt.Errorf("setting default role: %w", err)
 	}
+	authSettings.SetDefaults()
 	err = authSettings.Validate()
 	if err != nil {
 		return auth.Settings{}, fmt.Errorf("validating auth settings: %w", err)
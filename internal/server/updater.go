@@ -20,19 +20,22 @@ type UpdaterLooper interface {
 func newUpdaterHandler(
 	ctx context.Context,
 	looper UpdaterLooper,
+	storage Storage,
 	warner warner,
 ) http.Handler {
 	return &updaterHandler{
-		ctx:    ctx,
-		looper: looper,
-		warner: warner,
+		ctx:     ctx,
+		looper:  looper,
+		storage: storage,
+		warner:  warner,
 	}
 }
 
 type updaterHandler struct {
-	ctx    context.Context //nolint:containedctx
-	looper UpdaterLooper
-	warner warner
+	ctx     context.Context //nolint:containedctx
+	looper  UpdaterLooper
+	storage Storage
+	warner  warner
 }
 
 func (h *updaterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -47,6 +50,13 @@ func (h *updaterHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			errMethodNotSupported(w, r.Method)
 		}
+	case "/report":
+		switch r.Method {
+		case http.MethodGet:
+			h.getReport(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
 	default:
 		errRouteNotSupported(w, r.RequestURI)
 	}
@@ -63,6 +73,16 @@ func (h *updaterHandler) getStatus(w http.ResponseWriter) {
 	}
 }
 
+func (h *updaterHandler) getReport(w http.ResponseWriter) {
+	report := h.storage.GetUpdateReport()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(report); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
 func (h *updaterHandler) setStatus(w http.ResponseWriter, r *http.Request) {
 	decoder := json.NewDecoder(r.Body)
 	var data statusWrapper
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/server/middlewares/audit"
 )
 
 func newVPNHandler(ctx context.Context, looper VPNLooper,
@@ -50,6 +51,41 @@ func (h *vpnHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			errMethodNotSupported(w, r.Method)
 		}
+	case "/connection":
+		switch r.Method {
+		case http.MethodGet:
+			h.getConnection(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/choices":
+		switch r.Method {
+		case http.MethodGet:
+			h.getChoices(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/rotate":
+		switch r.Method {
+		case http.MethodPut:
+			h.rotate(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/mtu":
+		switch r.Method {
+		case http.MethodGet:
+			h.getMTU(w)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
+	case "/mtu/discover":
+		switch r.Method {
+		case http.MethodPut:
+			h.discoverMTU(w, r)
+		default:
+			errMethodNotSupported(w, r.Method)
+		}
 	default:
 		errRouteNotSupported(w, r.RequestURI)
 	}
@@ -91,6 +127,73 @@ func (h *vpnHandler) setStatus(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *vpnHandler) rotate(w http.ResponseWriter) {
+	outcome, err := h.looper.Rotate(h.ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(outcomeWrapper{Outcome: outcome}); err != nil {
+		h.warner.Warn(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getConnection(w http.ResponseWriter) {
+	connection := h.looper.GetConnection()
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(connection); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *vpnHandler) getMTU(w http.ResponseWriter) {
+	discovery, err := h.looper.GetMTUDiscovery()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(discovery); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// discoverMTU runs a path MTU discovery on the VPN interface and
+// writes its report, which can take a few seconds.
+func (h *vpnHandler) discoverMTU(w http.ResponseWriter, r *http.Request) {
+	discovery, err := h.looper.DiscoverMTU(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(discovery); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// getChoices writes the possible server selection filter values
+// for the current VPN provider.
+func (h *vpnHandler) getChoices(w http.ResponseWriter) {
+	provider := h.looper.GetSettings().Provider.Name
+	choices := h.storage.GetFilterChoices(provider)
+	encoder := json.NewEncoder(w)
+	if err := encoder.Encode(choices); err != nil {
+		h.warner.Warn(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
 func (h *vpnHandler) getSettings(w http.ResponseWriter) {
 	settings := h.looper.GetSettings()
 	encoder := json.NewEncoder(w)
@@ -115,6 +218,7 @@ func (h *vpnHandler) patchSettings(w http.ResponseWriter, r *http.Request) {
 		h.warner.Warn("closing body: " + err.Error())
 	}
 
+	previousSettings := h.looper.GetSettings()
 	updatedSettings := h.looper.GetSettings() // already copied
 	updatedSettings.OverrideWith(overrideSettings)
 	err = updatedSettings.Validate(h.storage, h.ipv6Supported, h.warner)
@@ -123,6 +227,7 @@ func (h *vpnHandler) patchSettings(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	audit.SetChanges(r.Context(), audit.Diff(previousSettings, updatedSettings))
 	outcome := h.looper.SetSettings(h.ctx, updatedSettings)
 	_, err = w.Write([]byte(outcome))
 	if err != nil {
@@ -22,11 +22,34 @@ func (sw *statusWrapper) getStatus() (status models.LoopStatus, err error) {
 	}
 }
 
+type openvpnStatusWrapper struct {
+	Status string `json:"status"`
+	*models.OpenVPNStatus
+}
+
 type portsWrapper struct {
-	Port  uint16   `json:"port"` // TODO v4 remove
-	Ports []uint16 `json:"ports"`
+	Port          uint16                      `json:"port"` // TODO v4 remove
+	Ports         []uint16                    `json:"ports"`
+	TorrentClient *models.TorrentClientStatus `json:"torrent_client,omitempty"`
 }
 
 type outcomeWrapper struct {
 	Outcome string `json:"outcome"`
 }
+
+type healthWrapper struct {
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type logsWrapper struct {
+	Lines []string `json:"lines"`
+}
+
+type auditWrapper struct {
+	Entries []models.AuditEntry `json:"entries"`
+}
+
+type lockoutsWrapper struct {
+	Lockouts []models.AuthLockout `json:"lockouts"`
+}
@@ -0,0 +1,95 @@
+// Package signature verifies the ed25519 signatures of servers data files.
+// A signature is stored in a file next to the file it signs, with the
+// [FileSuffix] extension, and contains the base64 encoded ed25519
+// signature of the file bytes.
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	_ "embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// FileSuffix is the suffix appended to the path or URL of a
+// signed file to get the path or URL of its signature file.
+const FileSuffix = ".sig"
+
+//go:embed keys.txt
+var embeddedKeys []byte
+
+// Verifier verifies signatures using a set of trusted public keys.
+type Verifier struct {
+	publicKeys []ed25519.PublicKey
+}
+
+// New creates a verifier trusting the public keys given.
+func New(publicKeys []ed25519.PublicKey) *Verifier {
+	return &Verifier{
+		publicKeys: publicKeys,
+	}
+}
+
+// NewEmbedded creates a verifier trusting the public keys
+// embedded in the program.
+func NewEmbedded() *Verifier {
+	// A unit test prevents parsing the embedded keys from ever failing.
+	publicKeys, err := ParsePublicKeys(embeddedKeys)
+	if err != nil {
+		panic(err)
+	}
+	return New(publicKeys)
+}
+
+var (
+	ErrPublicKeyNotValid = errors.New("public key is not valid")
+	ErrSignatureNotValid = errors.New("signature is not valid")
+	ErrSignatureMissing  = errors.New("signature is missing")
+)
+
+// ParsePublicKeys parses base64 encoded ed25519 public keys, one per
+// line, ignoring empty lines and lines starting with #.
+func ParsePublicKeys(data []byte) (publicKeys []ed25519.PublicKey, err error) {
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		publicKey, err := base64.StdEncoding.DecodeString(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w: %w", i+1, ErrPublicKeyNotValid, err)
+		} else if len(publicKey) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("line %d: %w: %d bytes instead of %d bytes",
+				i+1, ErrPublicKeyNotValid, len(publicKey), ed25519.PublicKeySize)
+		}
+		publicKeys = append(publicKeys, publicKey)
+	}
+	return publicKeys, nil
+}
+
+// Verify verifies the data given is signed by one of the trusted
+// public keys, where signature is the content of a signature file.
+func (v *Verifier) Verify(data, signature []byte) (err error) {
+	signature = bytes.TrimSpace(signature)
+	decoded := make([]byte, base64.StdEncoding.DecodedLen(len(signature)))
+	n, err := base64.StdEncoding.Decode(decoded, signature)
+	if err != nil {
+		return fmt.Errorf("%w: decoding base64: %w", ErrSignatureNotValid, err)
+	}
+	decoded = decoded[:n]
+
+	if len(decoded) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: %d bytes instead of %d bytes",
+			ErrSignatureNotValid, len(decoded), ed25519.SignatureSize)
+	}
+
+	for _, publicKey := range v.publicKeys {
+		if ed25519.Verify(publicKey, data, decoded) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no trusted public key matches", ErrSignatureNotValid)
+}
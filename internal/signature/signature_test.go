@@ -0,0 +1,145 @@
+package signature
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewEmbedded(t *testing.T) {
+	t.Parallel()
+
+	assert.NotPanics(t, func() {
+		_ = NewEmbedded()
+	})
+}
+
+func Test_ParsePublicKeys(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		publicKeys []ed25519.PublicKey
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {},
+		"comments_and_empty_lines": {
+			data: "# comment\n\n  \n",
+		},
+		"valid_key": {
+			data:       "# comment\n" + base64.StdEncoding.EncodeToString(make([]byte, ed25519.PublicKeySize)) + "\n",
+			publicKeys: []ed25519.PublicKey{make([]byte, ed25519.PublicKeySize)},
+		},
+		"bad_base64": {
+			data:       "\nnot base64",
+			errWrapped: ErrPublicKeyNotValid,
+			errMessage: "line 2: public key is not valid: illegal base64 data at input byte 3",
+		},
+		"bad_length": {
+			data:       base64.StdEncoding.EncodeToString([]byte{1, 2}),
+			errWrapped: ErrPublicKeyNotValid,
+			errMessage: "line 1: public key is not valid: 2 bytes instead of 32 bytes",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			publicKeys, err := ParsePublicKeys([]byte(testCase.data))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.publicKeys, publicKeys)
+		})
+	}
+}
+
+func Test_Verifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPublicKey, otherPrivateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	data := []byte(`{"version":1}`)
+	sign := func(privateKey ed25519.PrivateKey, data []byte) []byte {
+		signature := ed25519.Sign(privateKey, data)
+		return []byte(base64.StdEncoding.EncodeToString(signature) + "\n")
+	}
+
+	testCases := map[string]struct {
+		publicKeys []ed25519.PublicKey
+		data       []byte
+		signature  []byte
+		errWrapped error
+		errMessage string
+	}{
+		"valid_signature": {
+			publicKeys: []ed25519.PublicKey{publicKey},
+			data:       data,
+			signature:  sign(privateKey, data),
+		},
+		"valid_signature_second_key": {
+			publicKeys: []ed25519.PublicKey{publicKey, otherPublicKey},
+			data:       data,
+			signature:  sign(otherPrivateKey, data),
+		},
+		"no_public_key": {
+			data:       data,
+			signature:  sign(privateKey, data),
+			errWrapped: ErrSignatureNotValid,
+			errMessage: "signature is not valid: no trusted public key matches",
+		},
+		"tampered_data": {
+			publicKeys: []ed25519.PublicKey{publicKey},
+			data:       []byte(`{"version":2}`),
+			signature:  sign(privateKey, data),
+			errWrapped: ErrSignatureNotValid,
+			errMessage: "signature is not valid: no trusted public key matches",
+		},
+		"untrusted_key": {
+			publicKeys: []ed25519.PublicKey{publicKey},
+			data:       data,
+			signature:  sign(otherPrivateKey, data),
+			errWrapped: ErrSignatureNotValid,
+			errMessage: "signature is not valid: no trusted public key matches",
+		},
+		"bad_base64": {
+			publicKeys: []ed25519.PublicKey{publicKey},
+			data:       data,
+			signature:  []byte("not base64"),
+			errWrapped: ErrSignatureNotValid,
+			errMessage: "signature is not valid: decoding base64: illegal base64 data at input byte 3",
+		},
+		"bad_length": {
+			publicKeys: []ed25519.PublicKey{publicKey},
+			data:       data,
+			signature:  []byte(base64.StdEncoding.EncodeToString([]byte{1})),
+			errWrapped: ErrSignatureNotValid,
+			errMessage: "signature is not valid: 1 bytes instead of 64 bytes",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			verifier := New(testCase.publicKeys)
+
+			err := verifier.Verify(testCase.data, testCase.signature)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
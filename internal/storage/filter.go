@@ -12,6 +12,10 @@ import (
 	"github.com/qdm12/gluetun/internal/models"
 )
 
+// ErrNoServerFound is returned by FilterServers when no
+// server matches the selection given.
+var ErrNoServerFound = errors.New("no server found")
+
 // FilterServers filter servers for the given provider and according
 // to the given selection. The filtered servers are deep copied so they
 // are safe for mutation by the caller.
@@ -29,7 +33,7 @@ func (s *Storage) FilterServers(provider string, selection settings.ServerSelect
 	allServers := serversObject.Servers
 
 	if len(allServers) == 0 {
-		return nil, errors.New("no server found")
+		return nil, ErrNoServerFound
 	}
 
 	for _, server := range allServers {
@@ -45,44 +45,47 @@ func (s *Storage) flushToFile(manifestPath string) error {
 			providerFilepath = filepath.Join(serversDirectoryPath, provider+".json")
 		}
 
-		providerFile, err := os.OpenFile(providerFilepath,
-			os.O_CREATE|os.O_WRONLY|os.O_TRUNC, filePermission)
-		if err != nil {
-			return fmt.Errorf("opening servers data file for %s: %w", provider, err)
-		}
-
 		encodedProviderServers := providerServers
 		encodedProviderServers.Filepath = ""
 
-		encoder := json.NewEncoder(providerFile)
-		encoder.SetIndent("", "  ")
-		err = encoder.Encode(encodedProviderServers)
+		data, err := encodeIndented(encodedProviderServers)
 		if err != nil {
-			_ = providerFile.Close()
 			return fmt.Errorf("encoding servers data for %s: %w", provider, err)
 		}
 
-		err = providerFile.Close()
+		err = writeFile(providerFilepath, data, filePermission)
 		if err != nil {
-			return fmt.Errorf("closing servers data file for %s: %w", provider, err)
+			return fmt.Errorf("writing servers data file for %s: %w", provider, err)
 		}
 
 		metadata[provider] = map[string]string{"filepath": providerFilepath}
 	}
 
-	serversFile, err := os.OpenFile(manifestPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, filePermission)
+	data, err := encodeIndented(metadata)
 	if err != nil {
-		return err
+		return fmt.Errorf("encoding manifest: %w", err)
 	}
 
-	encoder := json.NewEncoder(serversFile)
-	encoder.SetIndent("", "  ")
-
-	err = encoder.Encode(metadata)
+	err = writeFile(manifestPath, data, filePermission)
 	if err != nil {
-		_ = serversFile.Close()
 		return err
 	}
 
-	return serversFile.Close()
+	if s.requireSignature {
+		// Servers data written locally is never signed, since a signing
+		// key stored next to it would not protect it from tampering.
+		s.logger.Warn("servers data written to " + serversDirectoryPath +
+			" is not signed and will be ignored on the next start since signatures are required")
+	}
+	return nil
+}
+
+// encodeIndented encodes the value given to indented JSON
+// terminated by a newline.
+func encodeIndented(value any) (data []byte, err error) {
+	data, err = json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
 }
@@ -6,7 +6,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/signature"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -62,3 +64,29 @@ func Test_flushToFile_filepathOnlyInManifest(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, providerFilepath, providerMetadata.Filepath)
 }
+
+func Test_flushToFile_requireSignature(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+
+	tempPath := t.TempDir()
+	manifestPath := filepath.Join(tempPath, "manifest.json")
+	signaturePath := manifestPath + signature.FileSuffix
+	err := os.WriteFile(signaturePath, []byte("outdated"), 0o600)
+	require.NoError(t, err)
+
+	logger := NewMockLogger(ctrl)
+	logger.EXPECT().Warn("servers data written to " + tempPath +
+		" is not signed and will be ignored on the next start since signatures are required")
+	storage := &Storage{
+		logger:           logger,
+		requireSignature: true,
+		mergedServers:    models.AllServers{Version: 1},
+	}
+
+	err = storage.flushToFile(manifestPath)
+	require.NoError(t, err)
+
+	assert.FileExists(t, manifestPath)
+	assert.NoFileExists(t, signaturePath)
+}
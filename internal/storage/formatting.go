@@ -157,5 +157,5 @@ func noServerFoundError(selection settings.ServerSelection) (err error) {
 
 	message := "for " + strings.Join(messageParts, "; ")
 
-	return fmt.Errorf("no server found: %s", message)
+	return fmt.Errorf("%w: %s", ErrNoServerFound, message)
 }
@@ -40,6 +40,12 @@ func (s *Storage) readFromFile(manifestPath string, hardcodedVersions map[string
 		return servers, false, nil
 	}
 
+	err = s.verifyFile(manifestPath, b)
+	if err != nil {
+		s.logger.Warn("ignoring servers data from " + manifestPath + ": " + err.Error())
+		return servers, false, nil
+	}
+
 	servers, err = s.extractServersFromBytes(b, hardcodedVersions)
 	return servers, true, err
 }
@@ -133,16 +139,21 @@ func (s *Storage) readServers(provider string, hardcodedVersion uint16,
 func (s *Storage) readServersFromFilepath(provider, filepath string, hardcodedVersion uint16) (
 	referencedServers models.Servers, versionsMatch bool, err error,
 ) {
-	providerFile, err := os.Open(filepath)
+	data, err := os.ReadFile(filepath)
 	if os.IsNotExist(err) {
 		return models.Servers{}, false, nil
 	} else if err != nil {
-		return models.Servers{}, false, fmt.Errorf("opening servers file %s for provider %s: %w",
+		return models.Servers{}, false, fmt.Errorf("reading servers file %s for provider %s: %w",
 			filepath, provider, err)
 	}
-	defer providerFile.Close()
 
-	err = json.NewDecoder(providerFile).Decode(&referencedServers)
+	err = s.verifyFile(filepath, data)
+	if err != nil {
+		s.logger.Warn("ignoring " + provider + " servers from file " + filepath + ": " + err.Error())
+		return models.Servers{}, false, nil
+	}
+
+	err = json.Unmarshal(data, &referencedServers)
 	if err != nil {
 		return models.Servers{}, false, fmt.Errorf("decoding servers file %s for provider %s: %w",
 			filepath, provider, err)
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+const updateReportFilename = "update_report.json"
+
+// SetUpdateReport sets the last servers update report in memory,
+// and writes it to a file in the storage directory if disk
+// writing is enabled.
+func (s *Storage) SetUpdateReport(report models.ServersUpdateReport) (err error) {
+	s.reportMutex.Lock()
+	defer s.reportMutex.Unlock()
+
+	s.updateReport = report
+
+	if !s.disk {
+		return nil
+	}
+
+	const dirPermission = 0o755
+	err = os.MkdirAll(s.directoryPath, dirPermission)
+	if err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding update report: %w", err)
+	}
+
+	const filePermission = 0o644
+	path := filepath.Join(s.directoryPath, updateReportFilename)
+	err = os.WriteFile(path, data, filePermission)
+	if err != nil {
+		return fmt.Errorf("writing update report file: %w", err)
+	}
+	return nil
+}
+
+// GetUpdateReport returns the last servers update report, which
+// has a zero time if no update changed any servers data yet.
+func (s *Storage) GetUpdateReport() (report models.ServersUpdateReport) {
+	s.reportMutex.RLock()
+	defer s.reportMutex.RUnlock()
+	return s.updateReport
+}
+
+// readUpdateReport reads the last servers update report from
+// the storage directory, if it exists.
+func (s *Storage) readUpdateReport() (err error) {
+	path := filepath.Join(s.directoryPath, updateReportFilename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading update report file: %w", err)
+	}
+
+	err = json.Unmarshal(data, &s.updateReport)
+	if err != nil {
+		return fmt.Errorf("decoding update report file %s: %w", path, err)
+	}
+	return nil
+}
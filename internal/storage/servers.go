@@ -62,6 +62,23 @@ func (s *Storage) GetServersCount(provider string) (count int) {
 	return len(serversObject.Servers)
 }
 
+// GetServers returns a deep copy of the servers for the provider given.
+func (s *Storage) GetServers(provider string) (servers []models.Server) {
+	if provider == providers.Custom {
+		return nil
+	}
+
+	s.mergedMutex.RLock()
+	defer s.mergedMutex.RUnlock()
+
+	serversObject := s.getMergedServersObject(provider)
+	servers = make([]models.Server, len(serversObject.Servers))
+	for i, server := range serversObject.Servers {
+		servers[i] = copyServer(server)
+	}
+	return servers
+}
+
 // Format formats the servers for the provider using the format given
 // and returns the resulting string.
 func (s *Storage) Format(provider, format string) (formatted string, err error) {
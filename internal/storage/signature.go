@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/qdm12/gluetun/internal/signature"
+)
+
+// verifyFile verifies the data read from the file path given using
+// the signature file next to it. A missing signature file is only an
+// error if signatures are required.
+func (s *Storage) verifyFile(path string, data []byte) (err error) {
+	signatureData, err := os.ReadFile(path + signature.FileSuffix)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if s.requireSignature {
+			return fmt.Errorf("%w: for %s", signature.ErrSignatureMissing, path)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("reading signature file: %w", err)
+	}
+	return s.verifier.Verify(data, signatureData)
+}
+
+// writeFile writes the data to the file path given. If the data differs
+// from the current file content, the signature file next to it is
+// removed since it would no longer be valid.
+func writeFile(path string, data []byte, perm os.FileMode) (err error) {
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+
+	err = os.WriteFile(path, data, perm)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path + signature.FileSuffix)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing outdated signature file: %w", err)
+	}
+	return nil
+}
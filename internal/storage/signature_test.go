@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Storage_verifyFile(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	data := []byte(`{"version":1}`)
+	validSignature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, data))
+
+	testCases := map[string]struct {
+		signature        string // empty for no signature file
+		requireSignature bool
+		errWrapped       error
+	}{
+		"unsigned_allowed": {},
+		"unsigned_refused": {
+			requireSignature: true,
+			errWrapped:       signature.ErrSignatureMissing,
+		},
+		"valid_signature": {
+			signature:        validSignature,
+			requireSignature: true,
+		},
+		"invalid_signature": {
+			signature:  base64.StdEncoding.EncodeToString(make([]byte, ed25519.SignatureSize)),
+			errWrapped: signature.ErrSignatureNotValid,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "provider.json")
+			if testCase.signature != "" {
+				err := os.WriteFile(path+signature.FileSuffix, []byte(testCase.signature), 0o600)
+				require.NoError(t, err)
+			}
+
+			storage := &Storage{
+				verifier:         signature.New([]ed25519.PublicKey{publicKey}),
+				requireSignature: testCase.requireSignature,
+			}
+
+			err := storage.verifyFile(path, data)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
+
+func Test_writeFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "provider.json")
+	signaturePath := path + signature.FileSuffix
+	const perm = 0o600
+
+	err := os.WriteFile(path, []byte("data"), perm)
+	require.NoError(t, err)
+	err = os.WriteFile(signaturePath, []byte("signature"), perm)
+	require.NoError(t, err)
+
+	// Same data keeps the signature file
+	err = writeFile(path, []byte("data"), perm)
+	require.NoError(t, err)
+	assert.FileExists(t, signaturePath)
+
+	// Different data removes the outdated signature file
+	err = writeFile(path, []byte("other data"), perm)
+	require.NoError(t, err)
+	assert.NoFileExists(t, signaturePath)
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "other data", string(written))
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/signature"
 )
 
 type Storage struct {
@@ -15,10 +16,14 @@ type Storage struct {
 	// the embedded JSON file on every call to the
 	// SyncServers method.
 	hardcodedServers models.AllServers
+	updateReport     models.ServersUpdateReport
+	reportMutex      sync.RWMutex
 	logger           Logger
 	disk             bool
 	directoryPath    string
 	legacyFilepath   string
+	verifier         *signature.Verifier
+	requireSignature bool
 }
 
 const manifestFilename = "manifest.json"
@@ -32,8 +37,12 @@ type Logger interface {
 // New creates a new storage and reads the servers from the
 // embedded servers files and the files on disk.
 // Passing an empty directoryPath disables the reading and writing of
-// servers.
-func New(logger Logger, disk bool, directoryPath, legacyFilepath string) (storage *Storage, err error) {
+// servers. If requireSignature is true, servers files on disk without
+// a valid signature file next to them are ignored, including the
+// servers files written by the storage itself which are not signed.
+func New(logger Logger, disk bool, directoryPath, legacyFilepath string,
+	requireSignature bool,
+) (storage *Storage, err error) {
 	// A unit test prevents [parseHardcodedServers] from ever failing,
 	// and ensures all providers are part of the servers returned.
 	hardcodedServers := parseHardcodedServers()
@@ -45,12 +54,19 @@ func New(logger Logger, disk bool, directoryPath, legacyFilepath string) (storag
 		disk:             disk,
 		directoryPath:    directoryPath,
 		legacyFilepath:   legacyFilepath,
+		verifier:         signature.NewEmbedded(),
+		requireSignature: requireSignature,
 	}
 
 	if disk {
 		if err := storage.syncServers(); err != nil {
 			return nil, err
 		}
+
+		if err := storage.readUpdateReport(); err != nil {
+			// The report is informational only, so do not fail.
+			logger.Warn(err.Error())
+		}
 	}
 
 	return storage, nil
@@ -0,0 +1,8 @@
+package tproxy
+
+type Logger interface {
+	Debug(s string)
+	Info(s string)
+	Warn(s string)
+	Error(s string)
+}
@@ -0,0 +1,169 @@
+// Package tproxy implements a transparent proxy for TCP and UDP traffic
+// intercepted by the firewall, dialing the original destinations out
+// through the VPN tunnel.
+package tproxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+)
+
+// FirewallMark is the mark set on UDP packets intercepted
+// with the iptables TPROXY target, which must be routed
+// to the loopback interface.
+const FirewallMark uint32 = 0x7470
+
+type Server struct {
+	port    uint16
+	ipv4    bool
+	ipv6    bool
+	acl     []settings.TransparentProxyRule
+	verbose bool
+	dialer  *net.Dialer
+	logger  Logger
+	// udpIdleTimeout is the duration after which a UDP
+	// session without any traffic is closed.
+	udpIdleTimeout time.Duration
+}
+
+// New creates a new transparent proxy server listening on the settings port,
+// for each IP family of the settings sources.
+func New(settings settings.TransparentProxy, logger Logger) *Server {
+	server := &Server{
+		port:    settings.Port,
+		acl:     settings.ACL,
+		verbose: *settings.Log,
+		dialer: &net.Dialer{
+			Timeout: 10 * time.Second, //nolint:mnd
+		},
+		logger:         logger,
+		udpIdleTimeout: time.Minute,
+	}
+	for _, source := range settings.Sources {
+		server.ipv4 = server.ipv4 || source.Addr().Is4()
+		server.ipv6 = server.ipv6 || source.Addr().Is6()
+	}
+	return server
+}
+
+// Run runs the transparent proxy server until ctx is canceled.
+// The ready channel is closed once the server is listening, and
+// the done channel is closed when the server is terminated.
+func (s *Server) Run(ctx context.Context, ready chan<- struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	tcpListeners, udpConns, err := s.listen(ctx)
+	if err != nil {
+		s.logger.Error(err.Error())
+		close(ready)
+		return
+	}
+	close(ready)
+
+	wg := new(sync.WaitGroup)
+	for _, listener := range tcpListeners {
+		s.logger.Info("listening on " + listener.Addr().String() + " (tcp)")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveTCP(ctx, listener)
+		}()
+	}
+	for _, conn := range udpConns {
+		s.logger.Info("listening on " + conn.LocalAddr().String() + " (udp)")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveUDP(ctx, conn)
+		}()
+	}
+
+	<-ctx.Done()
+	for _, listener := range tcpListeners {
+		_ = listener.Close()
+	}
+	for _, conn := range udpConns {
+		_ = conn.Close()
+	}
+	wg.Wait()
+}
+
+func (s *Server) listen(ctx context.Context) (tcpListeners []net.Listener,
+	udpConns []*net.UDPConn, err error,
+) {
+	type family struct {
+		tcpNetwork, udpNetwork string
+		address                netip.Addr
+	}
+	var families []family
+	if s.ipv4 {
+		families = append(families, family{"tcp4", "udp4", netip.IPv4Unspecified()})
+	}
+	if s.ipv6 {
+		families = append(families, family{"tcp6", "udp6", netip.IPv6Unspecified()})
+	}
+
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, listener := range tcpListeners {
+			_ = listener.Close()
+		}
+		for _, conn := range udpConns {
+			_ = conn.Close()
+		}
+	}()
+
+	for _, family := range families {
+		address := netip.AddrPortFrom(family.address, s.port).String()
+
+		listenConfig := &net.ListenConfig{}
+		listener, err := listenConfig.Listen(ctx, family.tcpNetwork, address)
+		if err != nil {
+			return tcpListeners, udpConns, fmt.Errorf("listening on TCP: %w", err)
+		}
+		tcpListeners = append(tcpListeners, listener)
+
+		listenConfig.Control = controlTransparentListener
+		packetConn, err := listenConfig.ListenPacket(ctx, family.udpNetwork, address)
+		if err != nil {
+			return tcpListeners, udpConns, fmt.Errorf("listening on UDP: %w", err)
+		}
+		udpConns = append(udpConns, packetConn.(*net.UDPConn)) //nolint:forcetypeassert
+	}
+
+	return tcpListeners, udpConns, nil
+}
+
+// allowed returns true if the access control rules allow
+// the connection from the source to the destination given.
+// Connections not matching any rule are allowed.
+func (s *Server) allowed(source netip.Addr, destination netip.AddrPort) bool {
+	for _, rule := range s.acl {
+		if rule.Match(source, destination) {
+			return rule.Allow
+		}
+	}
+	return true
+}
+
+// logConnection logs the connection at the info level if
+// logging is enabled, and at the debug level otherwise.
+func (s *Server) logConnection(protocol string, source, destination netip.AddrPort, allowed bool) {
+	message := fmt.Sprintf("%s %s -> %s", protocol, source, destination)
+	if !allowed {
+		message += " denied"
+	}
+	if s.verbose {
+		s.logger.Info(message)
+	} else {
+		s.logger.Debug(message)
+	}
+}
@@ -0,0 +1,59 @@
+package tproxy
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Server_allowed(t *testing.T) {
+	t.Parallel()
+
+	server := &Server{
+		acl: []settings.TransparentProxyRule{
+			{
+				Allow:       true,
+				Source:      netip.MustParsePrefix("10.0.0.2/32"),
+				Destination: netip.MustParsePrefix("0.0.0.0/0"),
+			},
+			{
+				Source:      netip.MustParsePrefix("10.0.0.0/24"),
+				Destination: netip.MustParsePrefix("0.0.0.0/0"),
+				Port:        25,
+			},
+		},
+	}
+
+	testCases := map[string]struct {
+		source      netip.Addr
+		destination netip.AddrPort
+		allowed     bool
+	}{
+		"first_rule_wins": {
+			source:      netip.MustParseAddr("10.0.0.2"),
+			destination: netip.MustParseAddrPort("1.2.3.4:25"),
+			allowed:     true,
+		},
+		"denied": {
+			source:      netip.MustParseAddr("10.0.0.3"),
+			destination: netip.MustParseAddrPort("1.2.3.4:25"),
+		},
+		"no_matching_rule": {
+			source:      netip.MustParseAddr("10.0.0.3"),
+			destination: netip.MustParseAddrPort("1.2.3.4:443"),
+			allowed:     true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			allowed := server.allowed(testCase.source, testCase.destination)
+
+			assert.Equal(t, testCase.allowed, allowed)
+		})
+	}
+}
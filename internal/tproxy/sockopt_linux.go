@@ -0,0 +1,148 @@
+package tproxy
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// originalDestinationOOBSize is the out of band data buffer size
+// to receive the original destination control message of a packet.
+var originalDestinationOOBSize = unix.CmsgSpace(unix.SizeofSockaddrInet6) //nolint:gochecknoglobals
+
+// controlTransparentListener sets the socket options to receive
+// UDP packets intercepted with the TPROXY target, together with
+// their original destination address.
+func controlTransparentListener(network, _ string, rawConn syscall.RawConn) error {
+	level, transparentOption, recvOrigDstOption := unix.SOL_IP, unix.IP_TRANSPARENT, unix.IP_RECVORIGDSTADDR
+	if network == "udp6" {
+		level, transparentOption, recvOrigDstOption = unix.SOL_IPV6, unix.IPV6_TRANSPARENT, unix.IPV6_RECVORIGDSTADDR
+	}
+	return setSockopts(rawConn, level, transparentOption, recvOrigDstOption)
+}
+
+// controlTransparentReply sets the socket options to bind a UDP socket
+// to a non local address, which is the original destination of a client.
+func controlTransparentReply(network, _ string, rawConn syscall.RawConn) error {
+	level, transparentOption := unix.SOL_IP, unix.IP_TRANSPARENT
+	if network == "udp6" {
+		level, transparentOption = unix.SOL_IPV6, unix.IPV6_TRANSPARENT
+	}
+	err := setSockopts(rawConn, level, transparentOption)
+	if err != nil {
+		return err
+	}
+	// Allow multiple clients to talk to the same original destination.
+	return setSockopts(rawConn, unix.SOL_SOCKET, unix.SO_REUSEADDR)
+}
+
+func setSockopts(rawConn syscall.RawConn, level int, options ...int) (err error) {
+	controlErr := rawConn.Control(func(fd uintptr) {
+		for _, option := range options {
+			err = unix.SetsockoptInt(int(fd), level, option, 1)
+			if err != nil {
+				err = fmt.Errorf("setting socket option %d at level %d: %w", option, level, err)
+				return
+			}
+		}
+	})
+	if controlErr != nil {
+		return controlErr
+	}
+	return err
+}
+
+// originalDestination returns the original destination of a TCP
+// connection intercepted with the REDIRECT target.
+func originalDestination(conn *net.TCPConn) (destination netip.AddrPort, err error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return destination, fmt.Errorf("getting raw connection: %w", err)
+	}
+
+	ipv4 := conn.LocalAddr().(*net.TCPAddr).AddrPort().Addr().Unmap().Is4() //nolint:forcetypeassert
+	controlErr := rawConn.Control(func(fd uintptr) {
+		if ipv4 {
+			// The IPv6 multicast request structure is used as a 20 bytes
+			// buffer large enough to store the sockaddr_in structure.
+			var mreq *unix.IPv6Mreq
+			mreq, err = unix.GetsockoptIPv6Mreq(int(fd), unix.SOL_IP, unix.SO_ORIGINAL_DST)
+			if err == nil {
+				destination, err = parseSockaddr(mreq.Multiaddr[:], ipv4)
+			}
+			return
+		}
+
+		// The IPv6 MTU information structure is used as a buffer
+		// large enough to store the sockaddr_in6 structure.
+		const ip6tSOOriginalDst = 80
+		var info *unix.IPv6MTUInfo
+		info, err = unix.GetsockoptIPv6MTUInfo(int(fd), unix.SOL_IPV6, ip6tSOOriginalDst)
+		if err == nil {
+			destination = netip.AddrPortFrom(netip.AddrFrom16(info.Addr.Addr), ntohs(info.Addr.Port))
+		}
+	})
+	if controlErr != nil {
+		return destination, fmt.Errorf("controlling raw connection: %w", controlErr)
+	} else if err != nil {
+		return destination, fmt.Errorf("getting original destination socket option: %w", err)
+	}
+	return destination, nil
+}
+
+var errOriginalDestinationNotFound = errors.New("original destination not found")
+
+// parseOriginalDestination parses the original destination address
+// of a UDP packet intercepted with the TPROXY target from the
+// socket control messages of the packet.
+func parseOriginalDestination(oob []byte) (destination netip.AddrPort, err error) {
+	messages, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return destination, fmt.Errorf("parsing socket control messages: %w", err)
+	}
+
+	for _, message := range messages {
+		switch {
+		case message.Header.Level == unix.SOL_IP && message.Header.Type == unix.IP_ORIGDSTADDR:
+			return parseSockaddr(message.Data, true)
+		case message.Header.Level == unix.SOL_IPV6 && message.Header.Type == unix.IPV6_ORIGDSTADDR:
+			return parseSockaddr(message.Data, false)
+		}
+	}
+	return destination, fmt.Errorf("%w", errOriginalDestinationNotFound)
+}
+
+var errSockaddrTooShort = errors.New("socket address is too short")
+
+// parseSockaddr parses a raw sockaddr_in structure if ipv4 is
+// true, and a raw sockaddr_in6 structure otherwise.
+func parseSockaddr(b []byte, ipv4 bool) (addrPort netip.AddrPort, err error) {
+	const portStart, ipv4Start, ipv6Start = 2, 4, 8
+	size := unix.SizeofSockaddrInet6
+	if ipv4 {
+		size = unix.SizeofSockaddrInet4
+	}
+	if len(b) < size {
+		return addrPort, fmt.Errorf("%w: %d bytes instead of %d bytes",
+			errSockaddrTooShort, len(b), size)
+	}
+
+	port := binary.BigEndian.Uint16(b[portStart:])
+	if ipv4 {
+		return netip.AddrPortFrom(netip.AddrFrom4([4]byte(b[ipv4Start:ipv4Start+net.IPv4len])), port), nil
+	}
+	return netip.AddrPortFrom(netip.AddrFrom16([16]byte(b[ipv6Start:ipv6Start+net.IPv6len])), port), nil
+}
+
+// ntohs converts a port in network byte order stored as a native
+// byte order integer to a port in native byte order.
+func ntohs(port uint16) uint16 {
+	b := make([]byte, 2) //nolint:mnd
+	binary.NativeEndian.PutUint16(b, port)
+	return binary.BigEndian.Uint16(b)
+}
@@ -0,0 +1,76 @@
+package tproxy
+
+import (
+	"net/netip"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func Test_parseOriginalDestination(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		oob         []byte
+		destination netip.AddrPort
+		errWrapped  error
+		errMessage  string
+	}{
+		"no_message": {
+			errWrapped: errOriginalDestinationNotFound,
+			errMessage: "original destination not found",
+		},
+		"ipv4": {
+			oob: makeControlMessage(unix.SOL_IP, unix.IP_ORIGDSTADDR, []byte{
+				unix.AF_INET, 0, // family
+				0x01, 0xbb, // port 443
+				1, 2, 3, 4, // address
+				0, 0, 0, 0, 0, 0, 0, 0, // padding
+			}),
+			destination: netip.MustParseAddrPort("1.2.3.4:443"),
+		},
+		"ipv6": {
+			oob: makeControlMessage(unix.SOL_IPV6, unix.IPV6_ORIGDSTADDR, []byte{
+				unix.AF_INET6, 0, // family
+				0, 53, // port 53
+				0, 0, 0, 0, // flow info
+				0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 1, // address
+				0, 0, 0, 0, // scope id
+			}),
+			destination: netip.MustParseAddrPort("[2001:db8::1]:53"),
+		},
+		"ipv4_too_short": {
+			oob:        makeControlMessage(unix.SOL_IP, unix.IP_ORIGDSTADDR, []byte{unix.AF_INET, 0, 0, 53}),
+			errWrapped: errSockaddrTooShort,
+			errMessage: "socket address is too short: 4 bytes instead of 16 bytes",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			destination, err := parseOriginalDestination(testCase.oob)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				require.EqualError(t, err, testCase.errMessage)
+			}
+			assert.Equal(t, testCase.destination, destination)
+		})
+	}
+}
+
+func makeControlMessage(level, messageType int32, data []byte) (oob []byte) {
+	oob = make([]byte, unix.CmsgSpace(len(data)))
+	header := (*unix.Cmsghdr)(unsafe.Pointer(&oob[0])) //nolint:gosec
+	header.Level = level
+	header.Type = messageType
+	header.SetLen(unix.CmsgLen(len(data)))
+	copy(oob[unix.CmsgLen(0):], data)
+	return oob
+}
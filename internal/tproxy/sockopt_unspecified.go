@@ -0,0 +1,27 @@
+//go:build !linux
+
+package tproxy
+
+import (
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+const originalDestinationOOBSize = 0
+
+func controlTransparentListener(network, address string, rawConn syscall.RawConn) error {
+	panic("not implemented")
+}
+
+func controlTransparentReply(network, address string, rawConn syscall.RawConn) error {
+	panic("not implemented")
+}
+
+func originalDestination(conn *net.TCPConn) (destination netip.AddrPort, err error) {
+	panic("not implemented")
+}
+
+func parseOriginalDestination(oob []byte) (destination netip.AddrPort, err error) {
+	panic("not implemented")
+}
@@ -0,0 +1,73 @@
+package tproxy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+func (s *Server) serveTCP(ctx context.Context, listener net.Listener) {
+	wg := new(sync.WaitGroup)
+	defer wg.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				s.logger.Error("accepting TCP connection: " + err.Error())
+				continue
+			}
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handleTCP(ctx, conn.(*net.TCPConn)) //nolint:forcetypeassert
+		}()
+	}
+}
+
+func (s *Server) handleTCP(ctx context.Context, clientConn *net.TCPConn) {
+	defer clientConn.Close()
+
+	source := clientConn.RemoteAddr().(*net.TCPAddr).AddrPort() //nolint:forcetypeassert
+	destination, err := originalDestination(clientConn)
+	if err != nil {
+		s.logger.Debug("getting original destination of TCP connection from " +
+			source.String() + ": " + err.Error())
+		return
+	}
+
+	allowed := s.allowed(source.Addr(), destination)
+	s.logConnection("tcp", source, destination, allowed)
+	if !allowed {
+		return
+	}
+
+	conn, err := s.dialer.DialContext(ctx, "tcp", destination.String())
+	if err != nil {
+		s.logger.Debug(err.Error())
+		return
+	}
+	destinationConn := conn.(*net.TCPConn) //nolint:forcetypeassert
+	defer destinationConn.Close()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = clientConn.Close()
+		_ = destinationConn.Close()
+	})
+	defer stop()
+
+	clientToDestinationDone := make(chan struct{})
+	go func() {
+		defer close(clientToDestinationDone)
+		_, _ = io.Copy(destinationConn, clientConn)
+		_ = destinationConn.CloseWrite()
+	}()
+	_, _ = io.Copy(clientConn, destinationConn)
+	_ = clientConn.CloseWrite()
+	<-clientToDestinationDone
+}
@@ -0,0 +1,143 @@
+package tproxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// udpSession is a UDP association between a client address
+// and an original destination address.
+type udpSession struct {
+	// upstream is the connection to the original destination,
+	// going out through the VPN tunnel.
+	upstream net.Conn
+	// reply is the connection to the client, bound to the original
+	// destination address so replies appear to come from it.
+	reply net.Conn
+}
+
+type udpSessionKey struct {
+	source      netip.AddrPort
+	destination netip.AddrPort
+}
+
+func (s *Server) serveUDP(ctx context.Context, conn *net.UDPConn) {
+	sessions := make(map[udpSessionKey]*udpSession)
+	sessionsMutex := new(sync.Mutex)
+	wg := new(sync.WaitGroup)
+	defer func() {
+		sessionsMutex.Lock()
+		for _, session := range sessions {
+			_ = session.upstream.Close()
+		}
+		sessionsMutex.Unlock()
+		wg.Wait()
+	}()
+
+	const maxUDPPacketSize = 65535
+	buffer := make([]byte, maxUDPPacketSize)
+	oob := make([]byte, originalDestinationOOBSize)
+	for {
+		n, oobn, _, source, err := conn.ReadMsgUDPAddrPort(buffer, oob)
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, net.ErrClosed) {
+				s.logger.Error("reading UDP packet: " + err.Error())
+				continue
+			}
+			return
+		}
+		source = netip.AddrPortFrom(source.Addr().Unmap(), source.Port())
+
+		destination, err := parseOriginalDestination(oob[:oobn])
+		if err != nil {
+			s.logger.Debug("getting original destination of UDP packet from " +
+				source.String() + ": " + err.Error())
+			continue
+		}
+
+		key := udpSessionKey{source: source, destination: destination}
+		sessionsMutex.Lock()
+		session, ok := sessions[key]
+		sessionsMutex.Unlock()
+		if !ok {
+			session, err = s.newUDPSession(ctx, source, destination)
+			if err != nil {
+				s.logger.Debug(err.Error())
+				continue
+			} else if session == nil { // denied
+				continue
+			}
+			sessionsMutex.Lock()
+			sessions[key] = session
+			sessionsMutex.Unlock()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.relayUDPReplies(session)
+				sessionsMutex.Lock()
+				delete(sessions, key)
+				sessionsMutex.Unlock()
+			}()
+		}
+
+		_ = session.upstream.SetReadDeadline(time.Now().Add(s.udpIdleTimeout))
+		_, err = session.upstream.Write(buffer[:n])
+		if err != nil {
+			s.logger.Debug("writing UDP packet to " + destination.String() + ": " + err.Error())
+		}
+	}
+}
+
+// newUDPSession returns a new UDP session, or nil if
+// the access control rules deny it.
+func (s *Server) newUDPSession(ctx context.Context,
+	source, destination netip.AddrPort,
+) (session *udpSession, err error) {
+	allowed := s.allowed(source.Addr(), destination)
+	s.logConnection("udp", source, destination, allowed)
+	if !allowed {
+		return nil, nil //nolint:nilnil
+	}
+
+	upstream, err := s.dialer.DialContext(ctx, "udp", destination.String())
+	if err != nil {
+		return nil, err
+	}
+
+	replyDialer := &net.Dialer{
+		LocalAddr: net.UDPAddrFromAddrPort(destination),
+		Control:   controlTransparentReply,
+	}
+	reply, err := replyDialer.DialContext(ctx, "udp", source.String())
+	if err != nil {
+		_ = upstream.Close()
+		return nil, err
+	}
+
+	return &udpSession{upstream: upstream, reply: reply}, nil
+}
+
+// relayUDPReplies relays packets from the original destination back
+// to the client, until the session is idle for too long or closed.
+func (s *Server) relayUDPReplies(session *udpSession) {
+	defer session.reply.Close()
+	defer session.upstream.Close()
+
+	const maxUDPPacketSize = 65535
+	buffer := make([]byte, maxUDPPacketSize)
+	for {
+		n, err := session.upstream.Read(buffer)
+		if err != nil {
+			return
+		}
+		_ = session.upstream.SetReadDeadline(time.Now().Add(s.udpIdleTimeout))
+		_, err = session.reply.Write(buffer[:n])
+		if err != nil {
+			s.logger.Debug("writing UDP reply to " + session.reply.RemoteAddr().String() + ": " + err.Error())
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package updater
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+// diffServers returns the differences between the previous and current
+// servers of a provider. Servers are matched using their VPN type and
+// name, see [serverIdentifier].
+func diffServers(provider string, previous, current []models.Server) (
+	diff models.ProviderServersDiff,
+) {
+	diff = models.ProviderServersDiff{
+		Provider:      provider,
+		PreviousCount: len(previous),
+		CurrentCount:  len(current),
+	}
+
+	previousByID := serversByIdentifier(previous)
+	currentByID := serversByIdentifier(current)
+
+	for id, currentServer := range currentByID {
+		previousServer, ok := previousByID[id]
+		if !ok {
+			diff.Added = append(diff.Added, id)
+			continue
+		}
+
+		changes := diffServer(previousServer, currentServer)
+		if len(changes.AddedIPs) > 0 || len(changes.RemovedIPs) > 0 || len(changes.Fields) > 0 {
+			changes.Server = id
+			diff.Changed = append(diff.Changed, changes)
+		}
+	}
+
+	for id := range previousByID {
+		_, ok := currentByID[id]
+		if !ok {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+
+	slices.Sort(diff.Added)
+	slices.Sort(diff.Removed)
+	slices.SortFunc(diff.Changed, func(a, b models.ServerChanges) int {
+		return strings.Compare(a.Server, b.Server)
+	})
+	return diff
+}
+
+// serversByIdentifier maps each server to its identifier, suffixing
+// identifiers with #2, #3, etc. for servers sharing the same identifier.
+func serversByIdentifier(servers []models.Server) (idToServer map[string]models.Server) {
+	idToServer = make(map[string]models.Server, len(servers))
+	idToCount := make(map[string]int, len(servers))
+	for _, server := range servers {
+		id := serverIdentifier(server)
+		idToCount[id]++
+		if count := idToCount[id]; count > 1 {
+			id = fmt.Sprintf("%s #%d", id, count)
+		}
+		idToServer[id] = server
+	}
+	return idToServer
+}
+
+// serverIdentifier returns an identifier for the server, made of its
+// server name or hostname and its VPN type, for example
+// "ch-zur-001 (wireguard)". If the server has no name nor hostname,
+// its location and number are used instead.
+func serverIdentifier(server models.Server) (id string) {
+	id = server.ServerName
+	if id == "" {
+		id = server.Hostname
+	}
+	if id == "" {
+		var parts []string
+		for _, part := range []string{server.Country, server.Region, server.City} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if server.Number > 0 {
+			parts = append(parts, fmt.Sprint(server.Number))
+		}
+		id = strings.Join(parts, " ")
+	}
+	if server.VPN != "" {
+		id += " (" + server.VPN + ")"
+	}
+	return id
+}
+
+// diffServer returns the IP addresses and fields changes between
+// the previous and current versions of the same server.
+func diffServer(previous, current models.Server) (changes models.ServerChanges) {
+	changes.AddedIPs = ipsDifference(current.IPs, previous.IPs)
+	changes.RemovedIPs = ipsDifference(previous.IPs, current.IPs)
+
+	previousValue := reflect.ValueOf(previous)
+	currentValue := reflect.ValueOf(current)
+	serverType := previousValue.Type()
+	for i := range serverType.NumField() {
+		field := serverType.Field(i)
+		if field.Name == "IPs" {
+			continue
+		}
+
+		previousField := previousValue.Field(i).Interface()
+		currentField := currentValue.Field(i).Interface()
+		if reflect.DeepEqual(previousField, currentField) {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		changes.Fields = append(changes.Fields, models.FieldChange{
+			Name:     name,
+			Previous: previousField,
+			Current:  currentField,
+		})
+	}
+	return changes
+}
+
+// ipsDifference returns the IP addresses in a but not in b.
+func ipsDifference(a, b []netip.Addr) (difference []netip.Addr) {
+	for _, ip := range a {
+		if !slices.Contains(b, ip) {
+			difference = append(difference, ip)
+		}
+	}
+	return difference
+}
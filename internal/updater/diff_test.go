@@ -0,0 +1,85 @@
+package updater
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffServers(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		previous []models.Server
+		current  []models.Server
+		diff     models.ProviderServersDiff
+	}{
+		"no_servers": {
+			diff: models.ProviderServersDiff{Provider: "provider"},
+		},
+		"same_servers_different_order": {
+			previous: []models.Server{
+				{VPN: "openvpn", ServerName: "a"},
+				{VPN: "wireguard", ServerName: "a"},
+			},
+			current: []models.Server{
+				{VPN: "wireguard", ServerName: "a"},
+				{VPN: "openvpn", ServerName: "a"},
+			},
+			diff: models.ProviderServersDiff{
+				Provider:      "provider",
+				PreviousCount: 2,
+				CurrentCount:  2,
+			},
+		},
+		"added_removed_and_changed": {
+			previous: []models.Server{
+				{VPN: "openvpn", ServerName: "removed"},
+				{
+					VPN:        "openvpn",
+					ServerName: "changed",
+					IPs:        []netip.Addr{netip.AddrFrom4([4]byte{1, 1, 1, 1}), netip.AddrFrom4([4]byte{2, 2, 2, 2})},
+				},
+				{VPN: "openvpn", Hostname: "unchanged.com"},
+			},
+			current: []models.Server{
+				{VPN: "openvpn", Hostname: "unchanged.com"},
+				{
+					VPN:         "openvpn",
+					ServerName:  "changed",
+					PortForward: true,
+					IPs:         []netip.Addr{netip.AddrFrom4([4]byte{2, 2, 2, 2}), netip.AddrFrom4([4]byte{3, 3, 3, 3})},
+				},
+				{Country: "Canada", City: "Montreal", Number: 2},
+				{Country: "Canada", City: "Montreal", Number: 2},
+			},
+			diff: models.ProviderServersDiff{
+				Provider:      "provider",
+				PreviousCount: 3,
+				CurrentCount:  4,
+				Added:         []string{"Canada Montreal 2", "Canada Montreal 2 #2"},
+				Removed:       []string{"removed (openvpn)"},
+				Changed: []models.ServerChanges{{
+					Server:     "changed (openvpn)",
+					AddedIPs:   []netip.Addr{netip.AddrFrom4([4]byte{3, 3, 3, 3})},
+					RemovedIPs: []netip.Addr{netip.AddrFrom4([4]byte{1, 1, 1, 1})},
+					Fields: []models.FieldChange{
+						{Name: "port_forward", Previous: false, Current: true},
+					},
+				}},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			diff := diffServers("provider", testCase.previous, testCase.current)
+
+			assert.Equal(t, testCase.diff, diff)
+		})
+	}
+}
@@ -14,8 +14,10 @@ type Providers interface {
 
 type Storage interface {
 	SetServers(provider string, servers []models.Server) (err error)
+	GetServers(provider string) (servers []models.Server)
 	GetServersCount(provider string) (count int)
 	ServersAreEqual(provider string, servers []models.Server) (equal bool)
+	SetUpdateReport(report models.ServersUpdateReport) (err error)
 	// Extra methods to match the provider.New storage interface
 	FilterServers(provider string, selection settings.ServerSelection) (filtered []models.Server, err error)
 }
@@ -3,6 +3,7 @@ package loop
 import (
 	"context"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,14 +14,29 @@ import (
 )
 
 type Updater interface {
-	UpdateServers(ctx context.Context, providers []string, minRatio float64) (err error)
+	UpdateServers(ctx context.Context, providers []string, minRatio float64) (
+		report models.ServersUpdateReport, err error)
+}
+
+type VPNLooper interface {
+	GetStatus() (status models.LoopStatus)
+	GetSettings() (settings settings.VPN)
+	GetConnection() (connection models.Connection)
+	Rotate(ctx context.Context) (outcome string, err error)
+}
+
+type Notifier interface {
+	Notify(event models.NotificationEvent, message string, fields map[string]string)
 }
 
 type Loop struct {
 	state state
 	// Objects
-	updater Updater
-	logger  Logger
+	updater  Updater
+	storage  updater.Storage
+	vpn      VPNLooper
+	notifier Notifier
+	logger   Logger
 	// Internal channels and locks
 	loopLock     sync.Mutex
 	start        chan struct{}
@@ -42,15 +58,21 @@ type Logger interface {
 	Error(s string)
 }
 
-func NewLoop(settings settings.Updater, providers updater.Providers,
-	storage updater.Storage, client *http.Client, logger Logger,
+func NewLoop(settings settings.Updater, requireSignature bool, providers updater.Providers,
+	storage updater.Storage, vpnLooper VPNLooper, client *http.Client,
+	notifier Notifier, logger Logger,
 ) *Loop {
+	serversUpdater := updater.New(client, storage, providers, logger,
+		*settings.PreferDirectDownload, requireSignature)
 	return &Loop{
 		state: state{
 			status:   constants.Stopped,
 			settings: settings,
 		},
-		updater:      updater.New(client, storage, providers, logger, *settings.PreferDirectDownload),
+		updater:      serversUpdater,
+		storage:      storage,
+		vpn:          vpnLooper,
+		notifier:     notifier,
 		logger:       logger,
 		start:        make(chan struct{}),
 		running:      make(chan models.LoopStatus),
@@ -98,7 +120,7 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		runWg.Add(1)
 		go func() {
 			defer runWg.Done()
-			err := l.updater.UpdateServers(updateCtx, settings.Providers, settings.MinRatio)
+			report, err := l.updater.UpdateServers(updateCtx, settings.Providers, settings.MinRatio)
 			if err != nil {
 				if updateCtx.Err() == nil {
 					errorCh <- err
@@ -106,6 +128,18 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 				return
 			}
 			l.state.setStatusWithLock(constants.Completed)
+			if len(report.Providers) > 0 {
+				updatedProviders := make([]string, len(report.Providers))
+				for i, providerDiff := range report.Providers {
+					updatedProviders[i] = providerDiff.Provider
+				}
+				l.notifier.Notify(models.NotificationServersUpdated,
+					"servers updated for "+strings.Join(updatedProviders, ", "),
+					map[string]string{"providers": strings.Join(updatedProviders, ",")})
+				// Use the loop context and not the update context, since canceling
+				// the update context while rotating would leave the VPN stopped.
+				l.checkServerSelection(ctx, report, *settings.ReconnectOnServerRemoved)
+			}
 		}()
 
 		if !crashed {
@@ -0,0 +1,4 @@
+package loop
+
+//go:generate mockgen -destination=mocks_test.go -package $GOPACKAGE . VPNLooper,Notifier,Logger
+//go:generate mockgen -destination=storage_mock_test.go -package $GOPACKAGE github.com/qdm12/gluetun/internal/updater Storage
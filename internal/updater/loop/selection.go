@@ -0,0 +1,94 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/storage"
+)
+
+// checkServerSelection checks the VPN server selection against the
+// servers data updated, if the VPN provider servers changed.
+// It warns if the selection no longer matches any server, or if it no
+// longer matches the current VPN server, in which case the VPN is
+// reconnected to another server if enabled in the settings.
+// The context given should be long lived, since the VPN is left stopped
+// if it is canceled while reconnecting.
+func (l *Loop) checkServerSelection(ctx context.Context, report models.ServersUpdateReport,
+	reconnect bool,
+) {
+	vpnSettings := l.vpn.GetSettings()
+	providerName := vpnSettings.Provider.Name
+	providerUpdated := slices.ContainsFunc(report.Providers, func(diff models.ProviderServersDiff) bool {
+		return diff.Provider == providerName
+	})
+	if !providerUpdated {
+		return
+	}
+
+	servers, err := l.storage.FilterServers(providerName, vpnSettings.Provider.ServerSelection)
+	switch {
+	case errors.Is(err, storage.ErrNoServerFound):
+		servers = nil
+	case err != nil:
+		l.logger.Warn("filtering servers after update: " + err.Error())
+		return
+	}
+
+	if len(servers) == 0 {
+		message := "the VPN server selection no longer matches any " + providerName +
+			" server after the servers update, the next VPN connection will fail"
+		l.logger.Warn(message)
+		l.notifier.Notify(models.NotificationServerUnavailable, message,
+			map[string]string{"provider": providerName})
+		return
+	}
+
+	if l.vpn.GetStatus() != constants.Running {
+		return
+	}
+
+	connection := l.vpn.GetConnection()
+	if slices.ContainsFunc(servers, func(server models.Server) bool {
+		return serverMatchesConnection(server, connection)
+	}) {
+		return
+	}
+
+	serverName := connection.ServerName
+	if serverName == "" {
+		serverName = connection.Hostname
+	}
+	if serverName == "" {
+		serverName = connection.IP.String()
+	}
+	message := "the current VPN server " + serverName +
+		" no longer matches the VPN server selection after the servers update"
+	l.logger.Warn(message)
+	l.notifier.Notify(models.NotificationServerUnavailable, message,
+		map[string]string{"provider": providerName, "server": serverName})
+
+	if !reconnect {
+		return
+	}
+
+	l.logger.Info("reconnecting VPN to another server")
+	_, err = l.vpn.Rotate(ctx)
+	if err != nil && ctx.Err() == nil {
+		l.logger.Error("reconnecting VPN: " + err.Error())
+	}
+}
+
+func serverMatchesConnection(server models.Server, connection models.Connection) bool {
+	switch {
+	case connection.ServerName != "":
+		return server.ServerName == connection.ServerName
+	case connection.Hostname != "":
+		return server.Hostname == connection.Hostname
+	default:
+		return slices.Contains(server.IPs, connection.IP)
+	}
+}
@@ -0,0 +1,157 @@
+package loop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/storage"
+)
+
+func Test_Loop_checkServerSelection(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+	vpnSettings := settings.VPN{
+		Provider: settings.Provider{
+			Name:            providers.Mullvad,
+			ServerSelection: settings.ServerSelection{Countries: []string{"Sweden"}},
+		},
+	}
+	report := models.ServersUpdateReport{
+		Providers: []models.ProviderServersDiff{{Provider: providers.Mullvad}},
+	}
+
+	testCases := map[string]struct {
+		report       models.ServersUpdateReport
+		reconnect    bool
+		makeStorage  func(ctrl *gomock.Controller) *MockStorage
+		makeVPN      func(ctrl *gomock.Controller) *MockVPNLooper
+		makeNotifier func(ctrl *gomock.Controller) *MockNotifier
+		makeLogger   func(ctrl *gomock.Controller) *MockLogger
+	}{
+		"provider_not_updated": {
+			report: models.ServersUpdateReport{
+				Providers: []models.ProviderServersDiff{{Provider: providers.Surfshark}},
+			},
+			makeVPN: func(ctrl *gomock.Controller) *MockVPNLooper {
+				vpn := NewMockVPNLooper(ctrl)
+				vpn.EXPECT().GetSettings().Return(vpnSettings)
+				return vpn
+			},
+		},
+		"no_server_matches": {
+			report: report,
+			makeStorage: func(ctrl *gomock.Controller) *MockStorage {
+				storageMock := NewMockStorage(ctrl)
+				storageMock.EXPECT().FilterServers(providers.Mullvad, vpnSettings.Provider.ServerSelection).
+					Return(nil, fmt.Errorf("%w: for country Sweden", storage.ErrNoServerFound))
+				return storageMock
+			},
+			makeVPN: func(ctrl *gomock.Controller) *MockVPNLooper {
+				vpn := NewMockVPNLooper(ctrl)
+				vpn.EXPECT().GetSettings().Return(vpnSettings)
+				return vpn
+			},
+			makeNotifier: func(ctrl *gomock.Controller) *MockNotifier {
+				notifier := NewMockNotifier(ctrl)
+				notifier.EXPECT().Notify(models.NotificationServerUnavailable,
+					"the VPN server selection no longer matches any mullvad server after "+
+						"the servers update, the next VPN connection will fail",
+					map[string]string{"provider": providers.Mullvad})
+				return notifier
+			},
+			makeLogger: func(ctrl *gomock.Controller) *MockLogger {
+				logger := NewMockLogger(ctrl)
+				logger.EXPECT().Warn("the VPN server selection no longer matches any mullvad server after " +
+					"the servers update, the next VPN connection will fail")
+				return logger
+			},
+		},
+		"filter_error": {
+			report: report,
+			makeStorage: func(ctrl *gomock.Controller) *MockStorage {
+				storageMock := NewMockStorage(ctrl)
+				storageMock.EXPECT().FilterServers(providers.Mullvad, vpnSettings.Provider.ServerSelection).
+					Return(nil, errTest)
+				return storageMock
+			},
+			makeVPN: func(ctrl *gomock.Controller) *MockVPNLooper {
+				vpn := NewMockVPNLooper(ctrl)
+				vpn.EXPECT().GetSettings().Return(vpnSettings)
+				return vpn
+			},
+			makeLogger: func(ctrl *gomock.Controller) *MockLogger {
+				logger := NewMockLogger(ctrl)
+				logger.EXPECT().Warn("filtering servers after update: test error")
+				return logger
+			},
+		},
+		"current_server_no_longer_matches": {
+			report:    report,
+			reconnect: true,
+			makeStorage: func(ctrl *gomock.Controller) *MockStorage {
+				storageMock := NewMockStorage(ctrl)
+				storageMock.EXPECT().FilterServers(providers.Mullvad, vpnSettings.Provider.ServerSelection).
+					Return([]models.Server{{Hostname: "se-sto-001"}}, nil)
+				return storageMock
+			},
+			makeVPN: func(ctrl *gomock.Controller) *MockVPNLooper {
+				vpn := NewMockVPNLooper(ctrl)
+				vpn.EXPECT().GetSettings().Return(vpnSettings)
+				vpn.EXPECT().GetStatus().Return(constants.Running)
+				vpn.EXPECT().GetConnection().Return(models.Connection{
+					Hostname: "se-sto-002",
+					IP:       netip.AddrFrom4([4]byte{1, 2, 3, 4}),
+				})
+				vpn.EXPECT().Rotate(context.Background()).Return("", nil)
+				return vpn
+			},
+			makeNotifier: func(ctrl *gomock.Controller) *MockNotifier {
+				notifier := NewMockNotifier(ctrl)
+				notifier.EXPECT().Notify(models.NotificationServerUnavailable,
+					"the current VPN server se-sto-002 no longer matches the VPN server selection "+
+						"after the servers update",
+					map[string]string{"provider": providers.Mullvad, "server": "se-sto-002"})
+				return notifier
+			},
+			makeLogger: func(ctrl *gomock.Controller) *MockLogger {
+				logger := NewMockLogger(ctrl)
+				logger.EXPECT().Warn("the current VPN server se-sto-002 no longer matches " +
+					"the VPN server selection after the servers update")
+				logger.EXPECT().Info("reconnecting VPN to another server")
+				return logger
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			loop := &Loop{}
+			if testCase.makeStorage != nil {
+				loop.storage = testCase.makeStorage(ctrl)
+			}
+			if testCase.makeVPN != nil {
+				loop.vpn = testCase.makeVPN(ctrl)
+			}
+			if testCase.makeNotifier != nil {
+				loop.notifier = testCase.makeNotifier(ctrl)
+			}
+			if testCase.makeLogger != nil {
+				loop.logger = testCase.makeLogger(ctrl)
+			}
+
+			loop.checkServerSelection(context.Background(), testCase.report, testCase.reconnect)
+		})
+	}
+}
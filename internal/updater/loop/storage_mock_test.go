@@ -0,0 +1,121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/qdm12/gluetun/internal/updater (interfaces: Storage)
+
+// Package loop is a generated GoMock package.
+package loop
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	settings "github.com/qdm12/gluetun/internal/configuration/settings"
+	models "github.com/qdm12/gluetun/internal/models"
+)
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// FilterServers mocks base method.
+func (m *MockStorage) FilterServers(arg0 string, arg1 settings.ServerSelection) ([]models.Server, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterServers", arg0, arg1)
+	ret0, _ := ret[0].([]models.Server)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterServers indicates an expected call of FilterServers.
+func (mr *MockStorageMockRecorder) FilterServers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterServers", reflect.TypeOf((*MockStorage)(nil).FilterServers), arg0, arg1)
+}
+
+// GetServers mocks base method.
+func (m *MockStorage) GetServers(arg0 string) []models.Server {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServers", arg0)
+	ret0, _ := ret[0].([]models.Server)
+	return ret0
+}
+
+// GetServers indicates an expected call of GetServers.
+func (mr *MockStorageMockRecorder) GetServers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServers", reflect.TypeOf((*MockStorage)(nil).GetServers), arg0)
+}
+
+// GetServersCount mocks base method.
+func (m *MockStorage) GetServersCount(arg0 string) int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServersCount", arg0)
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// GetServersCount indicates an expected call of GetServersCount.
+func (mr *MockStorageMockRecorder) GetServersCount(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServersCount", reflect.TypeOf((*MockStorage)(nil).GetServersCount), arg0)
+}
+
+// ServersAreEqual mocks base method.
+func (m *MockStorage) ServersAreEqual(arg0 string, arg1 []models.Server) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServersAreEqual", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ServersAreEqual indicates an expected call of ServersAreEqual.
+func (mr *MockStorageMockRecorder) ServersAreEqual(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServersAreEqual", reflect.TypeOf((*MockStorage)(nil).ServersAreEqual), arg0, arg1)
+}
+
+// SetServers mocks base method.
+func (m *MockStorage) SetServers(arg0 string, arg1 []models.Server) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetServers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetServers indicates an expected call of SetServers.
+func (mr *MockStorageMockRecorder) SetServers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetServers", reflect.TypeOf((*MockStorage)(nil).SetServers), arg0, arg1)
+}
+
+// SetUpdateReport mocks base method.
+func (m *MockStorage) SetUpdateReport(arg0 models.ServersUpdateReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUpdateReport", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUpdateReport indicates an expected call of SetUpdateReport.
+func (mr *MockStorageMockRecorder) SetUpdateReport(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUpdateReport", reflect.TypeOf((*MockStorage)(nil).SetUpdateReport), arg0)
+}
@@ -19,7 +19,7 @@ type Provider interface {
 
 func (u *Updater) updateProvider(ctx context.Context, provider Provider,
 	manifest manifest, minRatio float64,
-) (err error) {
+) (diff *models.ProviderServersDiff, err error) {
 	providerName := provider.Name()
 	existingServersCount := u.storage.GetServersCount(providerName)
 	minServers := int(minRatio * float64(existingServersCount))
@@ -33,7 +33,7 @@ func (u *Updater) updateProvider(ctx context.Context, provider Provider,
 				"-minratio to allow the update to succeed with less servers found")
 			fallthrough
 		case err != nil:
-			return fmt.Errorf("getting %s servers: %w", providerName, err)
+			return nil, fmt.Errorf("getting %s servers: %w", providerName, err)
 		}
 	} else {
 		providerFilepath := manifest.providerToFilepath[providerName]
@@ -42,11 +42,11 @@ func (u *Updater) updateProvider(ctx context.Context, provider Provider,
 		var data models.Servers
 		err = u.fetchJSON(ctx, providerFileURL, &data)
 		if err != nil {
-			return fmt.Errorf("downloading provider file %s: %w", providerFileURL, err)
+			return nil, fmt.Errorf("downloading provider file %s: %w", providerFileURL, err)
 		}
 		servers = data.Servers
 		if len(servers) < minServers {
-			return fmt.Errorf("provider %s has not enough servers from downloaded file: got %d and expected at least %d",
+			return nil, fmt.Errorf("provider %s has not enough servers from downloaded file: got %d and expected at least %d",
 				providerName, len(servers), minServers)
 		}
 	}
@@ -58,23 +58,28 @@ func (u *Updater) updateProvider(ctx context.Context, provider Provider,
 			if jsonErr != nil {
 				panic(jsonErr)
 			}
-			return fmt.Errorf("server %s has not enough information: %w", serverJSON, err)
+			return nil, fmt.Errorf("server %s has not enough information: %w", serverJSON, err)
 		}
 	}
 
 	if u.storage.ServersAreEqual(providerName, servers) {
-		return nil
+		return nil, nil //nolint:nilnil
 	}
 
+	// The diff must be computed before the [Storage.SetServers] call
+	// below, which replaces the previous servers in storage.
+	previousServers := u.storage.GetServers(providerName)
+	providerDiff := diffServers(providerName, previousServers, servers)
+
 	// Note the servers variable must NOT BE MUTATED after this call,
 	// since the implementation does not deep copy the servers.
 	// TODO set in storage in provider updater directly, server by server,
 	// to avoid accumulating server data in memory.
 	err = u.storage.SetServers(providerName, servers)
 	if err != nil {
-		return fmt.Errorf("setting servers to storage: %w", err)
+		return nil, fmt.Errorf("setting servers to storage: %w", err)
 	}
-	return nil
+	return &providerDiff, nil
 }
 
 func buildProviderFileURL(providerName, filePath string) (providerFileURL string) {
@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/qdm12/gluetun/internal/constants/providers"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/provider/common"
+	"github.com/qdm12/gluetun/internal/signature"
 	"github.com/qdm12/gluetun/internal/updater/unzip"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -20,6 +22,8 @@ import (
 type Updater struct {
 	providers            Providers
 	preferDirectDownload bool
+	verifier             *signature.Verifier
+	requireSignature     bool
 
 	// state
 	storage Storage
@@ -32,7 +36,7 @@ type Updater struct {
 }
 
 func New(httpClient *http.Client, storage Storage,
-	providers Providers, logger Logger, preferDirectDownload bool,
+	providers Providers, logger Logger, preferDirectDownload, requireSignature bool,
 ) *Updater {
 	unzipper := unzip.New(httpClient)
 	return &Updater{
@@ -43,20 +47,37 @@ func New(httpClient *http.Client, storage Storage,
 		client:               httpClient,
 		unzipper:             unzipper,
 		preferDirectDownload: preferDirectDownload,
+		verifier:             signature.NewEmbedded(),
+		requireSignature:     requireSignature,
 	}
 }
 
+// UpdateServers updates the servers of the providers given, and returns
+// a report of the changes made to the servers data. The report is also
+// set in storage if any provider had its servers changed.
 func (u *Updater) UpdateServers(ctx context.Context, providers []string,
 	minRatio float64,
-) (err error) {
+) (report models.ServersUpdateReport, err error) {
+	report.Time = u.timeNow()
+
 	var manifest manifest
 	if u.preferDirectDownload {
 		manifest, err = u.fetchManifest(ctx)
 		if err != nil {
-			return fmt.Errorf("fetching remote manifest: %w", err)
+			return report, fmt.Errorf("fetching remote manifest: %w", err)
 		}
 	}
 
+	defer func() {
+		if len(report.Providers) == 0 {
+			return
+		}
+		setErr := u.storage.SetUpdateReport(report)
+		if setErr != nil {
+			u.logger.Warn("setting update report to storage: " + setErr.Error())
+		}
+	}()
+
 	caser := cases.Title(language.English)
 	for _, providerName := range providers {
 		u.logger.Info("updating " + caser.String(providerName) + " servers...")
@@ -64,26 +85,36 @@ func (u *Updater) UpdateServers(ctx context.Context, providers []string,
 		fetcher := u.providers.Get(providerName)
 		// TODO support servers offering only TCP or only UDP
 		// for NordVPN and PureVPN
-		err := u.updateProvider(ctx, fetcher, manifest, minRatio)
+		diff, err := u.updateProvider(ctx, fetcher, manifest, minRatio)
 		switch {
 		case err == nil:
+			if diff != nil && !diff.IsEmpty() {
+				u.logger.Info(caser.String(providerName) + " servers changes: " + summarizeDiff(*diff))
+				report.Providers = append(report.Providers, *diff)
+			}
 			continue
 		case errors.Is(err, common.ErrCredentialsMissing):
 			u.logger.Warn(err.Error() + " - skipping update for " + providerName)
 			continue
 		case len(providers) == 1:
 			// return the only error for the single provider.
-			return err
+			return report, err
 		case ctx.Err() != nil:
 			// stop updating other providers if context is done
-			return ctx.Err()
+			return report, ctx.Err()
 		default: // error encountered updating one of multiple providers
 			// Log the error and continue updating the next provider.
 			u.logger.Error(err.Error())
 		}
 	}
 
-	return nil
+	return report, nil
+}
+
+func summarizeDiff(diff models.ProviderServersDiff) string {
+	return fmt.Sprintf("%d added, %d removed, %d changed (%d servers before, %d after)",
+		len(diff.Added), len(diff.Removed), len(diff.Changed),
+		diff.PreviousCount, diff.CurrentCount)
 }
 
 type manifest struct {
@@ -116,29 +147,79 @@ func (u *Updater) fetchManifest(ctx context.Context) (m manifest, err error) {
 	return m, nil
 }
 
+// fetchJSON fetches the JSON data at the URL given, verifies its signature
+// and decodes it into dst.
 func (u *Updater) fetchJSON(ctx context.Context, rawURL string, dst any) (err error) {
+	data, err := u.fetch(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+
+	err = u.verify(ctx, rawURL, data)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, dst)
+	if err != nil {
+		return fmt.Errorf("decoding response body: %w", err)
+	}
+
+	return nil
+}
+
+// verify verifies the data fetched from the URL given using the signature
+// file at the same URL suffixed with [signature.FileSuffix]. A missing
+// signature file is only an error if signatures are required.
+func (u *Updater) verify(ctx context.Context, rawURL string, data []byte) (err error) {
+	signatureData, err := u.fetch(ctx, rawURL+signature.FileSuffix)
+	switch {
+	case errors.Is(err, errNotFound):
+		if u.requireSignature {
+			return fmt.Errorf("%w: for %s", signature.ErrSignatureMissing, rawURL)
+		}
+		u.logger.Warn("servers data from " + rawURL + " is not signed")
+		return nil
+	case err != nil:
+		return fmt.Errorf("fetching signature: %w", err)
+	}
+
+	err = u.verifier.Verify(data, signatureData)
+	if err != nil {
+		return fmt.Errorf("verifying %s: %w", rawURL, err)
+	}
+	return nil
+}
+
+var errNotFound = errors.New("not found")
+
+func (u *Updater) fetch(ctx context.Context, rawURL string) (data []byte, err error) {
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
-		return fmt.Errorf("creating request: %w", err)
+		return nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	response, err := u.client.Do(request)
 	if err != nil {
-		return fmt.Errorf("doing request: %w", err)
+		return nil, fmt.Errorf("doing request: %w", err)
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		const limit = 10 * 1024 * 1024 // 10 MiB
+	const limit = 10 * 1024 * 1024 // 10 MiB
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", errNotFound, rawURL)
+	default:
 		body, _ := io.ReadAll(io.LimitReader(response.Body, limit))
-		return fmt.Errorf("HTTP status code %d for %s: %s",
+		return nil, fmt.Errorf("HTTP status code %d for %s: %s",
 			response.StatusCode, rawURL, strings.TrimSpace(string(body)))
 	}
 
-	err = json.NewDecoder(response.Body).Decode(dst)
+	data, err = io.ReadAll(response.Body)
 	if err != nil {
-		return fmt.Errorf("decoding response body: %w", err)
+		return nil, fmt.Errorf("reading response body: %w", err)
 	}
 
-	return nil
+	return data, nil
 }
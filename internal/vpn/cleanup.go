@@ -3,47 +3,57 @@ package vpn
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/logging"
+	"github.com/qdm12/gluetun/internal/models"
 )
 
 func (l *Loop) cleanup() {
 	settings := l.GetSettings()
 
+	l.notifier.Notify(models.NotificationTunnelDown, "VPN tunnel is down",
+		map[string]string{"interface": getVPNInterface(settings)})
+
 	var err error
 	if *settings.DownCommand != "" {
 		commandString := strings.ReplaceAll(*settings.DownCommand, "{{VPN_INTERFACE}}", getVPNInterface(settings))
 		err = l.cmder.RunAndLog(context.Background(), commandString, l.logger)
 		if err != nil {
-			l.logger.Error("failed to run VPN down command: " + err.Error())
+			l.logger.Error("failed to run VPN down command" +
+				logging.Field("interface", getVPNInterface(settings)) +
+				logging.Field("error", err.Error()))
 		}
 	}
 
 	for _, vpnPort := range l.vpnInputPorts {
 		err := l.fw.RemoveAllowedPort(context.Background(), vpnPort)
 		if err != nil {
-			l.logger.Error("cannot remove allowed input port from firewall: " + err.Error())
+			l.logger.Error("cannot remove allowed input port from firewall" +
+				logging.Field("port", fmt.Sprint(int(vpnPort))) +
+				logging.Field("error", err.Error()))
 		}
 	}
 
 	err = l.publicip.ClearData()
 	if err != nil {
-		l.logger.Error("clearing public IP data: " + err.Error())
+		l.logger.Error("clearing public IP data" + logging.Field("error", err.Error()))
 	}
 
 	err = l.stopPortForwarding()
 	if err != nil {
 		portForwardingAlreadyStopped := errors.Is(err, context.Canceled)
 		if !portForwardingAlreadyStopped {
-			l.logger.Error("stopping port forwarding: " + err.Error())
+			l.logger.Error("stopping port forwarding" + logging.Field("error", err.Error()))
 		}
 	}
 
 	err = l.boringPoll.Stop()
 	if err != nil {
-		l.logger.Error("stopping boring poll: " + err.Error())
+		l.logger.Error("stopping boring poll" + logging.Field("error", err.Error()))
 	}
 }
 
@@ -53,6 +63,8 @@ func getVPNInterface(settings settings.VPN) string {
 		return settings.OpenVPN.Interface
 	case vpn.Wireguard:
 		return settings.Wireguard.Interface
+	case vpn.AmneziaWg:
+		return settings.AmneziaWg.Wireguard.Interface
 	default:
 		panic("invalid VPN type: " + settings.Type)
 	}
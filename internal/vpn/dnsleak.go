@@ -0,0 +1,57 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const dnsLeakCheckName = "DNS leak check"
+
+var errDNSLeak = errors.New("DNS leak detected")
+
+// monitorDNSLeaks runs the DNS leak check once and then periodically
+// if a period is set, until the context is canceled. A DNS leak marks the
+// health server as unhealthy and, if enabled, restarts the VPN.
+func (l *Loop) monitorDNSLeaks(ctx, loopCtx context.Context) {
+	for {
+		leakSettings := l.dnsLooper.GetSettings().LeakCheck
+		if !*leakSettings.Enabled {
+			l.healthServer.SetCheckError(dnsLeakCheckName, nil)
+			return
+		}
+
+		report, err := l.dnsLooper.CheckLeak(ctx)
+		switch {
+		case ctx.Err() != nil:
+			l.healthServer.SetCheckError(dnsLeakCheckName, nil)
+			return
+		case err != nil:
+			// the leak check service may be unreachable,
+			// which is not a DNS leak, so ignore the error.
+		case report.Leaking:
+			l.healthServer.SetCheckError(dnsLeakCheckName, errDNSLeak)
+			if *leakSettings.RestartVPN {
+				l.restart(loopCtx, errDNSLeak)
+				return
+			}
+		default:
+			l.healthServer.SetCheckError(dnsLeakCheckName, nil)
+		}
+
+		if *leakSettings.Period == 0 {
+			<-ctx.Done()
+			l.healthServer.SetCheckError(dnsLeakCheckName, nil)
+			return
+		}
+
+		timer := time.NewTimer(*leakSettings.Period)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			l.healthServer.SetCheckError(dnsLeakCheckName, nil)
+			return
+		case <-timer.C:
+		}
+	}
+}
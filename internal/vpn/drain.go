@@ -0,0 +1,107 @@
+package vpn
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// drainProxyConnections waits for established connections to the
+// HTTP proxy and Shadowsocks servers to close, up to the timeout given.
+func (l *Loop) drainProxyConnections(ctx context.Context, timeout time.Duration) {
+	if timeout == 0 || len(l.proxyPorts) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	const checkPeriod = time.Second
+	ticker := time.NewTicker(checkPeriod)
+	defer ticker.Stop()
+
+	loggedCount := 0
+	for {
+		count, err := countEstablishedConnections(l.proxyPorts)
+		if err != nil {
+			l.logger.Warn("counting active proxy connections: " + err.Error())
+			return
+		} else if count == 0 {
+			return
+		}
+
+		if count != loggedCount {
+			l.logger.Infof("waiting for %d active proxy connection(s) to close", count)
+			loggedCount = count
+		}
+
+		select {
+		case <-ctx.Done():
+			l.logger.Infof("%d proxy connection(s) still active after %s", count, timeout)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// countEstablishedConnections counts the established TCP connections
+// with a local port in the ports given, using /proc/net/tcp and
+// /proc/net/tcp6.
+func countEstablishedConnections(ports []uint16) (count int, err error) {
+	for _, path := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		file, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return 0, fmt.Errorf("opening file: %w", err)
+		}
+
+		fileCount, err := countEstablished(file, ports)
+		_ = file.Close()
+		if err != nil {
+			return 0, fmt.Errorf("reading %s: %w", path, err)
+		}
+		count += fileCount
+	}
+	return count, nil
+}
+
+func countEstablished(reader io.Reader, ports []uint16) (count int, err error) {
+	scanner := bufio.NewScanner(reader)
+	_ = scanner.Scan() // skip header line
+	for scanner.Scan() {
+		// Line format is "sl local_address rem_address st ..."
+		// with local_address being "IPHEX:PORTHEX"
+		fields := strings.Fields(scanner.Text())
+		const minFields = 4
+		if len(fields) < minFields {
+			continue
+		}
+
+		const established = "01"
+		if fields[3] != established {
+			continue
+		}
+
+		i := strings.LastIndexByte(fields[1], ':')
+		if i == -1 {
+			continue
+		}
+		port, err := strconv.ParseUint(fields[1][i+1:], 16, 16)
+		if err != nil {
+			continue
+		}
+
+		if slices.Contains(ports, uint16(port)) {
+			count++
+		}
+	}
+	return count, scanner.Err()
+}
@@ -0,0 +1,36 @@
+package vpn
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_countEstablished(t *testing.T) {
+	t.Parallel()
+
+	//nolint:lll
+	const procNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:22B8 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1000 1 0000000000000000 100 0 0 10 0
+   1: 020011AC:22B8 010011AC:D2F0 01 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 20 4 30 10 -1
+   2: 020011AC:22B8 010011AC:D2F2 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 20 4 30 10 -1
+   3: 020011AC:22B8 010011AC:D2F4 06 00000000:00000000 00:00000000 00000000     0        0 0 3 0000000000000000
+   4: 020011AC:9F3A 01010101:01BB 01 00000000:00000000 00:00000000 00000000     0        0 1003 1 0000000000000000 20 4 30 10 -1
+`
+	//nolint:lll
+	const procNetTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0000000000000000FFFF0000020011AC:1F40 0000000000000000FFFF0000010011AC:C350 01 00000000:00000000 00:00000000 00000000     0        0 2000 1 0000000000000000 20 4 30 10 -1
+`
+
+	ports := []uint16{8888, 8000}
+
+	count, err := countEstablished(strings.NewReader(procNetTCP), ports)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = countEstablished(strings.NewReader(procNetTCP6), ports)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
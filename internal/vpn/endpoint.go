@@ -0,0 +1,92 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/netlink"
+)
+
+var errEndpointHostnameNoIP = errors.New("no IP address found for endpoint hostname")
+
+// resolveEndpointHostname resolves the Wireguard endpoint hostname to an IP
+// address using a plain DNS resolver, which is temporarily allowed through
+// the firewall since the VPN connection is not up yet.
+// The resolver used is picked by [bootstrapResolverAddress].
+func (l *Loop) resolveEndpointHostname(ctx context.Context, hostname string) (
+	ip netip.Addr, err error,
+) {
+	resolverAddress := bootstrapResolverAddress(l.dnsLooper.GetSettings())
+
+	const intf = "*" // all interfaces
+	remove := false
+	err = l.fw.AcceptOutput(ctx, constants.UDP, intf,
+		resolverAddress.Addr(), resolverAddress.Port(), remove)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("allowing DNS traffic through firewall: %w", err)
+	}
+	defer func() {
+		remove = true
+		firewallErr := l.fw.AcceptOutput(ctx, constants.UDP, intf,
+			resolverAddress.Addr(), resolverAddress.Port(), remove)
+		if err == nil && firewallErr != nil {
+			err = fmt.Errorf("removing DNS traffic firewall rule: %w", firewallErr)
+		}
+	}()
+
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			dialer := net.Dialer{}
+			return dialer.DialContext(ctx, constants.UDP, resolverAddress.String())
+		},
+	}
+
+	const timeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	network := "ip4"
+	if l.ipv6SupportLevel == netlink.IPv6Internet {
+		network = "ip"
+	}
+	ips, err := resolver.LookupNetIP(ctx, network, hostname)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("resolving endpoint hostname: %w", err)
+	} else if len(ips) == 0 {
+		return netip.Addr{}, fmt.Errorf("%w: %s", errEndpointHostnameNoIP, hostname)
+	}
+
+	ip = ips[0].Unmap()
+	l.logger.Infof("resolved endpoint hostname %s to %s using %s", hostname, ip, resolverAddress)
+	return ip, nil
+}
+
+// bootstrapResolverAddress returns the first upstream plain address if the DNS
+// upstream type is plain and this address is publicly reachable, and Cloudflare
+// otherwise. Private, loopback, link-local and CGNAT addresses are skipped,
+// since these are usually only reachable through the VPN tunnel, for example
+// the DNS addresses of a wg-quick configuration file.
+func bootstrapResolverAddress(dnsSettings settings.DNS) netip.AddrPort {
+	if dnsSettings.UpstreamType == settings.DNSUpstreamTypePlain {
+		for _, addrPort := range dnsSettings.UpstreamPlainAddresses {
+			if isPublicAddress(addrPort.Addr()) {
+				return addrPort
+			}
+		}
+	}
+	return netip.AddrPortFrom(netip.AddrFrom4([4]byte{1, 1, 1, 1}), 53) //nolint:mnd
+}
+
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10") //nolint:gochecknoglobals
+
+func isPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !cgnatPrefix.Contains(addr)
+}
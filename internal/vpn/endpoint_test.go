@@ -0,0 +1,69 @@
+package vpn
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_bootstrapResolverAddress(t *testing.T) {
+	t.Parallel()
+
+	cloudflare := netip.MustParseAddrPort("1.1.1.1:53")
+
+	testCases := map[string]struct {
+		dnsSettings settings.DNS
+		address     netip.AddrPort
+	}{
+		"not_plain": {
+			dnsSettings: settings.DNS{
+				UpstreamType:           settings.DNSUpstreamTypeDot,
+				UpstreamPlainAddresses: []netip.AddrPort{netip.MustParseAddrPort("9.9.9.9:53")},
+			},
+			address: cloudflare,
+		},
+		"plain_no_address": {
+			dnsSettings: settings.DNS{UpstreamType: settings.DNSUpstreamTypePlain},
+			address:     cloudflare,
+		},
+		"plain_public_address": {
+			dnsSettings: settings.DNS{
+				UpstreamType:           settings.DNSUpstreamTypePlain,
+				UpstreamPlainAddresses: []netip.AddrPort{netip.MustParseAddrPort("9.9.9.9:53")},
+			},
+			address: netip.MustParseAddrPort("9.9.9.9:53"),
+		},
+		"plain_tunnel_only_addresses_skipped": {
+			dnsSettings: settings.DNS{
+				UpstreamType: settings.DNSUpstreamTypePlain,
+				UpstreamPlainAddresses: []netip.AddrPort{
+					netip.MustParseAddrPort("10.64.0.1:53"),
+					netip.MustParseAddrPort("100.64.0.1:53"),
+					netip.MustParseAddrPort("127.0.0.1:53"),
+					netip.MustParseAddrPort("[fd00::1]:53"),
+					netip.MustParseAddrPort("8.8.8.8:53"),
+				},
+			},
+			address: netip.MustParseAddrPort("8.8.8.8:53"),
+		},
+		"plain_only_private_addresses": {
+			dnsSettings: settings.DNS{
+				UpstreamType:           settings.DNSUpstreamTypePlain,
+				UpstreamPlainAddresses: []netip.AddrPort{netip.MustParseAddrPort("10.2.0.1:53")},
+			},
+			address: cloudflare,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			address := bootstrapResolverAddress(testCase.dnsSettings)
+
+			assert.Equal(t, testCase.address, address)
+		})
+	}
+}
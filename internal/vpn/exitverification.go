@@ -0,0 +1,207 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/logging"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/publicip/api"
+)
+
+const exitVerificationName = "exit verification"
+
+var (
+	errExitCountryMismatch = errors.New("public IP country does not match the server selection")
+	errExitCityMismatch    = errors.New("public IP city does not match the server selection")
+	errExitIPUnknown       = errors.New("public IP address does not belong to the connected server")
+)
+
+// verifyExit verifies the public IP address information matches the
+// server selection, once the public IP information has been fetched.
+// A mismatch marks the health server as unhealthy until the context is
+// canceled and, if enabled, restarts the VPN.
+func (l *Loop) verifyExit(ctx, loopCtx context.Context) {
+	vpnSettings := l.GetSettings()
+	verification := vpnSettings.ExitVerification
+	if !*verification.Enabled {
+		return
+	}
+
+	publicIP := l.publicip.GetData()
+	if !publicIP.IP.IsValid() {
+		l.logger.Warn("cannot verify exit: public IP address information is not available")
+		return
+	}
+
+	selection := vpnSettings.Provider.ServerSelection
+	var server exitServer
+	if *verification.CheckIP {
+		server = l.connectedExitServer(vpnSettings.Provider.Name, selection)
+	}
+
+	err := checkExit(publicIP, selection, server)
+	if err == nil {
+		l.logger.Info("exit verification passed")
+		l.healthServer.SetCheckError(exitVerificationName, nil)
+		return
+	}
+
+	l.logger.Warn("exit verification failed" +
+		logging.Field("server", server.name) +
+		logging.Field("public_ip", publicIP.IP.String()) +
+		logging.Field("error", err.Error()))
+	l.healthServer.SetCheckError(exitVerificationName, err)
+	if *verification.RestartVPN {
+		l.restart(loopCtx, err)
+		return
+	}
+
+	<-ctx.Done()
+	l.healthServer.SetCheckError(exitVerificationName, nil)
+}
+
+// exitServer is the VPN server connected to, to verify the
+// public IP address against.
+type exitServer struct {
+	name string
+	ips  []netip.Addr
+}
+
+// connectedExitServer returns the server connected to, with all its IP
+// addresses if it is found in the servers matching the selection, or only
+// with the connection IP address otherwise. It returns the zero value if
+// there is no connection.
+func (l *Loop) connectedExitServer(providerName string,
+	selection settings.ServerSelection,
+) (server exitServer) {
+	connection := l.GetConnection()
+	if !connection.IP.IsValid() {
+		return exitServer{}
+	}
+
+	server.name = getServerName(connection)
+	server.ips = []netip.Addr{connection.IP}
+
+	servers, err := l.storage.FilterServers(providerName, selection)
+	if err != nil {
+		l.logger.Debug("cannot find connected server IP addresses: " + err.Error())
+	}
+	for _, candidate := range servers {
+		if !slices.Contains(candidate.IPs, connection.IP) {
+			continue
+		}
+		server.ips = candidate.IPs
+		if server.name == "" {
+			server.name = candidate.ServerName
+		}
+		if server.name == "" {
+			server.name = candidate.Hostname
+		}
+		break
+	}
+
+	if server.name == "" {
+		server.name = connection.IP.String()
+	}
+	return server
+}
+
+// checkExit returns an error if the public IP information does not match
+// the countries or cities of the selection given, or if the public IP
+// address is not one of the connected server IP addresses or in one of
+// their /24 IPv4 or /64 IPv6 subnets. Each check is skipped if there is
+// no data to verify against.
+func checkExit(publicIP models.PublicIP, selection settings.ServerSelection,
+	server exitServer,
+) (err error) {
+	if publicIP.Country != "" && len(selection.Countries) > 0 &&
+		!containsLocation(selection.Countries, publicIP.Country, countryAliases) {
+		return fmt.Errorf("%w: %s is not one of %s", errExitCountryMismatch,
+			publicIP.Country, strings.Join(selection.Countries, ", "))
+	}
+
+	if publicIP.City != "" && len(selection.Cities) > 0 &&
+		!containsLocation(selection.Cities, publicIP.City, nil) {
+		return fmt.Errorf("%w: %s is not one of %s", errExitCityMismatch,
+			publicIP.City, strings.Join(selection.Cities, ", "))
+	}
+
+	if len(server.ips) > 0 && !ipInSubnets(publicIP.IP, server.ips) {
+		return fmt.Errorf("%w: %s is not in the subnets of server %s",
+			errExitIPUnknown, publicIP.IP, server.name)
+	}
+
+	return nil
+}
+
+// countryAliases maps normalized country names returned by public IP
+// APIs to the normalized country names used in the servers data.
+var countryAliases = map[string]string{ //nolint:gochecknoglobals
+	"czechia":                  "czech republic",
+	"the netherlands":          "netherlands",
+	"united states of america": "united states",
+	"usa":                      "united states",
+	"united kingdom of great britain and northern ireland": "united kingdom",
+	"great britain":             "united kingdom",
+	"uk":                        "united kingdom",
+	"south korea":               "korea",
+	"republic of korea":         "korea",
+	"russia":                    "russian federation",
+	"north macedonia":           "macedonia",
+	"turkiye":                   "turkey",
+	"ivory coast":               "cote d'ivoire",
+	"vatican city":              "vatican city state",
+	"taiwan, province of china": "taiwan",
+}
+
+// containsLocation returns true if the target location matches one of
+// the values given, ignoring case, accents and parenthesized suffixes,
+// and resolving aliases using the aliases map given, which can be nil.
+func containsLocation(values []string, target string,
+	aliases map[string]string,
+) bool {
+	target = normalizeLocation(target, aliases)
+	for _, value := range values {
+		if normalizeLocation(value, aliases) == target {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeLocation(location string, aliases map[string]string) string {
+	location = api.Normalize(location)
+	alias, ok := aliases[location]
+	if ok {
+		return alias
+	}
+	return location
+}
+
+// ipInSubnets returns true if the ip is in the /24 IPv4 or /64 IPv6
+// subnet of one of the server IP addresses given.
+func ipInSubnets(ip netip.Addr, serverIPs []netip.Addr) bool {
+	const ipv4Bits, ipv6Bits = 24, 64
+	ip = ip.Unmap()
+	bits := ipv4Bits
+	if ip.Is6() {
+		bits = ipv6Bits
+	}
+	ipPrefix, err := ip.Prefix(bits)
+	if err != nil {
+		return false
+	}
+
+	for _, serverIP := range serverIPs {
+		if ipPrefix.Contains(serverIP.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package vpn
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkExit(t *testing.T) {
+	t.Parallel()
+
+	server := exitServer{
+		name: "server1",
+		ips: []netip.Addr{
+			netip.MustParseAddr("1.2.3.4"),
+			netip.MustParseAddr("2001:db8::1"),
+		},
+	}
+
+	testCases := map[string]struct {
+		publicIP   models.PublicIP
+		selection  settings.ServerSelection
+		server     exitServer
+		errMessage string
+	}{
+		"no_selection": {
+			publicIP: models.PublicIP{
+				IP:      netip.MustParseAddr("5.6.7.8"),
+				Country: "Germany",
+			},
+		},
+		"country_match": {
+			publicIP: models.PublicIP{
+				IP:      netip.MustParseAddr("5.6.7.8"),
+				Country: "United States",
+				City:    "New York",
+			},
+			selection: settings.ServerSelection{
+				Countries: []string{"Canada", " united states"},
+				Cities:    []string{"new york"},
+			},
+		},
+		"country_alias_match": {
+			publicIP: models.PublicIP{
+				IP:      netip.MustParseAddr("5.6.7.8"),
+				Country: "Czechia",
+			},
+			selection: settings.ServerSelection{
+				Countries: []string{"Czech Republic"},
+			},
+		},
+		"country_parenthesized_alias_match": {
+			publicIP: models.PublicIP{
+				IP:      netip.MustParseAddr("5.6.7.8"),
+				Country: "Korea (Republic of)",
+			},
+			selection: settings.ServerSelection{
+				Countries: []string{"South Korea"},
+			},
+		},
+		"accented_city_match": {
+			publicIP: models.PublicIP{
+				IP:   netip.MustParseAddr("5.6.7.8"),
+				City: "Zürich",
+			},
+			selection: settings.ServerSelection{
+				Cities: []string{"Zurich"},
+			},
+		},
+		"accented_selection_city_match": {
+			publicIP: models.PublicIP{
+				IP:   netip.MustParseAddr("5.6.7.8"),
+				City: "Sao Paulo",
+			},
+			selection: settings.ServerSelection{
+				Cities: []string{"São Paulo"},
+			},
+		},
+		"country_mismatch": {
+			publicIP: models.PublicIP{
+				IP:      netip.MustParseAddr("5.6.7.8"),
+				Country: "Germany",
+			},
+			selection: settings.ServerSelection{
+				Countries: []string{"Canada", "France"},
+			},
+			errMessage: "public IP country does not match the server selection: " +
+				"Germany is not one of Canada, France",
+		},
+		"city_mismatch": {
+			publicIP: models.PublicIP{
+				IP:   netip.MustParseAddr("5.6.7.8"),
+				City: "Berlin",
+			},
+			selection: settings.ServerSelection{
+				Cities: []string{"Paris"},
+			},
+			errMessage: "public IP city does not match the server selection: " +
+				"Berlin is not one of Paris",
+		},
+		"empty_public_ip_country": {
+			publicIP: models.PublicIP{
+				IP: netip.MustParseAddr("5.6.7.8"),
+			},
+			selection: settings.ServerSelection{
+				Countries: []string{"Canada"},
+			},
+		},
+		"ipv4_same_subnet": {
+			publicIP: models.PublicIP{IP: netip.MustParseAddr("1.2.3.200")},
+			server:   server,
+		},
+		"ipv6_same_subnet": {
+			publicIP: models.PublicIP{IP: netip.MustParseAddr("2001:db8::abcd")},
+			server:   server,
+		},
+		"ip_unknown": {
+			publicIP: models.PublicIP{IP: netip.MustParseAddr("1.2.4.4")},
+			server:   server,
+			errMessage: "public IP address does not belong to the connected server: " +
+				"1.2.4.4 is not in the subnets of server server1",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkExit(testCase.publicIP, testCase.selection, testCase.server)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
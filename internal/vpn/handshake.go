@@ -0,0 +1,85 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var (
+	ErrWireguardNotRunning = errors.New("no Wireguard connection is running")
+	errHandshakeTooOld     = errors.New("last Wireguard handshake is too old")
+)
+
+// GetWireguardStatus returns the status of the Wireguard or AmneziaWG
+// server peer. It is notably used by the HTTP control server.
+func (l *Loop) GetWireguardStatus() (status models.WireguardStatus, err error) {
+	settings := l.GetSettings()
+	if settings.Type == vpn.OpenVPN || l.GetStatus() != constants.Running {
+		return status, ErrWireguardNotRunning
+	}
+	return l.wireguardStatus(getVPNInterface(settings))
+}
+
+// monitorWireguardHandshake periodically checks the age of the last handshake
+// with the Wireguard server peer, and restarts the VPN if it is older than
+// the timeout given, until the context is canceled.
+func (l *Loop) monitorWireguardHandshake(ctx, loopCtx context.Context,
+	vpnInterface string, timeout time.Duration,
+) {
+	tunnelUpTime := time.Now()
+	const checkPeriod = 15 * time.Second
+	ticker := time.NewTicker(checkPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		status, err := l.wireguardStatus(vpnInterface)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			l.logger.Warn("getting Wireguard status: " + err.Error())
+			continue
+		}
+
+		err = checkHandshake(status.LastHandshake, tunnelUpTime, time.Now(), timeout)
+		if err != nil {
+			l.restart(loopCtx, err)
+			return
+		}
+	}
+}
+
+// checkHandshake returns an error if the last handshake is older than the
+// timeout given, or if no handshake occurred within the timeout since the
+// tunnel went up.
+func checkHandshake(lastHandshake, tunnelUpTime, now time.Time,
+	timeout time.Duration,
+) (err error) {
+	if lastHandshake.IsZero() {
+		sinceTunnelUp := now.Sub(tunnelUpTime)
+		if sinceTunnelUp > timeout {
+			return fmt.Errorf("%w: no handshake since the tunnel went up %s ago",
+				errHandshakeTooOld, sinceTunnelUp.Round(time.Second))
+		}
+		return nil
+	}
+
+	age := now.Sub(lastHandshake)
+	if age > timeout {
+		return fmt.Errorf("%w: %s ago exceeds the timeout of %s",
+			errHandshakeTooOld, age.Round(time.Second), timeout)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package vpn
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkHandshake(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	const timeout = 3 * time.Minute
+
+	testCases := map[string]struct {
+		lastHandshake time.Time
+		tunnelUpTime  time.Time
+		errMessage    string
+	}{
+		"no_handshake_yet": {
+			tunnelUpTime: now.Add(-time.Minute),
+		},
+		"no_handshake_after_timeout": {
+			tunnelUpTime: now.Add(-4 * time.Minute),
+			errMessage: "last Wireguard handshake is too old: " +
+				"no handshake since the tunnel went up 4m0s ago",
+		},
+		"recent_handshake": {
+			lastHandshake: now.Add(-2 * time.Minute),
+			tunnelUpTime:  now.Add(-time.Hour),
+		},
+		"old_handshake": {
+			lastHandshake: now.Add(-5 * time.Minute),
+			tunnelUpTime:  now.Add(-time.Hour),
+			errMessage: "last Wireguard handshake is too old: " +
+				"5m0s ago exceeds the timeout of 3m0s",
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkHandshake(testCase.lastHandshake,
+				testCase.tunnelUpTime, now, timeout)
+
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/log"
 )
 
 func ptrTo[T any](value T) *T { return &value }
@@ -53,6 +54,7 @@ func (l *Loop) signalOrSetStatus(status models.LoopStatus) {
 func (l *Loop) logAndWait(ctx context.Context, err error) {
 	if err != nil {
 		l.logger.Error(err.Error())
+		l.notifier.Notify(models.NotificationVPNCrashed, err.Error(), nil)
 	}
 	l.logger.Info("retrying in " + l.backoffTime.String())
 	timer := time.NewTimer(l.backoffTime)
@@ -65,3 +67,10 @@ func (l *Loop) logAndWait(ctx context.Context, err error) {
 		}
 	}
 }
+
+// newSubLogger returns a logger for the sub-component given,
+// with its log level resolved from the log settings.
+func (l *Loop) newSubLogger(component string) *log.Logger {
+	return l.logger.New(log.SetComponent(component),
+		log.SetLevel(l.logSettings.ComponentLevel(component)))
+}
@@ -19,6 +19,9 @@ type Firewall interface {
 	SetVPNConnection(ctx context.Context, connection models.Connection, interfaceName string) error
 	SetAllowedPort(ctx context.Context, port uint16, interfaceName string) error
 	RemoveAllowedPort(ctx context.Context, port uint16) error
+	AcceptOutput(ctx context.Context, protocol, intf string, ip netip.Addr,
+		port uint16, remove bool) error
+	SetTCPMSSClamp(ctx context.Context, vpnIntf string, mtu uint32) error
 	tcp.Firewall
 }
 
@@ -92,11 +95,18 @@ type DNSLoop interface {
 	ApplyStatus(ctx context.Context, status models.LoopStatus) (
 		outcome string, err error)
 	GetSettings() (settings settings.DNS)
+	CheckLeak(ctx context.Context) (report models.DNSLeakReport, err error)
 }
 
 type PublicIPLoop interface {
 	RunOnce(ctx context.Context) (err error)
+	GetData() (data models.PublicIP)
 	ClearData() (err error)
+	SetServerName(serverName string)
+}
+
+type Notifier interface {
+	Notify(event models.NotificationEvent, message string, fields map[string]string)
 }
 
 type Cmder interface {
@@ -116,6 +126,7 @@ type HealthChecker interface {
 
 type HealthServer interface {
 	SetError(err error)
+	SetCheckError(name string, err error)
 }
 
 type Service interface {
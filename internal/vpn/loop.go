@@ -2,6 +2,8 @@ package vpn
 
 import (
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/qdm12/gluetun/internal/configuration/settings"
@@ -9,7 +11,9 @@ import (
 	"github.com/qdm12/gluetun/internal/loopstate"
 	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
+	"github.com/qdm12/gluetun/internal/openvpn"
 	"github.com/qdm12/gluetun/internal/vpn/state"
+	"github.com/qdm12/gluetun/internal/wireguard"
 	"github.com/qdm12/log"
 )
 
@@ -26,6 +30,9 @@ type Loop struct {
 	versionInfo      bool
 	ipv6SupportLevel netlink.IPv6SupportLevel
 	vpnInputPorts    []uint16 // TODO make changeable through stateful firewall
+	// proxyPorts are the HTTP proxy and Shadowsocks listening ports,
+	// used to wait for their connections to close before a rotation.
+	proxyPorts []uint16
 	// Configurators
 	openvpnConf OpenVPN
 	netLinker   NetLinker
@@ -35,10 +42,29 @@ type Loop struct {
 	publicip    PublicIPLoop
 	dnsLooper   DNSLoop
 	boringPoll  Service
+	notifier    Notifier
+	// wireguardStatus returns the Wireguard status for an interface name.
+	wireguardStatus func(interfaceName string) (status models.WireguardStatus, err error)
+	// openvpnRunner is the current OpenVPN runner, and is nil
+	// if the VPN type is not OpenVPN.
+	openvpnRunner atomic.Pointer[openvpn.Runner]
+	// Server rotation
+	connection      models.Connection
+	connectionMutex sync.RWMutex
+	rotationMutex   sync.Mutex
+	// rotationAvoid is set before a rotation restart and consumed
+	// when picking the next server connection.
+	rotationAvoid atomic.Pointer[rotationAvoid]
+	// Path MTU discovery
+	mtuDiscovery atomic.Pointer[models.MTUDiscovery]
+	mtuTrigger   atomic.Pointer[mtuTrigger]
 	// Other objects
 	cmder  Cmder // for OpenVPN and up/down commands
 	logger log.LoggerInterface
-	client *http.Client
+	// logSettings are used to set the log level of sub-loggers
+	// such as the OpenVPN, Wireguard and MTU discovery loggers.
+	logSettings settings.Log
+	client      *http.Client
 	// Internal channels and values
 	stop        <-chan struct{}
 	stopped     chan<- struct{}
@@ -53,13 +79,14 @@ const (
 	defaultBackoffTime = 15 * time.Second
 )
 
-func NewLoop(vpnSettings settings.VPN, ipv6SupportLevel netlink.IPv6SupportLevel, vpnInputPorts []uint16,
+func NewLoop(vpnSettings settings.VPN, ipv6SupportLevel netlink.IPv6SupportLevel,
+	vpnInputPorts, proxyPorts []uint16,
 	providers Providers, storage Storage, boringPoll Service,
 	healthSettings settings.Health, healthChecker HealthChecker, healthServer HealthServer,
 	openvpnConf OpenVPN, netLinker NetLinker, fw Firewall, routing Routing,
 	portForward PortForward, cmder Cmder,
-	publicip PublicIPLoop, dnsLooper DNSLoop,
-	logger log.LoggerInterface, client *http.Client,
+	publicip PublicIPLoop, dnsLooper DNSLoop, notifier Notifier,
+	logger log.LoggerInterface, logSettings settings.Log, client *http.Client,
 	buildInfo models.BuildInformation, versionInfo bool,
 ) *Loop {
 	start := make(chan struct{})
@@ -82,7 +109,9 @@ func NewLoop(vpnSettings settings.VPN, ipv6SupportLevel netlink.IPv6SupportLevel
 		versionInfo:      versionInfo,
 		ipv6SupportLevel: ipv6SupportLevel,
 		vpnInputPorts:    vpnInputPorts,
+		proxyPorts:       proxyPorts,
 		boringPoll:       boringPoll,
+		wireguardStatus:  wireguard.GetStatus,
 		openvpnConf:      openvpnConf,
 		netLinker:        netLinker,
 		fw:               fw,
@@ -90,8 +119,10 @@ func NewLoop(vpnSettings settings.VPN, ipv6SupportLevel netlink.IPv6SupportLevel
 		portForward:      portForward,
 		publicip:         publicip,
 		dnsLooper:        dnsLooper,
+		notifier:         notifier,
 		cmder:            cmder,
 		logger:           logger,
+		logSettings:      logSettings,
 		client:           client,
 		start:            start,
 		running:          running,
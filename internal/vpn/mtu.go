@@ -0,0 +1,166 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+	pconstants "github.com/qdm12/gluetun/internal/pmtud/constants"
+)
+
+const (
+	mtuTriggerTunnelUp = "tunnel up"
+	mtuTriggerPeriodic = "periodic"
+	mtuTriggerManual   = "manual"
+)
+
+var (
+	ErrMTUDiscoveryNotDone    = errors.New("no path MTU discovery was done")
+	ErrMTUDiscoveryNotRunning = errors.New("path MTU discovery is not running " +
+		"since the VPN tunnel is not up or the VPN interface MTU is set")
+)
+
+// mtuTrigger is used to request a path MTU discovery
+// from the goroutine monitoring the current VPN tunnel.
+type mtuTrigger struct {
+	requests chan<- chan<- models.MTUDiscovery
+	// done is closed when the VPN tunnel goes down.
+	done <-chan struct{}
+}
+
+// GetMTUDiscovery returns the report of the last path MTU discovery.
+// It is notably used by the HTTP control server.
+func (l *Loop) GetMTUDiscovery() (discovery models.MTUDiscovery, err error) {
+	last := l.mtuDiscovery.Load()
+	if last == nil {
+		return discovery, ErrMTUDiscoveryNotDone
+	}
+	return *last, nil
+}
+
+// DiscoverMTU runs a path MTU discovery on the VPN interface,
+// updates its MTU and returns the discovery report. It is
+// notably used by the HTTP control server.
+func (l *Loop) DiscoverMTU(ctx context.Context) (discovery models.MTUDiscovery, err error) {
+	trigger := l.mtuTrigger.Load()
+	if trigger == nil {
+		return discovery, ErrMTUDiscoveryNotRunning
+	}
+
+	reply := make(chan models.MTUDiscovery, 1)
+	select {
+	case <-ctx.Done():
+		return discovery, ctx.Err()
+	case <-trigger.done:
+		return discovery, ErrMTUDiscoveryNotRunning
+	case trigger.requests <- reply:
+	}
+
+	select {
+	case <-ctx.Done():
+		return discovery, ctx.Err()
+	case discovery = <-reply:
+		return discovery, nil
+	}
+}
+
+// monitorMTU re-runs the path MTU discovery periodically if a period
+// is set, and on demand through [Loop.DiscoverMTU], until the context
+// is canceled.
+func (l *Loop) monitorMTU(ctx context.Context, vpnInterface string, data tunnelUpPMTUDData) {
+	requests := make(chan chan<- models.MTUDiscovery)
+	trigger := &mtuTrigger{requests: requests, done: ctx.Done()}
+	l.mtuTrigger.Store(trigger)
+	defer l.mtuTrigger.CompareAndSwap(trigger, nil)
+
+	var timerCh <-chan time.Time
+	var timer *time.Timer
+	if data.period > 0 {
+		timer = time.NewTimer(data.period)
+		defer timer.Stop()
+		timerCh = timer.C
+	}
+
+	for {
+		var reply chan<- models.MTUDiscovery
+		triggerName := mtuTriggerPeriodic
+		select {
+		case <-ctx.Done():
+			return
+		case <-timerCh:
+		case reply = <-requests:
+			triggerName = mtuTriggerManual
+		}
+
+		discovery := l.discoverMTU(ctx, vpnInterface, data, triggerName)
+		if discovery.MTU != discovery.PreviousMTU {
+			l.logger.Infof("VPN interface %s MTU changed from %d to %d",
+				vpnInterface, discovery.PreviousMTU, discovery.MTU)
+		}
+		l.clampTCPMSS(ctx, vpnInterface, data.openvpnMSSFix)
+		if reply != nil {
+			reply <- discovery
+		}
+		if timer != nil {
+			timer.Reset(data.period)
+		}
+	}
+}
+
+// discoverMTU runs the path MTU discovery on the VPN interface,
+// and records and returns its report.
+func (l *Loop) discoverMTU(ctx context.Context, vpnInterface string,
+	data tunnelUpPMTUDData, trigger string,
+) (discovery models.MTUDiscovery) {
+	mtuLogger := l.newSubLogger("MTU discovery")
+	start := time.Now()
+	discovery, err := updateToMaxMTU(ctx, vpnInterface, data.vpnType,
+		data.network, data.ipv6, data.icmpAddrs, data.tcpAddrs,
+		l.netLinker, l.routing, l.fw, mtuLogger)
+	if err != nil {
+		mtuLogger.Error(err.Error())
+		discovery.Error = err.Error()
+	}
+	discovery.Trigger = trigger
+	discovery.Start = start
+	discovery.DurationMilliseconds = time.Since(start).Milliseconds()
+	l.mtuDiscovery.Store(&discovery)
+	return discovery
+}
+
+// clampTCPMSS sets the firewall TCP MSS clamping for connections going
+// out through the VPN interface, which notably matters for traffic
+// forwarded from other containers or LAN clients.
+func (l *Loop) clampTCPMSS(ctx context.Context, vpnInterface string, openvpnMSSFix uint16) {
+	mtu := tcpMSSClampMTU(openvpnMSSFix, l.mtuDiscovery.Load())
+	err := l.fw.SetTCPMSSClamp(ctx, vpnInterface, mtu)
+	if err != nil {
+		l.logger.Error("clamping TCP MSS: " + err.Error())
+	}
+}
+
+// tcpMSSClampMTU returns the MTU to derive the clamped TCP MSS from.
+// The OpenVPN mssfix value takes precedence if set, since it is the
+// maximum size of the encapsulated packets including the OpenVPN header.
+// Otherwise the MTU found by the last successful path MTU discovery is
+// used. It returns 0 to clamp the MSS to the path MTU if neither is set.
+func tcpMSSClampMTU(openvpnMSSFix uint16, discovery *models.MTUDiscovery) (mtu uint32) {
+	mssFix := uint32(openvpnMSSFix)
+	if mssFix >= pconstants.OpenVPNHeaderMaxLength+pconstants.MinIPv4MTU {
+		return mssFix - pconstants.OpenVPNHeaderMaxLength
+	}
+	if discovery != nil && discovery.Method != "" {
+		return discovery.MTU
+	}
+	return 0
+}
+
+func getOpenVPNMSSFix(settings settings.VPN) (mssFix uint16) {
+	if settings.Type != vpn.OpenVPN {
+		return 0
+	}
+	return *settings.OpenVPN.MSSFix
+}
@@ -0,0 +1,113 @@
+package vpn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Loop_GetMTUDiscovery(t *testing.T) {
+	t.Parallel()
+
+	loop := &Loop{}
+	_, err := loop.GetMTUDiscovery()
+	require.ErrorIs(t, err, ErrMTUDiscoveryNotDone)
+
+	loop.mtuDiscovery.Store(&models.MTUDiscovery{MTU: 1400})
+	discovery, err := loop.GetMTUDiscovery()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1400), discovery.MTU)
+}
+
+func Test_Loop_DiscoverMTU(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tunnel_not_up", func(t *testing.T) {
+		t.Parallel()
+		loop := &Loop{}
+		_, err := loop.DiscoverMTU(t.Context())
+		require.ErrorIs(t, err, ErrMTUDiscoveryNotRunning)
+	})
+
+	t.Run("tunnel_going_down", func(t *testing.T) {
+		t.Parallel()
+		loop := &Loop{}
+		done := make(chan struct{})
+		close(done)
+		loop.mtuTrigger.Store(&mtuTrigger{
+			requests: make(chan chan<- models.MTUDiscovery),
+			done:     done,
+		})
+		_, err := loop.DiscoverMTU(t.Context())
+		require.ErrorIs(t, err, ErrMTUDiscoveryNotRunning)
+	})
+
+	t.Run("context_canceled", func(t *testing.T) {
+		t.Parallel()
+		loop := &Loop{}
+		loop.mtuTrigger.Store(&mtuTrigger{
+			requests: make(chan chan<- models.MTUDiscovery),
+			done:     make(chan struct{}),
+		})
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		_, err := loop.DiscoverMTU(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("discovery_done", func(t *testing.T) {
+		t.Parallel()
+		loop := &Loop{}
+		requests := make(chan chan<- models.MTUDiscovery)
+		loop.mtuTrigger.Store(&mtuTrigger{requests: requests, done: make(chan struct{})})
+		go func() {
+			reply := <-requests
+			reply <- models.MTUDiscovery{Trigger: mtuTriggerManual, MTU: 1420}
+		}()
+		discovery, err := loop.DiscoverMTU(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, models.MTUDiscovery{Trigger: mtuTriggerManual, MTU: 1420}, discovery)
+	})
+}
+
+func Test_tcpMSSClampMTU(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		openvpnMSSFix uint16
+		discovery     *models.MTUDiscovery
+		mtu           uint32
+	}{
+		"nothing_set": {},
+		"failed_discovery": {
+			discovery: &models.MTUDiscovery{MTU: 1400, Error: "PMTUD failed with both ICMP and TCP"},
+		},
+		"successful_discovery": {
+			discovery: &models.MTUDiscovery{MTU: 1420, Method: "tcp"},
+			mtu:       1420,
+		},
+		"openvpn_mssfix_precedence": {
+			openvpnMSSFix: 1450,
+			discovery:     &models.MTUDiscovery{MTU: 1420, Method: "tcp"},
+			mtu:           1409,
+		},
+		"openvpn_mssfix_too_small": {
+			openvpnMSSFix: 100,
+			discovery:     &models.MTUDiscovery{MTU: 1420, Method: "icmp"},
+			mtu:           1420,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			mtu := tcpMSSClampMTU(testCase.openvpnMSSFix, testCase.discovery)
+
+			assert.Equal(t, testCase.mtu, mtu)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package vpn
+
+import (
+	"errors"
+
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/models"
+)
+
+var ErrOpenVPNNotRunning = errors.New("no OpenVPN connection is running")
+
+// GetOpenVPNStatus returns the status of the OpenVPN connection obtained
+// from its management interface. It is notably used by the HTTP control server.
+func (l *Loop) GetOpenVPNStatus() (status models.OpenVPNStatus, err error) {
+	settings := l.GetSettings()
+	if settings.Type != vpn.OpenVPN || l.GetStatus() != constants.Running {
+		return status, ErrOpenVPNNotRunning
+	}
+	runner := l.openvpnRunner.Load()
+	if runner == nil {
+		return status, ErrOpenVPNNotRunning
+	}
+	return runner.Status(), nil
+}
@@ -0,0 +1,169 @@
+package vpn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/constants"
+	"github.com/qdm12/gluetun/internal/cron"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+)
+
+var (
+	ErrRotationInProgress    = errors.New("a server rotation is already in progress")
+	ErrRotationVPNNotRunning = errors.New("VPN is not running")
+)
+
+// Rotate reconnects the VPN to another server of the filtered servers
+// pool, avoiding the current server and IP address depending on the
+// rotation settings. It first waits for active proxy connections to
+// close, up to the rotation drain timeout.
+func (l *Loop) Rotate(ctx context.Context) (outcome string, err error) {
+	if !l.rotationMutex.TryLock() {
+		return "", ErrRotationInProgress
+	}
+	defer l.rotationMutex.Unlock()
+
+	if l.GetStatus() != constants.Running {
+		return "", ErrRotationVPNNotRunning
+	}
+
+	rotation := l.GetSettings().Rotation
+	l.drainProxyConnections(ctx, *rotation.DrainTimeout)
+
+	current := l.GetConnection()
+	avoid := rotationAvoid{}
+	if *rotation.AvoidSameServer {
+		avoid.serverName = getServerName(current)
+	}
+	if *rotation.AvoidSameIP {
+		avoid.ip = current.IP
+	}
+	l.rotationAvoid.Store(&avoid)
+
+	l.logger.Info("rotating VPN server away from " + formatConnection(current))
+	_, err = l.ApplyStatus(ctx, constants.Stopped)
+	if err != nil {
+		l.rotationAvoid.Store(nil)
+		return "", fmt.Errorf("stopping VPN: %w", err)
+	}
+	outcome, err = l.ApplyStatus(ctx, constants.Running)
+	if err != nil {
+		return "", fmt.Errorf("starting VPN: %w", err)
+	}
+	return outcome, nil
+}
+
+// rotateOnSchedule waits for the next rotation time according to the
+// rotation settings given and then rotates the VPN server, unless the
+// context is canceled before.
+func (l *Loop) rotateOnSchedule(ctx, loopCtx context.Context, rotation settings.Rotation) {
+	var next time.Time
+	switch {
+	case *rotation.Interval > 0:
+		next = time.Now().Add(*rotation.Interval)
+	case *rotation.Schedule != "":
+		schedule, err := cron.Parse(*rotation.Schedule)
+		if err != nil {
+			l.logger.Error("parsing rotation schedule: " + err.Error())
+			return
+		}
+		next = schedule.Next(time.Now())
+		if next.IsZero() {
+			l.logger.Warn("rotation schedule " + *rotation.Schedule + " never triggers")
+			return
+		}
+	default:
+		return
+	}
+
+	l.logger.Info("next server rotation at " + next.Format(time.DateTime))
+	timer := time.NewTimer(time.Until(next))
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return
+	case <-timer.C:
+	}
+
+	// Note this rotation call must be done in a separate goroutine
+	// from the VPN loop goroutine.
+	_, err := l.Rotate(loopCtx)
+	if err != nil {
+		l.logger.Error("rotating VPN server: " + err.Error())
+	}
+}
+
+func (l *Loop) setConnection(connection models.Connection) {
+	l.connectionMutex.Lock()
+	defer l.connectionMutex.Unlock()
+	l.connection = connection
+}
+
+// GetConnection returns the last VPN server connection picked,
+// which is the zero value if the VPN was never started.
+func (l *Loop) GetConnection() (connection models.Connection) {
+	l.connectionMutex.RLock()
+	defer l.connectionMutex.RUnlock()
+	return l.connection
+}
+
+// rotationAvoid contains the server name and IP address to avoid
+// when picking the next connection. Each field is ignored if empty.
+type rotationAvoid struct {
+	serverName string
+	ip         netip.Addr
+}
+
+func (r rotationAvoid) matches(connection models.Connection) bool {
+	return (r.serverName != "" && r.serverName == getServerName(connection)) ||
+		(r.ip.IsValid() && r.ip == connection.IP)
+}
+
+func formatConnection(connection models.Connection) string {
+	serverName := getServerName(connection)
+	if serverName == "" {
+		return connection.IP.String()
+	}
+	return serverName + " (" + connection.IP.String() + ")"
+}
+
+func getServerName(connection models.Connection) string {
+	if connection.ServerName != "" {
+		return connection.ServerName
+	}
+	return connection.Hostname
+}
+
+// avoidingProvider wraps a provider to pick a connection different from
+// the connection to avoid, if possible.
+type avoidingProvider struct {
+	provider.Provider
+	avoid  rotationAvoid
+	warner interface{ Warn(message string) }
+}
+
+func (p *avoidingProvider) GetConnection(selection settings.ServerSelection, ipv6Supported bool) (
+	connection models.Connection, err error,
+) {
+	// Connections are picked from a shuffled pool, so retrying a bounded
+	// number of times finds a different connection with high probability
+	// if there is one.
+	const maxAttempts = 20
+	for range maxAttempts {
+		connection, err = p.Provider.GetConnection(selection, ipv6Supported)
+		if err != nil {
+			return connection, err
+		}
+		if !p.avoid.matches(connection) {
+			return connection, nil
+		}
+	}
+	p.warner.Warn("no other server connection found to rotate to, using " + formatConnection(connection))
+	return connection, nil
+}
@@ -0,0 +1,81 @@
+package vpn
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/qdm12/gluetun/internal/configuration/settings"
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/qdm12/gluetun/internal/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cyclingProvider struct {
+	provider.Provider
+	connections []models.Connection
+	calls       int
+}
+
+func (p *cyclingProvider) GetConnection(settings.ServerSelection, bool) (
+	connection models.Connection, err error,
+) {
+	connection = p.connections[p.calls%len(p.connections)]
+	p.calls++
+	return connection, nil
+}
+
+type noopWarner struct{}
+
+func (noopWarner) Warn(string) {}
+
+func Test_avoidingProvider_GetConnection(t *testing.T) {
+	t.Parallel()
+
+	serverA1 := models.Connection{ServerName: "a", IP: netip.AddrFrom4([4]byte{1, 1, 1, 1})}
+	serverA2 := models.Connection{ServerName: "a", IP: netip.AddrFrom4([4]byte{1, 1, 1, 2})}
+	serverB := models.Connection{Hostname: "b", IP: netip.AddrFrom4([4]byte{2, 2, 2, 2})}
+
+	testCases := map[string]struct {
+		connections []models.Connection
+		avoid       rotationAvoid
+		connection  models.Connection
+	}{
+		"avoid_nothing": {
+			connections: []models.Connection{serverA1, serverB},
+			connection:  serverA1,
+		},
+		"avoid_ip": {
+			connections: []models.Connection{serverA1, serverA2, serverB},
+			avoid:       rotationAvoid{ip: serverA1.IP},
+			connection:  serverA2,
+		},
+		"avoid_server": {
+			connections: []models.Connection{serverA1, serverA2, serverB},
+			avoid:       rotationAvoid{serverName: "a"},
+			connection:  serverB,
+		},
+		"single_connection": {
+			connections: []models.Connection{serverA1},
+			avoid:       rotationAvoid{serverName: "a", ip: serverA1.IP},
+			connection:  serverA1,
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := &avoidingProvider{
+				Provider: &cyclingProvider{connections: testCase.connections},
+				avoid:    testCase.avoid,
+				warner:   noopWarner{},
+			}
+
+			connection, err := provider.GetConnection(settings.ServerSelection{}, false)
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.connection, connection)
+		})
+	}
+}
@@ -2,11 +2,12 @@ package vpn
 
 import (
 	"context"
+	"time"
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
 	"github.com/qdm12/gluetun/internal/models"
-	"github.com/qdm12/log"
+	"github.com/qdm12/gluetun/internal/openvpn"
 )
 
 func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
@@ -21,7 +22,25 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 	for ctx.Err() == nil {
 		settings := l.state.GetSettings()
 
+		wireguardSelection := &settings.Provider.ServerSelection.Wireguard
+		if settings.Type != vpn.OpenVPN && wireguardSelection.EndpointHostname != "" {
+			var err error
+			wireguardSelection.EndpointIP, err = l.resolveEndpointHostname(ctx,
+				wireguardSelection.EndpointHostname)
+			if err != nil {
+				l.crashed(ctx, err)
+				continue
+			}
+		}
+
 		providerConf := l.providers.Get(settings.Provider.Name)
+		if avoid := l.rotationAvoid.Swap(nil); avoid != nil {
+			providerConf = &avoidingProvider{
+				Provider: providerConf,
+				avoid:    *avoid,
+				warner:   l.logger,
+			}
+		}
 
 		portForwarder := getPortForwarder(providerConf, l.providers,
 			*settings.Provider.PortForwarding.Provider)
@@ -29,21 +48,28 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 		var vpnRunner interface {
 			Run(ctx context.Context, waitError chan<- error, tunnelReady chan<- struct{})
 		}
+		l.openvpnRunner.Store(nil)
 		var vpnInterface string
+		var handshakeTimeout time.Duration
 		var connection models.Connection
 		var err error
-		subLogger := l.logger.New(log.SetComponent(settings.Type))
+		subLogger := l.newSubLogger(settings.Type)
 		switch settings.Type {
 		case vpn.AmneziaWg:
 			vpnInterface = settings.AmneziaWg.Wireguard.Interface
+			handshakeTimeout = *settings.AmneziaWg.Wireguard.HandshakeTimeout
 			vpnRunner, connection, err = setupAmneziaWg(ctx, l.netLinker, l.fw,
 				providerConf, settings, l.ipv6SupportLevel, subLogger)
 		case vpn.OpenVPN:
 			vpnInterface = settings.OpenVPN.Interface
-			vpnRunner, connection, err = setupOpenVPN(ctx, l.fw,
+			var openvpnRunner *openvpn.Runner
+			openvpnRunner, connection, err = setupOpenVPN(ctx, l.fw,
 				l.openvpnConf, providerConf, settings, l.ipv6SupportLevel, l.cmder, subLogger)
+			vpnRunner = openvpnRunner
+			l.openvpnRunner.Store(openvpnRunner)
 		case vpn.Wireguard:
 			vpnInterface = settings.Wireguard.Interface
+			handshakeTimeout = *settings.Wireguard.HandshakeTimeout
 			vpnRunner, connection, err = setupWireguard(ctx, l.netLinker, l.fw,
 				providerConf, settings, l.ipv6SupportLevel, subLogger)
 		default:
@@ -53,23 +79,27 @@ func (l *Loop) Run(ctx context.Context, done chan<- struct{}) {
 			l.crashed(ctx, err)
 			continue
 		}
+		l.setConnection(connection)
 		tunnelUpData := tunnelUpData{
 			upCommand: *settings.UpCommand,
 			pmtud: tunnelUpPMTUDData{
-				enabled:   settings.Type != vpn.Wireguard || *settings.Wireguard.MTU == 0,
-				vpnType:   settings.Type,
-				network:   connection.Protocol,
-				ipv6:      l.isIPv6Used(settings),
-				icmpAddrs: settings.PMTUD.ICMPAddresses,
-				tcpAddrs:  settings.PMTUD.TCPAddresses,
+				enabled:       settings.Type != vpn.Wireguard || *settings.Wireguard.MTU == 0,
+				vpnType:       settings.Type,
+				network:       connection.Protocol,
+				ipv6:          l.isIPv6Used(settings),
+				icmpAddrs:     settings.PMTUD.ICMPAddresses,
+				tcpAddrs:      settings.PMTUD.TCPAddresses,
+				period:        *settings.PMTUD.Period,
+				openvpnMSSFix: getOpenVPNMSSFix(settings),
 			},
-			serverIP:       connection.IP,
-			serverName:     connection.ServerName,
-			canPortForward: connection.PortForward,
-			portForwarder:  portForwarder,
-			vpnIntf:        vpnInterface,
-			username:       settings.Provider.PortForwarding.Username,
-			password:       settings.Provider.PortForwarding.Password,
+			handshakeTimeout: handshakeTimeout,
+			serverIP:         connection.IP,
+			serverName:       connection.ServerName,
+			canPortForward:   connection.PortForward,
+			portForwarder:    portForwarder,
+			vpnIntf:          vpnInterface,
+			username:         settings.Provider.PortForwarding.Username,
+			password:         settings.Provider.PortForwarding.Password,
 		}
 
 		vpnCtx, vpnCancel := context.WithCancel(context.Background())
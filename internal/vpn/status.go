@@ -3,6 +3,7 @@ package vpn
 import (
 	"context"
 
+	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/models"
 )
 
@@ -15,3 +16,12 @@ func (l *Loop) ApplyStatus(ctx context.Context, status models.LoopStatus) (
 ) {
 	return l.statusManager.ApplyStatus(ctx, status)
 }
+
+// restart logs the reason given and restarts the VPN.
+// It must be called from a goroutine other than the VPN loop goroutine,
+// since the loop goroutine is the one handling the status changes.
+func (l *Loop) restart(ctx context.Context, reason error) {
+	l.logger.Warnf("restarting VPN because of: %s", reason)
+	_, _ = l.ApplyStatus(ctx, constants.Stopped)
+	_, _ = l.ApplyStatus(ctx, constants.Running)
+}
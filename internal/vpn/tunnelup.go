@@ -10,6 +10,8 @@ import (
 
 	"github.com/qdm12/gluetun/internal/constants"
 	"github.com/qdm12/gluetun/internal/constants/vpn"
+	"github.com/qdm12/gluetun/internal/logging"
+	"github.com/qdm12/gluetun/internal/models"
 	"github.com/qdm12/gluetun/internal/netlink"
 	"github.com/qdm12/gluetun/internal/pmtud"
 	pconstants "github.com/qdm12/gluetun/internal/pmtud/constants"
@@ -23,6 +25,9 @@ type tunnelUpData struct {
 	// Healthcheck
 	serverIP netip.Addr
 	pmtud    tunnelUpPMTUDData
+	// handshakeTimeout is the Wireguard handshake watchdog timeout,
+	// and is 0 for OpenVPN or if the watchdog is disabled.
+	handshakeTimeout time.Duration
 	// Port forwarding
 	vpnIntf        string
 	serverName     string // used for PIA
@@ -49,6 +54,13 @@ type tunnelUpPMTUDData struct {
 	// tcpAddrs is the list of addresses to use for TCP path MTU discovery.
 	// Each address should have a listening TCP server on the port specified.
 	tcpAddrs []netip.AddrPort
+	// period is the period to re-run the path MTU discovery,
+	// and is 0 to disable periodic re-discovery.
+	period time.Duration
+	// openvpnMSSFix is the OpenVPN mssfix option value, which takes
+	// precedence over the discovered MTU to clamp the TCP MSS.
+	// It is 0 if it is not set or if the VPN type is not OpenVPN.
+	openvpnMSSFix uint16
 }
 
 func (l *Loop) onTunnelUp(ctx, loopCtx context.Context, data tunnelUpData) {
@@ -60,24 +72,38 @@ func (l *Loop) onTunnelUp(ctx, loopCtx context.Context, data tunnelUpData) {
 			vpnType, vpnType, vpnType)
 	}
 
+	connection := l.GetConnection()
+	l.logger.Info("VPN tunnel is up" +
+		logging.Field("server", getServerName(connection)) +
+		logging.Field("server_ip", connection.IP.String()) +
+		logging.Field("interface", data.vpnIntf))
+	l.notifier.Notify(models.NotificationTunnelUp,
+		"VPN tunnel is up with server "+formatConnection(connection),
+		map[string]string{
+			"server_name": getServerName(connection),
+			"server_ip":   connection.IP.String(),
+			"interface":   data.vpnIntf,
+		})
+
 	l.client.CloseIdleConnections()
 
 	for _, vpnPort := range l.vpnInputPorts {
 		err := l.fw.SetAllowedPort(ctx, vpnPort, data.vpnIntf)
 		if err != nil {
-			l.logger.Error("cannot allow input port through firewall: " + err.Error())
+			l.logger.Error("cannot allow input port through firewall" +
+				logging.Field("port", fmt.Sprint(int(vpnPort))) +
+				logging.Field("interface", data.vpnIntf) +
+				logging.Field("error", err.Error()))
 		}
 	}
 
 	if data.pmtud.enabled {
-		mtuLogger := l.logger.New(log.SetComponent("MTU discovery"))
-		err := updateToMaxMTU(ctx, data.vpnIntf, data.pmtud.vpnType,
-			data.pmtud.network, data.pmtud.ipv6, data.pmtud.icmpAddrs, data.pmtud.tcpAddrs,
-			l.netLinker, l.routing, l.fw, mtuLogger)
-		if err != nil {
-			mtuLogger.Error(err.Error())
-		}
+		l.discoverMTU(ctx, data.vpnIntf, data.pmtud, mtuTriggerTunnelUp)
+		go l.monitorMTU(ctx, data.vpnIntf, data.pmtud)
+	} else {
+		l.mtuDiscovery.Store(nil)
 	}
+	l.clampTCPMSS(ctx, data.vpnIntf, data.pmtud.openvpnMSSFix)
 
 	_, _ = l.dnsLooper.ApplyStatus(ctx, constants.Running)
 
@@ -106,9 +132,22 @@ func (l *Loop) onTunnelUp(ctx, loopCtx context.Context, data tunnelUpData) {
 	// to start monitoring health and auto-healing.
 	go l.collectHealthErrors(ctx, loopCtx, healthErrCh)
 
+	go l.monitorDNSLeaks(ctx, loopCtx)
+
+	if data.handshakeTimeout > 0 {
+		go l.monitorWireguardHandshake(ctx, loopCtx, data.vpnIntf, data.handshakeTimeout)
+	}
+
+	go l.rotateOnSchedule(ctx, loopCtx, l.GetSettings().Rotation)
+
+	l.publicip.SetServerName(getServerName(connection))
 	err = l.publicip.RunOnce(ctx)
 	if err != nil {
-		l.logger.Error("getting public IP address information: " + err.Error())
+		l.logger.Error("getting public IP address information" +
+			logging.Field("server", getServerName(connection)) +
+			logging.Field("error", err.Error()))
+	} else {
+		go l.verifyExit(ctx, loopCtx)
 	}
 
 	if l.versionInfo {
@@ -125,7 +164,9 @@ func (l *Loop) onTunnelUp(ctx, loopCtx context.Context, data tunnelUpData) {
 		commandString := strings.ReplaceAll(data.upCommand, "{{VPN_INTERFACE}}", data.vpnIntf)
 		err := l.cmder.RunAndLog(context.Background(), commandString, l.logger)
 		if err != nil {
-			l.logger.Error("failed to run VPN up command: " + err.Error())
+			l.logger.Error("failed to run VPN up command" +
+				logging.Field("interface", data.vpnIntf) +
+				logging.Field("error", err.Error()))
 		}
 	}
 
@@ -136,7 +177,7 @@ func (l *Loop) onTunnelUp(ctx, loopCtx context.Context, data tunnelUpData) {
 
 	_, err = l.boringPoll.Start()
 	if err != nil {
-		l.logger.Error("cannot start boring poll: " + err.Error())
+		l.logger.Error("cannot start boring poll" + logging.Field("error", err.Error()))
 	}
 }
 
@@ -149,6 +190,9 @@ func (l *Loop) collectHealthErrors(ctx, loopCtx context.Context, healthErrCh <-c
 			return
 		case healthErr := <-healthErrCh:
 			l.healthServer.SetError(healthErr)
+			if healthErr != nil && previousHealthErr == nil {
+				l.notifier.Notify(models.NotificationHealthFailure, healthErr.Error(), nil)
+			}
 			if healthErr != nil {
 				if *l.healthSettings.RestartVPN {
 					// Note this restart call must be done in a separate goroutine
@@ -175,23 +219,31 @@ func (l *Loop) restartVPN(ctx context.Context, healthErr error) {
 	_, _ = l.ApplyStatus(ctx, constants.Running)
 }
 
+// updateToMaxMTU discovers the maximum valid MTU for the VPN interface
+// and sets it on the interface, together with a matching TCP MSS on the
+// VPN routes. If the discovery fails, the original MTU is restored and
+// the discovery error is recorded in the report returned, which is
+// returned even if an error is returned.
 func updateToMaxMTU(ctx context.Context, vpnInterface string,
 	vpnType, network string, ipv6 bool, icmpAddrs []netip.Addr, tcpAddrs []netip.AddrPort,
 	netlinker NetLinker, routing Routing, firewall tcp.Firewall, logger *log.Logger,
-) error {
+) (discovery models.MTUDiscovery, err error) {
+	discovery.Interface = vpnInterface
 	logger.Info("finding maximum MTU, this can take up to 6 seconds")
 
 	vpnRoutes, err := routing.VPNRoutes(vpnInterface)
 	if err != nil {
-		return fmt.Errorf("getting VPN routes: %w", err)
+		return discovery, fmt.Errorf("getting VPN routes: %w", err)
 	}
 
 	link, err := netlinker.LinkByName(vpnInterface)
 	if err != nil {
-		return fmt.Errorf("getting VPN interface by name: %w", err)
+		return discovery, fmt.Errorf("getting VPN interface by name: %w", err)
 	}
 
 	originalMTU := link.MTU
+	discovery.PreviousMTU = originalMTU
+	discovery.MTU = originalMTU
 
 	vpnLinkMTU := pmtud.MaxTheoreticalVPNMTU(vpnType, network, ipv6)
 
@@ -201,7 +253,7 @@ func updateToMaxMTU(ctx context.Context, vpnInterface string,
 
 	err = netlinker.LinkSetMTU(link.Index, vpnLinkMTU)
 	if err != nil {
-		return fmt.Errorf("setting VPN interface %s MTU to %d: %w", vpnInterface, vpnLinkMTU, err)
+		return discovery, fmt.Errorf("setting VPN interface %s MTU to %d: %w", vpnInterface, vpnLinkMTU, err)
 	}
 
 	if !ipv6 {
@@ -214,19 +266,25 @@ func updateToMaxMTU(ctx context.Context, vpnInterface string,
 	}
 
 	const pingTimeout = time.Second
-	vpnLinkMTU, err = pmtud.PathMTUDiscover(ctx, icmpAddrs, tcpAddrs,
+	result, err := pmtud.PathMTUDiscover(ctx, icmpAddrs, tcpAddrs,
 		vpnLinkMTU, pingTimeout, firewall, logger)
+	discovery.Probes = result.Probes
 	if err != nil {
+		discovery.Error = err.Error()
 		vpnLinkMTU = originalMTU
 		logger.Infof("reverting VPN interface %s MTU to %d (due to: %s)",
 			vpnInterface, originalMTU, err)
 	} else {
+		discovery.Method = result.Method
+		vpnLinkMTU = result.MTU
 		logger.Infof("setting VPN interface %s MTU to maximum valid MTU %d", vpnInterface, vpnLinkMTU)
 	}
 
 	err = setTCPMSSOnVPNRoutes(vpnLinkMTU, vpnRoutes, netlinker)
 	if err != nil {
 		err = fmt.Errorf("setting safe TCP MSS for MTU %d: %w", vpnLinkMTU, err)
+		discovery.Error = err.Error()
+		discovery.Method = ""
 		vpnLinkMTU = originalMTU
 		logger.Infof("reverting VPN interface %s MTU to %d (due to: %s)",
 			vpnInterface, originalMTU, err)
@@ -234,10 +292,11 @@ func updateToMaxMTU(ctx context.Context, vpnInterface string,
 
 	err = netlinker.LinkSetMTU(link.Index, vpnLinkMTU)
 	if err != nil {
-		return fmt.Errorf("setting VPN interface %s MTU to %d: %w", vpnInterface, vpnLinkMTU, err)
+		return discovery, fmt.Errorf("setting VPN interface %s MTU to %d: %w", vpnInterface, vpnLinkMTU, err)
 	}
+	discovery.MTU = vpnLinkMTU
 
-	return nil
+	return discovery, nil
 }
 
 func setTCPMSSOnVPNRoutes(mtu uint32, routes []netlink.Route, netlinker NetLinker) error {
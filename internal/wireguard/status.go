@@ -0,0 +1,58 @@
+package wireguard
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"golang.zx2c4.com/wireguard/wgctrl"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+var ErrPeerNotFound = errors.New("no peer found on device")
+
+// GetStatus returns the status of the server peer of the Wireguard
+// device with the given interface name. It works for both the kernelspace
+// and userspace implementations, as well as for AmneziaWG devices.
+func GetStatus(interfaceName string) (status models.WireguardStatus, err error) {
+	client, err := wgctrl.New()
+	if err != nil {
+		return status, fmt.Errorf("opening wgctrl: %w", err)
+	}
+
+	device, err := client.Device(interfaceName)
+	if err != nil {
+		_ = client.Close()
+		return status, fmt.Errorf("getting device: %w", err)
+	}
+
+	err = client.Close()
+	if err != nil {
+		return status, fmt.Errorf("closing wgctrl: %w", err)
+	}
+
+	return makeStatus(device)
+}
+
+func makeStatus(device *wgtypes.Device) (status models.WireguardStatus, err error) {
+	if len(device.Peers) == 0 {
+		return status, fmt.Errorf("%w: %s", ErrPeerNotFound, device.Name)
+	}
+	peer := device.Peers[0]
+
+	status = models.WireguardStatus{
+		Interface:                  device.Name,
+		PublicKey:                  peer.PublicKey.String(),
+		LastHandshake:              peer.LastHandshakeTime,
+		ReceivedBytes:              peer.ReceiveBytes,
+		TransmittedBytes:           peer.TransmitBytes,
+		PersistentKeepaliveSeconds: uint(peer.PersistentKeepaliveInterval / time.Second),
+	}
+	if peer.Endpoint != nil {
+		status.Endpoint = peer.Endpoint.AddrPort()
+		status.Endpoint = netip.AddrPortFrom(status.Endpoint.Addr().Unmap(), status.Endpoint.Port())
+	}
+	return status, nil
+}
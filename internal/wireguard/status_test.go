@@ -0,0 +1,74 @@
+package wireguard
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/qdm12/gluetun/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.zx2c4.com/wireguard/wgctrl/wgtypes"
+)
+
+func Test_makeStatus(t *testing.T) {
+	t.Parallel()
+
+	const validKey = "oMNSf/zJ0pt1ciy+qIRk8Rlyfs9accwuRLnKd85Yl1Q="
+	publicKey, err := wgtypes.ParseKey(validKey)
+	require.NoError(t, err)
+
+	handshakeTime := time.Unix(1700000000, 0)
+
+	testCases := map[string]struct {
+		device     *wgtypes.Device
+		status     models.WireguardStatus
+		errMessage string
+	}{
+		"no_peer": {
+			device:     &wgtypes.Device{Name: "wg0"},
+			errMessage: "no peer found on device: wg0",
+		},
+		"peer": {
+			device: &wgtypes.Device{
+				Name: "wg0",
+				Peers: []wgtypes.Peer{{
+					PublicKey: publicKey,
+					Endpoint: &net.UDPAddr{
+						IP:   net.IPv4(1, 2, 3, 4),
+						Port: 51820,
+					},
+					LastHandshakeTime:           handshakeTime,
+					ReceiveBytes:                100,
+					TransmitBytes:               200,
+					PersistentKeepaliveInterval: 25 * time.Second,
+				}},
+			},
+			status: models.WireguardStatus{
+				Interface:                  "wg0",
+				PublicKey:                  validKey,
+				Endpoint:                   netip.MustParseAddrPort("1.2.3.4:51820"),
+				LastHandshake:              handshakeTime,
+				ReceivedBytes:              100,
+				TransmittedBytes:           200,
+				PersistentKeepaliveSeconds: 25,
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			status, err := makeStatus(testCase.device)
+
+			assert.Equal(t, testCase.status, status)
+			if testCase.errMessage != "" {
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}